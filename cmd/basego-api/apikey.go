@@ -0,0 +1,272 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/apikey"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
+	"github.com/jonylim/basego/internal/pkg/common/crypto/secrethash"
+	"github.com/jonylim/basego/internal/pkg/common/data/redis"
+	"github.com/jonylim/basego/internal/pkg/common/helper"
+)
+
+const apiKeyUsage = `Usage: basego-api [flags] apikey <command> [command flags]
+
+Commands:
+  list     List the API keys of a domain.
+  create   Create an API key, its secret is only shown once.
+  update   Change an API key's platform, app identifier, expiry, scopes or quotas.
+  enable   Enable an API key.
+  disable  Disable an API key.
+  rotate   Replace an API key's secret, the old secret keeps working for the overlap.
+
+Run "basego-api apikey <command> -h" for the command flags.
+`
+
+const scopesUsage = `The endpoints the key may call separated by commas, empty for every endpoint,
+e.g. "client/server_time,auth/access_token/request" or "client/reset_password/*"`
+
+// runAPIKeyCommand runs an "apikey" subcommand and returns the exit code.
+func runAPIKeyCommand(args []string, out io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, apiKeyUsage)
+		return 2
+	}
+	if err := initAPIKeyCommand(); err != nil {
+		fmt.Fprintf(os.Stderr, "apikey %s: %v\n", args[0], err)
+		return 1
+	}
+	fs := flag.NewFlagSet("apikey "+args[0], flag.ContinueOnError)
+	domain := fs.String("domain", apikey.DomainCustomer, "The keys' domain, customer or internal")
+
+	redisConn := redis.GetConnection()
+	defer redisConn.Close()
+	newService := func() *apikey.Service {
+		return apikey.NewService(redisConn, *domain)
+	}
+
+	var err error
+	switch args[0] {
+	case "list":
+		if err = parseAPIKeyFlags(fs, args[1:], domain, nil); err != nil {
+			break
+		}
+		var apiKeys []model.XAPIKey
+		if apiKeys, err = newService().List(); err == nil {
+			printAPIKeys(out, apiKeys)
+		}
+
+	case "create":
+		platform := fs.String("platform", "", "The app platform allowed to use the key (required)")
+		appIdentifier := fs.String("app-identifier", "", "The app identifier allowed to use the key, empty for any")
+		expiry := fs.String("expiry", "", "The key's expiry, a date (2006-01-02) or RFC 3339 time (required)")
+		disabled := fs.Bool("disabled", false, "Create the key disabled")
+		requireSignature := fs.Bool("require-signature", false, "Require the requests to be signed, only for the server platform")
+		scopes := fs.String("scopes", "", scopesUsage)
+		dailyQuota := fs.Int64("daily-quota", 0, "The requests allowed per UTC day, 0 is unlimited")
+		monthlyQuota := fs.Int64("monthly-quota", 0, "The requests allowed per UTC month, 0 is unlimited")
+		if err = parseAPIKeyFlags(fs, args[1:], domain, nil); err != nil {
+			break
+		}
+		settings := apikey.Settings{
+			AppPlatform:      *platform,
+			AppIdentifier:    *appIdentifier,
+			IsEnabled:        !*disabled,
+			RequireSignature: *requireSignature,
+			Scopes:           apikey.ParseScopes(*scopes),
+			DailyQuota:       *dailyQuota,
+			MonthlyQuota:     *monthlyQuota,
+		}
+		if settings.ExpiryTime, err = parseExpiry(*expiry); err != nil {
+			break
+		}
+		var apiKey model.XAPIKey
+		var secret string
+		if apiKey, secret, err = newService().Create(settings); err == nil {
+			printAPIKeys(out, []model.XAPIKey{apiKey})
+			printAPIKeySecret(out, apiKey, secret)
+		}
+
+	case "update":
+		keyID := fs.String("id", "", "The API key ID (required)")
+		platform := fs.String("platform", "", "The app platform allowed to use the key")
+		appIdentifier := fs.String("app-identifier", "", "The app identifier allowed to use the key, empty for any")
+		expiry := fs.String("expiry", "", "The key's expiry, a date (2006-01-02) or RFC 3339 time")
+		requireSignature := fs.Bool("require-signature", false, "Require the requests to be signed, only for the server platform")
+		scopes := fs.String("scopes", "", scopesUsage)
+		dailyQuota := fs.Int64("daily-quota", 0, "The requests allowed per UTC day, 0 is unlimited")
+		monthlyQuota := fs.Int64("monthly-quota", 0, "The requests allowed per UTC month, 0 is unlimited")
+		if err = parseAPIKeyFlags(fs, args[1:], domain, keyID); err != nil {
+			break
+		}
+		// Only the flags passed are changed.
+		var changes apikey.Changes
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "platform":
+				changes.AppPlatform = platform
+			case "app-identifier":
+				changes.AppIdentifier = appIdentifier
+			case "require-signature":
+				changes.RequireSignature = requireSignature
+			case "scopes":
+				list := apikey.ParseScopes(*scopes)
+				changes.Scopes = &list
+			case "daily-quota":
+				changes.DailyQuota = dailyQuota
+			case "monthly-quota":
+				changes.MonthlyQuota = monthlyQuota
+			case "expiry":
+				var expiryTime int64
+				if expiryTime, err = parseExpiry(*expiry); err == nil {
+					changes.ExpiryTime = &expiryTime
+				}
+			}
+		})
+		if err != nil {
+			break
+		}
+		var apiKey model.XAPIKey
+		if apiKey, err = newService().Update(*keyID, changes); err == nil {
+			printAPIKeys(out, []model.XAPIKey{apiKey})
+		}
+
+	case "enable", "disable":
+		keyID := fs.String("id", "", "The API key ID (required)")
+		if err = parseAPIKeyFlags(fs, args[1:], domain, keyID); err != nil {
+			break
+		}
+		var apiKey model.XAPIKey
+		if apiKey, err = newService().SetEnabled(*keyID, args[0] == "enable"); err == nil {
+			printAPIKeys(out, []model.XAPIKey{apiKey})
+		}
+
+	case "rotate":
+		keyID := fs.String("id", "", "The API key ID (required)")
+		overlap := fs.Duration("overlap", 24*time.Hour, fmt.Sprintf("How long the old secret keeps working, up to %v", apikey.MaxOverlap))
+		if err = parseAPIKeyFlags(fs, args[1:], domain, keyID); err != nil {
+			break
+		}
+		var apiKey model.XAPIKey
+		var secret string
+		if apiKey, secret, err = newService().Rotate(*keyID, *overlap); err == nil {
+			printAPIKeys(out, []model.XAPIKey{apiKey})
+			printAPIKeySecret(out, apiKey, secret)
+		}
+
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown apikey command %q\n\n%s", args[0], apiKeyUsage)
+		return 2
+	}
+
+	if err == flag.ErrHelp {
+		return 0
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "apikey %s: %v\n", args[0], err)
+		return 1
+	}
+	return 0
+}
+
+// initAPIKeyCommand initializes the key of the secrets' hashes and the API keys' configuration, which are only
+// initialized for the server. The key must be configured, or the servers wouldn't match the created secrets.
+func initAPIKeyCommand() error {
+	secrethash.Init()
+	apikey.Init()
+	if !secrethash.IsConfigured() {
+		return fmt.Errorf("%s must be configured to hash the secrets as the servers do", envvar.SecretHash.Key)
+	}
+	return nil
+}
+
+// parseAPIKeyFlags parses the flags of an "apikey" subcommand, and checks the domain and the key ID if required.
+func parseAPIKeyFlags(fs *flag.FlagSet, args []string, domain, keyID *string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return fmt.Errorf("unexpected arguments %q", fs.Args())
+	}
+	if !apikey.IsValidDomain(*domain) {
+		return fmt.Errorf("domain %q is invalid", *domain)
+	}
+	if keyID != nil && *keyID == "" {
+		return errors.New("-id is required")
+	}
+	return nil
+}
+
+// parseExpiry parses an expiry date (2006-01-02, the end of the day in UTC) or RFC 3339 time into Unix milliseconds.
+func parseExpiry(s string) (int64, error) {
+	if s == "" {
+		return 0, errors.New("-expiry is required")
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return helper.UnixMillisecond(t.AddDate(0, 0, 1)), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return 0, fmt.Errorf("expiry %q is neither a date (2006-01-02) nor an RFC 3339 time", s)
+	}
+	return helper.UnixMillisecond(t), nil
+}
+
+func printAPIKeys(out io.Writer, apiKeys []model.XAPIKey) {
+	formatTime := func(millis int64) string {
+		if millis == 0 {
+			return "-"
+		}
+		return helper.FromUnixMillisecond(millis).UTC().Format(time.RFC3339)
+	}
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY ID\tDOMAIN\tPLATFORM\tAPP IDENTIFIER\tEXPIRY\tENABLED\tSIGNED\tSCOPES\tQUOTAS\tOLD SECRET UNTIL\tUPDATED")
+	for _, k := range apiKeys {
+		appIdentifier := k.AppIdentifier
+		if appIdentifier == "" {
+			appIdentifier = "*"
+		}
+		scopes := k.Scopes
+		if scopes == "" {
+			scopes = apikey.ScopeAll
+		}
+		prevExpiry := k.PreviousSecretExpiryTime
+		if prevExpiry <= helper.UnixMillisecond(time.Now()) {
+			prevExpiry = 0
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%v\t%v\t%s\t%s\t%s\t%s\n",
+			k.APIKeyID, k.Domain, k.AppPlatform, appIdentifier,
+			formatTime(k.ExpiryTime), k.IsEnabled, k.RequireSignature, scopes, formatQuotas(k),
+			formatTime(prevExpiry), formatTime(k.UpdatedTime))
+	}
+	tw.Flush()
+}
+
+// formatQuotas formats an API key's quotas as "<daily>/day,<monthly>/month", "-" if it has none.
+func formatQuotas(k model.XAPIKey) string {
+	res := ""
+	if k.DailyQuota > 0 {
+		res = fmt.Sprintf("%d/day", k.DailyQuota)
+	}
+	if k.MonthlyQuota > 0 {
+		if res != "" {
+			res += ","
+		}
+		res += fmt.Sprintf("%d/month", k.MonthlyQuota)
+	}
+	if res == "" {
+		return "-"
+	}
+	return res
+}
+
+func printAPIKeySecret(out io.Writer, apiKey model.XAPIKey, secret string) {
+	fmt.Fprintf(out, "\nSecret:      %s\nAPI-Key:     %s\nSigning key: %s\n\nThe secret isn't shown again, keep it safe.\n",
+		secret, apikey.Encode(apiKey.APIKeyID, secret), apikey.SigningKey(secret))
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/apikey"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
+	"github.com/jonylim/basego/internal/pkg/common/crypto/secrethash"
+)
+
+func TestInitAPIKeyCommand(t *testing.T) {
+	defer os.Unsetenv(envvar.SecretHash.Key)
+	defer secrethash.SetKey(nil)
+
+	// The secrets created by the commands, which run in a fresh process, match on the servers.
+	os.Setenv(envvar.SecretHash.Key, base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, secrethash.MinKeySize)))
+	secrethash.SetKey(nil)
+	if err := initAPIKeyCommand(); err != nil {
+		t.Fatalf("initAPIKeyCommand() = %v", err)
+	}
+	saved := apikey.HashSecret("secret")
+
+	secrethash.SetKey(nil)
+	secrethash.Init()
+	if !apikey.MatchesSecret(model.XAPIKey{APIKeySecret: saved}, "secret", time.Now()) {
+		t.Errorf("MatchesSecret() of the secret hashed by the command = false on the server")
+	}
+
+	// Without the key, the commands would hash with a random key the servers don't know.
+	os.Unsetenv(envvar.SecretHash.Key)
+	os.Setenv(envvar.Environment, "local")
+	defer os.Unsetenv(envvar.Environment)
+	if err := initAPIKeyCommand(); err == nil {
+		t.Errorf("initAPIKeyCommand() without %s = nil error", envvar.SecretHash.Key)
+	}
+}
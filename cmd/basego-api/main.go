@@ -12,12 +12,19 @@ import (
 	"time"
 
 	appV1 "github.com/jonylim/basego/internal/app/basego-api/v1"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/apikey"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/webpage"
 	"github.com/jonylim/basego/internal/pkg/common/api"
 	"github.com/jonylim/basego/internal/pkg/common/asset"
 	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
+	"github.com/jonylim/basego/internal/pkg/common/crypto/envelope"
+	"github.com/jonylim/basego/internal/pkg/common/crypto/secrethash"
 	"github.com/jonylim/basego/internal/pkg/common/data/db"
 	"github.com/jonylim/basego/internal/pkg/common/data/redis"
+	"github.com/jonylim/basego/internal/pkg/common/emailvetting"
+	"github.com/jonylim/basego/internal/pkg/common/imagepipeline"
 	"github.com/jonylim/basego/internal/pkg/common/logger"
+	"github.com/jonylim/basego/internal/pkg/common/passwordpolicy"
 	"github.com/jonylim/basego/internal/pkg/common/send/email"
 	"github.com/jonylim/basego/internal/pkg/common/storage"
 
@@ -41,6 +48,10 @@ func main() {
 	srvPort := flag.String("port", "", "HTTP server port")
 
 	// Parse app argument flags.
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [apikey|storage <command>]\n", AppName)
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
 	// Load the environment variables.
@@ -69,18 +80,51 @@ func main() {
 	redis.Init()
 	defer redis.Close()
 
+	// Run a command instead of the server, e.g. to manage the API keys.
+	if flag.NArg() != 0 {
+		code := runCommand(flag.Args())
+		redis.Close()
+		db.CloseAll()
+		os.Exit(code)
+	}
+
 	// Initialize asset configurations.
 	asset.Init()
 
 	// Init storage configurations.
 	storage.Init()
 
+	// Init file encryption keys.
+	envelope.Init()
+
+	// Init the image variants of the uploads.
+	imagepipeline.Init()
+
 	// Init email sender.
 	email.Init()
 
+	// Init password policy.
+	passwordpolicy.Init()
+
+	// Init email address vetting.
+	emailvetting.Init()
+
+	// Init the key of the secrets' hashes.
+	secrethash.Init()
+
+	// Init the verification of the signed requests.
+	apikey.Init()
+
+	// Init web page configurations.
+	webpage.Init()
+
 	// Create the server
 	srv := newServer(*srvPort)
 
+	// Run the scheduled jobs.
+	stopSchedulers := make(chan struct{})
+	go appV1.RunSchedulers(stopSchedulers)
+
 	stopped := make(chan bool)
 	go func() {
 		chsig := make(chan os.Signal, 1)
@@ -89,6 +133,7 @@ func main() {
 		// Wait for an interrupt or terminate signal.
 		sig := <-chsig
 		logger.Println("main", fmt.Sprintf("Signal received: %v", sig))
+		close(stopSchedulers)
 
 		// Shut the HTTP server down.
 		shutdown := make(chan bool)
@@ -118,6 +163,19 @@ func main() {
 	logger.Println("main", "App stopped")
 }
 
+// runCommand runs a command given in the arguments and returns the exit code.
+func runCommand(args []string) int {
+	switch args[0] {
+	case "apikey":
+		return runAPIKeyCommand(args[1:], os.Stdout)
+	case "storage":
+		return runStorageCommand(args[1:], os.Stdout)
+	}
+	fmt.Fprintf(os.Stderr, "Unknown command %q\n", args[0])
+	flag.Usage()
+	return 2
+}
+
 func newServer(port string) *http.Server {
 	// Validate the server port.
 	if port == "" {
@@ -138,6 +196,14 @@ func newServer(port string) *http.Server {
 	// Route APIs.
 	appV1.RouteAPIs(router)
 
+	// Route web pages.
+	appV1.RouteWebPages(router)
+
+	// Route the local storage's files.
+	router.GET("/files/*filepath", handleLocalFile)
+	router.HEAD("/files/*filepath", handleLocalFile)
+	router.PUT("/files/*filepath", handleLocalFile)
+
 	// Route apiDoc.
 	router.ServeFiles("/apidoc/*filepath", http.Dir("apidoc-basego-api"))
 
@@ -157,6 +223,10 @@ func startServer(srv *http.Server) {
 	}
 }
 
+func handleLocalFile(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	storage.ServeLocalFile(w, r, p.ByName("filepath"))
+}
+
 func handleTestShowRequestInfo(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	var sb strings.Builder
 	sb.WriteString("RemoteAddr: " + r.RemoteAddr +
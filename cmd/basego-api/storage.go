@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/filegc"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/storagemigration"
+	"github.com/jonylim/basego/internal/pkg/common/data/redis"
+	"github.com/jonylim/basego/internal/pkg/common/storage"
+)
+
+const storageUsage = `Usage: basego-api [flags] storage <command> [command flags]
+
+Commands:
+  migrate  Copy the files from a storage to another and move their records, e.g. --from local --to gcs.
+  gc       Report the orphaned objects and the objects of the deleted files, and delete them with -delete.
+
+Run "basego-api storage <command> -h" for the command flags.
+`
+
+// runStorageCommand runs a "storage" subcommand and returns the exit code.
+func runStorageCommand(args []string, out io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, storageUsage)
+		return 2
+	}
+	fs := flag.NewFlagSet("storage "+args[0], flag.ContinueOnError)
+
+	var err error
+	switch args[0] {
+	case "migrate":
+		from := fs.String("from", "", "The source storage: local, gcs, s3 or memory (required)")
+		to := fs.String("to", "", "The target storage: local, gcs, s3 or memory (required)")
+		dryRun := fs.Bool("dry-run", false, "Only check the files to migrate, without copying them")
+		concurrency := fs.Int("concurrency", storagemigration.DefaultConcurrency, "The number of files copied at once")
+		batchSize := fs.Int("batch-size", storagemigration.DefaultBatchSize, "The number of files moved in a transaction")
+		if err = fs.Parse(args[1:]); err != nil {
+			break
+		} else if fs.NArg() != 0 {
+			err = fmt.Errorf("unexpected arguments %q", fs.Args())
+			break
+		} else if *from == "" || *to == "" {
+			err = errors.New("-from and -to are required")
+			break
+		}
+		err = migrateStorage(out, storagemigration.Options{
+			From:        *from,
+			To:          *to,
+			DryRun:      *dryRun,
+			Concurrency: *concurrency,
+			BatchSize:   *batchSize,
+		})
+
+	case "gc":
+		filegc.Init()
+		c := filegc.Get()
+		del := fs.Bool("delete", false, "Delete the orphans and the expired objects, otherwise they're only reported")
+		retention := fs.Duration("retention", c.Retention, "How long the objects of the deleted files are kept")
+		gracePeriod := fs.Duration("grace-period", c.OrphanGracePeriod, "How old the objects without files must be to be orphans")
+		if err = fs.Parse(args[1:]); err != nil {
+			break
+		} else if fs.NArg() != 0 {
+			err = fmt.Errorf("unexpected arguments %q", fs.Args())
+			break
+		}
+		err = collectStorageGarbage(out, filegc.Options{
+			Now:               time.Now(),
+			Retention:         *retention,
+			OrphanGracePeriod: *gracePeriod,
+			Delete:            *del,
+		})
+
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown storage command %q\n\n%s", args[0], storageUsage)
+		return 2
+	}
+
+	if err == flag.ErrHelp {
+		return 0
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "storage %s: %v\n", args[0], err)
+		return 1
+	}
+	return 0
+}
+
+// migrateStorage migrates the files and prints the result of every file. Interrupting it stops the migration
+// after the files being copied, which can be resumed by running it again.
+func migrateStorage(out io.Writer, opts storagemigration.Options) error {
+	ctx, stop := initStorageCommand("Stopping after the files being copied...")
+	defer stop()
+
+	opts.OnFile = func(res storagemigration.FileResult) {
+		f := res.File
+		fmt.Fprintf(out, "file %d (%s %d %s %s): %s, %d objects copied, %d bytes",
+			f.ID, f.OwnerType, f.OwnerID, f.Category, f.Filename, res.Status, res.Copied, res.Size)
+		if res.Err != nil {
+			fmt.Fprintf(out, ": %v", res.Err)
+		}
+		fmt.Fprintln(out)
+	}
+
+	redisConn := redis.GetConnection()
+	defer redisConn.Close()
+	migrator, err := storagemigration.NewMigrator(redisConn, opts)
+	if err != nil {
+		return err
+	}
+	summary, err := migrator.Run(ctx)
+	fmt.Fprintf(out, "\nMigrated: %d, pending: %d, failed: %d, objects copied: %d, bytes: %d\n",
+		summary.Migrated, summary.Pending, summary.Failed, summary.Copied, summary.Size)
+	if err == nil && summary.Failed > 0 {
+		err = fmt.Errorf("%d files failed, run it again to retry them", summary.Failed)
+	}
+	return err
+}
+
+// collectStorageGarbage runs the file GC and prints the reported objects of every storage.
+func collectStorageGarbage(out io.Writer, opts filegc.Options) error {
+	ctx, stop := initStorageCommand("Stopping...")
+	defer stop()
+
+	reports, ran, err := filegc.RunOnce(ctx, opts)
+	if err != nil {
+		return err
+	} else if !ran {
+		return errors.New("the file GC is being run by another instance")
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STORAGE\tFILEPATH\tSTATUS\tFILE ID\tBYTES\tMODIFIED\tDELETED")
+	failed := 0
+	for _, r := range reports {
+		for _, e := range r.Entries {
+			fileID, modified, deleted := "-", "-", fmt.Sprint(e.Deleted)
+			if e.FileID != 0 {
+				fileID = fmt.Sprint(e.FileID)
+			}
+			if !e.ModifiedTime.IsZero() {
+				modified = e.ModifiedTime.UTC().Format(time.RFC3339)
+			}
+			if e.Err != nil {
+				deleted = "error: " + e.Err.Error()
+				failed++
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n", r.Storage, e.Filepath, e.Status, fileID, e.Size, modified, deleted)
+		}
+	}
+	tw.Flush()
+
+	fmt.Fprintln(out)
+	for _, r := range reports {
+		orphans, deletedOrphans := r.Count(filegc.StatusOrphan)
+		expired, deletedExpired := r.Count(filegc.StatusExpired)
+		retained, _ := r.Count(filegc.StatusRetained)
+		missing, _ := r.Count(filegc.StatusMissing)
+		fmt.Fprintf(out, "%s: %d referenced, %d recent, %d orphans (%d deleted), %d expired (%d deleted), %d retained, %d missing\n",
+			r.Storage, r.Referenced, r.Recent, orphans, deletedOrphans, expired, deletedExpired, retained, missing)
+		if r.Err != nil {
+			fmt.Fprintf(out, "%s: %v\n", r.Storage, r.Err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d errors", failed)
+	}
+	return nil
+}
+
+// initStorageCommand initializes the storages, which are only initialized for the server, and returns a context
+// canceled by an interrupt, printing the message. stop must be called when the command is done.
+func initStorageCommand(msg string) (ctx context.Context, stop func()) {
+	storage.Init()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		if _, ok := <-sig; ok {
+			fmt.Fprintln(os.Stderr, msg)
+			cancel()
+		}
+	}()
+	return ctx, func() {
+		signal.Stop(sig)
+		cancel()
+	}
+}
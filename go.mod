@@ -13,5 +13,7 @@ require (
 	github.com/lib/pq v1.3.0
 	github.com/satori/go.uuid v1.2.0
 	golang.org/x/image v0.0.0-20210607152325-775e3b0c77b9
+	golang.org/x/net v0.0.0-20210503060351-7fd8e65b6420
+	google.golang.org/api v0.47.0
 	gopkg.in/check.v1 v1.0.0-20180628173108-788fd7840127 // indirect
 )
@@ -1,15 +1,23 @@
 package v1
 
 import (
-	"errors"
 	"net/http"
-	"os"
-	"strings"
 
 	"github.com/jonylim/basego/internal/app/basego-api/v1/endpoint/accountapi"
 	"github.com/jonylim/basego/internal/app/basego-api/v1/endpoint/authapi"
 	"github.com/jonylim/basego/internal/app/basego-api/v1/endpoint/clientapi"
-	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
+	"github.com/jonylim/basego/internal/app/basego-api/v1/endpoint/staffapi"
+	"github.com/jonylim/basego/internal/app/basego-api/v1/reminder"
+	"github.com/jonylim/basego/internal/app/basego-api/v1/web"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/apikey"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/filegc"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/usage"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/webpage"
+	"github.com/jonylim/basego/internal/pkg/common/captcha"
+	"github.com/jonylim/basego/internal/pkg/common/cors"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
+	"github.com/jonylim/basego/internal/pkg/common/ratelimit"
+	"github.com/jonylim/basego/internal/pkg/common/storage"
 
 	"github.com/julienschmidt/httprouter"
 )
@@ -36,30 +44,43 @@ var accountAPIs = map[string]accountapi.Handle{
 	"time_zones":               accountapi.TimeZones,
 	"profile/get":              accountapi.AccountProfileGet,
 	"profile/accept_tos":       accountapi.AccountProfileAcceptTOS,
+	"preferences/get":          accountapi.AccountPreferencesGet,
+	"preferences/update":       accountapi.AccountPreferencesUpdate,
 	"security/change_password": accountapi.SecurityChangePassword,
 	"logout":                   accountapi.Logout,
 }
+var staffAPIs = map[string]staffapi.Handle{
+	"api_keys/list":    staffapi.APIKeysList,
+	"api_keys/create":  staffapi.APIKeysCreate,
+	"api_keys/update":  staffapi.APIKeysUpdate,
+	"api_keys/enable":  staffapi.APIKeysEnable,
+	"api_keys/disable": staffapi.APIKeysDisable,
+	"api_keys/rotate":  staffapi.APIKeysRotate,
+	"api_keys/usage":   staffapi.APIKeysUsage,
+}
 var mapAPIs = map[string]interface{}{
 	"auth":    authAPIs,
 	"client":  clientAPIs,
 	"account": accountAPIs,
+	"staff":   staffAPIs,
 }
 
 var (
-	errDatabase = errors.New("An error occurred while processing your request")
-	errInternal = errors.New("An error occurred while processing your request")
+	errDatabase = i18n.NewError(i18n.MsgProcessingFailed)
+	errInternal = i18n.NewError(i18n.MsgProcessingFailed)
 )
 
-var allowOriginURL string
-
 // RouteAPIs configure the router for APIs.
 func RouteAPIs(router *httprouter.Router) {
-	allowOriginURL = strings.Trim(os.Getenv(envvar.FrontendURL), "/")
-
 	// Init APIs.
 	authapi.Init()
 	clientapi.Init()
 	accountapi.Init()
+	staffapi.Init()
+	ratelimit.Init(defaultRateLimitRules)
+	captcha.Init(defaultCaptchaEndpoints)
+	cors.Init(defaultCORS, webAPIKeyOrigins)
+	usage.Init()
 
 	for apiType, apiList := range mapAPIs {
 		apiPrefix := APIPrefix + apiType + "/"
@@ -67,52 +88,111 @@ func RouteAPIs(router *httprouter.Router) {
 		switch apiType {
 		case "auth":
 			for apiName, apiHandle := range apiList.(map[string]authapi.Handle) {
-				var h = apiHandle
+				var apiType, apiName, h = apiType, apiName, apiHandle
 				router.OPTIONS(apiPrefix+apiName, func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
-					allowCORS(w, r)
+					handlePreflight(w, r)
 				})
 				router.POST(apiPrefix+apiName, func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+					w, r = usage.Track(w, r)
+					r = apikey.WithEndpoint(r, apiType, apiName)
+					defer recordUsage(w, apiType, apiName)
 					allowCORS(w, r)
-					authapi.HandleRequest(w, r, p, h)
+					if w, r, ok := checkRateLimit(w, r, apiType, apiName); ok {
+						authapi.HandleRequest(w, r, p, h)
+					}
 				})
 			}
 			break
 
 		case "client":
 			for apiName, apiHandle := range apiList.(map[string]clientapi.Handle) {
-				var h = apiHandle
+				var apiType, apiName, h = apiType, apiName, apiHandle
 				router.OPTIONS(apiPrefix+apiName, func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
-					allowCORS(w, r)
+					handlePreflight(w, r)
 				})
 				router.POST(apiPrefix+apiName, func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+					w, r = usage.Track(w, r)
+					r = apikey.WithEndpoint(r, apiType, apiName)
+					defer recordUsage(w, apiType, apiName)
 					allowCORS(w, r)
-					clientapi.HandleRequest(w, r, p, h)
+					if w, r, ok := checkRateLimit(w, r, apiType, apiName); ok {
+						clientapi.HandleRequest(w, r, p, h)
+					}
 				})
 			}
 			break
 
 		case "account":
 			for apiName, apiHandle := range apiList.(map[string]accountapi.Handle) {
-				var h = apiHandle
+				var apiType, apiName, h = apiType, apiName, apiHandle
 				router.OPTIONS(apiPrefix+apiName, func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+					handlePreflight(w, r)
+				})
+				router.POST(apiPrefix+apiName, func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+					w, r = usage.Track(w, r)
+					r = apikey.WithEndpoint(r, apiType, apiName)
+					defer recordUsage(w, apiType, apiName)
 					allowCORS(w, r)
+					if w, r, ok := checkRateLimit(w, r, apiType, apiName); ok {
+						accountapi.HandleRequest(w, r, p, h)
+					}
+				})
+			}
+			break
+
+		case "staff":
+			for apiName, apiHandle := range apiList.(map[string]staffapi.Handle) {
+				var apiType, apiName, h = apiType, apiName, apiHandle
+				router.OPTIONS(apiPrefix+apiName, func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+					handlePreflight(w, r)
 				})
 				router.POST(apiPrefix+apiName, func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+					w, r = usage.Track(w, r)
+					r = apikey.WithEndpoint(r, apiType, apiName)
+					defer recordUsage(w, apiType, apiName)
 					allowCORS(w, r)
-					accountapi.HandleRequest(w, r, p, h)
+					if w, r, ok := checkRateLimit(w, r, apiType, apiName); ok {
+						staffapi.HandleRequest(w, r, p, h)
+					}
 				})
 			}
 			break
 		}
 	}
+
+	// The account's files are downloaded with GET, and the signed URLs in the account's profile are downloaded without
+	// the request headers, so they can be loaded as they are, e.g. by an <img> element.
+	var apiType, apiName = "account", "files/download"
+	router.OPTIONS(APIPrefix+"account/files/:category/:variant", func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		handlePreflight(w, r)
+	})
+	router.GET(APIPrefix+"account/files/:category/:variant", func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		w, r = usage.Track(w, r)
+		r = apikey.WithEndpoint(r, apiType, apiName)
+		defer recordUsage(w, apiType, apiName)
+		allowCORS(w, r)
+		if w, r, ok := checkRateLimit(w, r, apiType, apiName); ok {
+			if storage.IsSignedCstAccountFileDownload(r.URL.Query()) {
+				accountapi.HandleSignedFilesDownload(w, r, p)
+			} else {
+				accountapi.HandleRequest(w, r, p, accountapi.FilesDownload)
+			}
+		}
+	})
+}
+
+// RunSchedulers runs the scheduled jobs until stop is closed.
+func RunSchedulers(stop <-chan struct{}) {
+	go usage.Run(stop)
+	filegc.Init()
+	go filegc.Run(stop)
+	reminder.Init()
+	reminder.Run(stop)
 }
 
-func allowCORS(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Access-Control-Allow-Origin", allowOriginURL)
-	if acrh := r.Header.Get("Access-Control-Request-Headers"); acrh != "" {
-		w.Header().Set("Access-Control-Allow-Headers", acrh)
-	} else {
-		w.Header().Set("Access-Control-Allow-Headers", "*")
+// RouteWebPages configures the router for the web pages, if they're enabled.
+func RouteWebPages(router *httprouter.Router) {
+	if webpage.Enabled() {
+		web.Route(router)
 	}
-	w.Header().Set("Vary", "Origin")
 }
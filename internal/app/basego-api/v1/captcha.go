@@ -0,0 +1,12 @@
+package v1
+
+// defaultCaptchaEndpoints are the endpoints requiring a CAPTCHA unless overridden by environment variables.
+// They're public and send emails, so they're the easiest to abuse. The tokens are verified by the groups'
+// HandleRequest once the API key is validated. The web form requesting a reset password email is checked by
+// package web, as it sends the same email.
+var defaultCaptchaEndpoints = []string{
+	"client/register",
+	"client/account_verification/resend_email",
+	"client/reset_password/request_token",
+	"web/reset_password/request",
+}
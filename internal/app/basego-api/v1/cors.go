@@ -0,0 +1,43 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/apikey"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/dao"
+	"github.com/jonylim/basego/internal/pkg/common/captcha"
+	"github.com/jonylim/basego/internal/pkg/common/cors"
+)
+
+// defaultCORS lists the methods and request headers sent by the web apps and the response headers they may read.
+var defaultCORS = cors.Config{
+	AllowedMethods: []string{http.MethodPost, http.MethodGet},
+	AllowedHeaders: []string{
+		"Accept-Language", "API-Key", "Authorization", "Content-Type",
+		"Device-Identifier", "Device-Model", "Device-Platform",
+		captcha.HeaderName, apikey.SignatureHeader,
+	},
+	ExposedHeaders: []string{
+		"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "Retry-After",
+	},
+}
+
+// webAPIKeyOrigins returns the origins of the customers' web API keys, which are allowed by the CORS policy.
+func webAPIKeyOrigins() ([]string, error) {
+	items, err := dao.NewXAPIKeyDAO(apikey.DomainCustomer).GetList()
+	if err != nil {
+		return nil, err
+	}
+	return apikey.WebOrigins(items, time.Now()), nil
+}
+
+// allowCORS sets the CORS headers of an API's response.
+func allowCORS(w http.ResponseWriter, r *http.Request) {
+	cors.Apply(w, r)
+}
+
+// handlePreflight responds to an API's OPTIONS request.
+func handlePreflight(w http.ResponseWriter, r *http.Request) {
+	cors.Preflight(w, r)
+}
@@ -4,6 +4,7 @@
  * #### HTTP Request Headers
  * | **Header Name**   | **Required** | **Description** |
  * |-------------------|:------------:|-----------------|
+ * | Accept-Language   |   | The language of the error messages. Values are `en` (default) or `id`. The account's saved `locale` preference is used instead if set. |
  * | API-Key           | ✓ | API key for accessing the API. |
  * | App-Identifier    |   | The app's identifier (package name for Android, bundle ID for iOS, or origin URL for web). |
  * | Authorization     | ✓ | Access token to validate the user session.<br>Format: <code>Bearer <i>&lt;access_token&gt;</i></code> |
@@ -39,6 +40,7 @@
  * |  40106   | User is not found for the specified token.                                                             |
  * |  40301   | The user does not have access to the requested resource or action.                                     |
  * |  40401   | The requested resource is not found.                                                                   |
+ * |  42901   | Too many requests, the rate limit is exceeded. Retry after the number of seconds in `Retry-After`.     |
  * |  49101   | The API key is not provided.                                                                           |
  * |  49102   | Failed to parse the API key, or the API key is invalid.                                                |
  * |  49103   | The provided API key is not found.                                                                     |
@@ -46,8 +48,15 @@
  * |  49105   | The provided API key is not intended to be used with the client's app identifier.                      |
  * |  49106   | The API key has expired.                                                                               |
  * |  49107   | The API key is disabled.                                                                               |
+ * |  49110   | The API key is not allowed to call the API, it's out of the key's scopes.                              |
+ * |  49111   | The API key's daily or monthly quota is used up. Retry after the number of seconds in `Retry-After`.   |
  * |  50001   | An error occurred while validating the API key.                                                        |
  * |  99999   | Other errors, usually without specific reason or action.                                               |
+ *
+ * #### Rate Limit Headers
+ * Every response of a rate limited API includes the `RateLimit-Limit`, `RateLimit-Remaining`, `RateLimit-Reset`
+ * (seconds until the window resets), and `RateLimit-Policy` headers. A response with HTTP status `429` also
+ * includes the `Retry-After` header in seconds.
  */
 
 /**
@@ -72,7 +81,6 @@ package accountapi
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -80,10 +88,13 @@ import (
 	"github.com/jonylim/basego/internal/app/basego-api/v1/endpoint/accountapi/requestheader"
 	"github.com/jonylim/basego/internal/app/basego-api/v1/requestvalidator"
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/preference"
 	"github.com/jonylim/basego/internal/pkg/common/api"
 	"github.com/jonylim/basego/internal/pkg/common/api/errcode"
 	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
 	"github.com/jonylim/basego/internal/pkg/common/constant/httpstatus"
+	"github.com/jonylim/basego/internal/pkg/common/data/redis"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
 	"github.com/jonylim/basego/internal/pkg/common/logger"
 
 	"github.com/julienschmidt/httprouter"
@@ -95,6 +106,7 @@ type (
 		context.Context
 		ReqID          string
 		ReqTag         string
+		Locale         string
 		Path           string
 		ReqHeader      requestheader.APIRequestHeader
 		APIKey         model.XAPIKey
@@ -107,9 +119,9 @@ type (
 )
 
 var (
-	errDatabase = errors.New("An error occurred while processing your request")
-	errInternal = errors.New("An error occurred while processing your request")
-	errStorage  = errors.New("An error occurred while processing your request")
+	errDatabase = i18n.NewError(i18n.MsgProcessingFailed)
+	errInternal = i18n.NewError(i18n.MsgProcessingFailed)
+	errStorage  = i18n.NewError(i18n.MsgProcessingFailed)
 )
 
 var env string
@@ -123,10 +135,14 @@ func Init() {
 func HandleRequest(w http.ResponseWriter, r *http.Request, p httprouter.Params, handle Handle) {
 	reqID := api.CreateReqID()
 
-	// Validate request headers.
+	// Send the responses in the requested language.
 	reqHeader := requestheader.Parse(r)
+	locale := i18n.LocaleFromAcceptLanguage(reqHeader.AcceptLanguage)
+	w = api.WithLocale(w, locale)
+
+	// Validate request headers.
 	if err := requestheader.CheckRequired(reqHeader); err != nil {
-		response := api.NewAPIResponseWithError(reqID, errcode.ReqHeaderValidationFailed, err.Error())
+		response := api.NewAPIResponseWithError(reqID, errcode.ReqHeaderValidationFailed, i18n.FromError(err))
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
 		return
 	}
@@ -138,12 +154,26 @@ func HandleRequest(w http.ResponseWriter, r *http.Request, p httprouter.Params,
 		return
 	}
 
+	// Verify the CAPTCHA if the API requires it.
+	if !validator.ValidateCaptcha() {
+		return
+	}
+
 	// Validate access token.
 	accountSession, account, ok := validator.ValidateAccessToken(reqHeader.Authorization, reqHeader.DeviceID, apiKey)
 	if !ok {
 		return
 	}
 
+	// The account's saved locale takes precedence over Accept-Language.
+	redisConn := redis.GetConnection()
+	prefs, err := preference.Get(redisConn, account.ID)
+	redisConn.Close()
+	if err == nil && prefs.IsSet(preference.KeyLocale) {
+		locale = prefs.Locale()
+		api.SetLocale(w, locale)
+	}
+
 	// OK!
 	reqTag := fmt.Sprintf("api:%s", reqID)
 	path := r.URL.Path
@@ -152,6 +182,7 @@ func HandleRequest(w http.ResponseWriter, r *http.Request, p httprouter.Params,
 		Context:        r.Context(),
 		ReqID:          reqID,
 		ReqTag:         reqTag,
+		Locale:         locale,
 		Path:           path,
 		ReqHeader:      reqHeader,
 		APIKey:         apiKey,
@@ -75,7 +75,7 @@ func Countries(w http.ResponseWriter, r *http.Request, _ httprouter.Params, ctx
 	// Get the country list.
 	countries, err := dao.NewXCountryDAO().GetActiveCountryList()
 	if err != nil {
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		return
 	}
@@ -0,0 +1,173 @@
+/**
+ * @api           {get} /v1/account/files/:category/:variant Download Account File
+ * @apiVersion    1.0.0
+ * @apiName       DownloadAccountFile
+ * @apiGroup      AccountAPI
+ * @apiPermission account
+ *
+ * @apiDescription Download a file of the current account, decrypted if the file is encrypted at rest.
+ * The encrypted files have no public URLs, so their URLs in the account's profile are signed URLs of this API,
+ * e.g. `/v1/account/files/photo/full?account=1&expires=1700000000&signature=...`. Until they expire,
+ * the signed URLs are downloaded without the request headers, e.g. by an `<img>` element.
+ * Without a signature, the request requires the account API's headers.
+ *
+ * @apiParam {string} category    The file's category. Value is `photo`.
+ * @apiParam {string} variant     The file's variant, e.g. `thumb`, `medium` or `full`. The older files only have `full` and `thumb`.
+ * @apiParam {long}   [account]   The account ID of a signed URL.
+ * @apiParam {long}   [expires]   The expiry of a signed URL, in Unix seconds.
+ * @apiParam {string} [signature] The signature of a signed URL.
+ *
+ * @apiSuccessExample {binary} Success Response:
+ *     HTTP/1.1 200 OK
+ *     Content-Type: image/jpeg
+ *     Cache-Control: private
+ *
+ *     <the file's contents>
+ *
+ * @apiError FileNotFound   The account has no such file.
+ * @apiError FileURLInvalid The signed URL is invalid or expired.
+ * @apiErrorExample {json} FileNotFound:
+ *     HTTP/1.1 404 Not Found
+ *     {
+ *       "status": 404,
+ *       "error": {
+ *         "code": "40401",
+ *         "message": "File is not found",
+ *         "field": ""
+ *       },
+ *       "data": {}
+ *     }
+ *
+ * @apiErrorExample {json} FileURLInvalid:
+ *     HTTP/1.1 403 Forbidden
+ *     {
+ *       "status": 403,
+ *       "error": {
+ *         "code": "40301",
+ *         "message": "File URL is invalid or expired",
+ *         "field": ""
+ *       },
+ *       "data": {}
+ *     }
+ *
+ * @apiUse   ErrorAccountHeaderValidationFailed
+ */
+
+package accountapi
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/repository"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/common/api"
+	"github.com/jonylim/basego/internal/pkg/common/api/errcode"
+	"github.com/jonylim/basego/internal/pkg/common/constant"
+	"github.com/jonylim/basego/internal/pkg/common/constant/httpstatus"
+	"github.com/jonylim/basego/internal/pkg/common/data/redis"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+	"github.com/jonylim/basego/internal/pkg/common/storage"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// FilesDownload sends a file of the logged in account, decrypting it if it's encrypted.
+func FilesDownload(w http.ResponseWriter, r *http.Request, p httprouter.Params, ctx Context) {
+	logger.Trace(ctx.ReqTag, "Handle: accountapi.FilesDownload")
+
+	category, variant := p.ByName("category"), p.ByName("variant")
+	if category != constant.FileCategoryPhoto || variant == "" {
+		sendFileNotFound(w, ctx)
+		return
+	}
+
+	// Get the Redis connection and defer closing connection.
+	redisConn := redis.GetConnection()
+	defer redisConn.Close()
+
+	// Get the account's file.
+	fileRepo := repository.NewFileRepo(redisConn)
+	file, err := fileRepo.GetByOwnerAndCategory(constant.FileOwnerTypeCstAccount, ctx.Account.ID, category)
+	if err == fileRepo.ErrNotFound {
+		sendFileNotFound(w, ctx)
+		return
+	} else if err != nil {
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, i18n.FromError(errDatabase))
+		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
+		return
+	}
+
+	// The files without variants only have their fullsize and thumbnail images.
+	var contentType string
+	if v, ok := file.Variants.Get(variant); ok {
+		contentType = v.MediaType
+	} else if len(file.Variants) == 0 && variant == storage.PhotoVariantFull {
+		contentType = file.MediaType
+	} else if len(file.Variants) == 0 && variant == storage.PhotoVariantThumbnail {
+		contentType = file.ThumbMediaType
+	} else {
+		sendFileNotFound(w, ctx)
+		return
+	}
+	filepath := storage.GetCstAccountPhotoVariantFilepath(file.Filename, variant)
+
+	// Fetch the file's object, decrypted.
+	st, err := storage.GetStorageInstance(file.Storage)
+	if err != nil {
+		logger.Error(ctx.ReqTag, logger.FromError(err))
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, i18n.FromError(errStorage))
+		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
+		return
+	}
+	reader, code, err := storage.FetchFileObject(st, file, filepath)
+	if code == storage.ErrNotFound {
+		sendFileNotFound(w, ctx)
+		return
+	} else if err != nil {
+		logger.Error(ctx.ReqTag, logger.FromError(err))
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, i18n.FromError(errStorage))
+		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
+		return
+	}
+	defer reader.Close()
+
+	// Send the file. The response has been started, so errors while sending can only be logged.
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "private")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if _, err = io.Copy(w, reader); err != nil {
+		logger.Error(ctx.ReqTag, logger.FromError(err))
+	}
+}
+
+// HandleSignedFilesDownload sends a file of an account to a request with a signed URL from the account's profile,
+// which has no request headers to validate.
+func HandleSignedFilesDownload(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	reqID := api.CreateReqID()
+	locale := i18n.LocaleFromAcceptLanguage(r.Header.Get("Accept-Language"))
+	w = api.WithLocale(w, locale)
+
+	accountID, ok := storage.VerifyCstAccountFileDownloadURL(p.ByName("category"), p.ByName("variant"), r.URL.Query(), time.Now())
+	if !ok {
+		response := api.NewAPIResponseWithError(reqID, errcode.PermissionDenied, i18n.NewMessage(i18n.MsgFileURLInvalid))
+		api.SendResponseJSONWithStatusCode(w, response, httpstatus.Forbidden)
+		return
+	}
+	FilesDownload(w, r, p, Context{
+		Context: r.Context(),
+		ReqID:   reqID,
+		ReqTag:  fmt.Sprintf("api:%s", reqID),
+		Locale:  locale,
+		Path:    r.URL.Path,
+		Account: model.CstAccount{ID: accountID},
+	})
+}
+
+func sendFileNotFound(w http.ResponseWriter, ctx Context) {
+	response := api.NewAPIResponseWithError(ctx.ReqID, errcode.FileNotFound, i18n.NewMessage(i18n.MsgFileNotFound))
+	api.SendResponseJSONWithStatusCode(w, response, httpstatus.NotFound)
+}
@@ -40,6 +40,7 @@ import (
 	"github.com/jonylim/basego/internal/pkg/common/constant/httpstatus"
 	"github.com/jonylim/basego/internal/pkg/common/data/db"
 	"github.com/jonylim/basego/internal/pkg/common/data/redis"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
 	"github.com/jonylim/basego/internal/pkg/common/logger"
 
 	"github.com/julienschmidt/httprouter"
@@ -60,7 +61,7 @@ func Logout(w http.ResponseWriter, r *http.Request, _ httprouter.Params, ctx Con
 	tx, err := db.Get().Begin()
 	if err != nil {
 		logger.Fatal("db.Begin", logger.FromError(err))
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		return
 	}
@@ -69,14 +70,14 @@ func Logout(w http.ResponseWriter, r *http.Request, _ httprouter.Params, ctx Con
 	// Delete the account session from database.
 	sessionDB := dao.NewCstAccountSessionDAO()
 	if _, err = sessionDB.DeleteSessionByID(tx, ctx.AccountSession.ID, true); err != nil {
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		return
 	}
 
 	// Delete the account session's tokens from database.
 	if _, err = sessionDB.DeleteSessionTokenBySessionID(tx, ctx.AccountSession.ID); err != nil {
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		return
 	}
@@ -85,7 +86,7 @@ func Logout(w http.ResponseWriter, r *http.Request, _ httprouter.Params, ctx Con
 	err = tx.Commit()
 	if err != nil {
 		logger.Fatal("tx.Commit", logger.FromError(err))
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		return
 	}
@@ -105,7 +106,7 @@ func Logout(w http.ResponseWriter, r *http.Request, _ httprouter.Params, ctx Con
 	// Return the result.
 	data := LogoutResponseData{
 		Success: true,
-		Message: "You have logged out",
+		Message: i18n.NewMessage(i18n.MsgAccountLoggedOut).Localize(ctx.Locale),
 	}
 	response := api.NewAPIResponse(ctx.ReqID)
 	response.SetData(data)
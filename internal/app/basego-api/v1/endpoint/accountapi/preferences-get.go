@@ -0,0 +1,80 @@
+/**
+ * @api           {post} /v1/account/preferences/get Get Preferences
+ * @apiVersion    1.3.0
+ * @apiName       GetPreferences
+ * @apiGroup      AccountAPI
+ * @apiPermission account
+ *
+ * @apiDescription Get the account's preferences. Preferences which are never set have their default values.
+ *
+ * @apiSuccess {object}  preferences                              The preferences.
+ * @apiSuccess {string}  preferences.locale                       The locale for emails and messages. Values are `en` (default) or `id`.
+ * @apiSuccess {string}  preferences.timeZone                     The time zone's name for times shown in emails (default: `"UTC"`).
+ * @apiSuccess {boolean} preferences.emailNotification.security  If security notifications, e.g. password changes, are sent by email (default: `true`).
+ *
+ * @apiSuccessExample {json} Success Response:
+ *     HTTP/1.1 200 OK
+ *     {
+ *       "status": 200,
+ *       "error": {
+ *         "code": "",
+ *         "message": "",
+ *         "field": ""
+ *       },
+ *       "data": {
+ *         "preferences": {
+ *           "emailNotification.security": true,
+ *           "locale": "en",
+ *           "timeZone": "Asia/Jakarta"
+ *         }
+ *       }
+ *     }
+ *
+ * @apiUse   ErrorAccountHeaderValidationFailed
+ */
+
+package accountapi
+
+import (
+	"net/http"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/preference"
+	"github.com/jonylim/basego/internal/pkg/common/api"
+	"github.com/jonylim/basego/internal/pkg/common/api/errcode"
+	"github.com/jonylim/basego/internal/pkg/common/constant/httpstatus"
+	"github.com/jonylim/basego/internal/pkg/common/data/redis"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// AccountPreferencesResponseData represents response data of Account API "Get Preferences" and "Update Preferences".
+type AccountPreferencesResponseData struct {
+	api.ResponseData
+	Preferences map[string]interface{} `json:"preferences"`
+}
+
+// AccountPreferencesGet returns the preferences of a customer account.
+func AccountPreferencesGet(w http.ResponseWriter, r *http.Request, _ httprouter.Params, ctx Context) {
+	logger.Trace(ctx.ReqTag, "Handle: accountapi.AccountPreferencesGet")
+
+	// Get the Redis connection and defer closing connection.
+	redisConn := redis.GetConnection()
+	defer redisConn.Close()
+
+	// Get the account's preferences.
+	prefs, err := preference.Get(redisConn, ctx.Account.ID)
+	if err != nil {
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
+		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
+		return
+	}
+
+	// Return the response.
+	data := AccountPreferencesResponseData{
+		Preferences: prefs.Values(),
+	}
+	response := api.NewAPIResponse(ctx.ReqID)
+	response.SetData(data)
+	api.SendResponseJSON(w, response)
+}
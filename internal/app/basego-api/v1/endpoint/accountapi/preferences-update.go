@@ -0,0 +1,175 @@
+/**
+ * @api           {post} /v1/account/preferences/update Update Preferences
+ * @apiVersion    1.3.0
+ * @apiName       UpdatePreferences
+ * @apiGroup      AccountAPI
+ * @apiPermission account
+ *
+ * @apiDescription Update some of the account's preferences. Preferences which aren't specified are unchanged.
+ *
+ * @apiParam {object}  preferences                              The preferences to update.
+ * @apiParam {string}  [preferences.locale]                     The locale. Values are `en` or `id`.
+ * @apiParam {string}  [preferences.timeZone]                   The time zone's name, from API "Get Time Zone List".
+ * @apiParam {boolean} [preferences.emailNotification.security] If security notifications are sent by email.
+ *
+ * @apiParamExample {json} Request Example:
+ *     {
+ *       "preferences": {
+ *         "locale": "id",
+ *         "timeZone": "Asia/Jakarta"
+ *       }
+ *     }
+ *
+ * @apiSuccess {object}  preferences                              All of the preferences after the update, same as API "Get Preferences".
+ *
+ * @apiSuccessExample {json} Success Response:
+ *     HTTP/1.1 200 OK
+ *     {
+ *       "status": 200,
+ *       "error": {
+ *         "code": "",
+ *         "message": "",
+ *         "field": ""
+ *       },
+ *       "data": {
+ *         "preferences": {
+ *           "emailNotification.security": true,
+ *           "locale": "id",
+ *           "timeZone": "Asia/Jakarta"
+ *         }
+ *       }
+ *     }
+ *
+ * @apiUse   ErrorAccountHeaderValidationFailed
+ * @apiError ParamValidationFailed The parameter validation failed.
+ *
+ * @apiErrorExample {json} ParamValidationFailed:
+ *     HTTP/1.1 200 OK
+ *     {
+ *       "status": 400,
+ *       "error": {
+ *         "code": "40002",
+ *         "message": "Time zone is invalid",
+ *         "field": "preferences.timeZone"
+ *       },
+ *       "data": {}
+ *     }
+ */
+
+package accountapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/dao"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/repository"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/preference"
+	"github.com/jonylim/basego/internal/pkg/common/api"
+	"github.com/jonylim/basego/internal/pkg/common/api/errcode"
+	"github.com/jonylim/basego/internal/pkg/common/constant/httpstatus"
+	"github.com/jonylim/basego/internal/pkg/common/data/db"
+	"github.com/jonylim/basego/internal/pkg/common/data/redis"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// AccountPreferencesUpdateRequestParam represents request body of Account API "Update Preferences".
+type AccountPreferencesUpdateRequestParam struct {
+	Preferences map[string]interface{} `json:"preferences"`
+}
+
+// AccountPreferencesUpdate updates the preferences of a customer account.
+func AccountPreferencesUpdate(w http.ResponseWriter, r *http.Request, _ httprouter.Params, ctx Context) {
+	logger.Trace(ctx.ReqTag, "Handle: accountapi.AccountPreferencesUpdate")
+	account := ctx.Account
+
+	// Get the Redis connection and defer closing connection.
+	redisConn := redis.GetConnection()
+	defer redisConn.Close()
+
+	var param AccountPreferencesUpdateRequestParam
+	if err := json.NewDecoder(r.Body).Decode(&param); err != nil {
+		logger.Error(ctx.ReqTag, err.Error())
+		msg := i18n.NewMessage(i18n.MsgRequestBodyInvalid)
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.ReqParamValidationFailed, msg)
+		api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
+		return
+	}
+
+	if len(param.Preferences) == 0 {
+		msg, field := i18n.NewMessage(i18n.MsgPreferencesRequired), "preferences"
+		response := api.NewAPIResponseWithErrorField(ctx.ReqID, errcode.ReqParamValidationFailed, msg, field)
+		api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
+		return
+	}
+
+	// Validate the values in a stable order, so the same request always returns the same error.
+	keys := make([]string, 0, len(param.Preferences))
+	for k := range param.Preferences {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	values := make(map[string]string, len(keys))
+	for _, k := range keys {
+		v, err := preference.Normalize(ctx, redisConn, k, param.Preferences[k])
+		if err == preference.ErrDatabase {
+			response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
+			api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
+			return
+		} else if err != nil {
+			response := api.NewAPIResponseWithErrorField(ctx.ReqID, errcode.ReqParamValidationFailed, i18n.FromError(err), "preferences."+k)
+			api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
+			return
+		}
+		values[k] = v
+	}
+
+	// Begin database transaction.
+	tx, err := db.Get().Begin()
+	if err != nil {
+		logger.Fatal("db.Begin", logger.FromError(err))
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
+		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	// Save the preferences to database.
+	prefDAO := dao.NewCstAccountPreferenceDAO()
+	for _, k := range keys {
+		if _, err = prefDAO.Upsert(tx, account.ID, k, values[k]); err != nil {
+			response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
+			api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
+			return
+		}
+	}
+
+	// Commit database transaction.
+	err = tx.Commit()
+	if err != nil {
+		logger.Fatal("tx.Commit", logger.FromError(err))
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
+		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
+		return
+	}
+
+	// Sync to Redis.
+	stored, err := repository.NewCstAccountPreferenceRepo(redisConn).SyncByAccountID(account.ID)
+	if err != nil {
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
+		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
+		return
+	}
+
+	// Return the response.
+	data := AccountPreferencesResponseData{
+		Preferences: preference.New(stored).Values(),
+	}
+	response := api.NewAPIResponse(ctx.ReqID)
+	response.SetData(data)
+	api.SendResponseJSON(w, response)
+}
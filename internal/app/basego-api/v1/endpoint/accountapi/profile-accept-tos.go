@@ -61,6 +61,7 @@ import (
 	"github.com/jonylim/basego/internal/pkg/common/constant/httpstatus"
 	"github.com/jonylim/basego/internal/pkg/common/data/db"
 	"github.com/jonylim/basego/internal/pkg/common/data/redis"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
 	"github.com/jonylim/basego/internal/pkg/common/logger"
 
 	"github.com/julienschmidt/httprouter"
@@ -90,21 +91,22 @@ func AccountProfileAcceptTOS(w http.ResponseWriter, r *http.Request, _ httproute
 	var param AccountProfileAcceptTOSRequestParam
 	if err := json.NewDecoder(r.Body).Decode(&param); err != nil {
 		logger.Error(ctx.ReqTag, err.Error())
-		msg := "Request body format is invalid"
+		msg := i18n.NewMessage(i18n.MsgRequestBodyInvalid)
 		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.ReqParamValidationFailed, msg)
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
 		return
 	}
 
-	var msg, field string
+	var msg i18n.Message
+	var field string
 	if param.CreatedTime == 0 {
-		msg = "Created time is required"
+		msg = i18n.NewMessage(i18n.MsgCreatedTimeRequired)
 		field = "createdTime"
 	} else if param.CreatedTime != account.CreatedTime {
-		msg = "Created time is invalid"
+		msg = i18n.NewMessage(i18n.MsgCreatedTimeInvalid)
 		field = "createdTime"
 	}
-	if msg != "" {
+	if !msg.IsEmpty() {
 		response := api.NewAPIResponseWithErrorField(ctx.ReqID, errcode.ReqParamValidationFailed, msg, field)
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
 		return
@@ -121,7 +123,7 @@ func AccountProfileAcceptTOS(w http.ResponseWriter, r *http.Request, _ httproute
 			tx, err := db.Get().Begin()
 			if err != nil {
 				logger.Fatal("db.Begin", logger.FromError(err))
-				response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
+				response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
 				api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 				return
 			}
@@ -130,7 +132,7 @@ func AccountProfileAcceptTOS(w http.ResponseWriter, r *http.Request, _ httproute
 			// Insert the Terms of Service acceptance to database.
 			accountTOS, err := dao.NewCstAccountTOSDAO().Insert(tx, account.ID)
 			if err != nil {
-				response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
+				response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
 				api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 				return
 			}
@@ -139,7 +141,7 @@ func AccountProfileAcceptTOS(w http.ResponseWriter, r *http.Request, _ httproute
 			err = tx.Commit()
 			if err != nil {
 				logger.Fatal("tx.Commit", logger.FromError(err))
-				response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
+				response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
 				api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 				return
 			}
@@ -148,15 +150,15 @@ func AccountProfileAcceptTOS(w http.ResponseWriter, r *http.Request, _ httproute
 			tosRepo.RedisStore().Save(accountTOS)
 
 			success = true
-			message = "Terms of Service is accepted."
+			message = i18n.NewMessage(i18n.MsgTOSAccepted).Localize(ctx.Locale)
 		} else {
-			response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, err.Error())
+			response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, i18n.FromError(err))
 			api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 			return
 		}
 	} else {
 		success = false
-		message = "Terms of Service is already accepted"
+		message = i18n.NewMessage(i18n.MsgTOSAlreadyAccepted).Localize(ctx.Locale)
 	}
 
 	// Return the result.
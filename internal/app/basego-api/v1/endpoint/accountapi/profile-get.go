@@ -89,6 +89,7 @@ import (
 	"github.com/jonylim/basego/internal/pkg/common/api/errcode"
 	"github.com/jonylim/basego/internal/pkg/common/constant/httpstatus"
 	"github.com/jonylim/basego/internal/pkg/common/data/redis"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
 	"github.com/jonylim/basego/internal/pkg/common/logger"
 
 	"github.com/julienschmidt/httprouter"
@@ -122,7 +123,7 @@ func AccountProfileGet(w http.ResponseWriter, r *http.Request, _ httprouter.Para
 		tosStatus.IsAccepted = true
 		tosStatus.AcceptedTime = tos.CreatedTime
 	} else if err != tosRepo.ErrNotFound {
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, err.Error())
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, i18n.FromError(err))
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		return
 	}
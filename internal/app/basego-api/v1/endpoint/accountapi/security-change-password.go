@@ -36,7 +36,9 @@
  *
  * @apiUse   ErrorAccountHeaderValidationFailed
  * @apiError PasswordInvalid          The current password is invalid.
- * @apiError NewPasswordFormatInvalid The new password format is invalid.
+ * @apiError PasswordPolicyViolated   The new password violates the password policy.
+ *   `data.violations` lists the violated rules: `required`, `minLength`, `maxLength`,
+ *   `lowercase`, `uppercase`, `number`, `special`, `breached`, or `reused`.
  *
  * @apiErrorExample {json} PasswordInvalid:
  *     HTTP/1.1 200 OK
@@ -50,16 +52,29 @@
  *       "data": {}
  *     }
  *
- * @apiErrorExample {json} NewPasswordFormatInvalid:
+ * @apiErrorExample {json} PasswordPolicyViolated:
  *     HTTP/1.1 200 OK
  *     {
  *       "status": 400,
  *       "error": {
  *         "code": "40002",
- *         "message": "Password must contain at least 1 lowercase, uppercase, and special characters and 1 number",
+ *         "message": "Password must contain at least 1 uppercase character",
  *         "field": "newPassword"
  *       },
- *       "data": {}
+ *       "data": {
+ *         "violations": [
+ *           {
+ *             "rule": "uppercase",
+ *             "limit": 1,
+ *             "message": "Password must contain at least 1 uppercase character"
+ *           },
+ *           {
+ *             "rule": "special",
+ *             "limit": 1,
+ *             "message": "Password must contain at least 1 special character"
+ *           }
+ *         ]
+ *       }
  *     }
  */
 
@@ -68,16 +83,19 @@ package accountapi
 import (
 	"encoding/json"
 	"net/http"
+	"time"
 
+	"github.com/jonylim/basego/internal/app/basego-api/v1/requestvalidator"
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/dao"
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/repository"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/notification"
 	"github.com/jonylim/basego/internal/pkg/common/api"
 	"github.com/jonylim/basego/internal/pkg/common/api/errcode"
 	"github.com/jonylim/basego/internal/pkg/common/constant/httpstatus"
 	"github.com/jonylim/basego/internal/pkg/common/crypto/password"
 	"github.com/jonylim/basego/internal/pkg/common/data/db"
 	"github.com/jonylim/basego/internal/pkg/common/data/redis"
-	"github.com/jonylim/basego/internal/pkg/common/helper"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
 	"github.com/jonylim/basego/internal/pkg/common/logger"
 
 	"github.com/julienschmidt/httprouter"
@@ -104,31 +122,34 @@ func SecurityChangePassword(w http.ResponseWriter, r *http.Request, _ httprouter
 	errReq := json.NewDecoder(r.Body).Decode(&param)
 	if errReq != nil {
 		logger.Error(ctx.ReqTag, errReq.Error())
-		msg := "Request body format is invalid"
+		msg := i18n.NewMessage(i18n.MsgRequestBodyInvalid)
 		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.ReqParamValidationFailed, msg)
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
 		return
 	}
 
-	var msg, field string
+	var msg i18n.Message
+	var field string
 	if param.CurrentPassword == "" {
-		msg = "Current password is required"
+		msg = i18n.NewMessage(i18n.MsgCurrentPasswordRequired)
 		field = "password"
 	} else if chk := password.HashWithSalt(param.CurrentPassword, ctx.Account.PasswordSalt); chk != ctx.Account.Password {
-		msg = "Current password is invalid"
+		msg = i18n.NewMessage(i18n.MsgCurrentPasswordInvalid)
 		field = "password"
 	} else if param.NewPassword == "" {
-		msg = "New password is required"
-		field = "newPassword"
-	} else if err := helper.ValidatePasswordFormat(param.NewPassword); err != nil {
-		msg = err.Error()
+		msg = i18n.NewMessage(i18n.MsgNewPasswordRequired)
 		field = "newPassword"
 	}
-	if msg != "" {
+	if !msg.IsEmpty() {
 		response := api.NewAPIResponseWithErrorField(ctx.ReqID, errcode.ReqParamValidationFailed, msg, field)
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
 		return
 	}
+	validator := requestvalidator.New(w, r, ctx.ReqID)
+	if !validator.ValidateNewPassword(param.NewPassword, "newPassword") ||
+		!validator.ValidatePasswordReuse(ctx.Account, param.NewPassword, "newPassword") {
+		return
+	}
 
 	// Generate new password salt and hash the password.
 	pwdHash, pwdSalt := password.Hash(param.NewPassword)
@@ -137,7 +158,7 @@ func SecurityChangePassword(w http.ResponseWriter, r *http.Request, _ httprouter
 	tx, err := db.Get().Begin()
 	if err != nil {
 		logger.Fatal("db.Begin", logger.FromError(err))
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		return
 	}
@@ -146,14 +167,14 @@ func SecurityChangePassword(w http.ResponseWriter, r *http.Request, _ httprouter
 	// Save the new password to database.
 	success, err := dao.NewCstAccountDAO().ChangePassword(tx, ctx.Account.ID, pwdHash, pwdSalt)
 	if err != nil {
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		return
 	} else if !success {
 		response := api.NewAPIResponse(ctx.ReqID)
 		response.SetData(SecurityChangePasswordResponseData{
 			Success: false,
-			Message: "Failed to change password",
+			Message: i18n.NewMessage(i18n.MsgPasswordChangeFailed).Localize(ctx.Locale),
 		})
 		api.SendResponseJSON(w, response)
 		return
@@ -163,7 +184,7 @@ func SecurityChangePassword(w http.ResponseWriter, r *http.Request, _ httprouter
 	err = tx.Commit()
 	if err != nil {
 		logger.Fatal("tx.Commit", logger.FromError(err))
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		return
 	}
@@ -177,10 +198,13 @@ func SecurityChangePassword(w http.ResponseWriter, r *http.Request, _ httprouter
 		repository.NewCstAccountRepo(redisConn).SyncByID(accountID)
 	}(ctx.Account.ID)
 
+	// Send the security notification email.
+	go notification.SendPasswordChanged(ctx.Account, time.Now())
+
 	// Return the result.
 	data := SecurityChangePasswordResponseData{
 		Success: true,
-		Message: "Password changed successfully",
+		Message: i18n.NewMessage(i18n.MsgPasswordChanged).Localize(ctx.Locale),
 	}
 	response := api.NewAPIResponse(ctx.ReqID)
 	response.SetData(data)
@@ -71,7 +71,7 @@ func TimeZones(w http.ResponseWriter, r *http.Request, _ httprouter.Params, ctx
 	// Get timezones list.
 	timeZones, err := dao.NewPgTimeZoneDAO().GetAll(ctx, "")
 	if err != nil {
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		return
 	}
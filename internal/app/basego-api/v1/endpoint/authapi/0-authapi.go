@@ -4,6 +4,7 @@
  * #### HTTP Request Headers
  * | **Header Name**   | **Required** | **Description** |
  * |-------------------|:------------:|-----------------|
+ * | Accept-Language   |   | The language of the error messages. Values are `en` (default) or `id`. |
  * | API-Key           | ✓ | API key for accessing the API. |
  * | App-Identifier    |   | The app's identifier (package name for Android, bundle ID for iOS, or origin URL for web). |
  * | Authorization     | ✓ | Authorization type and credentials, e.g.: basic credentials or refresh token to request new access token.<br>Format: <code><i>&lt;type&gt; &lt;credentials&gt;</i></code> |
@@ -40,6 +41,7 @@
  * |  40107   | The user's account has not been verified.                                                              |
  * |  40301   | The user does not have access to the requested resource or action.                                     |
  * |  40401   | The requested resource is not found.                                                                   |
+ * |  42901   | Too many requests, the rate limit is exceeded. Retry after the number of seconds in `Retry-After`.     |
  * |  49101   | The API key is not provided.                                                                           |
  * |  49102   | Failed to parse the API key, or the API key is invalid.                                                |
  * |  49103   | The provided API key is not found.                                                                     |
@@ -47,8 +49,15 @@
  * |  49105   | The provided API key is not intended to be used with the client's app identifier.                      |
  * |  49106   | The API key has expired.                                                                               |
  * |  49107   | The API key is disabled.                                                                               |
+ * |  49110   | The API key is not allowed to call the API, it's out of the key's scopes.                              |
+ * |  49111   | The API key's daily or monthly quota is used up. Retry after the number of seconds in `Retry-After`.   |
  * |  50001   | An error occurred while validating the API key.                                                        |
  * |  99999   | Other errors, usually without specific reason or action.                                               |
+ *
+ * #### Rate Limit Headers
+ * Every response of a rate limited API includes the `RateLimit-Limit`, `RateLimit-Remaining`, `RateLimit-Reset`
+ * (seconds until the window resets), and `RateLimit-Policy` headers. A response with HTTP status `429` also
+ * includes the `Retry-After` header in seconds.
  */
 
 /**
@@ -73,7 +82,6 @@ package authapi
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -85,6 +93,7 @@ import (
 	"github.com/jonylim/basego/internal/pkg/common/api/errcode"
 	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
 	"github.com/jonylim/basego/internal/pkg/common/constant/httpstatus"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
 	"github.com/jonylim/basego/internal/pkg/common/logger"
 
 	"github.com/julienschmidt/httprouter"
@@ -96,6 +105,7 @@ type (
 		context.Context
 		ReqID     string
 		ReqTag    string
+		Locale    string
 		Path      string
 		ReqHeader requestheader.APIRequestHeader
 		APIKey    model.XAPIKey
@@ -106,8 +116,8 @@ type (
 )
 
 var (
-	errDatabase = errors.New("An error occurred while processing your request")
-	errInternal = errors.New("An error occurred while processing your request")
+	errDatabase = i18n.NewError(i18n.MsgProcessingFailed)
+	errInternal = i18n.NewError(i18n.MsgProcessingFailed)
 )
 
 var env string
@@ -121,10 +131,14 @@ func Init() {
 func HandleRequest(w http.ResponseWriter, r *http.Request, p httprouter.Params, handle Handle) {
 	reqID := api.CreateReqID()
 
-	// Validate request headers.
+	// Send the responses in the requested language.
 	reqHeader := requestheader.Parse(r)
+	locale := i18n.LocaleFromAcceptLanguage(reqHeader.AcceptLanguage)
+	w = api.WithLocale(w, locale)
+
+	// Validate request headers.
 	if err := requestheader.CheckRequired(reqHeader); err != nil {
-		response := api.NewAPIResponseWithError(reqID, errcode.ReqHeaderValidationFailed, err.Error())
+		response := api.NewAPIResponseWithError(reqID, errcode.ReqHeaderValidationFailed, i18n.FromError(err))
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
 		return
 	}
@@ -136,6 +150,11 @@ func HandleRequest(w http.ResponseWriter, r *http.Request, p httprouter.Params,
 		return
 	}
 
+	// Verify the CAPTCHA if the API requires it.
+	if !validator.ValidateCaptcha() {
+		return
+	}
+
 	// OK!
 	reqTag := fmt.Sprintf("api:%s", reqID)
 	path := r.URL.Path
@@ -144,6 +163,7 @@ func HandleRequest(w http.ResponseWriter, r *http.Request, p httprouter.Params,
 		Context:   r.Context(),
 		ReqID:     reqID,
 		ReqTag:    reqTag,
+		Locale:    locale,
 		Path:      path,
 		ReqHeader: reqHeader,
 		APIKey:    apiKey,
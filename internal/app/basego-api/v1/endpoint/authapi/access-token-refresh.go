@@ -144,6 +144,7 @@ import (
 	"github.com/jonylim/basego/internal/pkg/common/data/db"
 	"github.com/jonylim/basego/internal/pkg/common/data/redis"
 	"github.com/jonylim/basego/internal/pkg/common/helper"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
 	"github.com/jonylim/basego/internal/pkg/common/logger"
 
 	"github.com/julienschmidt/httprouter"
@@ -160,17 +161,17 @@ func AccessTokenRefresh(w http.ResponseWriter, r *http.Request, _ httprouter.Par
 
 	// Get refresh token from header.
 	if ctx.ReqHeader.Authorization == "" {
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.AuthorizationEmpty, "Authorization is required")
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.AuthorizationEmpty, i18n.NewMessage(i18n.MsgAuthorizationRequired))
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.Unauthorized)
 		return
 	}
 	parts := strings.SplitN(ctx.ReqHeader.Authorization, " ", 2)
 	if len(parts) < 2 {
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.AuthorizationFormatInvalid, "Authorization format is invalid")
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.AuthorizationFormatInvalid, i18n.NewMessage(i18n.MsgAuthorizationFormatInvalid))
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.Unauthorized)
 		return
 	} else if parts[0] != "Bearer" {
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.AuthorizationFormatInvalid, "Authorization type is invalid or not supported")
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.AuthorizationFormatInvalid, i18n.NewMessage(i18n.MsgAuthorizationTypeInvalid))
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.Unauthorized)
 		return
 	}
@@ -185,7 +186,7 @@ func AccessTokenRefresh(w http.ResponseWriter, r *http.Request, _ httprouter.Par
 		case refreshtoken.ErrTokenExpired:
 			errCode = errcode.AuthorizationTokenExpired
 		}
-		response := api.NewAPIResponseWithError(ctx.ReqID, errCode, err.Error())
+		response := api.NewAPIResponseWithError(ctx.ReqID, errCode, i18n.FromError(err))
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.Unauthorized)
 		return
 	}
@@ -199,7 +200,7 @@ func AccessTokenRefresh(w http.ResponseWriter, r *http.Request, _ httprouter.Par
 	session, sessionToken, err := sessionRepo.GetSessionDetailsBySessionID(claims.SessionID)
 	if err != nil {
 		if err == sessionRepo.ErrNotFound {
-			msg := "Account session is not found"
+			msg := i18n.NewMessage(i18n.MsgAccountSessionNotFound)
 			response := api.NewAPIResponseWithError(ctx.ReqID, errcode.AuthorizationTokenInvalid, msg)
 			api.SendResponseJSONWithStatusCode(w, response, httpstatus.Unauthorized)
 		} else {
@@ -208,7 +209,7 @@ func AccessTokenRefresh(w http.ResponseWriter, r *http.Request, _ httprouter.Par
 			} else {
 				err = errInternal
 			}
-			response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, err.Error())
+			response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, i18n.FromError(err))
 			api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		}
 		return
@@ -228,7 +229,7 @@ func AccessTokenRefresh(w http.ResponseWriter, r *http.Request, _ httprouter.Par
 		case refreshtoken.ErrTokenExpired:
 			errCode = errcode.AuthorizationTokenExpired
 		}
-		response := api.NewAPIResponseWithError(ctx.ReqID, errCode, err.Error())
+		response := api.NewAPIResponseWithError(ctx.ReqID, errCode, i18n.FromError(err))
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.Unauthorized)
 		return
 	}
@@ -238,7 +239,7 @@ func AccessTokenRefresh(w http.ResponseWriter, r *http.Request, _ httprouter.Par
 	account, err := accRepo.GetByID(session.AccountID)
 	if err != nil {
 		if err == accRepo.ErrNotFound {
-			msg := "Account is not found"
+			msg := i18n.NewMessage(i18n.MsgAccountNotFound)
 			response := api.NewAPIResponseWithError(ctx.ReqID, errcode.AuthorizationUserNotFound, msg)
 			api.SendResponseJSONWithStatusCode(w, response, httpstatus.Unauthorized)
 		} else {
@@ -247,7 +248,7 @@ func AccessTokenRefresh(w http.ResponseWriter, r *http.Request, _ httprouter.Par
 			} else {
 				err = errInternal
 			}
-			response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, err.Error())
+			response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, i18n.FromError(err))
 			api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		}
 		return
@@ -257,7 +258,7 @@ func AccessTokenRefresh(w http.ResponseWriter, r *http.Request, _ httprouter.Par
 	tx, err := db.Get().Begin()
 	if err != nil {
 		logger.Fatal("db.Begin", logger.FromError(err))
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		return
 	}
@@ -280,18 +281,22 @@ func AccessTokenRefresh(w http.ResponseWriter, r *http.Request, _ httprouter.Par
 	refreshExpirySeconds := refreshExpiryTime.Unix()
 	refreshExpiryMillis := refreshExpirySeconds * 1000
 
+	// Only the hashes of the token strings are saved, the strings are only sent inside the JWTs.
+	accessTokenHash := accesstoken.HashToken(accessTokenStr)
+	refreshTokenHash := refreshtoken.HashToken(refreshTokenStr)
+
 	// Save the new tokens to database.
 	sessionDAO := dao.NewCstAccountSessionDAO()
-	tokenID, err := sessionDAO.InsertSessionToken(tx, session.ID, accessTokenStr, accessExpiryMillis, refreshTokenStr, refreshExpiryMillis)
+	tokenID, err := sessionDAO.InsertSessionToken(tx, session.ID, accessTokenHash, accessExpiryMillis, refreshTokenHash, refreshExpiryMillis)
 	if err != nil {
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		return
 	}
 
 	// Delete old tokens from database.
 	if _, err = sessionDAO.DeleteSessionTokenByID(tx, claims.TokenID, session.ID); err != nil {
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		return
 	}
@@ -299,13 +304,13 @@ func AccessTokenRefresh(w http.ResponseWriter, r *http.Request, _ httprouter.Par
 	// Generate JWT for the access token and refresh token.
 	accessTokenJWT, err := accesstoken.GenerateJWT(tokenID, accessTokenStr, nowSeconds, accessExpirySeconds, session.ID, account.ID)
 	if err != nil {
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errInternal.Error())
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errInternal.Message())
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		return
 	}
 	refreshTokenJWT, err := refreshtoken.GenerateJWT(tokenID, refreshTokenStr, nowSeconds, refreshExpirySeconds, session.ID, account.ID)
 	if err != nil {
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errInternal.Error())
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errInternal.Message())
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		return
 	}
@@ -314,7 +319,7 @@ func AccessTokenRefresh(w http.ResponseWriter, r *http.Request, _ httprouter.Par
 	err = tx.Commit()
 	if err != nil {
 		logger.Fatal("tx.Commit", logger.FromError(err))
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		return
 	}
@@ -322,9 +327,9 @@ func AccessTokenRefresh(w http.ResponseWriter, r *http.Request, _ httprouter.Par
 	sessionToken = model.CstAccountSessionToken{
 		ID:                 tokenID,
 		SessionID:          session.ID,
-		AccessToken:        accessTokenStr,
+		AccessToken:        accessTokenHash,
 		AccessTokenExpiry:  accessExpiryMillis,
-		RefreshToken:       refreshTokenStr,
+		RefreshToken:       refreshTokenHash,
 		RefreshTokenExpiry: refreshExpiryMillis,
 		CreatedTime:        nowMillis,
 	}
@@ -160,8 +160,11 @@ import (
 	"github.com/jonylim/basego/internal/pkg/common/crypto/password"
 	"github.com/jonylim/basego/internal/pkg/common/data/db"
 	"github.com/jonylim/basego/internal/pkg/common/data/redis"
+	"github.com/jonylim/basego/internal/pkg/common/emailvetting"
 	"github.com/jonylim/basego/internal/pkg/common/helper"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
 	"github.com/jonylim/basego/internal/pkg/common/logger"
+	"github.com/jonylim/basego/internal/pkg/common/passwordpolicy"
 
 	"github.com/julienschmidt/httprouter"
 )
@@ -182,20 +185,20 @@ func AccessTokenRequest(w http.ResponseWriter, r *http.Request, _ httprouter.Par
 
 	// Get credentials from header.
 	if ctx.ReqHeader.Authorization == "" {
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.AuthorizationEmpty, "Authorization is required")
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.AuthorizationEmpty, i18n.NewMessage(i18n.MsgAuthorizationRequired))
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.Unauthorized)
 		return
 	}
 	parts := strings.SplitN(ctx.ReqHeader.Authorization, " ", 2)
 	if len(parts) < 2 {
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.AuthorizationFormatInvalid, "Authorization format is invalid")
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.AuthorizationFormatInvalid, i18n.NewMessage(i18n.MsgAuthorizationFormatInvalid))
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.Unauthorized)
 		return
 	}
 	switch parts[0] {
 	case "Basic":
 	default:
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.AuthorizationFormatInvalid, "Authorization type is invalid or not supported")
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.AuthorizationFormatInvalid, i18n.NewMessage(i18n.MsgAuthorizationTypeInvalid))
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.Unauthorized)
 		return
 	}
@@ -204,23 +207,23 @@ func AccessTokenRequest(w http.ResponseWriter, r *http.Request, _ httprouter.Par
 	bytes, err := base64.StdEncoding.DecodeString(parts[1])
 	if err != nil {
 		logger.Error(ctx.ReqTag, err.Error())
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.AuthorizationTokenInvalid, "Failed to parse the credentials")
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.AuthorizationTokenInvalid, i18n.NewMessage(i18n.MsgCredentialsParseFailed))
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.Unauthorized)
 		return
 	}
 	parts = strings.SplitN(string(bytes), ":", 2)
 	if len(parts) < 2 {
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.AuthorizationTokenInvalid, "The credentials are invalid")
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.AuthorizationTokenInvalid, i18n.NewMessage(i18n.MsgCredentialsInvalid))
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.Unauthorized)
 		return
 	}
 	email, pwd := parts[0], parts[1]
 	if email == "" || pwd == "" {
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.AuthorizationTokenInvalid, "The credentials are invalid")
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.AuthorizationTokenInvalid, i18n.NewMessage(i18n.MsgCredentialsInvalid))
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.Unauthorized)
 		return
 	}
-	email = strings.ToLower(email)
+	email = emailvetting.Normalize(email)
 
 	// Get the Redis connection and defer closing connection.
 	redisConn := redis.GetConnection()
@@ -231,7 +234,7 @@ func AccessTokenRequest(w http.ResponseWriter, r *http.Request, _ httprouter.Par
 	account, err := accRepo.GetByEmail(email)
 	if err != nil {
 		if err == accRepo.ErrNotFound {
-			msg := "Account is not found"
+			msg := i18n.NewMessage(i18n.MsgAccountNotFound)
 			response := api.NewAPIResponseWithError(ctx.ReqID, errcode.AuthorizationUserNotFound, msg)
 			api.SendResponseJSONWithStatusCode(w, response, httpstatus.Unauthorized)
 		} else {
@@ -240,7 +243,7 @@ func AccessTokenRequest(w http.ResponseWriter, r *http.Request, _ httprouter.Par
 			} else {
 				err = errInternal
 			}
-			response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, err.Error())
+			response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, i18n.FromError(err))
 			api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		}
 		return
@@ -248,7 +251,7 @@ func AccessTokenRequest(w http.ResponseWriter, r *http.Request, _ httprouter.Par
 
 	// Validate the password.
 	if check := password.HashWithSalt(pwd, account.PasswordSalt); check != account.Password {
-		msg := "The email and password does not match"
+		msg := i18n.NewMessage(i18n.MsgAccountCredentialsMismatch)
 		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.AuthorizationTokenInvalid, msg)
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.Unauthorized)
 		return
@@ -256,7 +259,7 @@ func AccessTokenRequest(w http.ResponseWriter, r *http.Request, _ httprouter.Par
 
 	// Check if the account has been verified.
 	if !account.IsEmailVerified {
-		msg := "Your account has not been verified yet"
+		msg := i18n.NewMessage(i18n.MsgAccountNotVerified)
 		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.AuthorizationUserNotVerified, msg)
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.Unauthorized)
 		return
@@ -266,7 +269,7 @@ func AccessTokenRequest(w http.ResponseWriter, r *http.Request, _ httprouter.Par
 	tx, err := db.Get().Begin()
 	if err != nil {
 		logger.Fatal("db.Begin", logger.FromError(err))
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		return
 	}
@@ -282,7 +285,7 @@ func AccessTokenRequest(w http.ResponseWriter, r *http.Request, _ httprouter.Par
 		// There can only be 1 customer account sessions per device.
 		deletedSessionIDs, err = sessionDAO.DeleteSessionsByDevice(tx, deviceID)
 		if err != nil {
-			response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
+			response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
 			api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 			return
 		}
@@ -291,7 +294,7 @@ func AccessTokenRequest(w http.ResponseWriter, r *http.Request, _ httprouter.Par
 	// Save the new customer account session to database.
 	sessionID, err := sessionDAO.InsertSession(tx, account.ID, ctx.ReqHeader.DevicePlatform, ctx.ReqHeader.DeviceModel, deviceID, ctx.ReqHeader.UserAgent, ipAddr)
 	if err != nil {
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		return
 	}
@@ -302,6 +305,17 @@ func AccessTokenRequest(w http.ResponseWriter, r *http.Request, _ httprouter.Par
 	nowMillis := helper.UnixMillisecond(now)
 	dao.NewCstAccountDAO().UpdateLastLogin(tx, account.ID, now)
 
+	// Require the account to change the password if it has exceeded the maximum age.
+	if !account.RequireChangePassword && passwordpolicy.Get().IsExpired(account.PasswordChangedTime, now) {
+		updated, err := dao.NewCstAccountDAO().SetPasswordChangeRequired(tx, account.ID)
+		if err != nil {
+			response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
+			api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
+			return
+		}
+		account.RequireChangePassword = updated
+	}
+
 	// Generate new access token & refresh tokens, calculate expiry times.
 	accessTokenStr := accesstoken.GenerateAccessToken(sessionID)
 	refreshTokenStr := refreshtoken.GenerateRefreshToken(sessionID)
@@ -312,10 +326,14 @@ func AccessTokenRequest(w http.ResponseWriter, r *http.Request, _ httprouter.Par
 	refreshExpirySeconds := refreshExpiryTime.Unix()
 	refreshExpiryMillis := refreshExpirySeconds * 1000
 
+	// Only the hashes of the token strings are saved, the strings are only sent inside the JWTs.
+	accessTokenHash := accesstoken.HashToken(accessTokenStr)
+	refreshTokenHash := refreshtoken.HashToken(refreshTokenStr)
+
 	// Save the tokens to database.
-	tokenID, err := sessionDAO.InsertSessionToken(tx, sessionID, accessTokenStr, accessExpiryMillis, refreshTokenStr, refreshExpiryMillis)
+	tokenID, err := sessionDAO.InsertSessionToken(tx, sessionID, accessTokenHash, accessExpiryMillis, refreshTokenHash, refreshExpiryMillis)
 	if err != nil {
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		return
 	}
@@ -323,13 +341,13 @@ func AccessTokenRequest(w http.ResponseWriter, r *http.Request, _ httprouter.Par
 	// Generate JWT for the access token and refresh token.
 	accessTokenJWT, err := accesstoken.GenerateJWT(tokenID, accessTokenStr, nowSeconds, accessExpirySeconds, sessionID, account.ID)
 	if err != nil {
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errInternal.Error())
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errInternal.Message())
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		return
 	}
 	refreshTokenJWT, err := refreshtoken.GenerateJWT(tokenID, refreshTokenStr, nowSeconds, refreshExpirySeconds, sessionID, account.ID)
 	if err != nil {
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errInternal.Error())
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errInternal.Message())
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		return
 	}
@@ -338,7 +356,7 @@ func AccessTokenRequest(w http.ResponseWriter, r *http.Request, _ httprouter.Par
 	err = tx.Commit()
 	if err != nil {
 		logger.Fatal("tx.Commit", logger.FromError(err))
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		return
 	}
@@ -356,9 +374,9 @@ func AccessTokenRequest(w http.ResponseWriter, r *http.Request, _ httprouter.Par
 	sessionToken := model.CstAccountSessionToken{
 		ID:                 tokenID,
 		SessionID:          sessionID,
-		AccessToken:        accessTokenStr,
+		AccessToken:        accessTokenHash,
 		AccessTokenExpiry:  accessExpiryMillis,
-		RefreshToken:       refreshTokenStr,
+		RefreshToken:       refreshTokenHash,
 		RefreshTokenExpiry: refreshExpiryMillis,
 		CreatedTime:        nowMillis,
 	}
@@ -4,8 +4,10 @@
  * #### HTTP Request Headers
  * | **Header Name**   | **Required** | **Description** |
  * |-------------------|:------------:|-----------------|
+ * | Accept-Language   |   | The language of the error messages. Values are `en` (default) or `id`. |
  * | API-Key           | ✓ | API key for accessing the API. |
  * | App-Identifier    |   | The app's identifier (package name for Android, bundle ID for iOS, or origin URL for web). |
+ * | Captcha-Token     |   | The solved CAPTCHA token, for the APIs requiring a CAPTCHA. |
  * | Content-Type      |   | Content type of the request body. |
  * | Device-Identifier | ✓ | The device ID (optional for web). |
  * | Device-Model      | ✓ | Model name of the device (optional for web). |
@@ -30,7 +32,12 @@
  * |:--------:|--------------------------------------------------------------------------------------------------------|
  * |  40001   | HTTP request header validation failed.                                                                 |
  * |  40002   | API request parameter validation failed.                                                               |
+ * |  40003   | Too many incorrect OTP attempts, the OTP is invalidated and a new one must be requested.               |
+ * |  40004   | The CAPTCHA token is missing or its verification failed.                                               |
  * |  40401   | The requested resource is not found.                                                                   |
+ * |  42901   | Too many requests, the rate limit is exceeded. Retry after the number of seconds in `Retry-After`.     |
+ * |  42902   | A new OTP is requested too soon. Retry after the number of seconds in `Retry-After`.                   |
+ * |  42903   | The daily limit of OTPs for the account and action is reached.                                         |
  * |  49101   | The API key is not provided.                                                                           |
  * |  49102   | Failed to parse the API key, or the API key is invalid.                                                |
  * |  49103   | The provided API key is not found.                                                                     |
@@ -38,8 +45,19 @@
  * |  49105   | The provided API key is not intended to be used with the client's app identifier.                      |
  * |  49106   | The API key has expired.                                                                               |
  * |  49107   | The API key is disabled.                                                                               |
+ * |  49110   | The API key is not allowed to call the API, it's out of the key's scopes.                              |
+ * |  49111   | The API key's daily or monthly quota is used up. Retry after the number of seconds in `Retry-After`.   |
  * |  50001   | An error occurred while validating the API key.                                                        |
  * |  99999   | Other errors, usually without specific reason or action.                                               |
+ *
+ * #### Rate Limit Headers
+ * Every response of a rate limited API includes the `RateLimit-Limit`, `RateLimit-Remaining`, `RateLimit-Reset`
+ * (seconds until the window resets), and `RateLimit-Policy` headers. A response with HTTP status `429` also
+ * includes the `Retry-After` header in seconds.
+ *
+ * #### CAPTCHA
+ * When configured, `register`, `account_verification/resend_email` and `reset_password/request_token` require a
+ * CAPTCHA token, sent in the `Captcha-Token` header or the `captchaToken` field of the request body.
  */
 
 /**
@@ -64,7 +82,6 @@ package clientapi
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -72,10 +89,13 @@ import (
 	"github.com/jonylim/basego/internal/app/basego-api/v1/endpoint/clientapi/requestheader"
 	"github.com/jonylim/basego/internal/app/basego-api/v1/requestvalidator"
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/token/linktoken"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/token/otp"
 	"github.com/jonylim/basego/internal/pkg/common/api"
 	"github.com/jonylim/basego/internal/pkg/common/api/errcode"
 	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
 	"github.com/jonylim/basego/internal/pkg/common/constant/httpstatus"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
 	"github.com/jonylim/basego/internal/pkg/common/logger"
 
 	"github.com/julienschmidt/httprouter"
@@ -87,6 +107,7 @@ type (
 		context.Context
 		ReqID     string
 		ReqTag    string
+		Locale    string
 		Path      string
 		ReqHeader requestheader.APIRequestHeader
 		APIKey    model.XAPIKey
@@ -97,9 +118,9 @@ type (
 )
 
 var (
-	errDatabase = errors.New("An error occurred while processing your request")
-	errInternal = errors.New("An error occurred while processing your request")
-	errStorage  = errors.New("An error occurred while processing your request")
+	errDatabase = i18n.NewError(i18n.MsgProcessingFailed)
+	errInternal = i18n.NewError(i18n.MsgProcessingFailed)
+	errStorage  = i18n.NewError(i18n.MsgProcessingFailed)
 )
 
 var env string
@@ -107,16 +128,22 @@ var env string
 // Init initializes required variables.
 func Init() {
 	env = os.Getenv(envvar.Environment)
+	linktoken.Init()
+	otp.Init()
 }
 
 // HandleRequest handles a request for client APIs.
 func HandleRequest(w http.ResponseWriter, r *http.Request, p httprouter.Params, handle Handle) {
 	reqID := api.CreateReqID()
 
-	// Validate request headers.
+	// Send the responses in the requested language.
 	reqHeader := requestheader.Parse(r)
+	locale := i18n.LocaleFromAcceptLanguage(reqHeader.AcceptLanguage)
+	w = api.WithLocale(w, locale)
+
+	// Validate request headers.
 	if err := requestheader.CheckRequired(reqHeader); err != nil {
-		response := api.NewAPIResponseWithError(reqID, errcode.ReqHeaderValidationFailed, err.Error())
+		response := api.NewAPIResponseWithError(reqID, errcode.ReqHeaderValidationFailed, i18n.FromError(err))
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
 		return
 	}
@@ -128,6 +155,11 @@ func HandleRequest(w http.ResponseWriter, r *http.Request, p httprouter.Params,
 		return
 	}
 
+	// Verify the CAPTCHA if the API requires it.
+	if !validator.ValidateCaptcha() {
+		return
+	}
+
 	// OK!
 	reqTag := fmt.Sprintf("api:%s", reqID)
 	path := r.URL.Path
@@ -136,6 +168,7 @@ func HandleRequest(w http.ResponseWriter, r *http.Request, p httprouter.Params,
 		Context:   r.Context(),
 		ReqID:     reqID,
 		ReqTag:    reqTag,
+		Locale:    locale,
 		Path:      path,
 		ReqHeader: reqHeader,
 		APIKey:    apiKey,
@@ -0,0 +1,67 @@
+package clientapi
+
+import (
+	"math"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/token/otp"
+	"github.com/jonylim/basego/internal/pkg/common/api/errcode"
+	"github.com/jonylim/basego/internal/pkg/common/constant/httpstatus"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
+)
+
+// otpMessages are the message IDs of an OTP flow's validation errors, e.g. the reset password flow calls its OTP a token.
+type otpMessages struct {
+	notFound, idInvalid, keyInvalid, codeIncorrect, emailChanged string
+}
+
+var emailVerificationMessages = otpMessages{
+	notFound:      i18n.MsgVerificationNotFound,
+	idInvalid:     i18n.MsgVerificationIDInvalid,
+	keyInvalid:    i18n.MsgVerificationKeyInvalid,
+	codeIncorrect: i18n.MsgVerificationCodeIncorrect,
+	emailChanged:  i18n.MsgVerificationEmailChanged,
+}
+
+var resetPasswordMessages = otpMessages{
+	notFound:      i18n.MsgResetPasswordNotFound,
+	idInvalid:     i18n.MsgResetTokenInvalid,
+	keyInvalid:    i18n.MsgResetTokenInvalid,
+	codeIncorrect: i18n.MsgResetTokenIncorrect,
+	emailChanged:  i18n.MsgResetTokenInvalid,
+}
+
+// otpErrorResult maps an error of the OTP service to the result of a flow.
+func otpErrorResult(err error, msgs otpMessages) Result {
+	invalid := func(id, field string) Result {
+		return newErrorResult(httpstatus.BadRequest, errcode.ReqParamValidationFailed, i18n.NewMessage(id), field)
+	}
+	switch err {
+	case otp.ErrNotFound:
+		return invalid(msgs.notFound, "")
+	case otp.ErrIDInvalid:
+		return invalid(msgs.idInvalid, "otpID")
+	case otp.ErrKeyInvalid:
+		return invalid(msgs.keyInvalid, "otpKey")
+	case otp.ErrCodeIncorrect:
+		return invalid(msgs.codeIncorrect, "otpCode")
+	case otp.ErrEmailChanged:
+		return invalid(msgs.emailChanged, "email")
+	case otp.ErrTooManyAttempts:
+		return newErrorResult(httpstatus.BadRequest, errcode.OTPAttemptsExceeded, i18n.FromError(err), "")
+	case otp.ErrSendLimit:
+		return newErrorResult(httpstatus.TooManyRequests, errcode.OTPSendLimitExceeded, i18n.FromError(err), "")
+	case otp.ErrDatabase:
+		return newErrorResult(httpstatus.InternalServerError, errcode.Other, errDatabase.Message(), "")
+	}
+	if e, ok := err.(*otp.CooldownError); ok {
+		res := newErrorResult(httpstatus.TooManyRequests, errcode.OTPResendCooldown, e.Message(), "")
+		res.RetryAfter = e.Seconds()
+		return res
+	}
+	return newErrorResult(httpstatus.InternalServerError, errcode.Other, errInternal.Message(), "")
+}
+
+// otpTTLHours returns the TTL of an action's OTPs in whole hours for the emails, rounded up.
+func otpTTLHours(action string) int {
+	return int(math.Ceil(otp.GetPolicy(action).TTL.Hours()))
+}
@@ -0,0 +1,54 @@
+package clientapi
+
+import (
+	"net/http"
+
+	"github.com/jonylim/basego/internal/app/basego-api/v1/requestvalidator"
+	"github.com/jonylim/basego/internal/pkg/common/api"
+	"github.com/jonylim/basego/internal/pkg/common/helper"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
+	"github.com/jonylim/basego/internal/pkg/common/passwordpolicy"
+)
+
+// Result is the result of an action shared by the client APIs and the web pages.
+// The messages are localized by the caller.
+type Result struct {
+	Success bool
+	Message i18n.Message
+
+	// StatusCode, ErrCode and ErrField are set if the action fails with an error.
+	StatusCode int
+	ErrCode    string
+	ErrField   string
+
+	// Violations is set if a new password violates the password policy.
+	Violations passwordpolicy.Violations
+
+	// RetryAfter is the number of seconds to wait before retrying, if the action is limited.
+	RetryAfter int
+}
+
+// IsError checks if the action fails with an error.
+func (res Result) IsError() bool {
+	return res.ErrCode != ""
+}
+
+func newResult(success bool, msg i18n.Message) Result {
+	return Result{Success: success, Message: msg}
+}
+
+func newErrorResult(statusCode int, errCode string, msg i18n.Message, field string) Result {
+	return Result{Message: msg, StatusCode: statusCode, ErrCode: errCode, ErrField: field}
+}
+
+// sendErrorResult sends the error of a result as the API response.
+func sendErrorResult(w http.ResponseWriter, reqID string, res Result) {
+	response := api.NewAPIResponseWithErrorField(reqID, res.ErrCode, res.Message, res.ErrField)
+	if res.RetryAfter > 0 {
+		w.Header().Set("Retry-After", helper.IntToString(res.RetryAfter))
+	}
+	if len(res.Violations) != 0 {
+		response.SetData(requestvalidator.PasswordPolicyViolationData{Violations: res.Violations.Localize(api.GetLocale(w))})
+	}
+	api.SendResponseJSONWithStatusCode(w, response, res.StatusCode)
+}
@@ -1,8 +1,10 @@
 package clientapi
 
 import (
-	"encoding/base64"
-	"encoding/json"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/token/linktoken"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/token/otp"
 )
 
 // -----------------------------------------------------------------------------
@@ -16,20 +18,12 @@ type emailVerificationToken struct {
 	Email string `json:"email"`
 }
 
-func (t *emailVerificationToken) Encode() (string, error) {
-	b, err := json.Marshal(t)
-	if err != nil {
-		return "", err
-	}
-	return base64.StdEncoding.EncodeToString(b), nil
+func (t *emailVerificationToken) Encode(expiresAt int64) (string, error) {
+	return linktoken.Encode(otp.ActionVerifyEmail, expiresAt, t)
 }
 
 func (t *emailVerificationToken) Decode(s string) error {
-	b, err := base64.StdEncoding.DecodeString(s)
-	if err != nil {
-		return err
-	}
-	return json.Unmarshal(b, t)
+	return linktoken.Decode(s, otp.ActionVerifyEmail, time.Now(), t)
 }
 
 // -----------------------------------------------------------------------------
@@ -43,18 +37,10 @@ type emailResetPasswordToken struct {
 	Email string `json:"email"`
 }
 
-func (t *emailResetPasswordToken) Encode() (string, error) {
-	b, err := json.Marshal(t)
-	if err != nil {
-		return "", err
-	}
-	return base64.StdEncoding.EncodeToString(b), nil
+func (t *emailResetPasswordToken) Encode(expiresAt int64) (string, error) {
+	return linktoken.Encode(otp.ActionResetPassword, expiresAt, t)
 }
 
 func (t *emailResetPasswordToken) Decode(s string) error {
-	b, err := base64.StdEncoding.DecodeString(s)
-	if err != nil {
-		return err
-	}
-	return json.Unmarshal(b, t)
+	return linktoken.Decode(s, otp.ActionResetPassword, time.Now(), t)
 }
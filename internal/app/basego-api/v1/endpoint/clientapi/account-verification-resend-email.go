@@ -56,6 +56,8 @@
  * @apiUse   ErrorClientHeaderValidationFailed
  * @apiError ParamValidationFailed The parameter validation failed.
  * @apiError EmailNotRegistered    The email address is not registered.
+ * @apiError OTPResendCooldown     A new OTP is requested before the resend cooldown has passed.
+ * @apiError OTPSendLimitExceeded  The daily limit of OTPs is reached.
  *
  * @apiErrorExample {json} ParamValidationFailed:
  *     HTTP/1.1 200 OK
@@ -80,6 +82,18 @@
  *       },
  *       "data": {}
  *     }
+ *
+ * @apiErrorExample {json} OTPResendCooldown:
+ *     HTTP/1.1 200 OK
+ *     {
+ *       "status": 429,
+ *       "error": {
+ *         "code": "42902",
+ *         "message": "Please wait 42 seconds before requesting a new code",
+ *         "field": ""
+ *       },
+ *       "data": {}
+ *     }
  */
 
 package clientapi
@@ -87,19 +101,15 @@ package clientapi
 import (
 	"encoding/json"
 	"net/http"
-	"time"
 
-	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/dao"
-	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/redisstore"
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/repository"
-	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/token/otp"
 	"github.com/jonylim/basego/internal/pkg/common/api"
 	"github.com/jonylim/basego/internal/pkg/common/api/errcode"
 	"github.com/jonylim/basego/internal/pkg/common/constant/httpstatus"
-	"github.com/jonylim/basego/internal/pkg/common/data/db"
 	"github.com/jonylim/basego/internal/pkg/common/data/redis"
 	"github.com/jonylim/basego/internal/pkg/common/helper"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
 	"github.com/jonylim/basego/internal/pkg/common/logger"
 
 	"github.com/julienschmidt/httprouter"
@@ -128,21 +138,22 @@ func AccountVerificationResendEmail(w http.ResponseWriter, r *http.Request, _ ht
 	errReq := json.NewDecoder(r.Body).Decode(&param)
 	if errReq != nil {
 		logger.Error(ctx.ReqTag, errReq.Error())
-		msg := "Request body format is invalid"
+		msg := i18n.NewMessage(i18n.MsgRequestBodyInvalid)
 		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.ReqParamValidationFailed, msg)
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
 		return
 	}
 
-	var msg, field string
+	var msg i18n.Message
+	var field string
 	if param.Email == "" {
-		msg = "Email address is required"
+		msg = i18n.NewMessage(i18n.MsgEmailRequired)
 		field = "email"
 	} else if err := helper.ValidateEmailFormat(param.Email); err != nil {
-		msg = err.Error()
+		msg = i18n.FromError(err)
 		field = "email"
 	}
-	if msg != "" {
+	if !msg.IsEmpty() {
 		response := api.NewAPIResponseWithErrorField(ctx.ReqID, errcode.ReqParamValidationFailed, msg, field)
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
 		return
@@ -157,7 +168,7 @@ func AccountVerificationResendEmail(w http.ResponseWriter, r *http.Request, _ ht
 	account, err := accRepo.GetByEmail(param.Email)
 	if err != nil {
 		if err == accRepo.ErrNotFound {
-			msg = "The email address is not registered"
+			msg = i18n.NewMessage(i18n.MsgAccountEmailNotRegistered)
 			field = "email"
 			response := api.NewAPIResponseWithErrorField(ctx.ReqID, errcode.ReqParamValidationFailed, msg, field)
 			api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
@@ -168,7 +179,7 @@ func AccountVerificationResendEmail(w http.ResponseWriter, r *http.Request, _ ht
 		} else {
 			err = errInternal
 		}
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, err.Error())
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, i18n.FromError(err))
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		return
 	}
@@ -177,7 +188,7 @@ func AccountVerificationResendEmail(w http.ResponseWriter, r *http.Request, _ ht
 	if account.IsEmailVerified {
 		data := AccountVerificationResendEmailResponseData{
 			Success: false,
-			Message: "The account has already been verified",
+			Message: i18n.NewMessage(i18n.MsgAccountAlreadyVerified).Localize(ctx.Locale),
 		}
 		response := api.NewAPIResponse(ctx.ReqID)
 		response.SetData(data)
@@ -185,77 +196,23 @@ func AccountVerificationResendEmail(w http.ResponseWriter, r *http.Request, _ ht
 		return
 	}
 
-	// Begin database transaction.
-	tx, err := db.Get().Begin()
-	if err != nil {
-		logger.Fatal("db.Begin", logger.FromError(err))
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
-		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
-		return
-	}
-	defer tx.Rollback()
-
-	// Generate OTP for email verification.
-	otpKey, otpCode := otp.GenerateAlphanumeric()
-	expiryTime := time.Now().Add(emailVerificationTTL * time.Second)
-	otpData := model.CstAccountOTP{
-		AccountID:  account.ID,
-		Key:        otpKey,
-		Code:       otpCode,
-		Action:     otp.ActionVerifyEmail,
-		Method:     otp.MethodEmail,
-		Email:      account.Email,
-		ExpiryTime: helper.UnixMillisecond(expiryTime),
-		SendCount:  1,
-	}
-
-	// Delete currently active OTP by account and action.
-	otpDAO := dao.NewCstAccountOTPDAO()
-	deletedID, lastSendCount, err := otpDAO.DeleteActiveOTPByAccountAndAction(tx, otpData.AccountID, otpData.Action)
+	// Replace the active OTP for email verification.
+	otpData, otpCode, err := otp.NewService(redisConn).Resend(account, otp.ActionVerifyEmail)
 	if err != nil {
-		// NOTE: Error deleting active OTP can be ignored.
-		// response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
-		// api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
-		// return
-	}
-
-	// Set next send count.
-	otpData.SendCount = lastSendCount + 1
-
-	// Insert the new OTP to database.
-	otpID, otpCreatedMillis, err := otpDAO.InsertOTP(tx, otpData)
-	if err != nil {
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
-		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
+		sendErrorResult(w, ctx.ReqID, otpErrorResult(err, emailVerificationMessages))
 		return
 	}
-	otpData.ID, otpData.CreatedTime = otpID, otpCreatedMillis
-
-	// Commit database transaction.
-	err = tx.Commit()
-	if err != nil {
-		logger.Fatal("tx.Commit", logger.FromError(err))
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
-		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
-		return
-	}
-
-	// Save to Redis.
-	otpStore := redisstore.NewCstAccountOTPStore(redisConn)
-	otpStore.DeleteOTPByAccountAndAction(otpData.AccountID, otpData.Action)
-	otpStore.SaveNilByID(deletedID, emailVerificationTTL)
-	otpStore.SaveOTPByAccountAndAction(otpData, emailVerificationTTL*2)
 
 	// Send verification email.
-	go sendVerificationEmail(account, otpData)
+	go SendVerificationEmail(account, otpData, otpCode)
 
 	// Return the response.
 	data := AccountVerificationResendEmailResponseData{
 		Success:    true,
 		Message:    "",
-		OTPID:      otpID,
-		OTPKey:     otpKey,
-		CodeLength: otp.Length,
+		OTPID:      otpData.ID,
+		OTPKey:     otpData.Key,
+		CodeLength: int32(len(otpCode)),
 	}
 	response := api.NewAPIResponse(ctx.ReqID)
 	response.SetData(data)
@@ -7,10 +7,11 @@
  *
  * @apiDescription Submit code for account verification.
  *
- * @apiParam {long}   otpID   The OTP ID.
- * @apiParam {string} otpKey  The OTP key.
- * @apiParam {string} otpCode The OTP code.
- * @apiParam {string} email   The email address of the account to be verified.
+ * @apiParam {long}   [otpID]   The OTP ID. Required if `token` is not provided.
+ * @apiParam {string} [otpKey]  The OTP key. Required if `token` is not provided.
+ * @apiParam {string} [otpCode] The OTP code. Required if `token` is not provided.
+ * @apiParam {string} [email]   The email address of the account to be verified. Required if `token` is not provided.
+ * @apiParam {string} [token]   The token from the verification email's link. If provided, the other parameters are read from it.
  *
  * @apiParamExample {json} Request Example:
  *     {
@@ -41,6 +42,7 @@
  * @apiUse   ErrorClientHeaderValidationFailed
  * @apiError ParamValidationFailed The parameter validation failed.
  * @apiError EmailNotRegistered    The email address is not registered.
+ * @apiError OTPAttemptsExceeded   Too many incorrect attempts, the OTP is invalidated.
  *
  * @apiErrorExample {json} ParamValidationFailed:
  *     HTTP/1.1 200 OK
@@ -65,6 +67,18 @@
  *       },
  *       "data": {}
  *     }
+ *
+ * @apiErrorExample {json} OTPAttemptsExceeded:
+ *     HTTP/1.1 200 OK
+ *     {
+ *       "status": 400,
+ *       "error": {
+ *         "code": "40003",
+ *         "message": "Too many incorrect attempts, please request a new code",
+ *         "field": ""
+ *       },
+ *       "data": {}
+ *     }
  */
 
 package clientapi
@@ -72,7 +86,6 @@ package clientapi
 import (
 	"encoding/json"
 	"net/http"
-	"time"
 
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/dao"
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/repository"
@@ -83,6 +96,7 @@ import (
 	"github.com/jonylim/basego/internal/pkg/common/data/db"
 	"github.com/jonylim/basego/internal/pkg/common/data/redis"
 	"github.com/jonylim/basego/internal/pkg/common/helper"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
 	"github.com/jonylim/basego/internal/pkg/common/logger"
 
 	"github.com/julienschmidt/httprouter"
@@ -94,6 +108,7 @@ type AccountVerificationSubmitRequestParam struct {
 	OTPID   int64  `json:"otpID"`
 	OTPKey  string `json:"otpKey"`
 	OTPCode string `json:"otpCode"`
+	Token   string `json:"token"`
 }
 
 // AccountVerificationSubmitResponseData represents response data of Client API "Account Verification - Submit".
@@ -111,33 +126,60 @@ func AccountVerificationSubmit(w http.ResponseWriter, r *http.Request, _ httprou
 	errReq := json.NewDecoder(r.Body).Decode(&param)
 	if errReq != nil {
 		logger.Error(ctx.ReqTag, errReq.Error())
-		msg := "Request body format is invalid"
+		msg := i18n.NewMessage(i18n.MsgRequestBodyInvalid)
 		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.ReqParamValidationFailed, msg)
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
 		return
 	}
 
-	var msg, field string
+	res := SubmitAccountVerification(param)
+	if res.IsError() {
+		sendErrorResult(w, ctx.ReqID, res)
+		return
+	}
+
+	// Return the response.
+	data := AccountVerificationSubmitResponseData{
+		Success: res.Success,
+		Message: res.Message.Localize(ctx.Locale),
+	}
+	response := api.NewAPIResponse(ctx.ReqID)
+	response.SetData(data)
+	api.SendResponseJSON(w, response)
+}
+
+// SubmitAccountVerification verifies the OTP and email for account verification.
+// It's the logic of Client API "Account Verification - Submit", shared with the web pages.
+func SubmitAccountVerification(param AccountVerificationSubmitRequestParam) Result {
+	// Read the OTP from the email link's token if provided, the token must be valid before anything else is checked.
+	if param.Token != "" {
+		var t emailVerificationToken
+		if err := t.Decode(param.Token); err != nil {
+			return newErrorResult(httpstatus.BadRequest, errcode.ReqParamValidationFailed, i18n.FromError(err), "token")
+		}
+		param.OTPID, param.OTPKey, param.OTPCode, param.Email = t.ID, t.Key, t.Code, t.Email
+	}
+
+	var msg i18n.Message
+	var field string
 	if param.OTPID == 0 {
-		msg = "Verification ID is required"
+		msg = i18n.NewMessage(i18n.MsgVerificationIDRequired)
 		field = "otpID"
 	} else if param.OTPKey == "" {
-		msg = "Verification key is required"
+		msg = i18n.NewMessage(i18n.MsgVerificationKeyRequired)
 		field = "otpKey"
 	} else if param.OTPCode == "" {
-		msg = "Verification code is required"
+		msg = i18n.NewMessage(i18n.MsgVerificationCodeRequired)
 		field = "otpCode"
 	} else if param.Email == "" {
-		msg = "Email address is required"
+		msg = i18n.NewMessage(i18n.MsgEmailRequired)
 		field = "email"
 	} else if err := helper.ValidateEmailFormat(param.Email); err != nil {
-		msg = err.Error()
+		msg = i18n.FromError(err)
 		field = "email"
 	}
-	if msg != "" {
-		response := api.NewAPIResponseWithErrorField(ctx.ReqID, errcode.ReqParamValidationFailed, msg, field)
-		api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
-		return
+	if !msg.IsEmpty() {
+		return newErrorResult(httpstatus.BadRequest, errcode.ReqParamValidationFailed, msg, field)
 	}
 
 	// Get the Redis connection and defer closing connection.
@@ -149,106 +191,27 @@ func AccountVerificationSubmit(w http.ResponseWriter, r *http.Request, _ httprou
 	account, err := accRepo.GetByEmail(param.Email)
 	if err != nil {
 		if err == accRepo.ErrNotFound {
-			msg = "The email address is not registered"
-			field = "email"
-			response := api.NewAPIResponseWithErrorField(ctx.ReqID, errcode.ReqParamValidationFailed, msg, field)
-			api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
-			return
+			msg = i18n.NewMessage(i18n.MsgAccountEmailNotRegistered)
+			return newErrorResult(httpstatus.BadRequest, errcode.ReqParamValidationFailed, msg, "email")
 		}
 		if err == accRepo.ErrDatabase {
 			err = errDatabase
 		} else {
 			err = errInternal
 		}
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, err.Error())
-		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
-		return
+		return newErrorResult(httpstatus.InternalServerError, errcode.Other, i18n.FromError(err), "")
 	}
 
 	// Check if the email address has been verified.
 	if account.IsEmailVerified {
-		data := AccountVerificationSubmitResponseData{
-			Success: false,
-			Message: "The account has already been verified",
-		}
-		response := api.NewAPIResponse(ctx.ReqID)
-		response.SetData(data)
-		api.SendResponseJSON(w, response)
-		return
+		return newResult(false, i18n.NewMessage(i18n.MsgAccountAlreadyVerified))
 	}
 
-	// Get active OTP's details.
-	otpRepo := repository.NewCstAccountOTPRepo(redisConn)
-	otpData, err := otpRepo.GetActiveOTPByAccountAndAction(account.ID, otp.ActionVerifyEmail)
+	// Verify the submitted OTP.
+	otpService := otp.NewService(redisConn)
+	otpData, err := otpService.Verify(account, otp.ActionVerifyEmail, otp.Submission{ID: param.OTPID, Key: param.OTPKey, Code: param.OTPCode})
 	if err != nil {
-		if err == otpRepo.ErrNotFound {
-			msg = "There is no pending verification found, or the code has expired"
-			response := api.NewAPIResponseWithError(ctx.ReqID, errcode.ReqParamValidationFailed, msg)
-			api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
-		} else {
-			if err == accRepo.ErrDatabase {
-				err = errDatabase
-			} else {
-				err = errInternal
-			}
-			response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, err.Error())
-			api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
-		}
-		return
-	}
-
-	// Validate the submitted OTP.
-	isValidID := true
-	isValidCode := false
-	if otpData.ID != param.OTPID {
-		msg = "Verification ID is invalid"
-		field = "otpID"
-		isValidID = false
-	} else if otpData.Key != param.OTPKey {
-		msg = "Verification key is invalid"
-		field = "otpKey"
-	} else if otpData.Code != param.OTPCode {
-		msg = "Verification code is incorrect"
-		field = "otpCode"
-	} else if otpData.Email != account.Email {
-		msg = "Email address has changed, you should request a new verification code"
-		field = "email"
-	} else {
-		isValidCode = true
-	}
-	if isValidID && !isValidCode {
-		// Begin database transaction.
-		tx, err := db.Get().Begin()
-		if err != nil {
-			logger.Fatal("db.Begin", logger.FromError(err))
-			response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
-			api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
-			return
-		}
-		defer tx.Rollback()
-
-		// Increment the OTP's verification attempt count.
-		attemptCount, err := dao.NewCstAccountOTPDAO().IncrementAttemptCountByID(tx, otpData.ID)
-		if err != nil {
-			otpRepo.RedisStore().DeleteOTPByID(otpData.ID)
-			otpRepo.RedisStore().DeleteOTPByAccountAndAction(otpData.AccountID, otpData.Action)
-		} else {
-			// Commit database transaction.
-			err = tx.Commit()
-			if err != nil {
-				logger.Fatal("tx.Commit", logger.FromError(err))
-			} else {
-				// Update to Redis.
-				otpData.AttemptCount = attemptCount
-				otpData.UpdatedTime = helper.UnixMillisecond(time.Now())
-				otpRepo.RedisStore().SaveOTPByAccountAndAction(otpData, emailVerificationTTL)
-			}
-		}
-	}
-	if msg != "" {
-		response := api.NewAPIResponseWithErrorField(ctx.ReqID, errcode.ReqParamValidationFailed, msg, field)
-		api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
-		return
+		return otpErrorResult(err, emailVerificationMessages)
 	}
 
 	// The verification is successful.
@@ -256,32 +219,20 @@ func AccountVerificationSubmit(w http.ResponseWriter, r *http.Request, _ httprou
 	tx, err := db.Get().Begin()
 	if err != nil {
 		logger.Fatal("db.Begin", logger.FromError(err))
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
-		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
-		return
+		return newErrorResult(httpstatus.InternalServerError, errcode.Other, errDatabase.Message(), "")
 	}
 	defer tx.Rollback()
 
 	// Mark the OTP as verified.
-	attemptCount, isVerified, err := dao.NewCstAccountOTPDAO().SetVerified(tx, otpData.ID)
-	if attemptCount == 0 || err != nil {
-		otpRepo.RedisStore().DeleteOTPByID(otpData.ID)
-		otpRepo.RedisStore().DeleteOTPByAccountAndAction(otpData.AccountID, otpData.Action)
-
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
-		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
-		return
+	otpData, err = otpService.Consume(tx, otpData)
+	if err != nil {
+		return otpErrorResult(err, emailVerificationMessages)
 	}
-	otpData.AttemptCount = attemptCount
-	otpData.IsVerified = isVerified
-	otpData.UpdatedTime = helper.UnixMillisecond(time.Now())
 
 	// Mark the account's email address as verified.
 	_, err = dao.NewCstAccountDAO().SetVerifiedEmail(tx, account.ID)
 	if err != nil {
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
-		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
-		return
+		return newErrorResult(httpstatus.InternalServerError, errcode.Other, errDatabase.Message(), "")
 	}
 	account.IsEmailVerified = true
 
@@ -289,23 +240,14 @@ func AccountVerificationSubmit(w http.ResponseWriter, r *http.Request, _ httprou
 	err = tx.Commit()
 	if err != nil {
 		logger.Fatal("tx.Commit", logger.FromError(err))
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
-		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
-		return
+		return newErrorResult(httpstatus.InternalServerError, errcode.Other, errDatabase.Message(), "")
 	}
 
 	// Update to Redis.
 	if err := accRepo.RedisStore().Save(account); err != nil {
 		accRepo.RedisStore().Delete(account)
 	}
-	otpRepo.RedisStore().SaveOTPByAccountAndAction(otpData, emailVerificationTTL)
+	otpService.Save(otpData)
 
-	// Return the response.
-	data := AccountVerificationSubmitResponseData{
-		Success: true,
-		Message: "Your account verification is successful",
-	}
-	response := api.NewAPIResponse(ctx.ReqID)
-	response.SetData(data)
-	api.SendResponseJSON(w, response)
+	return newResult(true, i18n.NewMessage(i18n.MsgAccountVerified))
 }
@@ -80,10 +80,11 @@
  *
  * @apiDescription Register a new customer account.
  *
- * @apiParam {string}  fullName        The full name.
- * @apiParam {string}  email           The email address.
- * @apiParam {string}  password        The password.
- * @apiParam {string}  [isTOSAccepted] If the Terms of Service is accepted.
+ * @apiParam {string}  fullName           The full name.
+ * @apiParam {string}  email              The email address.
+ * @apiParam {string}  password           The password.
+ * @apiParam {string}  [isTOSAccepted]    If the Terms of Service is accepted.
+ * @apiParam {boolean} [isEmailConfirmed] If the user keeps the email address after a `typo` rejection.
  *
  * @apiParamExample {json} Request Example:
  *     {
@@ -120,6 +121,12 @@
  * @apiUse   ErrorClientHeaderValidationFailed
  * @apiError ParamValidationFailed  The parameter validation failed.
  * @apiError EmailAlreadyRegistered The email address is already registered.
+ * @apiError PasswordPolicyViolated The password violates the password policy.
+ *   `data.violations` lists the violated rules: `required`, `minLength`, `maxLength`,
+ *   `lowercase`, `uppercase`, `number`, `special`, or `breached`.
+ * @apiError EmailRejected          The email address is rejected. `data.rejection.reason` is `format`, `typo`,
+ *   `disposable`, or `domainInvalid`. A `typo` rejection suggests the address in `data.rejection.suggestion`,
+ *   the address is accepted if it's sent again with `isEmailConfirmed`.
  *
  * @apiErrorExample {json} ParamValidationFailed:
  *     HTTP/1.1 200 OK
@@ -144,6 +151,49 @@
  *       },
  *       "data": {}
  *     }
+ *
+ * @apiErrorExample {json} PasswordPolicyViolated:
+ *     HTTP/1.1 200 OK
+ *     {
+ *       "status": 400,
+ *       "error": {
+ *         "code": "40002",
+ *         "message": "Password must contain at least 1 uppercase character",
+ *         "field": "password"
+ *       },
+ *       "data": {
+ *         "violations": [
+ *           {
+ *             "rule": "uppercase",
+ *             "limit": 1,
+ *             "message": "Password must contain at least 1 uppercase character"
+ *           },
+ *           {
+ *             "rule": "special",
+ *             "limit": 1,
+ *             "message": "Password must contain at least 1 special character"
+ *           }
+ *         ]
+ *       }
+ *     }
+ *
+ * @apiErrorExample {json} EmailRejected:
+ *     HTTP/1.1 200 OK
+ *     {
+ *       "status": 400,
+ *       "error": {
+ *         "code": "40002",
+ *         "message": "Did you mean john@gmail.com?",
+ *         "field": "email"
+ *       },
+ *       "data": {
+ *         "rejection": {
+ *           "reason": "typo",
+ *           "suggestion": "john@gmail.com",
+ *           "message": "Did you mean john@gmail.com?"
+ *         }
+ *       }
+ *     }
  */
 
 package clientapi
@@ -154,15 +204,16 @@ import (
 	"net/http"
 	"net/url"
 	"os"
-	"strings"
-	"time"
 
+	"github.com/jonylim/basego/internal/app/basego-api/v1/requestvalidator"
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/dao"
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/redisstore"
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/repository"
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/emailtemplate"
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/preference"
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/token/otp"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/webpage"
 	"github.com/jonylim/basego/internal/pkg/common/api"
 	"github.com/jonylim/basego/internal/pkg/common/api/errcode"
 	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
@@ -170,7 +221,7 @@ import (
 	"github.com/jonylim/basego/internal/pkg/common/crypto/password"
 	"github.com/jonylim/basego/internal/pkg/common/data/db"
 	"github.com/jonylim/basego/internal/pkg/common/data/redis"
-	"github.com/jonylim/basego/internal/pkg/common/helper"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
 	"github.com/jonylim/basego/internal/pkg/common/logger"
 	"github.com/jonylim/basego/internal/pkg/common/send/email"
 
@@ -179,10 +230,11 @@ import (
 
 // RegisterRequestParam represents request body of Client API "Register".
 type RegisterRequestParam struct {
-	FullName      string `json:"fullName"`
-	Email         string `json:"email"`
-	Password      string `json:"password"`
-	IsTOSAccepted bool   `json:"isTOSAccepted"`
+	FullName         string `json:"fullName"`
+	Email            string `json:"email"`
+	Password         string `json:"password"`
+	IsTOSAccepted    bool   `json:"isTOSAccepted"`
+	IsEmailConfirmed bool   `json:"isEmailConfirmed"`
 }
 
 // RegisterResponseData represents response data of Client API "Register".
@@ -195,8 +247,6 @@ type RegisterResponseData struct {
 	CodeLength int32  `json:"codeLength"`
 }
 
-const emailVerificationTTL = 86400
-
 // Register registers a new customer account.
 func Register(w http.ResponseWriter, r *http.Request, _ httprouter.Params, ctx Context) {
 	logger.Trace(ctx.ReqTag, "Handle: clientapi.Register")
@@ -205,35 +255,37 @@ func Register(w http.ResponseWriter, r *http.Request, _ httprouter.Params, ctx C
 	errReq := json.NewDecoder(r.Body).Decode(&param)
 	if errReq != nil {
 		logger.Error(ctx.ReqTag, errReq.Error())
-		msg := "Request body format is invalid"
+		msg := i18n.NewMessage(i18n.MsgRequestBodyInvalid)
 		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.ReqParamValidationFailed, msg)
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
 		return
 	}
 
-	var msg, field string
+	var msg i18n.Message
+	var field string
 	if param.FullName == "" {
-		msg = "Full name is required"
+		msg = i18n.NewMessage(i18n.MsgFullNameRequired)
 		field = "fullName"
 	} else if param.Email == "" {
-		msg = "Email address is required"
-		field = "email"
-	} else if err := helper.ValidateEmailFormat(param.Email); err != nil {
-		msg = err.Error()
+		msg = i18n.NewMessage(i18n.MsgEmailRequired)
 		field = "email"
 	} else if param.Password == "" {
-		msg = "Password is required"
-		field = "password"
-	} else if err := helper.ValidatePasswordFormat(param.Password); err != nil {
-		msg = err.Error()
+		msg = i18n.NewMessage(i18n.MsgPasswordRequired)
 		field = "password"
 	}
-	if msg != "" {
+	if !msg.IsEmpty() {
 		response := api.NewAPIResponseWithErrorField(ctx.ReqID, errcode.ReqParamValidationFailed, msg, field)
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
 		return
 	}
-	param.Email = strings.ToLower(param.Email)
+	validator := requestvalidator.New(w, r, ctx.ReqID)
+	var ok bool
+	if param.Email, ok = validator.ValidateNewEmail(param.Email, "email", param.IsEmailConfirmed); !ok {
+		return
+	}
+	if !validator.ValidateNewPassword(param.Password, "password") {
+		return
+	}
 
 	// Get the Redis connection and defer closing connection.
 	redisConn := redis.GetConnection()
@@ -243,7 +295,7 @@ func Register(w http.ResponseWriter, r *http.Request, _ httprouter.Params, ctx C
 	accRepo := repository.NewCstAccountRepo(redisConn)
 	exists, err := accRepo.ExistsByEmail(param.Email)
 	if exists {
-		msg = "The email address is already registered"
+		msg = i18n.NewMessage(i18n.MsgAccountEmailRegistered)
 		field = "email"
 		response := api.NewAPIResponseWithErrorField(ctx.ReqID, errcode.ReqParamValidationFailed, msg, field)
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
@@ -254,7 +306,7 @@ func Register(w http.ResponseWriter, r *http.Request, _ httprouter.Params, ctx C
 		} else {
 			err = errInternal
 		}
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, err.Error())
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, i18n.FromError(err))
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		return
 	}
@@ -275,7 +327,7 @@ func Register(w http.ResponseWriter, r *http.Request, _ httprouter.Params, ctx C
 	tx, err := db.Get().Begin()
 	if err != nil {
 		logger.Fatal("db.Begin", logger.FromError(err))
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		return
 	}
@@ -284,57 +336,43 @@ func Register(w http.ResponseWriter, r *http.Request, _ httprouter.Params, ctx C
 	// Insert the new account to database.
 	account.ID, account.CreatedTime, err = dao.NewCstAccountDAO().Insert(tx, account)
 	if err != nil {
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		return
 	}
+	account.PasswordChangedTime = account.CreatedTime
 
 	var accountTOS model.CstAccountTOS
 	if param.IsTOSAccepted {
 		// Insert the account's Terms of Service acceptance to database.
 		accountTOS, err = dao.NewCstAccountTOSDAO().Insert(tx, account.ID)
 		if err != nil {
-			response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
+			response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
 			api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 			return
 		}
 	}
 
-	// Generate OTP for email verification.
-	otpKey, otpCode := otp.GenerateAlphanumeric()
-	expiryTime := time.Now().Add(emailVerificationTTL * time.Second)
-	otpData := model.CstAccountOTP{
-		AccountID:  account.ID,
-		Key:        otpKey,
-		Code:       otpCode,
-		Action:     otp.ActionVerifyEmail,
-		Method:     otp.MethodEmail,
-		Email:      param.Email,
-		ExpiryTime: helper.UnixMillisecond(expiryTime),
-		SendCount:  1,
-	}
-
-	// Insert the OTP to database.
-	otpID, otpCreatedMillis, err := dao.NewCstAccountOTPDAO().InsertOTP(tx, otpData)
+	// Issue the OTP for email verification.
+	otpService := otp.NewService(redisConn)
+	otpData, otpCode, err := otpService.Issue(tx, account, otp.ActionVerifyEmail)
 	if err != nil {
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
-		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
+		sendErrorResult(w, ctx.ReqID, otpErrorResult(err, emailVerificationMessages))
 		return
 	}
-	otpData.ID, otpData.CreatedTime = otpID, otpCreatedMillis
 
 	// Commit database transaction.
 	err = tx.Commit()
 	if err != nil {
 		logger.Fatal("tx.Commit", logger.FromError(err))
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Message())
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		return
 	}
 
 	// Save to Redis.
 	accRepo.RedisStore().Save(account)
-	redisstore.NewCstAccountOTPStore(redisConn).SaveOTPByAccountAndAction(otpData, emailVerificationTTL*2)
+	otpService.Save(otpData)
 	accountTOSStore := redisstore.NewCstAccountTOSStore(redisConn)
 	if param.IsTOSAccepted && accountTOS.ID != 0 {
 		accountTOSStore.Save(accountTOS)
@@ -344,37 +382,42 @@ func Register(w http.ResponseWriter, r *http.Request, _ httprouter.Params, ctx C
 
 	if !account.IsEmailVerified {
 		// Send verification email.
-		go sendVerificationEmail(account, otpData)
+		go SendVerificationEmail(account, otpData, otpCode)
 	}
 
 	// Return the response.
 	data := RegisterResponseData{
 		Success:    true,
 		Message:    "",
-		OTPID:      otpID,
-		OTPKey:     otpKey,
-		CodeLength: otp.Length,
+		OTPID:      otpData.ID,
+		OTPKey:     otpData.Key,
+		CodeLength: int32(len(otpCode)),
 	}
 	response := api.NewAPIResponse(ctx.ReqID)
 	response.SetData(data)
 	api.SendResponseJSON(w, response)
 }
 
-func sendVerificationEmail(account model.CstAccount, otpData model.CstAccountOTP) {
-	tokenData := emailVerificationToken{otpData.ID, otpData.Key, otpData.Code, account.Email}
-	tokenString, err := tokenData.Encode()
+// SendVerificationEmail sends the email verification link and code of an OTP to an account.
+// It's shared with the reminders of unverified accounts.
+func SendVerificationEmail(account model.CstAccount, otpData model.CstAccountOTP, otpCode string) {
+	tokenData := emailVerificationToken{otpData.ID, otpData.Key, otpCode, account.Email}
+	tokenString, err := tokenData.Encode(otpData.ExpiryTime)
 	if err != nil {
-		logger.Fatal("api", fmt.Sprintf("sendVerificationEmail: %v", err))
+		logger.Fatal("api", fmt.Sprintf("SendVerificationEmail: %v", err))
 		return
 	}
 	q := url.Values{"token": []string{tokenString}}
 	link := os.Getenv(envvar.FrontendURL) + "/verify/email?" + q.Encode()
+	if webpage.Enabled() {
+		link = webpage.URL(webpage.PathVerifyEmail, q)
+	}
 
 	/* data := struct{ Name, Code, Link, TTLHours string }{
 		Name:     account.FullName,
 		Link:     os.Getenv(envvar.FrontendURL) + "/verify/email?" + q.Encode(),
-		Code:     otpData.Code,
-		TTLHours: helper.IntToString(emailVerificationTTL / 3600),
+		Code:     otpCode,
+		TTLHours: helper.IntToString(otpTTLHours(otpData.Action)),
 	}
 
 	subject := "Please verify your email address"
@@ -405,18 +448,24 @@ func sendVerificationEmail(account model.CstAccount, otpData model.CstAccountOTP
 		</html>`
 	t, err := template.New("emailVerificationTemplate").Parse(body)
 	if err != nil {
-		logger.Fatal("api", fmt.Sprintf("sendVerificationEmail: %v", err))
+		logger.Fatal("api", fmt.Sprintf("SendVerificationEmail: %v", err))
 		return
 	}
 
 	var buf bytes.Buffer
 	err = t.Execute(&buf, data)
 	if err != nil {
-		logger.Fatal("api", fmt.Sprintf("sendVerificationEmail: %v", err))
+		logger.Fatal("api", fmt.Sprintf("SendVerificationEmail: %v", err))
 		return
 	} */
 
-	subject, body, err := emailtemplate.VerifyEmailAddress(account.FullName, link, otpData.Code, emailVerificationTTL/3600)
+	// Use the account's locale and time zone.
+	redisConn := redis.GetConnection()
+	prefs := preference.GetOrDefault(redisConn, account.ID)
+	redisConn.Close()
+	recipient := emailtemplate.NewRecipient(account.FullName, prefs)
+
+	subject, body, err := emailtemplate.VerifyEmailAddress(recipient, link, otpCode, otpTTLHours(otpData.Action))
 	if err == nil {
 		email.Send(email.NewHTMLMessage(subject, body), email.Recipients{
 			To: []string{account.Email},
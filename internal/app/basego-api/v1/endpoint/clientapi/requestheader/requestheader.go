@@ -1,15 +1,16 @@
 package requestheader
 
 import (
-	"errors"
 	"net/http"
 	"strings"
 
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
 	"github.com/jonylim/basego/internal/pkg/common/platform"
 )
 
 // APIRequestHeader defines data passed to request header.
 type APIRequestHeader struct {
+	AcceptLanguage string
 	APIKey         string
 	AppIdentifier  string
 	ContentType    string
@@ -22,6 +23,7 @@ type APIRequestHeader struct {
 // Parse parses the API request headers.
 func Parse(r *http.Request) APIRequestHeader {
 	h := APIRequestHeader{
+		AcceptLanguage: r.Header.Get("Accept-Language"),
 		APIKey:         r.Header.Get("API-Key"),
 		AppIdentifier:  r.Header.Get("App-Identifier"),
 		ContentType:    r.Header.Get("Content-Type"),
@@ -55,10 +57,10 @@ func CheckRequired(h APIRequestHeader) error {
 		keys = append(keys, "Device-Platform")
 	}
 	if len(keys) != 0 {
-		return errors.New("Request headers are required (" + strings.Join(keys, ", ") + ")")
+		return i18n.NewErrorWithParams(i18n.MsgRequestHeadersEmpty, i18n.Params{"headers": strings.Join(keys, ", ")})
 	}
 	if !platform.IsValidClient(h.DevicePlatform) {
-		return errors.New("Request header is invalid (Device-Platform)")
+		return i18n.NewErrorWithParams(i18n.MsgRequestHeaderInvalid, i18n.Params{"header": "Device-Platform"})
 	}
 	return nil
 }
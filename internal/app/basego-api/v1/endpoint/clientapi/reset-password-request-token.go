@@ -40,6 +40,8 @@
  *
  * @apiUse   ErrorClientHeaderValidationFailed
  * @apiError ParamValidationFailed  The parameter validation failed.
+ * @apiError OTPResendCooldown      A new OTP is requested before the resend cooldown has passed.
+ * @apiError OTPSendLimitExceeded   The daily limit of OTPs is reached.
  *
  * @apiErrorExample {json} ParamValidationFailed:
  *     HTTP/1.1 200 OK
@@ -63,22 +65,21 @@ import (
 	"net/http"
 	"net/url"
 	"os"
-	"strings"
-	"time"
 
-	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/dao"
-	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/redisstore"
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/repository"
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/emailtemplate"
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/preference"
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/token/otp"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/webpage"
 	"github.com/jonylim/basego/internal/pkg/common/api"
 	"github.com/jonylim/basego/internal/pkg/common/api/errcode"
 	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
 	"github.com/jonylim/basego/internal/pkg/common/constant/httpstatus"
-	"github.com/jonylim/basego/internal/pkg/common/data/db"
 	"github.com/jonylim/basego/internal/pkg/common/data/redis"
+	"github.com/jonylim/basego/internal/pkg/common/emailvetting"
 	"github.com/jonylim/basego/internal/pkg/common/helper"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
 	"github.com/jonylim/basego/internal/pkg/common/logger"
 	"github.com/jonylim/basego/internal/pkg/common/send/email"
 
@@ -100,8 +101,6 @@ type ResetPasswordRequestTokenResponseData struct {
 	CodeLength int32  `json:"codeLength"`
 }
 
-const emailResetPasswordTTL = 3600
-
 // ResetPasswordRequestToken sends email containing request token for reset password.
 func ResetPasswordRequestToken(w http.ResponseWriter, r *http.Request, _ httprouter.Params, ctx Context) {
 	logger.Trace(ctx.ReqTag, "Handle: clientapi.ResetPasswordRequestToken")
@@ -110,26 +109,48 @@ func ResetPasswordRequestToken(w http.ResponseWriter, r *http.Request, _ httprou
 	errReq := json.NewDecoder(r.Body).Decode(&param)
 	if errReq != nil {
 		logger.Error(ctx.ReqTag, errReq.Error())
-		msg := "Request body format is invalid"
+		msg := i18n.NewMessage(i18n.MsgRequestBodyInvalid)
 		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.ReqParamValidationFailed, msg)
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
 		return
 	}
 
-	var msg, field string
+	res, otpData := RequestResetPasswordToken(param)
+	if res.IsError() {
+		sendErrorResult(w, ctx.ReqID, res)
+		return
+	}
+
+	// Return the response.
+	data := ResetPasswordRequestTokenResponseData{
+		Success:    res.Success,
+		Message:    "",
+		OTPID:      otpData.ID,
+		OTPKey:     otpData.Key,
+		CodeLength: int32(otp.GetPolicy(otp.ActionResetPassword).Length),
+	}
+	response := api.NewAPIResponse(ctx.ReqID)
+	response.SetData(data)
+	api.SendResponseJSON(w, response)
+}
+
+// RequestResetPasswordToken creates the reset password token of an account and sends it by email.
+// It's the logic of Client API "Reset Password - Request Token", shared with the web pages.
+func RequestResetPasswordToken(param ResetPasswordRequestTokenRequestParam) (Result, model.CstAccountOTP) {
+	var otpData model.CstAccountOTP
+	param.Email = emailvetting.Normalize(param.Email)
+	var msg i18n.Message
+	var field string
 	if param.Email == "" {
-		msg = "Email address is required"
+		msg = i18n.NewMessage(i18n.MsgEmailRequired)
 		field = "email"
 	} else if err := helper.ValidateEmailFormat(param.Email); err != nil {
-		msg = err.Error()
+		msg = i18n.FromError(err)
 		field = "email"
 	}
-	if msg != "" {
-		response := api.NewAPIResponseWithErrorField(ctx.ReqID, errcode.ReqParamValidationFailed, msg, field)
-		api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
-		return
+	if !msg.IsEmpty() {
+		return newErrorResult(httpstatus.BadRequest, errcode.ReqParamValidationFailed, msg, field), otpData
 	}
-	param.Email = strings.ToLower(param.Email)
 
 	// Get the Redis connection and defer closing connection.
 	redisConn := redis.GetConnection()
@@ -140,97 +161,46 @@ func ResetPasswordRequestToken(w http.ResponseWriter, r *http.Request, _ httprou
 	account, err := accRepo.GetByEmail(param.Email)
 	if err != nil {
 		if err == accRepo.ErrNotFound {
-			msg = "The email address is invalid"
-			response := api.NewAPIResponseWithError(ctx.ReqID, errcode.ReqParamValidationFailed, msg)
-			api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
+			msg = i18n.NewMessage(i18n.MsgAccountEmailInvalid)
+			return newErrorResult(httpstatus.BadRequest, errcode.ReqParamValidationFailed, msg, ""), otpData
+		} else if err == accRepo.ErrDatabase {
+			err = errDatabase
 		} else {
-			if err == accRepo.ErrDatabase {
-				err = errDatabase
-			} else {
-				err = errInternal
-			}
-			response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, err.Error())
-			api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
+			err = errInternal
 		}
-		return
-	}
-
-	// Begin database transaction.
-	tx, err := db.Get().Begin()
-	if err != nil {
-		logger.Fatal("db.Begin", logger.FromError(err))
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
-		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
-		return
-	}
-	defer tx.Rollback()
-
-	// Generate OTP for reset password.
-	otpKey, otpCode := otp.GenerateAlphanumeric()
-	expiryTime := time.Now().Add(emailResetPasswordTTL * time.Second)
-	otpData := model.CstAccountOTP{
-		AccountID:  account.ID,
-		Key:        otpKey,
-		Code:       otpCode,
-		Action:     otp.ActionResetPassword,
-		Method:     otp.MethodEmail,
-		Email:      param.Email,
-		ExpiryTime: helper.UnixMillisecond(expiryTime),
-		SendCount:  1,
-	}
-
-	// Insert the OTP to database.
-	otpID, otpCreatedMillis, err := dao.NewCstAccountOTPDAO().InsertOTP(tx, otpData)
-	if err != nil {
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
-		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
-		return
+		return newErrorResult(httpstatus.InternalServerError, errcode.Other, i18n.FromError(err), ""), otpData
 	}
-	otpData.ID, otpData.CreatedTime = otpID, otpCreatedMillis
 
-	// Commit database transaction.
-	err = tx.Commit()
+	// Replace the active OTP for reset password.
+	otpData, otpCode, err := otp.NewService(redisConn).Resend(account, otp.ActionResetPassword)
 	if err != nil {
-		logger.Fatal("tx.Commit", logger.FromError(err))
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
-		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
-		return
+		return otpErrorResult(err, resetPasswordMessages), otpData
 	}
 
-	// Save to Redis.
-	redisstore.NewCstAccountOTPStore(redisConn).SaveOTPByAccountAndAction(otpData, emailResetPasswordTTL*2)
-
 	// Send reset password email.
-	go sendResetPasswordEmail(account, otpData)
+	go sendResetPasswordEmail(account, otpData, otpCode)
 
-	// Return the response.
-	data := ResetPasswordRequestTokenResponseData{
-		Success:    true,
-		Message:    "",
-		OTPID:      otpID,
-		OTPKey:     otpKey,
-		CodeLength: otp.Length,
-	}
-	response := api.NewAPIResponse(ctx.ReqID)
-	response.SetData(data)
-	api.SendResponseJSON(w, response)
+	return newResult(true, i18n.Message{}), otpData
 }
 
-func sendResetPasswordEmail(account model.CstAccount, otpData model.CstAccountOTP) {
-	tokenData := emailResetPasswordToken{otpData.ID, otpData.Key, otpData.Code, account.Email}
-	tokenString, err := tokenData.Encode()
+func sendResetPasswordEmail(account model.CstAccount, otpData model.CstAccountOTP, otpCode string) {
+	tokenData := emailResetPasswordToken{otpData.ID, otpData.Key, otpCode, account.Email}
+	tokenString, err := tokenData.Encode(otpData.ExpiryTime)
 	if err != nil {
 		logger.Fatal("api", fmt.Sprintf("sendResetPasswordEmail: %v", err))
 		return
 	}
 	q := url.Values{"token": []string{tokenString}}
 	link := os.Getenv(envvar.FrontendURL) + "/reset-password?" + q.Encode()
+	if webpage.Enabled() {
+		link = webpage.URL(webpage.PathResetPassword, q)
+	}
 
 	/* data := struct{ Name, Code, Link, TTLHours string }{
 			Name:     account.FullName,
 			Link:     os.Getenv(envvar.FrontendURL) + "/reset-password?" + q.Encode(),
-			Code:     otpData.Code,
-			TTLHours: helper.IntToString(emailResetPasswordTTL / 3600),
+			Code:     otpCode,
+			TTLHours: helper.IntToString(otpTTLHours(otpData.Action)),
 		}
 
 		subject := "Reset password request"
@@ -280,7 +250,13 @@ func sendResetPasswordEmail(account model.CstAccount, otpData model.CstAccountOT
 			return
 		} */
 
-	subject, body, err := emailtemplate.ResetPassword(account.FullName, link, otpData.Code, emailResetPasswordTTL/3600)
+	// Use the account's locale and time zone.
+	redisConn := redis.GetConnection()
+	prefs := preference.GetOrDefault(redisConn, account.ID)
+	redisConn.Close()
+	recipient := emailtemplate.NewRecipient(account.FullName, prefs)
+
+	subject, body, err := emailtemplate.ResetPassword(recipient, link, otpCode, otpTTLHours(otpData.Action))
 	if err == nil {
 		email.Send(email.NewHTMLMessage(subject, body), email.Recipients{
 			To: []string{account.Email},
@@ -7,11 +7,12 @@
  *
  * @apiDescription Set new password for a customer account.
  *
- * @apiParam {long}   otpID    The OTP ID of the reset password token.
- * @apiParam {string} otpKey   The OTP key.
- * @apiParam {string} otpCode  The OTP code.
- * @apiParam {string} email    The account's email address.
- * @apiParam {string} password The new password.
+ * @apiParam {long}   [otpID]    The OTP ID of the reset password token. Required if `token` is not provided.
+ * @apiParam {string} [otpKey]   The OTP key. Required if `token` is not provided.
+ * @apiParam {string} [otpCode]  The OTP code. Required if `token` is not provided.
+ * @apiParam {string} [email]    The account's email address. Required if `token` is not provided.
+ * @apiParam {string} [token]    The token from the reset password email's link. If provided, the OTP parameters and `email` are read from it.
+ * @apiParam {string} password   The new password.
  *
  * @apiParamExample {json} Request Example:
  *     {
@@ -42,7 +43,10 @@
  *
  * @apiUse   ErrorClientHeaderValidationFailed
  * @apiError ParamValidationFailed  The parameter validation failed.
- * @apiError PasswordFormatInvalid  The new password's format is invalid.
+ * @apiError PasswordPolicyViolated The new password violates the password policy.
+ * @apiError OTPAttemptsExceeded    Too many incorrect attempts, the OTP is invalidated.
+ *   `data.violations` lists the violated rules: `required`, `minLength`, `maxLength`,
+ *   `lowercase`, `uppercase`, `number`, `special`, `breached`, or `reused`.
  *
  * @apiErrorExample {json} ParamValidationFailed:
  *     HTTP/1.1 200 OK
@@ -55,16 +59,29 @@
  *       },
  *       "data": {}
  *     }
- * @apiErrorExample {json} PasswordFormatInvalid:
+ * @apiErrorExample {json} PasswordPolicyViolated:
  *     HTTP/1.1 200 OK
  *     {
  *       "status": 400,
  *       "error": {
  *         "code": "40002",
- *         "message": "Password must contain at least 1 lowercase, uppercase, and special characters and 1 number",
+ *         "message": "Password must contain at least 1 uppercase character",
  *         "field": "password"
  *       },
- *       "data": {}
+ *       "data": {
+ *         "violations": [
+ *           {
+ *             "rule": "uppercase",
+ *             "limit": 1,
+ *             "message": "Password must contain at least 1 uppercase character"
+ *           },
+ *           {
+ *             "rule": "special",
+ *             "limit": 1,
+ *             "message": "Password must contain at least 1 special character"
+ *           }
+ *         ]
+ *       }
  *     }
  */
 
@@ -75,8 +92,10 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/jonylim/basego/internal/app/basego-api/v1/requestvalidator"
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/dao"
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/repository"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/notification"
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/token/otp"
 	"github.com/jonylim/basego/internal/pkg/common/api"
 	"github.com/jonylim/basego/internal/pkg/common/api/errcode"
@@ -85,7 +104,9 @@ import (
 	"github.com/jonylim/basego/internal/pkg/common/data/db"
 	"github.com/jonylim/basego/internal/pkg/common/data/redis"
 	"github.com/jonylim/basego/internal/pkg/common/helper"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
 	"github.com/jonylim/basego/internal/pkg/common/logger"
+	"github.com/jonylim/basego/internal/pkg/common/passwordpolicy"
 
 	"github.com/julienschmidt/httprouter"
 )
@@ -97,6 +118,7 @@ type ResetPasswordSetPasswordRequestParam struct {
 	OTPCode  string `json:"otpCode"`
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	Token    string `json:"token"`
 }
 
 // ResetPasswordSetPasswordResponseData represents response data of Client API "Reset Password - Set Password".
@@ -114,39 +136,68 @@ func ResetPasswordSetPassword(w http.ResponseWriter, r *http.Request, _ httprout
 	errReq := json.NewDecoder(r.Body).Decode(&param)
 	if errReq != nil {
 		logger.Error(ctx.ReqTag, errReq.Error())
-		msg := "Request body format is invalid"
+		msg := i18n.NewMessage(i18n.MsgRequestBodyInvalid)
 		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.ReqParamValidationFailed, msg)
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
 		return
 	}
 
-	var msg, field string
+	res := SetPasswordWithResetToken(param)
+	if res.IsError() {
+		sendErrorResult(w, ctx.ReqID, res)
+		return
+	}
+
+	// Return the result.
+	data := ResetPasswordSetPasswordResponseData{
+		Success: res.Success,
+		Message: res.Message.Localize(ctx.Locale),
+	}
+	response := api.NewAPIResponse(ctx.ReqID)
+	response.SetData(data)
+	api.SendResponseJSON(w, response)
+}
+
+// SetPasswordWithResetToken sets new password for a customer account after verifying the reset password token.
+// It's the logic of Client API "Reset Password - Set Password", shared with the web pages.
+func SetPasswordWithResetToken(param ResetPasswordSetPasswordRequestParam) Result {
+	// Read the OTP from the email link's token if provided, the token must be valid before anything else is checked.
+	if param.Token != "" {
+		var t emailResetPasswordToken
+		if err := t.Decode(param.Token); err != nil {
+			return newErrorResult(httpstatus.BadRequest, errcode.ReqParamValidationFailed, i18n.FromError(err), "token")
+		}
+		param.OTPID, param.OTPKey, param.OTPCode, param.Email = t.ID, t.Key, t.Code, t.Email
+	}
+
+	var msg i18n.Message
+	var field string
 	if param.OTPID == 0 {
-		msg = "Token is required"
+		msg = i18n.NewMessage(i18n.MsgResetTokenRequired)
 		field = "otpID"
 	} else if param.OTPKey == "" {
-		msg = "Token is required"
+		msg = i18n.NewMessage(i18n.MsgResetTokenRequired)
 		field = "otpKey"
 	} else if param.OTPCode == "" {
-		msg = "Token is required"
+		msg = i18n.NewMessage(i18n.MsgResetTokenRequired)
 		field = "otpCode"
 	} else if param.Email == "" {
-		msg = "Email address is required"
+		msg = i18n.NewMessage(i18n.MsgEmailRequired)
 		field = "email"
 	} else if err := helper.ValidateEmailFormat(param.Email); err != nil {
-		msg = err.Error()
+		msg = i18n.FromError(err)
 		field = "email"
 	} else if param.Password == "" {
-		msg = "Password is required"
-		field = "password"
-	} else if err := helper.ValidatePasswordFormat(param.Password); err != nil {
-		msg = err.Error()
+		msg = i18n.NewMessage(i18n.MsgPasswordRequired)
 		field = "password"
 	}
-	if msg != "" {
-		response := api.NewAPIResponseWithErrorField(ctx.ReqID, errcode.ReqParamValidationFailed, msg, field)
-		api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
-		return
+	if !msg.IsEmpty() {
+		return newErrorResult(httpstatus.BadRequest, errcode.ReqParamValidationFailed, msg, field)
+	}
+	if violations := passwordpolicy.Get().Validate(param.Password); len(violations) != 0 {
+		res := newErrorResult(httpstatus.BadRequest, errcode.ReqParamValidationFailed, violations.Message(), "password")
+		res.Violations = violations
+		return res
 	}
 
 	// Get the Redis connection and defer closing connection.
@@ -158,95 +209,29 @@ func ResetPasswordSetPassword(w http.ResponseWriter, r *http.Request, _ httprout
 	account, err := accRepo.GetByEmail(param.Email)
 	if err != nil {
 		if err == accRepo.ErrNotFound {
-			response := api.NewAPIResponse(ctx.ReqID)
-			response.SetData(ResetPasswordSetPasswordResponseData{
-				Success: false,
-				Message: "Invalid request",
-			})
-			api.SendResponseJSON(w, response)
-			return
+			return newResult(false, i18n.NewMessage(i18n.MsgRequestInvalid))
 		} else if err == accRepo.ErrDatabase {
 			err = errDatabase
 		} else {
 			err = errInternal
 		}
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, err.Error())
-		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
-		return
+		return newErrorResult(httpstatus.InternalServerError, errcode.Other, i18n.FromError(err), "")
 	}
 
-	// Get active OTP's details.
-	otpRepo := repository.NewCstAccountOTPRepo(redisConn)
-	otpData, err := otpRepo.GetActiveOTPByAccountAndAction(account.ID, otp.ActionResetPassword)
+	// Verify the submitted OTP.
+	otpService := otp.NewService(redisConn)
+	otpData, err := otpService.Verify(account, otp.ActionResetPassword, otp.Submission{ID: param.OTPID, Key: param.OTPKey, Code: param.OTPCode})
 	if err != nil {
-		if err == otpRepo.ErrNotFound {
-			msg = "There is no reset password request found, or the code has expired"
-			response := api.NewAPIResponseWithError(ctx.ReqID, errcode.ReqParamValidationFailed, msg)
-			api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
-		} else {
-			if err == accRepo.ErrDatabase {
-				err = errDatabase
-			} else {
-				err = errInternal
-			}
-			response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, err.Error())
-			api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
-		}
-		return
+		return otpErrorResult(err, resetPasswordMessages)
 	}
 
-	// Validate the submitted OTP.
-	isValidID := true
-	isValidCode := false
-	if otpData.ID != param.OTPID {
-		msg = "Token is invalid"
-		field = "otpID"
-		isValidID = false
-	} else if otpData.Key != param.OTPKey {
-		msg = "Token is invalid"
-		field = "otpKey"
-	} else if otpData.Code != param.OTPCode {
-		msg = "Token is incorrect"
-		field = "otpCode"
-	} else if otpData.Email != account.Email {
-		msg = "Token is invalid"
-		field = "email"
-	} else {
-		isValidCode = true
-	}
-	if isValidID && !isValidCode {
-		// Begin database transaction.
-		tx, err := db.Get().Begin()
-		if err != nil {
-			logger.Fatal("db.Begin", logger.FromError(err))
-			response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
-			api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
-			return
-		}
-		defer tx.Rollback()
-
-		// Increment the OTP's reset password attempt count.
-		attemptCount, err := dao.NewCstAccountOTPDAO().IncrementAttemptCountByID(tx, otpData.ID)
-		if err != nil {
-			otpRepo.RedisStore().DeleteOTPByID(otpData.ID)
-			otpRepo.RedisStore().DeleteOTPByAccountAndAction(otpData.AccountID, otpData.Action)
-		} else {
-			// Commit database transaction.
-			err = tx.Commit()
-			if err != nil {
-				logger.Fatal("tx.Commit", logger.FromError(err))
-			} else {
-				// Update to Redis.
-				otpData.AttemptCount = attemptCount
-				otpData.UpdatedTime = helper.UnixMillisecond(time.Now())
-				otpRepo.RedisStore().SaveOTPByAccountAndAction(otpData, emailResetPasswordTTL)
-			}
-		}
-	}
-	if msg != "" {
-		response := api.NewAPIResponseWithErrorField(ctx.ReqID, errcode.ReqParamValidationFailed, msg, field)
-		api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
-		return
+	// Check the password history before the OTP is used up.
+	if violations, err := requestvalidator.CheckPasswordReuse(account, param.Password); err != nil {
+		return newErrorResult(httpstatus.InternalServerError, errcode.Other, errDatabase.Message(), "")
+	} else if len(violations) != 0 {
+		res := newErrorResult(httpstatus.BadRequest, errcode.ReqParamValidationFailed, violations.Message(), "password")
+		res.Violations = violations
+		return res
 	}
 
 	// Generate new password salt and hash the password.
@@ -256,61 +241,37 @@ func ResetPasswordSetPassword(w http.ResponseWriter, r *http.Request, _ httprout
 	tx, err := db.Get().Begin()
 	if err != nil {
 		logger.Fatal("db.Begin", logger.FromError(err))
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
-		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
-		return
+		return newErrorResult(httpstatus.InternalServerError, errcode.Other, errDatabase.Message(), "")
 	}
 	defer tx.Rollback()
 
 	// Mark the OTP as verified.
-	attemptCount, isVerified, err := dao.NewCstAccountOTPDAO().SetVerified(tx, otpData.ID)
-	if attemptCount == 0 || err != nil {
-		otpRepo.RedisStore().DeleteOTPByID(otpData.ID)
-		otpRepo.RedisStore().DeleteOTPByAccountAndAction(otpData.AccountID, otpData.Action)
-
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
-		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
-		return
+	otpData, err = otpService.Consume(tx, otpData)
+	if err != nil {
+		return otpErrorResult(err, resetPasswordMessages)
 	}
-	otpData.AttemptCount = attemptCount
-	otpData.IsVerified = isVerified
-	otpData.UpdatedTime = helper.UnixMillisecond(time.Now())
 
 	// Save the new password to database.
 	success, err := dao.NewCstAccountDAO().ChangePassword(tx, account.ID, pwdHash, pwdSalt)
 	if err != nil {
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
-		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
-		return
+		return newErrorResult(httpstatus.InternalServerError, errcode.Other, errDatabase.Message(), "")
 	} else if !success {
-		response := api.NewAPIResponse(ctx.ReqID)
-		response.SetData(ResetPasswordSetPasswordResponseData{
-			Success: false,
-			Message: "Failed to change password",
-		})
-		api.SendResponseJSON(w, response)
-		return
+		return newResult(false, i18n.NewMessage(i18n.MsgPasswordChangeFailed))
 	}
 
 	// Commit database transaction.
 	err = tx.Commit()
 	if err != nil {
 		logger.Fatal("tx.Commit", logger.FromError(err))
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
-		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
-		return
+		return newErrorResult(httpstatus.InternalServerError, errcode.Other, errDatabase.Message(), "")
 	}
 
 	// Sync and update to Redis.
 	accRepo.SyncByID(account.ID)
-	otpRepo.RedisStore().SaveOTPByAccountAndAction(otpData, emailResetPasswordTTL)
+	otpService.Save(otpData)
 
-	// Return the result.
-	data := ResetPasswordSetPasswordResponseData{
-		Success: true,
-		Message: "Password changed successfully",
-	}
-	response := api.NewAPIResponse(ctx.ReqID)
-	response.SetData(data)
-	api.SendResponseJSON(w, response)
+	// Send the security notification email.
+	go notification.SendPasswordChanged(account, time.Now())
+
+	return newResult(true, i18n.NewMessage(i18n.MsgPasswordChanged))
 }
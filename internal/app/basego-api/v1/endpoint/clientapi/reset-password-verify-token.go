@@ -7,10 +7,11 @@
  *
  * @apiDescription Verify a reset password token.
  *
- * @apiParam {long}   otpID   The OTP ID of the reset password token.
- * @apiParam {string} otpKey  The OTP key.
- * @apiParam {string} otpCode The OTP code.
- * @apiParam {string} email   The account's email address.
+ * @apiParam {long}   [otpID]   The OTP ID of the reset password token. Required if `token` is not provided.
+ * @apiParam {string} [otpKey]  The OTP key. Required if `token` is not provided.
+ * @apiParam {string} [otpCode] The OTP code. Required if `token` is not provided.
+ * @apiParam {string} [email]   The account's email address. Required if `token` is not provided.
+ * @apiParam {string} [token]   The token from the reset password email's link. If provided, the other parameters are read from it.
  *
  * @apiParamExample {json} Request Example:
  *     {
@@ -40,6 +41,7 @@
  *
  * @apiUse   ErrorClientHeaderValidationFailed
  * @apiError ParamValidationFailed The parameter validation failed.
+ * @apiError OTPAttemptsExceeded   Too many incorrect attempts, the OTP is invalidated.
  *
  * @apiErrorExample {json} ParamValidationFailed:
  *     HTTP/1.1 200 OK
@@ -60,17 +62,15 @@ package clientapi
 import (
 	"encoding/json"
 	"net/http"
-	"time"
 
-	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/dao"
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/repository"
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/token/otp"
 	"github.com/jonylim/basego/internal/pkg/common/api"
 	"github.com/jonylim/basego/internal/pkg/common/api/errcode"
 	"github.com/jonylim/basego/internal/pkg/common/constant/httpstatus"
-	"github.com/jonylim/basego/internal/pkg/common/data/db"
 	"github.com/jonylim/basego/internal/pkg/common/data/redis"
 	"github.com/jonylim/basego/internal/pkg/common/helper"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
 	"github.com/jonylim/basego/internal/pkg/common/logger"
 
 	"github.com/julienschmidt/httprouter"
@@ -82,6 +82,7 @@ type ResetPasswordVerifyTokenRequestParam struct {
 	OTPID   int64  `json:"otpID"`
 	OTPKey  string `json:"otpKey"`
 	OTPCode string `json:"otpCode"`
+	Token   string `json:"token"`
 }
 
 // ResetPasswordVerifyTokenResponseData represents response data of Client API "Reset Password - Verify Token".
@@ -99,30 +100,42 @@ func ResetPasswordVerifyToken(w http.ResponseWriter, r *http.Request, _ httprout
 	errReq := json.NewDecoder(r.Body).Decode(&param)
 	if errReq != nil {
 		logger.Error(ctx.ReqTag, errReq.Error())
-		msg := "Request body format is invalid"
+		msg := i18n.NewMessage(i18n.MsgRequestBodyInvalid)
 		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.ReqParamValidationFailed, msg)
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
 		return
 	}
 
-	var msg, field string
+	// Read the OTP from the email link's token if provided, the token must be valid before anything else is checked.
+	if param.Token != "" {
+		var t emailResetPasswordToken
+		if err := t.Decode(param.Token); err != nil {
+			response := api.NewAPIResponseWithErrorField(ctx.ReqID, errcode.ReqParamValidationFailed, i18n.FromError(err), "token")
+			api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
+			return
+		}
+		param.OTPID, param.OTPKey, param.OTPCode, param.Email = t.ID, t.Key, t.Code, t.Email
+	}
+
+	var msg i18n.Message
+	var field string
 	if param.OTPID == 0 {
-		msg = "Token is required"
+		msg = i18n.NewMessage(i18n.MsgResetTokenRequired)
 		field = "otpID"
 	} else if param.OTPKey == "" {
-		msg = "Token is required"
+		msg = i18n.NewMessage(i18n.MsgResetTokenRequired)
 		field = "otpKey"
 	} else if param.OTPCode == "" {
-		msg = "Token is required"
+		msg = i18n.NewMessage(i18n.MsgResetTokenRequired)
 		field = "otpCode"
 	} else if param.Email == "" {
-		msg = "Email address is required"
+		msg = i18n.NewMessage(i18n.MsgEmailRequired)
 		field = "email"
 	} else if err := helper.ValidateEmailFormat(param.Email); err != nil {
-		msg = err.Error()
+		msg = i18n.FromError(err)
 		field = "email"
 	}
-	if msg != "" {
+	if !msg.IsEmpty() {
 		response := api.NewAPIResponseWithErrorField(ctx.ReqID, errcode.ReqParamValidationFailed, msg, field)
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
 		return
@@ -140,7 +153,7 @@ func ResetPasswordVerifyToken(w http.ResponseWriter, r *http.Request, _ httprout
 			response := api.NewAPIResponse(ctx.ReqID)
 			response.SetData(ResetPasswordVerifyTokenResponseData{
 				IsValid: false,
-				Message: "Invalid request",
+				Message: i18n.NewMessage(i18n.MsgRequestInvalid).Localize(ctx.Locale),
 			})
 			api.SendResponseJSON(w, response)
 			return
@@ -149,82 +162,15 @@ func ResetPasswordVerifyToken(w http.ResponseWriter, r *http.Request, _ httprout
 		} else {
 			err = errInternal
 		}
-		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, err.Error())
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, i18n.FromError(err))
 		api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
 		return
 	}
 
-	// Get active OTP's details.
-	otpRepo := repository.NewCstAccountOTPRepo(redisConn)
-	otpData, err := otpRepo.GetActiveOTPByAccountAndAction(account.ID, otp.ActionResetPassword)
-	if err != nil {
-		if err == otpRepo.ErrNotFound {
-			msg = "There is no reset password request found, or the code has expired"
-			response := api.NewAPIResponseWithError(ctx.ReqID, errcode.ReqParamValidationFailed, msg)
-			api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
-		} else {
-			if err == accRepo.ErrDatabase {
-				err = errDatabase
-			} else {
-				err = errInternal
-			}
-			response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, err.Error())
-			api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
-		}
-		return
-	}
-
-	// Validate the submitted OTP.
-	isValidID := true
-	isValidCode := false
-	if otpData.ID != param.OTPID {
-		msg = "Token is invalid"
-		field = "otpID"
-		isValidID = false
-	} else if otpData.Key != param.OTPKey {
-		msg = "Token is invalid"
-		field = "otpKey"
-	} else if otpData.Code != param.OTPCode {
-		msg = "Token is incorrect"
-		field = "otpCode"
-	} else if otpData.Email != account.Email {
-		msg = "Token is invalid"
-		field = "email"
-	} else {
-		isValidCode = true
-	}
-	if isValidID && !isValidCode {
-		// Begin database transaction.
-		tx, err := db.Get().Begin()
-		if err != nil {
-			logger.Fatal("db.Begin", logger.FromError(err))
-			response := api.NewAPIResponseWithError(ctx.ReqID, errcode.Other, errDatabase.Error())
-			api.SendResponseJSONWithStatusCode(w, response, httpstatus.InternalServerError)
-			return
-		}
-		defer tx.Rollback()
-
-		// Increment the OTP's reset password attempt count.
-		attemptCount, err := dao.NewCstAccountOTPDAO().IncrementAttemptCountByID(tx, otpData.ID)
-		if err != nil {
-			otpRepo.RedisStore().DeleteOTPByID(otpData.ID)
-			otpRepo.RedisStore().DeleteOTPByAccountAndAction(otpData.AccountID, otpData.Action)
-		} else {
-			// Commit database transaction.
-			err = tx.Commit()
-			if err != nil {
-				logger.Fatal("tx.Commit", logger.FromError(err))
-			} else {
-				// Update to Redis.
-				otpData.AttemptCount = attemptCount
-				otpData.UpdatedTime = helper.UnixMillisecond(time.Now())
-				otpRepo.RedisStore().SaveOTPByAccountAndAction(otpData, emailResetPasswordTTL)
-			}
-		}
-	}
-	if msg != "" {
-		response := api.NewAPIResponseWithErrorField(ctx.ReqID, errcode.ReqParamValidationFailed, msg, field)
-		api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
+	// Verify the submitted OTP, without using it up.
+	otpSub := otp.Submission{ID: param.OTPID, Key: param.OTPKey, Code: param.OTPCode}
+	if _, err := otp.NewService(redisConn).Verify(account, otp.ActionResetPassword, otpSub); err != nil {
+		sendErrorResult(w, ctx.ReqID, otpErrorResult(err, resetPasswordMessages))
 		return
 	}
 
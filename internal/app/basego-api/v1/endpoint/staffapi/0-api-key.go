@@ -0,0 +1,139 @@
+package staffapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/apikey"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/common/api"
+	"github.com/jonylim/basego/internal/pkg/common/api/errcode"
+	"github.com/jonylim/basego/internal/pkg/common/constant/httpstatus"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+)
+
+// APIKeyData represents an API key in the responses of Staff API, without its secrets.
+type APIKeyData struct {
+	KeyID                    string   `json:"keyID"`
+	Domain                   string   `json:"domain"`
+	AppPlatform              string   `json:"appPlatform"`
+	AppIdentifier            string   `json:"appIdentifier"`
+	ExpiryTime               int64    `json:"expiryTime"`
+	IsEnabled                bool     `json:"isEnabled"`
+	RequireSignature         bool     `json:"requireSignature"`
+	Scopes                   []string `json:"scopes"`
+	DailyQuota               int64    `json:"dailyQuota"`
+	MonthlyQuota             int64    `json:"monthlyQuota"`
+	PreviousSecretExpiryTime int64    `json:"previousSecretExpiryTime"`
+	CreatedTime              int64    `json:"createdTime"`
+	UpdatedTime              int64    `json:"updatedTime"`
+}
+
+func newAPIKeyData(k model.XAPIKey) APIKeyData {
+	return APIKeyData{
+		KeyID:                    k.APIKeyID,
+		Domain:                   k.Domain,
+		AppPlatform:              k.AppPlatform,
+		AppIdentifier:            k.AppIdentifier,
+		ExpiryTime:               k.ExpiryTime,
+		IsEnabled:                k.IsEnabled,
+		RequireSignature:         k.RequireSignature,
+		Scopes:                   apikey.ParseScopes(k.Scopes),
+		DailyQuota:               k.DailyQuota,
+		MonthlyQuota:             k.MonthlyQuota,
+		PreviousSecretExpiryTime: k.PreviousSecretExpiryTime,
+		CreatedTime:              k.CreatedTime,
+		UpdatedTime:              k.UpdatedTime,
+	}
+}
+
+// APIKeyResponseData represents response data of the Staff APIs changing an API key.
+type APIKeyResponseData struct {
+	api.ResponseData
+	APIKey APIKeyData `json:"apiKey"`
+}
+
+// APIKeySecretResponseData represents response data of the Staff APIs generating an API key's secret.
+// The secret is only returned once, only its hash is saved.
+type APIKeySecretResponseData struct {
+	api.ResponseData
+	APIKey     APIKeyData `json:"apiKey"`
+	Secret     string     `json:"secret"`
+	APIKeyStr  string     `json:"apiKeyString"`
+	SigningKey string     `json:"signingKey"`
+}
+
+func newAPIKeySecretResponseData(k model.XAPIKey, secret string) APIKeySecretResponseData {
+	return APIKeySecretResponseData{
+		APIKey:     newAPIKeyData(k),
+		Secret:     secret,
+		APIKeyStr:  apikey.Encode(k.APIKeyID, secret),
+		SigningKey: apikey.SigningKey(secret),
+	}
+}
+
+// apiKeyRequestParam is the part of the request bodies identifying an API key.
+type apiKeyRequestParam struct {
+	Domain string `json:"domain"`
+	KeyID  string `json:"keyID"`
+}
+
+// decodeParam decodes the request body, and validates the domain, defaulting to the customer domain.
+// The key ID is validated if requireKeyID is true. The response is sent if it fails.
+func decodeParam(w http.ResponseWriter, r *http.Request, ctx Context, param interface{}, key *apiKeyRequestParam, requireKeyID bool) bool {
+	if err := json.NewDecoder(r.Body).Decode(param); err != nil {
+		logger.Error(ctx.ReqTag, err.Error())
+		msg := i18n.NewMessage(i18n.MsgRequestBodyInvalid)
+		response := api.NewAPIResponseWithError(ctx.ReqID, errcode.ReqParamValidationFailed, msg)
+		api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
+		return false
+	}
+	if key.Domain == "" {
+		key.Domain = apikey.DomainCustomer
+	}
+
+	var msg i18n.Message
+	var field string
+	if !apikey.IsValidDomain(key.Domain) {
+		msg, field = i18n.NewMessage(i18n.MsgAPIKeyDomainInvalid), "domain"
+	} else if requireKeyID && key.KeyID == "" {
+		msg, field = i18n.NewMessage(i18n.MsgAPIKeyIDRequired), "keyID"
+	}
+	if field != "" {
+		response := api.NewAPIResponseWithErrorField(ctx.ReqID, errcode.ReqParamValidationFailed, msg, field)
+		api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
+		return false
+	}
+	return true
+}
+
+// sendAPIKeyError sends an error of the API key service as the API response.
+func sendAPIKeyError(w http.ResponseWriter, ctx Context, err error) {
+	var statusCode int
+	var code, field string
+	switch err {
+	case apikey.ErrNotFound:
+		statusCode, code, field = httpstatus.NotFound, errcode.ItemNotFound, "keyID"
+	case apikey.ErrPlatformInvalid:
+		statusCode, code, field = httpstatus.BadRequest, errcode.ReqParamValidationFailed, "appPlatform"
+	case apikey.ErrExpiryInvalid:
+		statusCode, code, field = httpstatus.BadRequest, errcode.ReqParamValidationFailed, "expiryTime"
+	case apikey.ErrSigningPlatform:
+		statusCode, code, field = httpstatus.BadRequest, errcode.ReqParamValidationFailed, "requireSignature"
+	case apikey.ErrScopesInvalid:
+		statusCode, code, field = httpstatus.BadRequest, errcode.ReqParamValidationFailed, "scopes"
+	case apikey.ErrDailyQuotaInvalid:
+		statusCode, code, field = httpstatus.BadRequest, errcode.ReqParamValidationFailed, "dailyQuota"
+	case apikey.ErrMonthlyQuotaInvalid:
+		statusCode, code, field = httpstatus.BadRequest, errcode.ReqParamValidationFailed, "monthlyQuota"
+	case apikey.ErrOverlapInvalid:
+		statusCode, code, field = httpstatus.BadRequest, errcode.ReqParamValidationFailed, "overlapHours"
+	case apikey.ErrDatabase:
+		err, statusCode, code = errDatabase, httpstatus.InternalServerError, errcode.Other
+	default:
+		err, statusCode, code = errInternal, httpstatus.InternalServerError, errcode.Other
+	}
+	response := api.NewAPIResponseWithErrorField(ctx.ReqID, code, i18n.FromError(err), field)
+	api.SendResponseJSONWithStatusCode(w, response, statusCode)
+}
@@ -0,0 +1,159 @@
+/**
+ * @apiDefine StaffAPI Staff API
+ *
+ * The staff APIs are for the internal tools, e.g. the back office. They require an API key of the `internal` domain
+ * with the `server` platform, never give it to the client apps.
+ *
+ * #### HTTP Request Headers
+ * | **Header Name**   | **Required** | **Description** |
+ * |-------------------|:------------:|-----------------|
+ * | Accept-Language   |   | The language of the error messages. Values are `en` (default) or `id`. |
+ * | API-Key           | ✓ | API key of the `internal` domain for accessing the API. |
+ * | App-Identifier    |   | The tool's identifier, if the API key is restricted to one. |
+ * | Content-Type      |   | Content type of the request body. |
+ * | Signature         |   | The request's signature, required if the API key requires signatures. See below. |
+ * | User-Agent        |   | The user agent of the client accessing the API. |
+ *
+ * #### HTTP Response Status Codes
+ * | **Code** | **Description**                                                                                        |
+ * |:--------:|--------------------------------------------------------------------------------------------------------|
+ * |   200    | OK, request proceed without error.                                                                     |
+ * |   400    | Bad request, either the request header or the API parameter validation failed.                         |
+ * |   404    | Not found, requested resource does not exist.                                                          |
+ * |   429    | Too many requests sent in a given amount of time, intended for use with rate-limiting schemes.         |
+ * |   491    | The API key specified in HTTP request header `API-Key` is invalid.                                     |
+ * |   500    | Internal server error while processing the request.                                                    |
+ *
+ * #### API Error Codes
+ * | **Code** | **Description**                                                                                        |
+ * |:--------:|--------------------------------------------------------------------------------------------------------|
+ * |  40001   | HTTP request header validation failed.                                                                 |
+ * |  40002   | API request parameter validation failed.                                                               |
+ * |  40401   | The requested resource is not found.                                                                   |
+ * |  42901   | Too many requests, the rate limit is exceeded. Retry after the number of seconds in `Retry-After`.     |
+ * |  49101   | The API key is not provided.                                                                           |
+ * |  49102   | Failed to parse the API key, or the API key is invalid.                                                |
+ * |  49103   | The provided API key is not found.                                                                     |
+ * |  49104   | The provided API key is not an `internal` key for the `server` platform.                               |
+ * |  49105   | The provided API key is not intended to be used with the client's app identifier.                      |
+ * |  49106   | The API key has expired.                                                                               |
+ * |  49107   | The API key is disabled.                                                                               |
+ * |  49108   | The API key requires signatures, but the request isn't signed.                                         |
+ * |  49109   | The signature is invalid, too far from the server time, or has already been used.                      |
+ * |  49110   | The API key is not allowed to call the API, it's out of the key's scopes.                              |
+ * |  49111   | The API key's daily or monthly quota is used up. Retry after the number of seconds in `Retry-After`.   |
+ * |  50001   | An error occurred while validating the API key.                                                        |
+ * |  99999   | Other errors, usually without specific reason or action.                                               |
+ *
+ * #### Signed Requests
+ * A request is signed with the signing key, the hex of the SHA-256 of the API key's secret, in the header
+ * <code>Signature: t=<i>&lt;Unix seconds&gt;</i>,n=<i>&lt;nonce&gt;</i>,s=<i>&lt;signature&gt;</i></code>.
+ * The nonce is 16 to 64 letters, digits, `-` or `_`, unique for each request. The signature is the hex of the
+ * HMAC-SHA256 keyed by the signing key over the lines, joined by `\n`, of the uppercase method, the path with
+ * the query, the timestamp, the nonce, and the hex of the SHA-256 of the body.
+ */
+
+/**
+ * @apiDefine ErrorStaffHeaderValidationFailed
+ * @apiVersion 1.0.0
+ *
+ * @apiError HeaderValidationFailed The request header validation failed.
+ * @apiErrorExample {json} HeaderValidationFailed:
+ *     HTTP/1.1 200 OK
+ *     {
+ *       "status": 400,
+ *       "error": {
+ *         "code": "40001",
+ *         "message": "Request headers are required (API-Key)",
+ *         "field": ""
+ *       },
+ *       "data": {}
+ *     }
+ */
+
+package staffapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/jonylim/basego/internal/app/basego-api/v1/endpoint/staffapi/requestheader"
+	"github.com/jonylim/basego/internal/app/basego-api/v1/requestvalidator"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/common/api"
+	"github.com/jonylim/basego/internal/pkg/common/api/errcode"
+	"github.com/jonylim/basego/internal/pkg/common/constant/httpstatus"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type (
+	// Context contains a request's context.
+	Context struct {
+		context.Context
+		ReqID     string
+		ReqTag    string
+		Locale    string
+		Path      string
+		ReqHeader requestheader.APIRequestHeader
+		APIKey    model.XAPIKey
+	}
+
+	// Handle handles requests for staff APIs.
+	Handle func(http.ResponseWriter, *http.Request, httprouter.Params, Context)
+)
+
+var (
+	errDatabase = i18n.NewError(i18n.MsgProcessingFailed)
+	errInternal = i18n.NewError(i18n.MsgProcessingFailed)
+)
+
+// Init initializes required variables.
+func Init() {
+}
+
+// HandleRequest handles a request for staff APIs.
+func HandleRequest(w http.ResponseWriter, r *http.Request, p httprouter.Params, handle Handle) {
+	reqID := api.CreateReqID()
+
+	// Send the responses in the requested language.
+	reqHeader := requestheader.Parse(r)
+	locale := i18n.LocaleFromAcceptLanguage(reqHeader.AcceptLanguage)
+	w = api.WithLocale(w, locale)
+
+	// Validate request headers.
+	if err := requestheader.CheckRequired(reqHeader); err != nil {
+		response := api.NewAPIResponseWithError(reqID, errcode.ReqHeaderValidationFailed, i18n.FromError(err))
+		api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
+		return
+	}
+
+	// Validate request's API key, only the internal servers' keys are allowed.
+	validator := requestvalidator.New(w, r, reqID)
+	apiKey, ok := validator.ValidateInternalAPIKey(reqHeader.APIKey, reqHeader.AppIdentifier)
+	if !ok {
+		return
+	}
+
+	// Verify the CAPTCHA if the API requires it.
+	if !validator.ValidateCaptcha() {
+		return
+	}
+
+	// OK!
+	reqTag := fmt.Sprintf("api:%s", reqID)
+	path := r.URL.Path
+	logger.Trace(reqTag, "Path: "+path)
+	handle(w, r, p, Context{
+		Context:   r.Context(),
+		ReqID:     reqID,
+		ReqTag:    reqTag,
+		Locale:    locale,
+		Path:      path,
+		ReqHeader: reqHeader,
+		APIKey:    apiKey,
+	})
+}
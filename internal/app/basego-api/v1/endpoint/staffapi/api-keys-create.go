@@ -0,0 +1,122 @@
+/**
+ * @api           {post} /v1/staff/api_keys/create Create API Key
+ * @apiVersion    1.0.0
+ * @apiName       APIKeysCreate
+ * @apiGroup      StaffAPI
+ * @apiPermission staff
+ * @apiDescription Create an API key with a random key ID and secret.
+ * The secret is only returned in this response.
+ *
+ * @apiParam {string}  [domain="customer"] The key's domain. Values are `customer` or `internal`.
+ * @apiParam {string}  appPlatform         The app platform allowed to use the key.
+ *                                         Values are `android`, `ios`, or `web` for `customer`, and `server` for `internal`.
+ * @apiParam {string}  [appIdentifier]     The app identifier allowed to use the key, empty for any.
+ * @apiParam {number}  expiryTime          The key's expiry time in Unix milliseconds.
+ * @apiParam {boolean} [isEnabled=true]    If the key is enabled.
+ * @apiParam {boolean} [requireSignature=false] If the requests must be signed, only for the `server` platform.
+ * @apiParam {string[]} [scopes]          The endpoints the key may call, empty for every endpoint. Each is `*`,
+ *                                         an API group (e.g. `client`), or an API group and endpoint
+ *                                         (e.g. `client/server_time`), which may end with `/*` (e.g. `client/reset_password/*`).
+ * @apiParam {number}  [dailyQuota=0]      The requests allowed per UTC day, 0 is unlimited.
+ * @apiParam {number}  [monthlyQuota=0]    The requests allowed per UTC month, 0 is unlimited.
+ *
+ * @apiSuccess {object} apiKey       The API key, see <a href="#api-StaffAPI-APIKeysList">List API Keys</a>.
+ * @apiSuccess {string} secret       The API key's secret.
+ * @apiSuccess {string} apiKeyString The value of the `API-Key` header, base64 of `keyID:secret`.
+ * @apiSuccess {string} signingKey   The key signing the requests, the hex of the secret's SHA-256.
+ * @apiSuccessExample {json} Success Response:
+ *     HTTP/1.1 200 OK
+ *     {
+ *       "status": 200,
+ *       "error": {
+ *         "code": "",
+ *         "message": "",
+ *         "field": ""
+ *       },
+ *       "data": {
+ *         "apiKey": {
+ *           "keyID": "6f1c0b8e9d2a4c7f8b3e5a1d0c9f2e4b",
+ *           "domain": "customer",
+ *           "appPlatform": "android",
+ *           "appIdentifier": "com.example.app",
+ *           "expiryTime": 1830297600000,
+ *           "isEnabled": true,
+ *           "requireSignature": false,
+ *           "scopes": ["client/server_time", "auth/access_token/request"],
+ *           "dailyQuota": 10000,
+ *           "monthlyQuota": 0,
+ *           "previousSecretExpiryTime": 0,
+ *           "createdTime": 1767225600000,
+ *           "updatedTime": 1767225600000
+ *         },
+ *         "secret": "0d4e...9a1c",
+ *         "apiKeyString": "NmYxYzBiOGU5ZDJhNGM3ZjhiM2U1YTFkMGM5ZjJlNGI6MGQ0ZS4uLjlhMWM=",
+ *         "signingKey": "5b2f...c3d8"
+ *       }
+ *     }
+ * @apiUse   ErrorStaffHeaderValidationFailed
+ */
+
+package staffapi
+
+import (
+	"net/http"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/apikey"
+	"github.com/jonylim/basego/internal/pkg/common/api"
+	"github.com/jonylim/basego/internal/pkg/common/data/redis"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// APIKeysCreateRequestParam represents request body of Staff API "Create API Key".
+type APIKeysCreateRequestParam struct {
+	apiKeyRequestParam
+	AppPlatform   string `json:"appPlatform"`
+	AppIdentifier string `json:"appIdentifier"`
+	ExpiryTime    int64  `json:"expiryTime"`
+	IsEnabled     *bool  `json:"isEnabled"`
+
+	RequireSignature bool     `json:"requireSignature"`
+	Scopes           []string `json:"scopes"`
+	DailyQuota       int64    `json:"dailyQuota"`
+	MonthlyQuota     int64    `json:"monthlyQuota"`
+}
+
+// APIKeysCreate creates an API key.
+func APIKeysCreate(w http.ResponseWriter, r *http.Request, _ httprouter.Params, ctx Context) {
+	logger.Trace(ctx.ReqTag, "Handle: staffapi.APIKeysCreate")
+
+	var param APIKeysCreateRequestParam
+	if !decodeParam(w, r, ctx, &param, &param.apiKeyRequestParam, false) {
+		return
+	}
+
+	// Get the Redis connection and defer closing connection.
+	redisConn := redis.GetConnection()
+	defer redisConn.Close()
+
+	settings := apikey.Settings{
+		AppPlatform:   param.AppPlatform,
+		AppIdentifier: param.AppIdentifier,
+		ExpiryTime:    param.ExpiryTime,
+		IsEnabled:     param.IsEnabled == nil || *param.IsEnabled,
+
+		RequireSignature: param.RequireSignature,
+		Scopes:           param.Scopes,
+		DailyQuota:       param.DailyQuota,
+		MonthlyQuota:     param.MonthlyQuota,
+	}
+	apiKey, secret, err := apikey.NewService(redisConn, param.Domain).Create(settings)
+	if err != nil {
+		sendAPIKeyError(w, ctx, err)
+		return
+	}
+	logger.Println(ctx.ReqTag, "API key "+apiKey.APIKeyID+" created")
+
+	// Return the response.
+	response := api.NewAPIResponse(ctx.ReqID)
+	response.SetData(newAPIKeySecretResponseData(apiKey, secret))
+	api.SendResponseJSON(w, response)
+}
@@ -0,0 +1,110 @@
+/**
+ * @api           {post} /v1/staff/api_keys/list List API Keys
+ * @apiVersion    1.0.0
+ * @apiName       APIKeysList
+ * @apiGroup      StaffAPI
+ * @apiPermission staff
+ * @apiDescription Get the list of a domain's API keys, without their secrets.
+ *
+ * @apiParam {string} [domain="customer"] The keys' domain. Values are `customer` or `internal`.
+ *
+ * @apiSuccess {object[]} apiKeys                          The list of API keys.
+ * @apiSuccess {string}   apiKeys.keyID                    The API key ID.
+ * @apiSuccess {string}   apiKeys.domain                   The API key's domain.
+ * @apiSuccess {string}   apiKeys.appPlatform              The app platform allowed to use the key.
+ * @apiSuccess {string}   apiKeys.appIdentifier            The app identifier allowed to use the key, empty for any.
+ * @apiSuccess {number}   apiKeys.expiryTime               The key's expiry time in Unix milliseconds.
+ * @apiSuccess {boolean}  apiKeys.isEnabled                If the key is enabled.
+ * @apiSuccess {boolean}  apiKeys.requireSignature         If the requests must be signed.
+ * @apiSuccess {string[]} apiKeys.scopes                   The endpoints the key may call, empty for every endpoint.
+ * @apiSuccess {number}   apiKeys.dailyQuota               The requests allowed per UTC day, 0 is unlimited.
+ * @apiSuccess {number}   apiKeys.monthlyQuota             The requests allowed per UTC month, 0 is unlimited.
+ * @apiSuccess {number}   apiKeys.previousSecretExpiryTime The time the secret replaced by the last rotation stops working, in Unix milliseconds.
+ * @apiSuccess {number}   apiKeys.createdTime              The time the key was created in Unix milliseconds.
+ * @apiSuccess {number}   apiKeys.updatedTime              The time the key was last changed in Unix milliseconds.
+ * @apiSuccessExample {json} Success Response:
+ *     HTTP/1.1 200 OK
+ *     {
+ *       "status": 200,
+ *       "error": {
+ *         "code": "",
+ *         "message": "",
+ *         "field": ""
+ *       },
+ *       "data": {
+ *         "apiKeys": [
+ *           {
+ *             "keyID": "6f1c0b8e9d2a4c7f8b3e5a1d0c9f2e4b",
+ *             "domain": "customer",
+ *             "appPlatform": "android",
+ *             "appIdentifier": "com.example.app",
+ *             "expiryTime": 1830297600000,
+ *             "isEnabled": true,
+ *             "requireSignature": false,
+ *             "scopes": [],
+ *             "dailyQuota": 0,
+ *             "monthlyQuota": 0,
+ *             "previousSecretExpiryTime": 0,
+ *             "createdTime": 1767225600000,
+ *             "updatedTime": 1767225600000
+ *           }
+ *         ]
+ *       }
+ *     }
+ * @apiUse   ErrorStaffHeaderValidationFailed
+ */
+
+package staffapi
+
+import (
+	"net/http"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/apikey"
+	"github.com/jonylim/basego/internal/pkg/common/api"
+	"github.com/jonylim/basego/internal/pkg/common/data/redis"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// APIKeysListRequestParam represents request body of Staff API "List API Keys".
+type APIKeysListRequestParam struct {
+	apiKeyRequestParam
+}
+
+// APIKeysListResponseData represents response data of Staff API "List API Keys".
+type APIKeysListResponseData struct {
+	api.ResponseData
+	APIKeys []APIKeyData `json:"apiKeys"`
+}
+
+// APIKeysList returns the list of a domain's API keys.
+func APIKeysList(w http.ResponseWriter, r *http.Request, _ httprouter.Params, ctx Context) {
+	logger.Trace(ctx.ReqTag, "Handle: staffapi.APIKeysList")
+
+	var param APIKeysListRequestParam
+	if !decodeParam(w, r, ctx, &param, &param.apiKeyRequestParam, false) {
+		return
+	}
+
+	// Get the Redis connection and defer closing connection.
+	redisConn := redis.GetConnection()
+	defer redisConn.Close()
+
+	apiKeys, err := apikey.NewService(redisConn, param.Domain).List()
+	if err != nil {
+		sendAPIKeyError(w, ctx, err)
+		return
+	}
+
+	// Return the response.
+	data := APIKeysListResponseData{
+		APIKeys: make([]APIKeyData, len(apiKeys)),
+	}
+	for i, k := range apiKeys {
+		data.APIKeys[i] = newAPIKeyData(k)
+	}
+	response := api.NewAPIResponse(ctx.ReqID)
+	response.SetData(data)
+	api.SendResponseJSON(w, response)
+}
@@ -0,0 +1,77 @@
+/**
+ * @api           {post} /v1/staff/api_keys/rotate Rotate API Key
+ * @apiVersion    1.0.0
+ * @apiName       APIKeysRotate
+ * @apiGroup      StaffAPI
+ * @apiPermission staff
+ * @apiDescription Replace an API key's secret with a random one. The replaced secret keeps working for the overlap,
+ * so the apps can switch to the new secret. A secret replaced by an earlier rotation stops working immediately.
+ * The new secret is only returned in this response.
+ *
+ * @apiParam {string} [domain="customer"] The key's domain. Values are `customer` or `internal`.
+ * @apiParam {string} keyID               The API key ID.
+ * @apiParam {number} [overlapHours=24]   The hours the replaced secret keeps working, up to 720. 0 stops it immediately.
+ *
+ * @apiSuccess {object} apiKey       The API key, see <a href="#api-StaffAPI-APIKeysList">List API Keys</a>.
+ * @apiSuccess {string} secret       The API key's new secret.
+ * @apiSuccess {string} apiKeyString The new value of the `API-Key` header, base64 of `keyID:secret`.
+ * @apiSuccess {string} signingKey   The new key signing the requests, the hex of the secret's SHA-256.
+ * @apiUse   ErrorStaffHeaderValidationFailed
+ */
+
+package staffapi
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/apikey"
+	"github.com/jonylim/basego/internal/pkg/common/api"
+	"github.com/jonylim/basego/internal/pkg/common/data/redis"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// defaultOverlapHours is the default hours the replaced secret of a rotation keeps working.
+const defaultOverlapHours = 24
+
+// APIKeysRotateRequestParam represents request body of Staff API "Rotate API Key".
+type APIKeysRotateRequestParam struct {
+	apiKeyRequestParam
+	OverlapHours *int `json:"overlapHours"`
+}
+
+// APIKeysRotate replaces an API key's secret.
+func APIKeysRotate(w http.ResponseWriter, r *http.Request, _ httprouter.Params, ctx Context) {
+	logger.Trace(ctx.ReqTag, "Handle: staffapi.APIKeysRotate")
+
+	var param APIKeysRotateRequestParam
+	if !decodeParam(w, r, ctx, &param, &param.apiKeyRequestParam, true) {
+		return
+	}
+	overlapHours := defaultOverlapHours
+	if param.OverlapHours != nil {
+		overlapHours = *param.OverlapHours
+	}
+	if overlapHours < 0 || overlapHours > int(apikey.MaxOverlap.Hours()) {
+		sendAPIKeyError(w, ctx, apikey.ErrOverlapInvalid)
+		return
+	}
+
+	// Get the Redis connection and defer closing connection.
+	redisConn := redis.GetConnection()
+	defer redisConn.Close()
+
+	apiKey, secret, err := apikey.NewService(redisConn, param.Domain).Rotate(param.KeyID, time.Duration(overlapHours)*time.Hour)
+	if err != nil {
+		sendAPIKeyError(w, ctx, err)
+		return
+	}
+	logger.Println(ctx.ReqTag, "API key "+apiKey.APIKeyID+" rotated")
+
+	// Return the response.
+	response := api.NewAPIResponse(ctx.ReqID)
+	response.SetData(newAPIKeySecretResponseData(apiKey, secret))
+	api.SendResponseJSON(w, response)
+}
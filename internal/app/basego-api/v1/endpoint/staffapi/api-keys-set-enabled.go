@@ -0,0 +1,86 @@
+/**
+ * @api           {post} /v1/staff/api_keys/enable Enable API Key
+ * @apiVersion    1.0.0
+ * @apiName       APIKeysEnable
+ * @apiGroup      StaffAPI
+ * @apiPermission staff
+ * @apiDescription Enable an API key.
+ *
+ * @apiParam {string} [domain="customer"] The key's domain. Values are `customer` or `internal`.
+ * @apiParam {string} keyID               The API key ID.
+ *
+ * @apiSuccess {object} apiKey The API key, see <a href="#api-StaffAPI-APIKeysList">List API Keys</a>.
+ * @apiUse   ErrorStaffHeaderValidationFailed
+ */
+
+/**
+ * @api           {post} /v1/staff/api_keys/disable Disable API Key
+ * @apiVersion    1.0.0
+ * @apiName       APIKeysDisable
+ * @apiGroup      StaffAPI
+ * @apiPermission staff
+ * @apiDescription Disable an API key, the requests with it are rejected immediately.
+ *
+ * @apiParam {string} [domain="customer"] The key's domain. Values are `customer` or `internal`.
+ * @apiParam {string} keyID               The API key ID.
+ *
+ * @apiSuccess {object} apiKey The API key, see <a href="#api-StaffAPI-APIKeysList">List API Keys</a>.
+ * @apiUse   ErrorStaffHeaderValidationFailed
+ */
+
+package staffapi
+
+import (
+	"net/http"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/apikey"
+	"github.com/jonylim/basego/internal/pkg/common/api"
+	"github.com/jonylim/basego/internal/pkg/common/data/redis"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// APIKeysSetEnabledRequestParam represents request body of Staff APIs "Enable API Key" and "Disable API Key".
+type APIKeysSetEnabledRequestParam struct {
+	apiKeyRequestParam
+}
+
+// APIKeysEnable enables an API key.
+func APIKeysEnable(w http.ResponseWriter, r *http.Request, _ httprouter.Params, ctx Context) {
+	logger.Trace(ctx.ReqTag, "Handle: staffapi.APIKeysEnable")
+	setAPIKeyEnabled(w, r, ctx, true)
+}
+
+// APIKeysDisable disables an API key.
+func APIKeysDisable(w http.ResponseWriter, r *http.Request, _ httprouter.Params, ctx Context) {
+	logger.Trace(ctx.ReqTag, "Handle: staffapi.APIKeysDisable")
+	setAPIKeyEnabled(w, r, ctx, false)
+}
+
+func setAPIKeyEnabled(w http.ResponseWriter, r *http.Request, ctx Context, enabled bool) {
+	var param APIKeysSetEnabledRequestParam
+	if !decodeParam(w, r, ctx, &param, &param.apiKeyRequestParam, true) {
+		return
+	}
+
+	// Get the Redis connection and defer closing connection.
+	redisConn := redis.GetConnection()
+	defer redisConn.Close()
+
+	apiKey, err := apikey.NewService(redisConn, param.Domain).SetEnabled(param.KeyID, enabled)
+	if err != nil {
+		sendAPIKeyError(w, ctx, err)
+		return
+	}
+	if enabled {
+		logger.Println(ctx.ReqTag, "API key "+apiKey.APIKeyID+" enabled")
+	} else {
+		logger.Println(ctx.ReqTag, "API key "+apiKey.APIKeyID+" disabled")
+	}
+
+	// Return the response.
+	response := api.NewAPIResponse(ctx.ReqID)
+	response.SetData(APIKeyResponseData{APIKey: newAPIKeyData(apiKey)})
+	api.SendResponseJSON(w, response)
+}
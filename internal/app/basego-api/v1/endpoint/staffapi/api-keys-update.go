@@ -0,0 +1,84 @@
+/**
+ * @api           {post} /v1/staff/api_keys/update Update API Key
+ * @apiVersion    1.0.0
+ * @apiName       APIKeysUpdate
+ * @apiGroup      StaffAPI
+ * @apiPermission staff
+ * @apiDescription Change an API key's platform, app identifier, expiry, scopes, quotas, or if the requests must be signed. The omitted fields are unchanged.
+ *
+ * @apiParam {string} [domain="customer"] The key's domain. Values are `customer` or `internal`.
+ * @apiParam {string} keyID               The API key ID.
+ * @apiParam {string} [appPlatform]       The app platform allowed to use the key.
+ * @apiParam {string} [appIdentifier]     The app identifier allowed to use the key, empty for any.
+ * @apiParam {number} [expiryTime]        The key's expiry time in Unix milliseconds.
+ * @apiParam {boolean} [requireSignature] If the requests must be signed, only for the `server` platform.
+ * @apiParam {string[]} [scopes]         The endpoints the key may call, empty for every endpoint,
+ *                                        see <a href="#api-StaffAPI-APIKeysCreate">Create API Key</a>.
+ * @apiParam {number} [dailyQuota]        The requests allowed per UTC day, 0 is unlimited.
+ * @apiParam {number} [monthlyQuota]      The requests allowed per UTC month, 0 is unlimited.
+ *
+ * @apiSuccess {object} apiKey The API key, see <a href="#api-StaffAPI-APIKeysList">List API Keys</a>.
+ * @apiUse   ErrorStaffHeaderValidationFailed
+ */
+
+package staffapi
+
+import (
+	"net/http"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/apikey"
+	"github.com/jonylim/basego/internal/pkg/common/api"
+	"github.com/jonylim/basego/internal/pkg/common/data/redis"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// APIKeysUpdateRequestParam represents request body of Staff API "Update API Key".
+type APIKeysUpdateRequestParam struct {
+	apiKeyRequestParam
+	AppPlatform   *string `json:"appPlatform"`
+	AppIdentifier *string `json:"appIdentifier"`
+	ExpiryTime    *int64  `json:"expiryTime"`
+
+	RequireSignature *bool     `json:"requireSignature"`
+	Scopes           *[]string `json:"scopes"`
+	DailyQuota       *int64    `json:"dailyQuota"`
+	MonthlyQuota     *int64    `json:"monthlyQuota"`
+}
+
+// APIKeysUpdate changes an API key's settings.
+func APIKeysUpdate(w http.ResponseWriter, r *http.Request, _ httprouter.Params, ctx Context) {
+	logger.Trace(ctx.ReqTag, "Handle: staffapi.APIKeysUpdate")
+
+	var param APIKeysUpdateRequestParam
+	if !decodeParam(w, r, ctx, &param, &param.apiKeyRequestParam, true) {
+		return
+	}
+
+	// Get the Redis connection and defer closing connection.
+	redisConn := redis.GetConnection()
+	defer redisConn.Close()
+
+	changes := apikey.Changes{
+		AppPlatform:   param.AppPlatform,
+		AppIdentifier: param.AppIdentifier,
+		ExpiryTime:    param.ExpiryTime,
+
+		RequireSignature: param.RequireSignature,
+		Scopes:           param.Scopes,
+		DailyQuota:       param.DailyQuota,
+		MonthlyQuota:     param.MonthlyQuota,
+	}
+	apiKey, err := apikey.NewService(redisConn, param.Domain).Update(param.KeyID, changes)
+	if err != nil {
+		sendAPIKeyError(w, ctx, err)
+		return
+	}
+	logger.Println(ctx.ReqTag, "API key "+apiKey.APIKeyID+" updated")
+
+	// Return the response.
+	response := api.NewAPIResponse(ctx.ReqID)
+	response.SetData(APIKeyResponseData{APIKey: newAPIKeyData(apiKey)})
+	api.SendResponseJSON(w, response)
+}
@@ -0,0 +1,185 @@
+/**
+ * @api           {post} /v1/staff/api_keys/usage Get API Key Usage
+ * @apiVersion    1.0.0
+ * @apiName       APIKeysUsage
+ * @apiGroup      StaffAPI
+ * @apiPermission staff
+ * @apiDescription Get the requests made with an API key by period, endpoint and status class, and the key's quota usage.
+ * Only the requests authenticated by the key are counted. The series lag behind by the flush interval
+ * (`BASEGO_API_KEY_USAGE_FLUSH_INTERVAL`), the quota counts are live.
+ *
+ * @apiParam {string} [domain="customer"] The key's domain. Values are `customer` or `internal`.
+ * @apiParam {string} keyID               The API key ID.
+ * @apiParam {number} [fromTime]          The start of the series in Unix milliseconds. Defaults to 30 days before `toTime`.
+ * @apiParam {number} [toTime]            The end of the series (exclusive) in Unix milliseconds. Defaults to now.
+ *                                        The range is up to 366 days.
+ * @apiParam {string} [granularity="day"] The periods of the series in UTC. Values are `hour`, `day` or `month`.
+ * @apiParam {string} [endpoint]          Only the requests to the endpoint, e.g. `client/register`.
+ *
+ * @apiSuccess {object}   apiKey                The API key, see <a href="#api-StaffAPI-APIKeysList">List API Keys</a>.
+ * @apiSuccess {object}   quota                 The requests counted against the key's quotas.
+ * @apiSuccess {object}   quota.daily           The quota of the current UTC day.
+ * @apiSuccess {number}   quota.daily.limit     The requests allowed, 0 is unlimited.
+ * @apiSuccess {number}   quota.daily.used      The requests counted.
+ * @apiSuccess {number}   quota.daily.resetTime The time the quota resets in Unix milliseconds.
+ * @apiSuccess {object}   quota.monthly         The quota of the current UTC month, with the same fields as `quota.daily`.
+ * @apiSuccess {object[]} series                The request counts, ordered by period.
+ * @apiSuccess {number}   series.periodStart    The start of the period in Unix milliseconds.
+ * @apiSuccess {string}   series.endpoint       The endpoint, e.g. `client/register`.
+ * @apiSuccess {string}   series.statusClass    The responses' status class, e.g. `2xx`.
+ * @apiSuccess {number}   series.count          The number of requests.
+ * @apiSuccessExample {json} Success Response:
+ *     HTTP/1.1 200 OK
+ *     {
+ *       "status": 200,
+ *       "error": {
+ *         "code": "",
+ *         "message": "",
+ *         "field": ""
+ *       },
+ *       "data": {
+ *         "apiKey": {
+ *           "keyID": "6f1c0b8e9d2a4c7f8b3e5a1d0c9f2e4b",
+ *           ...
+ *         },
+ *         "quota": {
+ *           "daily": { "limit": 10000, "used": 1250, "resetTime": 1767312000000 },
+ *           "monthly": { "limit": 0, "used": 20480, "resetTime": 1769904000000 }
+ *         },
+ *         "series": [
+ *           { "periodStart": 1767139200000, "endpoint": "client/register", "statusClass": "2xx", "count": 120 },
+ *           { "periodStart": 1767139200000, "endpoint": "client/register", "statusClass": "4xx", "count": 8 }
+ *         ]
+ *       }
+ *     }
+ * @apiUse   ErrorStaffHeaderValidationFailed
+ */
+
+package staffapi
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/apikey"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/usage"
+	"github.com/jonylim/basego/internal/pkg/common/api"
+	"github.com/jonylim/basego/internal/pkg/common/api/errcode"
+	"github.com/jonylim/basego/internal/pkg/common/constant/httpstatus"
+	"github.com/jonylim/basego/internal/pkg/common/data/redis"
+	"github.com/jonylim/basego/internal/pkg/common/helper"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// defaultUsageRange is the default time range of the usage series.
+const defaultUsageRange = 30 * 24 * time.Hour
+
+// APIKeysUsageRequestParam represents request body of Staff API "Get API Key Usage".
+type APIKeysUsageRequestParam struct {
+	apiKeyRequestParam
+	FromTime    int64  `json:"fromTime"`
+	ToTime      int64  `json:"toTime"`
+	Granularity string `json:"granularity"`
+	Endpoint    string `json:"endpoint"`
+}
+
+// APIKeyQuotaData represents the usage of an API key's quota.
+type APIKeyQuotaData struct {
+	Limit     int64 `json:"limit"`
+	Used      int64 `json:"used"`
+	ResetTime int64 `json:"resetTime"`
+}
+
+// APIKeyUsageData represents the requests made with an API key in a period, to an endpoint, with a status class.
+type APIKeyUsageData struct {
+	PeriodStart int64  `json:"periodStart"`
+	Endpoint    string `json:"endpoint"`
+	StatusClass string `json:"statusClass"`
+	Count       int64  `json:"count"`
+}
+
+// APIKeysUsageResponseData represents response data of Staff API "Get API Key Usage".
+type APIKeysUsageResponseData struct {
+	api.ResponseData
+	APIKey APIKeyData `json:"apiKey"`
+	Quota  struct {
+		Daily   APIKeyQuotaData `json:"daily"`
+		Monthly APIKeyQuotaData `json:"monthly"`
+	} `json:"quota"`
+	Series []APIKeyUsageData `json:"series"`
+}
+
+// APIKeysUsage returns an API key's usage series and quota usage.
+func APIKeysUsage(w http.ResponseWriter, r *http.Request, _ httprouter.Params, ctx Context) {
+	logger.Trace(ctx.ReqTag, "Handle: staffapi.APIKeysUsage")
+
+	var param APIKeysUsageRequestParam
+	if !decodeParam(w, r, ctx, &param, &param.apiKeyRequestParam, true) {
+		return
+	}
+	now := time.Now()
+	if param.ToTime == 0 {
+		param.ToTime = helper.UnixMillisecond(now)
+	}
+	if param.FromTime == 0 {
+		param.FromTime = param.ToTime - int64(defaultUsageRange/time.Millisecond)
+	}
+	if param.Granularity == "" {
+		param.Granularity = usage.ByDay
+	}
+
+	// Validate the parameters.
+	var msg i18n.Message
+	var field string
+	if param.FromTime >= param.ToTime || param.ToTime-param.FromTime > int64(usage.MaxSeriesRange/time.Millisecond) {
+		msg, field = i18n.NewMessageWithParams(i18n.MsgAPIKeyUsageRangeInvalid, i18n.Params{"maxDays": int(usage.MaxSeriesRange.Hours() / 24)}), "fromTime"
+	} else if !usage.IsValidGranularity(param.Granularity) {
+		msg, field = i18n.NewMessage(i18n.MsgAPIKeyUsageGranularityInvalid), "granularity"
+	}
+	if field != "" {
+		response := api.NewAPIResponseWithErrorField(ctx.ReqID, errcode.ReqParamValidationFailed, msg, field)
+		api.SendResponseJSONWithStatusCode(w, response, httpstatus.BadRequest)
+		return
+	}
+
+	// Get the Redis connection and defer closing connection.
+	redisConn := redis.GetConnection()
+	defer redisConn.Close()
+
+	apiKey, err := apikey.NewService(redisConn, param.Domain).Get(param.KeyID)
+	if err != nil {
+		sendAPIKeyError(w, ctx, err)
+		return
+	}
+	items, err := usage.GetSeries(param.Domain, apiKey.APIKeyID, param.FromTime, param.ToTime, param.Granularity, param.Endpoint)
+	if err != nil {
+		sendAPIKeyError(w, ctx, apikey.ErrDatabase)
+		return
+	}
+	dayCount, monthCount, err := usage.GetQuotaCounts(redisConn, param.Domain, apiKey.APIKeyID, now)
+	if err != nil {
+		logger.Error(ctx.ReqTag, logger.FromError(err))
+		sendAPIKeyError(w, ctx, apikey.ErrInternal)
+		return
+	}
+	dayEnd, monthEnd := usage.QuotaResetTimes(now)
+
+	// Return the response.
+	data := APIKeysUsageResponseData{APIKey: newAPIKeyData(apiKey), Series: make([]APIKeyUsageData, len(items))}
+	data.Quota.Daily = APIKeyQuotaData{Limit: apiKey.DailyQuota, Used: dayCount, ResetTime: helper.UnixMillisecond(dayEnd)}
+	data.Quota.Monthly = APIKeyQuotaData{Limit: apiKey.MonthlyQuota, Used: monthCount, ResetTime: helper.UnixMillisecond(monthEnd)}
+	for i, item := range items {
+		data.Series[i] = APIKeyUsageData{
+			PeriodStart: item.PeriodStart,
+			Endpoint:    item.Endpoint,
+			StatusClass: item.StatusClass,
+			Count:       item.RequestCount,
+		}
+	}
+	response := api.NewAPIResponse(ctx.ReqID)
+	response.SetData(data)
+	api.SendResponseJSON(w, response)
+}
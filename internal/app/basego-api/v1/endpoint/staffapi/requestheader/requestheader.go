@@ -0,0 +1,40 @@
+package requestheader
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
+)
+
+// APIRequestHeader defines data passed to request header.
+type APIRequestHeader struct {
+	AcceptLanguage string
+	APIKey         string
+	AppIdentifier  string
+	ContentType    string
+	UserAgent      string
+}
+
+// Parse parses the API request headers.
+func Parse(r *http.Request) APIRequestHeader {
+	return APIRequestHeader{
+		AcceptLanguage: r.Header.Get("Accept-Language"),
+		APIKey:         r.Header.Get("API-Key"),
+		AppIdentifier:  r.Header.Get("App-Identifier"),
+		ContentType:    r.Header.Get("Content-Type"),
+		UserAgent:      r.UserAgent(),
+	}
+}
+
+// CheckRequired checks required request headers.
+func CheckRequired(h APIRequestHeader) error {
+	var keys []string
+	if h.APIKey == "" {
+		keys = append(keys, "API-Key")
+	}
+	if len(keys) != 0 {
+		return i18n.NewErrorWithParams(i18n.MsgRequestHeadersEmpty, i18n.Params{"headers": strings.Join(keys, ", ")})
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package v1
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/common/api"
+	"github.com/jonylim/basego/internal/pkg/common/api/errcode"
+	"github.com/jonylim/basego/internal/pkg/common/constant/httpstatus"
+	"github.com/jonylim/basego/internal/pkg/common/data/redis"
+	"github.com/jonylim/basego/internal/pkg/common/helper"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+	"github.com/jonylim/basego/internal/pkg/common/ratelimit"
+)
+
+// defaultRateLimitRules are the rate limits used unless overridden by environment variables.
+var defaultRateLimitRules = ratelimit.Rules{
+	{Scope: ratelimit.ScopeAll, By: ratelimit.ByIP, Limit: ratelimit.Limit{Requests: 300, Window: time.Minute}},
+	{Scope: ratelimit.ScopeAll, By: ratelimit.ByAPIKey, Limit: ratelimit.Limit{Requests: 6000, Window: time.Minute}},
+	{Scope: ratelimit.ScopeAll, By: ratelimit.ByAccount, Limit: ratelimit.Limit{Requests: 120, Window: time.Minute}},
+	{Scope: "auth/access_token/request", By: ratelimit.ByIP, Limit: ratelimit.Limit{Requests: 20, Window: time.Minute}},
+	{Scope: "client/register", By: ratelimit.ByIP, Limit: ratelimit.Limit{Requests: 10, Window: time.Hour}},
+	{Scope: "client/account_verification/resend_email", By: ratelimit.ByIP, Limit: ratelimit.Limit{Requests: 10, Window: time.Hour}},
+	{Scope: "client/reset_password/request_token", By: ratelimit.ByIP, Limit: ratelimit.Limit{Requests: 10, Window: time.Hour}},
+	{Scope: "web", By: ratelimit.ByIP, Limit: ratelimit.Limit{Requests: 30, Window: time.Minute}},
+	{Scope: "web/reset_password/request", By: ratelimit.ByIP, Limit: ratelimit.Limit{Requests: 10, Window: time.Hour}},
+}
+
+// checkRateLimit counts a request against the IP address's rate limits of the API and sets the RateLimit headers.
+// The API key's and account's limits are counted by the returned request's context once the request validation
+// authenticates them, see ratelimit.CheckAuthenticated.
+// The boolean is false if the limit is exceeded and the 429 response has been sent.
+func checkRateLimit(w http.ResponseWriter, r *http.Request, apiType, apiName string) (http.ResponseWriter, *http.Request, bool) {
+	config := ratelimit.Get()
+	if !config.Enabled {
+		return w, r, true
+	}
+	w = api.WithLocale(w, i18n.LocaleFromAcceptLanguage(r.Header.Get("Accept-Language")))
+
+	l := &rateLimiter{w: w, rules: config.Rules, apiType: apiType, apiName: apiName}
+	if !l.allow(ratelimit.ByIP, api.GetClientIPAddress(r)) {
+		return w, r, false
+	}
+	return w, ratelimit.WithAuthenticatedCheck(r, func(by, id string) bool {
+		// Only the account APIs are limited by account.
+		if by == ratelimit.ByAccount && apiType != "account" {
+			return true
+		}
+		return l.allow(by, id)
+	}), true
+}
+
+// rateLimiter counts a request against the rate limits of its identities, reporting the strictest result.
+type rateLimiter struct {
+	w                http.ResponseWriter
+	rules            ratelimit.Rules
+	apiType, apiName string
+
+	strictest ratelimit.Result
+	found     bool
+}
+
+// allow counts the request against the limit of an identity and sets the RateLimit headers of the strictest result.
+// The boolean is false if the limit is exceeded and the 429 response has been sent.
+func (l *rateLimiter) allow(by, id string) bool {
+	limit, ok := l.rules.Find(l.apiType, l.apiName, by)
+	if !ok || limit.Requests <= 0 {
+		return true
+	}
+
+	// Get the Redis connection and defer closing connection.
+	redisConn := redis.GetConnection()
+	defer redisConn.Close()
+
+	key := fmt.Sprintf("%s/%s:%s:%s", l.apiType, l.apiName, by, id)
+	res, err := ratelimit.Allow(redisConn, key, limit, time.Now())
+	if err != nil {
+		// Don't block the requests if Redis is unavailable.
+		logger.Error("ratelimit", logger.FromError(err))
+		return true
+	}
+	if l.found && !isStricter(res, l.strictest) {
+		return true
+	}
+	l.strictest, l.found = res, true
+
+	h := l.w.Header()
+	h.Set("RateLimit-Limit", helper.IntToString(res.Limit.Requests))
+	h.Set("RateLimit-Remaining", helper.IntToString(res.Remaining))
+	h.Set("RateLimit-Reset", helper.IntToString(ceilSeconds(res.Reset)))
+	h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", res.Limit.Requests, ceilSeconds(res.Limit.Window)))
+	if res.Allowed {
+		return true
+	}
+
+	seconds := ceilSeconds(res.RetryAfter)
+	h.Set("Retry-After", helper.IntToString(seconds))
+	msg := i18n.NewMessageWithParams(i18n.MsgTooManyRequests, i18n.Params{"seconds": seconds})
+	response := api.NewAPIResponseWithError(api.CreateReqID(), errcode.TooManyRequests, msg)
+	api.SendResponseJSONWithStatusCode(l.w, response, httpstatus.TooManyRequests)
+	return false
+}
+
+// isStricter checks if a result should be reported instead of the current one.
+// A denied result always wins, otherwise the one with the fewest remaining requests.
+func isStricter(res, current ratelimit.Result) bool {
+	if res.Allowed != current.Allowed {
+		return !res.Allowed
+	}
+	if !res.Allowed {
+		return res.RetryAfter > current.RetryAfter
+	}
+	return res.Remaining < current.Remaining
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
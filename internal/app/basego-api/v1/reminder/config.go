@@ -0,0 +1,131 @@
+package reminder
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
+	"github.com/jonylim/basego/internal/pkg/common/helper"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+)
+
+// Config is the reminder scheduler's configuration.
+type Config struct {
+	Enabled  bool
+	Interval time.Duration
+
+	// VerifyEmailDelays are the durations after registration when the unverified accounts are reminded, ascending.
+	VerifyEmailDelays []time.Duration
+
+	// MaxSendCount is the number of verification emails, including the one sent at registration,
+	// after which an account isn't reminded anymore. 0 is unlimited.
+	MaxSendCount int
+
+	// DeleteUnverifiedAfterDays is the number of days after registration when the unverified accounts are deleted.
+	// 0 never deletes them.
+	DeleteUnverifiedAfterDays int
+
+	// BatchSize is the maximum number of accounts processed per step in each run.
+	BatchSize int
+}
+
+// Default returns the default configuration.
+func Default() Config {
+	return Config{
+		Enabled:           false,
+		Interval:          10 * time.Minute,
+		VerifyEmailDelays: []time.Duration{24 * time.Hour, 72 * time.Hour},
+		MaxSendCount:      5,
+		BatchSize:         100,
+	}
+}
+
+var (
+	current = Default()
+	mutex   sync.RWMutex
+)
+
+// Init loads the configuration from environment variables.
+func Init() {
+	c := Default()
+	if s := os.Getenv(envvar.Reminder.Enabled); s != "" {
+		b, err := strconv.ParseBool(strings.TrimSpace(s))
+		if err != nil {
+			logger.Println("reminder", fmt.Sprintf("WARN: %s '%s' is invalid, set to '%v' as default", envvar.Reminder.Enabled, s, c.Enabled))
+		} else {
+			c.Enabled = b
+		}
+	}
+	if s := os.Getenv(envvar.Reminder.Interval); s != "" {
+		d, err := time.ParseDuration(strings.TrimSpace(s))
+		if err != nil || d < time.Minute {
+			logger.Println("reminder", fmt.Sprintf("WARN: %s '%s' is invalid, set to '%v' as default", envvar.Reminder.Interval, s, c.Interval))
+		} else {
+			c.Interval = d
+		}
+	}
+	if s := os.Getenv(envvar.Reminder.VerifyEmailDelays); s != "" {
+		delays, err := ParseDelays(s)
+		if err != nil {
+			logger.Println("reminder", fmt.Sprintf("WARN: %s is invalid, using the default delays: %v", envvar.Reminder.VerifyEmailDelays, err))
+		} else {
+			c.VerifyEmailDelays = delays
+		}
+	}
+	c.MaxSendCount = getEnvInt(envvar.Reminder.MaxSendCount, c.MaxSendCount)
+	c.DeleteUnverifiedAfterDays = getEnvInt(envvar.Reminder.DeleteUnverifiedAfterDays, c.DeleteUnverifiedAfterDays)
+	logger.Println("reminder", fmt.Sprintf("Enabled = %v, Interval = %v, VerifyEmailDelays = %v, MaxSendCount = %d, DeleteUnverifiedAfterDays = %d",
+		c.Enabled, c.Interval, c.VerifyEmailDelays, c.MaxSendCount, c.DeleteUnverifiedAfterDays))
+	Set(c)
+}
+
+// Get returns the active configuration.
+func Get() Config {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return current
+}
+
+// Set replaces the active configuration.
+func Set(c Config) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	current = c
+}
+
+// ParseDelays parses Go durations separated by commas, e.g. "24h, 72h". They must be positive and ascending.
+func ParseDelays(s string) ([]time.Duration, error) {
+	delays := make([]time.Duration, 0)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		d, err := time.ParseDuration(item)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("delay '%s' is invalid", item)
+		}
+		if n := len(delays); n != 0 && d <= delays[n-1] {
+			return nil, fmt.Errorf("delay '%s' must be longer than the previous delay", item)
+		}
+		delays = append(delays, d)
+	}
+	return delays, nil
+}
+
+func getEnvInt(key string, def int) int {
+	s := os.Getenv(key)
+	if s == "" {
+		return def
+	}
+	n, err := helper.StringToInt(s)
+	if err != nil || n < 0 {
+		logger.Println("reminder", fmt.Sprintf("WARN: %s '%s' is invalid, set to '%d' as default", key, s, def))
+		return def
+	}
+	return n
+}
@@ -0,0 +1,42 @@
+package reminder
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseDelays(t *testing.T) {
+	var tests = []struct {
+		s        string
+		expected []time.Duration
+	}{
+		{"24h", []time.Duration{24 * time.Hour}},
+		{"24h, 72h", []time.Duration{24 * time.Hour, 72 * time.Hour}},
+		{" 30m,,6h ", []time.Duration{30 * time.Minute, 6 * time.Hour}},
+		{"", []time.Duration{}},
+	}
+	for _, test := range tests {
+		res, err := ParseDelays(test.s)
+		if err != nil {
+			t.Errorf("ParseDelays(%q) returns error %v", test.s, err)
+		} else if !reflect.DeepEqual(res, test.expected) {
+			t.Errorf("ParseDelays(%q) = %v; expected %v", test.s, res, test.expected)
+		}
+	}
+}
+
+func TestParseDelaysInvalid(t *testing.T) {
+	var tests = []string{
+		"1d",
+		"-1h",
+		"0s",
+		"72h, 24h",
+		"24h, 24h",
+	}
+	for _, s := range tests {
+		if _, err := ParseDelays(s); err == nil {
+			t.Errorf("ParseDelays(%q) = nil error; expected error", s)
+		}
+	}
+}
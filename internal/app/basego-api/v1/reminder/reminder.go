@@ -0,0 +1,172 @@
+// Package reminder runs the scheduled lifecycle emails of customer accounts,
+// e.g. reminding the unverified accounts to verify their email addresses.
+package reminder
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jonylim/basego/internal/app/basego-api/v1/endpoint/clientapi"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/dao"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/repository"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/token/otp"
+	"github.com/jonylim/basego/internal/pkg/common/data/db"
+	"github.com/jonylim/basego/internal/pkg/common/data/redis"
+	"github.com/jonylim/basego/internal/pkg/common/helper"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+
+	redigo "github.com/gomodule/redigo/redis"
+)
+
+// KindVerifyEmail is the kind of the reminders to verify the email address.
+const KindVerifyEmail = "verifyEmail"
+
+// lockKey is the Redis key of the lock, so only one instance runs the reminders at a time.
+const lockKey = "lock:reminder"
+
+// Run runs the reminders every interval until stop is closed. It returns immediately if the reminders are disabled.
+func Run(stop <-chan struct{}) {
+	c := Get()
+	if !c.Enabled {
+		return
+	}
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			RunOnce(Get(), now)
+		}
+	}
+}
+
+// RunOnce sends the due reminders and deletes the expired unverified accounts, if no other instance is running them.
+func RunOnce(c Config, now time.Time) {
+	redisConn := redis.GetConnection()
+	defer redisConn.Close()
+
+	// The lock expires after an interval, in case this instance stops while holding it.
+	token, err := redis.TryLock(redisConn, lockKey, c.Interval)
+	if err != nil {
+		logger.Error("reminder", logger.FromError(err))
+		return
+	} else if token == "" {
+		return
+	}
+	defer redis.Unlock(redisConn, lockKey, token)
+
+	sent := sendVerifyEmailReminders(redisConn, c, now)
+	deleted := deleteUnverifiedAccounts(redisConn, c, now)
+	if sent != 0 || deleted != 0 {
+		logger.Println("reminder", fmt.Sprintf("Sent %d verification reminders, deleted %d unverified accounts", sent, deleted))
+	}
+}
+
+// sendVerifyEmailReminders sends each unverified account the latest step it's due, and returns the number of emails sent.
+// An account registered before the previous steps were sent, e.g. when the reminders are enabled, only gets the latest one.
+func sendVerifyEmailReminders(redisConn redigo.Conn, c Config, now time.Time) (sent int) {
+	accDAO := dao.NewCstAccountDAO()
+	for i, delay := range c.VerifyEmailDelays {
+		step := i + 1
+		var createdAfter int64
+		if step < len(c.VerifyEmailDelays) {
+			createdAfter = helper.UnixMillisecond(now.Add(-c.VerifyEmailDelays[step]))
+		} else if c.DeleteUnverifiedAfterDays > 0 {
+			createdAfter = helper.UnixMillisecond(now.AddDate(0, 0, -c.DeleteUnverifiedAfterDays))
+		}
+		accounts, err := accDAO.GetUnverifiedForReminder(KindVerifyEmail, step, createdAfter, helper.UnixMillisecond(now.Add(-delay)), c.BatchSize)
+		if err != nil {
+			return
+		}
+		for _, account := range accounts {
+			if sendVerifyEmailReminder(redisConn, c, account, step) {
+				sent++
+			}
+		}
+	}
+	return
+}
+
+// sendVerifyEmailReminder replaces the account's verification OTP and emails it, recording the reminder step.
+// An account which has been sent MaxSendCount verification emails is recorded without an email.
+// If the OTP policy's cooldown or daily limit stops it, the step isn't recorded so it's retried on the next run.
+func sendVerifyEmailReminder(redisConn redigo.Conn, c Config, account model.CstAccount, step int) bool {
+	otpDAO := dao.NewCstAccountOTPDAO()
+	sendCount, _, err := otpDAO.CountSentSince(account.ID, otp.ActionVerifyEmail, 0)
+	if err != nil {
+		return false
+	}
+
+	tx, err := db.Get().Begin()
+	if err != nil {
+		logger.Fatal("db.Begin", logger.FromError(err))
+		return false
+	}
+	defer tx.Rollback()
+
+	reminder := model.CstAccountReminder{AccountID: account.ID, Kind: KindVerifyEmail, Step: step}
+	var otpData model.CstAccountOTP
+	var otpCode string
+	otpService := otp.NewService(redisConn)
+	limited := c.MaxSendCount > 0 && sendCount >= c.MaxSendCount
+	if !limited {
+		otpData, otpCode, err = otpService.Issue(tx, account, otp.ActionVerifyEmail)
+		if err != nil {
+			if err != otp.ErrDatabase {
+				logger.Println("reminder", fmt.Sprintf("Account %d, step %d: %v", account.ID, step, err))
+			}
+			return false
+		}
+		reminder.OTPID = otpData.ID
+	}
+	inserted, err := dao.NewCstAccountReminderDAO().Insert(tx, reminder)
+	if err != nil || !inserted {
+		return false
+	}
+	if err = tx.Commit(); err != nil {
+		logger.Fatal("tx.Commit", logger.FromError(err))
+		return false
+	}
+	if limited {
+		return false
+	}
+	otpService.Save(otpData)
+	clientapi.SendVerificationEmail(account, otpData, otpCode)
+	return true
+}
+
+// deleteUnverifiedAccounts soft deletes the accounts still unverified after DeleteUnverifiedAfterDays,
+// and returns the number of accounts deleted.
+func deleteUnverifiedAccounts(redisConn redigo.Conn, c Config, now time.Time) (deleted int) {
+	if c.DeleteUnverifiedAfterDays <= 0 {
+		return
+	}
+	accDAO := dao.NewCstAccountDAO()
+	accounts, err := accDAO.GetUnverifiedCreatedBefore(helper.UnixMillisecond(now.AddDate(0, 0, -c.DeleteUnverifiedAfterDays)), c.BatchSize)
+	if err != nil {
+		return
+	}
+	accStore := repository.NewCstAccountRepo(redisConn).RedisStore()
+	for _, account := range accounts {
+		tx, err := db.Get().Begin()
+		if err != nil {
+			logger.Fatal("db.Begin", logger.FromError(err))
+			return
+		}
+		ok, err := accDAO.SoftDeleteUnverified(tx, account.ID)
+		if err != nil || !ok {
+			tx.Rollback()
+			continue
+		}
+		if err = tx.Commit(); err != nil {
+			logger.Fatal("tx.Commit", logger.FromError(err))
+			continue
+		}
+		accStore.Delete(account)
+		deleted++
+	}
+	return
+}
@@ -0,0 +1,70 @@
+package requestvalidator
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/apikey"
+	"github.com/jonylim/basego/internal/pkg/common/api"
+	"github.com/jonylim/basego/internal/pkg/common/api/errcode"
+	"github.com/jonylim/basego/internal/pkg/common/captcha"
+	"github.com/jonylim/basego/internal/pkg/common/constant/httpstatus"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+)
+
+// maxCaptchaBodySize is the size of the request body read to find the token.
+const maxCaptchaBodySize = 1 << 20
+
+// ValidateCaptcha verifies the CAPTCHA token of a request if the API requires it. It's called once the API key is
+// validated, so the requests without a valid key can't make the server call the provider.
+// The boolean is false if the verification fails and the request should not be processed any further.
+func (v Validator) ValidateCaptcha() bool {
+	group, endpoint, known := apikey.EndpointFromContext(v.ctx)
+	config := captcha.Get()
+	if !known || !config.Requires(group, endpoint) {
+		return true
+	}
+
+	token := v.r.Header.Get(captcha.HeaderName)
+	if token == "" {
+		token = v.readCaptchaTokenFromBody()
+	}
+	err := config.Verifier.Verify(token, api.GetClientIPAddress(v.r))
+	if err == nil {
+		return true
+	}
+
+	if err != captcha.ErrFailed {
+		logger.Error(tag, "ValidateCaptcha: "+logger.FromError(err))
+		v.sendAPIResponseWithError(httpstatus.InternalServerError, errcode.Other, i18n.NewMessage(i18n.MsgProcessingFailed))
+		return false
+	}
+	msg := i18n.NewMessage(i18n.MsgCaptchaFailed)
+	if token == "" {
+		msg = i18n.NewMessage(i18n.MsgCaptchaRequired)
+	}
+	response := api.NewAPIResponseWithErrorField(v.reqID, errcode.CaptchaFailed, msg, captcha.FieldName)
+	api.SendResponseJSONWithStatusCode(v.w, response, httpstatus.BadRequest)
+	return false
+}
+
+// readCaptchaTokenFromBody returns the token field of a JSON request body, leaving the body readable by the handler.
+func (v Validator) readCaptchaTokenFromBody() string {
+	if v.r.Body == nil {
+		return ""
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(v.r.Body, maxCaptchaBodySize))
+	v.r.Body = ioutil.NopCloser(io.MultiReader(bytes.NewReader(body), v.r.Body))
+	if err != nil {
+		return ""
+	}
+	var param map[string]interface{}
+	if json.Unmarshal(body, &param) != nil {
+		return ""
+	}
+	token, _ := param[captcha.FieldName].(string)
+	return token
+}
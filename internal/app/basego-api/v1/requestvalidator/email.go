@@ -0,0 +1,28 @@
+package requestvalidator
+
+import (
+	"github.com/jonylim/basego/internal/pkg/common/api"
+	"github.com/jonylim/basego/internal/pkg/common/api/errcode"
+	"github.com/jonylim/basego/internal/pkg/common/constant/httpstatus"
+	"github.com/jonylim/basego/internal/pkg/common/emailvetting"
+)
+
+// EmailRejectionData represents response data of a new email address which is rejected.
+type EmailRejectionData struct {
+	api.ResponseData
+	Rejection emailvetting.Rejection `json:"rejection"`
+}
+
+// ValidateNewEmail checks a new email address's format, domain and typos, and returns its normalized form.
+// typoConfirmed accepts the address the user keeps after a typo rejection.
+// The boolean is false if the validation fails and the request should not be processed any further.
+func (v Validator) ValidateNewEmail(email, field string, typoConfirmed bool) (string, bool) {
+	email, rejection := emailvetting.Get().Vet(v.ctx, email, typoConfirmed)
+	if rejection != nil {
+		response := api.NewAPIResponseWithErrorField(v.reqID, errcode.ReqParamValidationFailed, rejection.Msg(), field)
+		response.SetData(EmailRejectionData{Rejection: rejection.Localize(api.GetLocale(v.w))})
+		api.SendResponseJSONWithStatusCode(v.w, response, httpstatus.BadRequest)
+		return email, false
+	}
+	return email, true
+}
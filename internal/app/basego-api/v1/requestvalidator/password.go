@@ -0,0 +1,68 @@
+package requestvalidator
+
+import (
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/dao"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/common/api"
+	"github.com/jonylim/basego/internal/pkg/common/api/errcode"
+	"github.com/jonylim/basego/internal/pkg/common/constant/httpstatus"
+	"github.com/jonylim/basego/internal/pkg/common/passwordpolicy"
+)
+
+// PasswordPolicyViolationData represents response data of a password which violates the password policy.
+type PasswordPolicyViolationData struct {
+	api.ResponseData
+	Violations passwordpolicy.Violations `json:"violations"`
+}
+
+// ValidateNewPassword checks if a new password satisfies the password policy's format and breached password rules.
+// The boolean is false if the validation fails and the request should not be processed any further.
+func (v Validator) ValidateNewPassword(plain, field string) bool {
+	if violations := passwordpolicy.Get().Validate(plain); len(violations) != 0 {
+		v.sendPasswordPolicyViolations(violations, field)
+		return false
+	}
+	return true
+}
+
+// ValidatePasswordReuse checks if a new password is not one of the account's recent passwords.
+// The boolean is false if the validation fails and the request should not be processed any further.
+func (v Validator) ValidatePasswordReuse(account model.CstAccount, plain, field string) bool {
+	violations, err := CheckPasswordReuse(account, plain)
+	if err != nil {
+		v.sendAPIResponseWithError(httpstatus.InternalServerError, errcode.Other, errDatabase.Message())
+		return false
+	}
+	if len(violations) != 0 {
+		v.sendPasswordPolicyViolations(violations, field)
+		return false
+	}
+	return true
+}
+
+// CheckPasswordReuse returns the violation if a new password is one of the account's recent passwords.
+func CheckPasswordReuse(account model.CstAccount, plain string) (passwordpolicy.Violations, error) {
+	policy := passwordpolicy.Get()
+	if policy.HistoryCount <= 0 {
+		return nil, nil
+	}
+
+	// The current password counts as the most recent one.
+	previous := []passwordpolicy.HashedPassword{{Hash: account.Password, Salt: account.PasswordSalt}}
+	if policy.HistoryCount > 1 {
+		history, err := dao.NewCstAccountPasswordHistoryDAO().GetRecentByAccountID(account.ID, policy.HistoryCount-1)
+		if err != nil {
+			return nil, err
+		}
+		for _, it := range history {
+			previous = append(previous, passwordpolicy.HashedPassword{Hash: it.Password, Salt: it.PasswordSalt})
+		}
+	}
+	return policy.CheckReuse(plain, previous), nil
+}
+
+func (v Validator) sendPasswordPolicyViolations(violations passwordpolicy.Violations, field string) {
+	response := api.NewAPIResponseWithErrorField(v.reqID, errcode.ReqParamValidationFailed, violations.Message(), field)
+	response.SetData(PasswordPolicyViolationData{Violations: violations.Localize(api.GetLocale(v.w))})
+	api.SendResponseJSONWithStatusCode(v.w, response, httpstatus.BadRequest)
+}
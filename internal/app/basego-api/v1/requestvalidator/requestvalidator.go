@@ -1,31 +1,43 @@
 package requestvalidator
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
-	"errors"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/jonylim/basego/internal/app/basego-api/v1/token/accesstoken"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/apikey"
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/dao"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/redisstore"
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/repository"
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/usage"
 	"github.com/jonylim/basego/internal/pkg/common/api"
 	"github.com/jonylim/basego/internal/pkg/common/api/errcode"
 	"github.com/jonylim/basego/internal/pkg/common/constant/httpstatus"
 	"github.com/jonylim/basego/internal/pkg/common/data/redis"
 	"github.com/jonylim/basego/internal/pkg/common/helper"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
 	"github.com/jonylim/basego/internal/pkg/common/logger"
+	"github.com/jonylim/basego/internal/pkg/common/platform"
+	"github.com/jonylim/basego/internal/pkg/common/ratelimit"
+
+	redigo "github.com/gomodule/redigo/redis"
 )
 
 const tag = "requestvalidator"
-const apiDomain = "customer"
+
+// maxSignedBodySize is the maximum size of a signed request's body.
+const maxSignedBodySize = 10 << 20
 
 var (
-	errDatabase = errors.New("An error occurred while processing your request")
-	errInternal = errors.New("An error occurred while processing your request")
+	errDatabase = i18n.NewError(i18n.MsgProcessingFailed)
+	errInternal = i18n.NewError(i18n.MsgProcessingFailed)
 )
 
 // Validator manages validation for incoming requests.
@@ -41,7 +53,7 @@ func New(w http.ResponseWriter, r *http.Request, reqID string) Validator {
 	return Validator{w, r, r.Context(), reqID}
 }
 
-func (v Validator) sendAPIResponseWithError(statusCode int, errCode, errMsg string) {
+func (v Validator) sendAPIResponseWithError(statusCode int, errCode string, errMsg i18n.Message) {
 	response := api.NewAPIResponseWithError(v.reqID, errCode, errMsg)
 	api.SendResponseJSONWithStatusCode(v.w, response, statusCode)
 	return
@@ -50,8 +62,19 @@ func (v Validator) sendAPIResponseWithError(statusCode int, errCode, errMsg stri
 // ValidateAPIKey checks if an API key string is valid and returns the API key's details.
 // The boolean is false if the API key validation fails and the request should not be processed any further.
 func (v Validator) ValidateAPIKey(apiKeyStr, appIdentifier, appPlatform string) (apiKey model.XAPIKey, ok bool) {
+	return v.validateAPIKey(apikey.DomainCustomer, apiKeyStr, appIdentifier, appPlatform)
+}
+
+// ValidateInternalAPIKey checks if an API key string is a valid key of the internal servers, e.g. for the staff APIs,
+// and returns the API key's details.
+// The boolean is false if the API key validation fails and the request should not be processed any further.
+func (v Validator) ValidateInternalAPIKey(apiKeyStr, appIdentifier string) (apiKey model.XAPIKey, ok bool) {
+	return v.validateAPIKey(apikey.DomainInternal, apiKeyStr, appIdentifier, platform.SERVER)
+}
+
+func (v Validator) validateAPIKey(domain, apiKeyStr, appIdentifier, appPlatform string) (apiKey model.XAPIKey, ok bool) {
 	if apiKeyStr == "" {
-		v.sendAPIResponseWithError(httpstatus.APIKeyInvalid, errcode.APIKeyEmpty, "API-Key is required")
+		v.sendAPIResponseWithError(httpstatus.APIKeyInvalid, errcode.APIKeyEmpty, i18n.NewMessage(i18n.MsgAPIKeyRequired))
 		return
 	}
 
@@ -59,12 +82,12 @@ func (v Validator) ValidateAPIKey(apiKeyStr, appIdentifier, appPlatform string)
 	bytes, err := base64.StdEncoding.DecodeString(apiKeyStr)
 	if err != nil {
 		logger.Error(tag, "ValidateAPIKey: "+logger.FromError(err))
-		v.sendAPIResponseWithError(httpstatus.APIKeyInvalid, errcode.APIKeyInvalid, "API-Key failed to parse")
+		v.sendAPIResponseWithError(httpstatus.APIKeyInvalid, errcode.APIKeyInvalid, i18n.NewMessage(i18n.MsgAPIKeyParseFailed))
 		return
 	}
 	parts := strings.SplitN(string(bytes), ":", 2)
 	if len(parts) < 2 {
-		v.sendAPIResponseWithError(httpstatus.APIKeyInvalid, errcode.APIKeyInvalid, "API-Key format is invalid")
+		v.sendAPIResponseWithError(httpstatus.APIKeyInvalid, errcode.APIKeyInvalid, i18n.NewMessage(i18n.MsgAPIKeyFormatInvalid))
 		return
 	}
 
@@ -77,41 +100,64 @@ func (v Validator) ValidateAPIKey(apiKeyStr, appIdentifier, appPlatform string)
 	defer redisConn.Close()
 
 	// Get the API key.
-	apiKeyRepo := repository.NewAPIKeyRepo(redisConn, apiDomain)
+	apiKeyRepo := repository.NewAPIKeyRepo(redisConn, domain)
 	apiKey, err = apiKeyRepo.GetByAPIKeyID(apiKeyID)
 	if err != nil {
 		if err == apiKeyRepo.ErrNotFound {
-			v.sendAPIResponseWithError(httpstatus.APIKeyInvalid, errcode.APIKeyNotFound, "API-Key is not found")
+			v.sendAPIResponseWithError(httpstatus.APIKeyInvalid, errcode.APIKeyNotFound, i18n.NewMessage(i18n.MsgAPIKeyNotFound))
 			return
 		}
-		v.sendAPIResponseWithError(httpstatus.InternalServerError, errcode.InternalAPIKeyValidationFailed, "An error occurred while validating API-Key")
+		v.sendAPIResponseWithError(httpstatus.InternalServerError, errcode.InternalAPIKeyValidationFailed, i18n.NewMessage(i18n.MsgAPIKeyValidationFailed))
 		return
 	}
 
-	// Validate the API key secret.
-	if apiKey.APIKeyID != apiKeyID || apiKey.APIKeySecret != apiKeySecret {
-		v.sendAPIResponseWithError(httpstatus.APIKeyInvalid, errcode.APIKeyInvalid, "API-Key is invalid")
+	// Validate the API key secret, the previous secret works until the overlap of the last rotation ends.
+	if apiKey.APIKeyID != apiKeyID || !apikey.MatchesSecret(apiKey, apiKeySecret, time.Now()) {
+		v.sendAPIResponseWithError(httpstatus.APIKeyInvalid, errcode.APIKeyInvalid, i18n.NewMessage(i18n.MsgAPIKeyInvalid))
 		return
 	}
+	// The request is authenticated by the key, record it in the key's usage.
+	usage.SetAPIKey(v.ctx, domain, apiKey.APIKeyID)
+
 	// Check if the device platform matches the API key's platform.
 	if apiKey.AppPlatform != appPlatform {
-		v.sendAPIResponseWithError(httpstatus.APIKeyInvalid, errcode.APIKeyAppPlatformInvalid, "API-Key is invalid for the platform")
+		v.sendAPIResponseWithError(httpstatus.APIKeyInvalid, errcode.APIKeyAppPlatformInvalid, i18n.NewMessage(i18n.MsgAPIKeyAppPlatformInvalid))
 		return
 	}
 	// Check if the app identifier matches the API key's.
 	if apiKey.AppIdentifier != "" && apiKey.AppIdentifier != appIdentifier {
-		v.sendAPIResponseWithError(httpstatus.APIKeyInvalid, errcode.APIKeyAppIdentifierInvalid, "API-Key is invalid for the app identifier")
+		v.sendAPIResponseWithError(httpstatus.APIKeyInvalid, errcode.APIKeyAppIdentifierInvalid, i18n.NewMessage(i18n.MsgAPIKeyAppIdentifierInvalid))
 		return
 	}
 	// Check the API key's expiry.
 	now := helper.UnixMillisecond(time.Now())
 	if now >= apiKey.ExpiryTime {
-		v.sendAPIResponseWithError(httpstatus.APIKeyInvalid, errcode.APIKeyExpired, "API-Key has expired")
+		v.sendAPIResponseWithError(httpstatus.APIKeyInvalid, errcode.APIKeyExpired, i18n.NewMessage(i18n.MsgAPIKeyExpired))
 		return
 	}
 	// Check if the API key is enabled.
 	if !apiKey.IsEnabled {
-		v.sendAPIResponseWithError(httpstatus.APIKeyInvalid, errcode.APIKeyDisabled, "API-Key is disabled")
+		v.sendAPIResponseWithError(httpstatus.APIKeyInvalid, errcode.APIKeyDisabled, i18n.NewMessage(i18n.MsgAPIKeyDisabled))
+		return
+	}
+	// Count the request against the API key's rate limits, only once the key is usable.
+	if !ratelimit.CheckAuthenticated(v.ctx, ratelimit.ByAPIKey, apiKey.APIKeyID) {
+		return
+	}
+	// Verify the request's signature if the API key requires it, or if a server signs the request anyway.
+	signature := v.r.Header.Get(apikey.SignatureHeader)
+	if apiKey.RequireSignature || (signature != "" && platform.IsValidServer(apiKey.AppPlatform)) {
+		if !v.verifySignature(redisConn, domain, apiKey, signature) {
+			return
+		}
+	}
+	// Check if the API is in the API key's scopes.
+	if group, endpoint, known := apikey.EndpointFromContext(v.ctx); known && !apikey.AllowsEndpoint(apiKey, group, endpoint) {
+		v.sendAPIResponseWithError(httpstatus.APIKeyInvalid, errcode.APIKeyScopeNotAllowed, i18n.NewMessage(i18n.MsgAPIKeyScopeNotAllowed))
+		return
+	}
+	// Count the request against the API key's quotas.
+	if !v.checkQuota(redisConn, apiKey) {
 		return
 	}
 
@@ -120,6 +166,66 @@ func (v Validator) ValidateAPIKey(apiKeyStr, appIdentifier, appPlatform string)
 	return
 }
 
+// verifySignature checks a request's signature by the API key, and saves its nonce to reject the replays.
+// The boolean is false if the verification fails and the response has been sent.
+func (v Validator) verifySignature(redisConn redigo.Conn, domain string, apiKey model.XAPIKey, signature string) bool {
+	if signature == "" {
+		v.sendAPIResponseWithError(httpstatus.APIKeyInvalid, errcode.APIKeySignatureRequired, i18n.NewMessage(i18n.MsgAPIKeySignatureRequired))
+		return false
+	}
+	sig, err := apikey.ParseSignature(signature)
+	if err != nil {
+		v.sendAPIResponseWithError(httpstatus.APIKeyInvalid, errcode.APIKeySignatureInvalid, i18n.FromError(err))
+		return false
+	}
+
+	// Read the body, leaving it readable by the handler. A body over the limit fails to match the signature.
+	var body []byte
+	if v.r.Body != nil {
+		body, err = ioutil.ReadAll(io.LimitReader(v.r.Body, maxSignedBodySize))
+		v.r.Body = ioutil.NopCloser(io.MultiReader(bytes.NewReader(body), v.r.Body))
+		if err != nil {
+			logger.Error(tag, "verifySignature: "+logger.FromError(err))
+			v.sendAPIResponseWithError(httpstatus.BadRequest, errcode.ReqParamValidationFailed, i18n.NewMessage(i18n.MsgRequestBodyInvalid))
+			return false
+		}
+	}
+
+	c := apikey.Get()
+	if err = sig.Verify(apiKey, v.r.Method, v.r.URL.RequestURI(), body, time.Now(), c.MaxClockSkew); err != nil {
+		v.sendAPIResponseWithError(httpstatus.APIKeyInvalid, errcode.APIKeySignatureInvalid, i18n.FromError(err))
+		return false
+	}
+	saved, err := redisstore.NewXAPIKeyStore(redisConn, domain).SaveNonce(apiKey.APIKeyID, sig.Nonce, c.NonceTTL())
+	if err != nil {
+		v.sendAPIResponseWithError(httpstatus.InternalServerError, errcode.InternalAPIKeyValidationFailed, i18n.NewMessage(i18n.MsgAPIKeyValidationFailed))
+		return false
+	} else if !saved {
+		v.sendAPIResponseWithError(httpstatus.APIKeyInvalid, errcode.APIKeySignatureInvalid, i18n.FromError(apikey.ErrSignatureReplayed))
+		return false
+	}
+	return true
+}
+
+// checkQuota counts a request against the API key's daily and monthly quotas.
+// The boolean is false if a quota is used up and the response has been sent. The request is allowed if Redis fails.
+func (v Validator) checkQuota(redisConn redigo.Conn, apiKey model.XAPIKey) bool {
+	res, err := usage.CheckQuota(redisConn, apiKey, time.Now())
+	if err != nil {
+		logger.Error(tag, "checkQuota: "+logger.FromError(err))
+		return true
+	} else if res.Allowed {
+		return true
+	}
+	msgID := i18n.MsgAPIKeyDailyQuotaExceeded
+	if res.Period == usage.PeriodMonth {
+		msgID = i18n.MsgAPIKeyMonthlyQuotaExceeded
+	}
+	v.w.Header().Set("Retry-After", helper.Int64ToString(int64((res.RetryAfter+time.Second-1)/time.Second)))
+	v.sendAPIResponseWithError(httpstatus.TooManyRequests, errcode.APIKeyQuotaExceeded, i18n.NewMessageWithParams(msgID, i18n.Params{"limit": res.Limit}))
+	return false
+}
+
 // ValidateAccessToken checks if an access token is valid and returns the account session and account's details.
 // The boolean is false if the access token validation fails and the request should not be processed any further.
 func (v Validator) ValidateAccessToken(authorization, deviceID string, apiKey model.XAPIKey) (model.CstAccountSession, model.CstAccount, bool) {
@@ -128,15 +234,15 @@ func (v Validator) ValidateAccessToken(authorization, deviceID string, apiKey mo
 
 	// Get access token from header.
 	if authorization == "" {
-		v.sendAPIResponseWithError(httpstatus.Unauthorized, errcode.AuthorizationEmpty, "Authorization is required")
+		v.sendAPIResponseWithError(httpstatus.Unauthorized, errcode.AuthorizationEmpty, i18n.NewMessage(i18n.MsgAuthorizationRequired))
 		return emptySession, emptyAccount, false
 	}
 	parts := strings.SplitN(authorization, " ", 2)
 	if len(parts) < 2 {
-		v.sendAPIResponseWithError(httpstatus.Unauthorized, errcode.AuthorizationFormatInvalid, "Authorization format is invalid")
+		v.sendAPIResponseWithError(httpstatus.Unauthorized, errcode.AuthorizationFormatInvalid, i18n.NewMessage(i18n.MsgAuthorizationFormatInvalid))
 		return emptySession, emptyAccount, false
 	} else if parts[0] != "Bearer" {
-		v.sendAPIResponseWithError(httpstatus.Unauthorized, errcode.AuthorizationFormatInvalid, "Authorization type is invalid or not supported")
+		v.sendAPIResponseWithError(httpstatus.Unauthorized, errcode.AuthorizationFormatInvalid, i18n.NewMessage(i18n.MsgAuthorizationTypeInvalid))
 		return emptySession, emptyAccount, false
 	}
 
@@ -150,7 +256,7 @@ func (v Validator) ValidateAccessToken(authorization, deviceID string, apiKey mo
 		case accesstoken.ErrTokenExpired:
 			errCode = errcode.AuthorizationTokenExpired
 		}
-		v.sendAPIResponseWithError(httpstatus.Unauthorized, errCode, err.Error())
+		v.sendAPIResponseWithError(httpstatus.Unauthorized, errCode, i18n.FromError(err))
 		return emptySession, emptyAccount, false
 	}
 
@@ -163,7 +269,7 @@ func (v Validator) ValidateAccessToken(authorization, deviceID string, apiKey mo
 	session, sessionToken, err := sessionRepo.GetSessionDetailsBySessionID(claims.SessionID)
 	if err != nil {
 		if err == sessionRepo.ErrNotFound {
-			msg := "Account session is not found"
+			msg := i18n.NewMessage(i18n.MsgAccountSessionNotFound)
 			v.sendAPIResponseWithError(httpstatus.Unauthorized, errcode.AuthorizationTokenInvalid, msg)
 		} else {
 			if err == sessionRepo.ErrDatabase {
@@ -171,7 +277,7 @@ func (v Validator) ValidateAccessToken(authorization, deviceID string, apiKey mo
 			} else {
 				err = errInternal
 			}
-			v.sendAPIResponseWithError(httpstatus.InternalServerError, errcode.Other, err.Error())
+			v.sendAPIResponseWithError(httpstatus.InternalServerError, errcode.Other, i18n.FromError(err))
 		}
 		return emptySession, emptyAccount, false
 	}
@@ -189,7 +295,11 @@ func (v Validator) ValidateAccessToken(authorization, deviceID string, apiKey mo
 		case accesstoken.ErrTokenExpired:
 			errCode = errcode.AuthorizationTokenExpired
 		}
-		v.sendAPIResponseWithError(httpstatus.Unauthorized, errCode, err.Error())
+		v.sendAPIResponseWithError(httpstatus.Unauthorized, errCode, i18n.FromError(err))
+		return emptySession, emptyAccount, false
+	}
+	// The request is authenticated as the account, count it against the account's rate limits.
+	if !ratelimit.CheckAuthenticated(v.ctx, ratelimit.ByAccount, helper.Int64ToString(session.AccountID)) {
 		return emptySession, emptyAccount, false
 	}
 
@@ -198,7 +308,7 @@ func (v Validator) ValidateAccessToken(authorization, deviceID string, apiKey mo
 	account, err := accRepo.GetByID(session.AccountID)
 	if err != nil {
 		if err == accRepo.ErrNotFound {
-			msg := "Account is not found"
+			msg := i18n.NewMessage(i18n.MsgAccountNotFound)
 			v.sendAPIResponseWithError(httpstatus.Unauthorized, errcode.AuthorizationUserNotFound, msg)
 		} else {
 			if err == accRepo.ErrDatabase {
@@ -206,7 +316,7 @@ func (v Validator) ValidateAccessToken(authorization, deviceID string, apiKey mo
 			} else {
 				err = errInternal
 			}
-			v.sendAPIResponseWithError(httpstatus.InternalServerError, errcode.Other, err.Error())
+			v.sendAPIResponseWithError(httpstatus.InternalServerError, errcode.Other, i18n.FromError(err))
 		}
 		return emptySession, emptyAccount, false
 	}
@@ -8,7 +8,9 @@ import (
 
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
 	"github.com/jonylim/basego/internal/pkg/common/crypto/hash"
+	"github.com/jonylim/basego/internal/pkg/common/crypto/secrethash"
 	"github.com/jonylim/basego/internal/pkg/common/helper"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
 	"github.com/jonylim/basego/internal/pkg/common/logger"
 
 	jwt "github.com/dgrijalva/jwt-go"
@@ -44,6 +46,14 @@ func GenerateRefreshToken(sessionID int64) string {
 	return hash
 }
 
+// hashPurpose is the purpose of the refresh tokens' hashes, only the hash of a token string is saved.
+const hashPurpose = "refreshToken"
+
+// HashToken returns the hash of a refresh token string to be saved to database and Redis.
+func HashToken(tokenString string) string {
+	return secrethash.Sum(hashPurpose, tokenString)
+}
+
 // GenerateJWT converts a refresh token string into JWT.
 func GenerateJWT(tokenID int64, tokenString string, issuedAt, expiresAt int64, sessionID int64, accountID int64) (string, error) {
 	strTokenID := helper.Int64ToString(tokenID)
@@ -74,7 +84,7 @@ func ParseJWT(jwtToken string) (*JWTClaims, int, error) {
 		var code = ErrParseFailed
 		errText := err.Error()
 		if errText == jwt.ErrSignatureInvalid.Error() {
-			err = errors.New("Token signature is invalid, please get a new token")
+			err = i18n.NewError(i18n.MsgTokenSignatureInvalid)
 		} else {
 			if claims != nil && claims.VerifyExpiresAt(time.Now().Unix(), false) == false {
 				code = ErrTokenExpired
@@ -83,15 +93,15 @@ func ParseJWT(jwtToken string) (*JWTClaims, int, error) {
 		}
 		return claims, code, err
 	} else if claims == nil {
-		return claims, ErrParseFailed, errors.New("Failed retrieving token detail")
+		return claims, ErrParseFailed, i18n.NewError(i18n.MsgTokenDetailFailed)
 	}
 	return claims, 0, nil
 }
 
 // ValidateState compares the parsed token with saved account session's details and refresh token.
 func (claims *JWTClaims) ValidateState(accountSession model.CstAccountSession, sessionToken model.CstAccountSessionToken, apiKey model.XAPIKey, deviceID string) (int, error) {
-	if claims.TokenString != sessionToken.RefreshToken || claims.TokenID != sessionToken.ID {
-		return ErrTokenInvalid, errors.New("Refresh token is invalid")
+	if !secrethash.Matches(sessionToken.RefreshToken, hashPurpose, claims.TokenString) || claims.TokenID != sessionToken.ID {
+		return ErrTokenInvalid, i18n.NewError(i18n.MsgRefreshTokenInvalid)
 	} else if apiKey.AppPlatform != accountSession.Platform ||
 		deviceID != accountSession.DeviceID {
 		logger.Debug("refreshtoken", "Refresh token does not belong to the device"+
@@ -99,12 +109,12 @@ func (claims *JWTClaims) ValidateState(accountSession model.CstAccountSession, s
 			"\n    Platform-2: "+apiKey.AppPlatform+
 			"\n    DeviceID-1: "+accountSession.DeviceID+
 			"\n    DeviceID-2: "+deviceID)
-		return ErrDeviceInvalid, errors.New("Refresh token does not belong to the device")
+		return ErrDeviceInvalid, i18n.NewError(i18n.MsgRefreshTokenDeviceInvalid)
 	} else if claims.SessionID != accountSession.ID || claims.AccountID != accountSession.AccountID {
-		return ErrNotOwner, errors.New("Refresh token does not belong to the user")
+		return ErrNotOwner, i18n.NewError(i18n.MsgRefreshTokenNotOwner)
 	} else if now := helper.UnixMillisecond(time.Now()); now > sessionToken.RefreshTokenExpiry {
 		logger.Debug("refreshtoken", fmt.Sprintf("ErrTokenExpired: (%v) %v > %v", accountSession.ID, now, sessionToken.RefreshTokenExpiry))
-		return ErrTokenExpired, errors.New("Refresh token is expired")
+		return ErrTokenExpired, i18n.NewError(i18n.MsgRefreshTokenExpired)
 	}
 	return 0, nil
 }
@@ -120,7 +130,7 @@ func parseJWT(jwtToken string) (*JWTClaims, error) {
 		if claims != nil && ok {
 			return createJWTSecretKey(claims.Id), nil
 		}
-		return nil, errors.New("Failed to parse token")
+		return nil, i18n.NewError(i18n.MsgTokenParseFailed)
 	})
 	if token != nil {
 		claims, ok := token.Claims.(*JWTClaims)
@@ -0,0 +1,31 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/usage"
+	"github.com/jonylim/basego/internal/pkg/common/data/redis"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+)
+
+// recordUsage records an API's request in the usage of the API key authenticating it, if any.
+// w must be the response writer returned by usage.Track.
+func recordUsage(w http.ResponseWriter, apiType, apiName string) {
+	t, ok := w.(*usage.Tracker)
+	if !ok {
+		return
+	}
+	domain, apiKeyID := t.APIKey()
+	if apiKeyID == "" {
+		return
+	}
+
+	// Get the Redis connection and defer closing connection.
+	redisConn := redis.GetConnection()
+	defer redisConn.Close()
+
+	if err := usage.Record(redisConn, domain, apiKeyID, apiType+"/"+apiName, t.Status(), time.Now()); err != nil {
+		logger.Error("usage", logger.FromError(err))
+	}
+}
@@ -0,0 +1,157 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/jonylim/basego/internal/app/basego-api/v1/endpoint/clientapi"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/token/linktoken"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/webpage"
+	"github.com/jonylim/basego/internal/pkg/common/captcha"
+	"github.com/jonylim/basego/internal/pkg/common/constant/httpstatus"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// resetPasswordRequestData is the data of page "reset-password-request.html".
+type resetPasswordRequestData struct {
+	Email        string
+	Error        string
+	Captcha      *captcha.Widget
+	CaptchaError string
+}
+
+// resetPasswordData is the data of page "reset-password.html".
+type resetPasswordData struct {
+	Token  string
+	Errors []string
+}
+
+// ResetPasswordRequest shows the form to request a reset password email.
+func ResetPasswordRequest(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	renderResetPasswordRequest(w, httpstatus.OK, getLocale(r), resetPasswordRequestData{})
+}
+
+// ResetPasswordRequestSubmit sends the reset password email, the same as Client API "Reset Password - Request Token".
+func ResetPasswordRequestSubmit(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	locale := getLocale(r)
+	if !allowSubmit(w, r, locale, "reset_password/request", i18n.MsgWebResetPasswordFailed) {
+		return
+	}
+	email := r.PostFormValue("email")
+	if msgID, statusCode, ok := checkCaptcha(r, "reset_password/request"); !ok {
+		renderResetPasswordRequest(w, statusCode, locale, resetPasswordRequestData{
+			Email:        email,
+			CaptchaError: i18n.NewMessage(msgID).Localize(locale),
+		})
+		return
+	}
+
+	res, _ := clientapi.RequestResetPasswordToken(clientapi.ResetPasswordRequestTokenRequestParam{Email: email})
+	if res.IsError() {
+		if res.ErrField == "email" {
+			renderResetPasswordRequest(w, res.StatusCode, locale, resetPasswordRequestData{
+				Email: email,
+				Error: res.Message.Localize(locale),
+			})
+			return
+		} else if res.StatusCode >= httpstatus.InternalServerError {
+			renderResult(w, res.StatusCode, locale, i18n.MsgWebResetPasswordFailed, resultData{
+				Message: res.Message.Localize(locale),
+			})
+			return
+		}
+		// Don't reveal whether the email address is registered, which the OTP resend limits would tell too.
+	}
+	renderResult(w, httpstatus.OK, locale, i18n.MsgWebResetPasswordRequestSent, resultData{
+		Success: true,
+		Message: i18n.NewMessage(i18n.MsgWebResetPasswordRequestSentDescription).Localize(locale),
+	})
+}
+
+// ResetPassword shows the form to set a new password using the token from the reset password email.
+func ResetPassword(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	locale := getLocale(r)
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		renderResetPasswordFailed(w, httpstatus.BadRequest, locale, linktoken.ErrInvalid.Message())
+		return
+	}
+	renderResetPassword(w, httpstatus.OK, locale, resetPasswordData{Token: token})
+}
+
+// ResetPasswordSubmit sets the new password, the same as Client API "Reset Password - Set Password".
+func ResetPasswordSubmit(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	locale := getLocale(r)
+	if !allowSubmit(w, r, locale, "reset_password", i18n.MsgWebResetPasswordFailed) {
+		return
+	}
+	token := r.PostFormValue("token")
+	if token == "" {
+		renderResetPasswordFailed(w, httpstatus.BadRequest, locale, linktoken.ErrInvalid.Message())
+		return
+	}
+	password := r.PostFormValue("password")
+	if password != r.PostFormValue("confirmPassword") {
+		renderResetPassword(w, httpstatus.BadRequest, locale, resetPasswordData{
+			Token:  token,
+			Errors: []string{i18n.NewMessage(i18n.MsgWebPasswordMismatch).Localize(locale)},
+		})
+		return
+	}
+
+	res := clientapi.SetPasswordWithResetToken(clientapi.ResetPasswordSetPasswordRequestParam{Token: token, Password: password})
+	if res.IsError() {
+		if res.ErrField == "password" {
+			// Show all the violated rules, so the user can fix them at once.
+			var errors []string
+			for _, it := range res.Violations.Localize(locale) {
+				errors = append(errors, it.Message)
+			}
+			if len(errors) == 0 {
+				errors = []string{res.Message.Localize(locale)}
+			}
+			renderResetPassword(w, res.StatusCode, locale, resetPasswordData{Token: token, Errors: errors})
+			return
+		}
+		renderResetPasswordFailed(w, res.StatusCode, locale, res.Message)
+		return
+	}
+	if !res.Success {
+		renderResetPasswordFailed(w, httpstatus.OK, locale, res.Message)
+		return
+	}
+	renderResult(w, httpstatus.OK, locale, i18n.MsgWebResetPasswordSucceeded, resultData{
+		Success: true,
+		Message: res.Message.Localize(locale),
+	})
+}
+
+func renderResetPasswordRequest(w http.ResponseWriter, statusCode int, locale string, data resetPasswordRequestData) {
+	page := webpage.Page{
+		Locale: locale,
+		Title:  i18n.NewMessage(i18n.MsgWebResetPasswordRequestTitle).Localize(locale),
+	}
+	if data.Captcha = captchaWidget("reset_password/request"); data.Captcha != nil {
+		page.ExternalOrigins = data.Captcha.Origins
+	}
+	page.Data = data
+	webpage.Render(w, statusCode, "reset-password-request.html", page)
+}
+
+func renderResetPassword(w http.ResponseWriter, statusCode int, locale string, data resetPasswordData) {
+	webpage.Render(w, statusCode, "reset-password.html", webpage.Page{
+		Locale: locale,
+		Title:  i18n.NewMessage(i18n.MsgWebResetPasswordTitle).Localize(locale),
+		Data:   data,
+	})
+}
+
+// renderResetPasswordFailed shows the error with a link to request a new reset password email.
+func renderResetPasswordFailed(w http.ResponseWriter, statusCode int, locale string, msg i18n.Message) {
+	renderResult(w, statusCode, locale, i18n.MsgWebResetPasswordFailed, resultData{
+		Message:  msg.Localize(locale),
+		LinkURL:  webpage.URL(webpage.PathResetPasswordRequest, nil),
+		LinkText: i18n.NewMessage(i18n.MsgWebRequestNewLink).Localize(locale),
+	})
+}
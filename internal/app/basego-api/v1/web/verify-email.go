@@ -0,0 +1,62 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/jonylim/basego/internal/app/basego-api/v1/endpoint/clientapi"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/token/linktoken"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/webpage"
+	"github.com/jonylim/basego/internal/pkg/common/constant/httpstatus"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// VerifyEmail shows the page to confirm the email verification.
+// The verification is only submitted by the button, so a link preview or scanner opening the link doesn't use the token.
+func VerifyEmail(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	locale := getLocale(r)
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		renderResult(w, httpstatus.BadRequest, locale, i18n.MsgWebVerifyEmailFailed, resultData{
+			Message: linktoken.ErrInvalid.Message().Localize(locale),
+		})
+		return
+	}
+	webpage.Render(w, httpstatus.OK, "verify-email.html", webpage.Page{
+		Locale: locale,
+		Title:  i18n.NewMessage(i18n.MsgWebVerifyEmailTitle).Localize(locale),
+		Data:   struct{ Token string }{token},
+	})
+}
+
+// VerifyEmailSubmit verifies the email address using the token, the same as Client API "Account Verification - Submit".
+func VerifyEmailSubmit(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	locale := getLocale(r)
+	if !allowSubmit(w, r, locale, "verify_email", i18n.MsgWebVerifyEmailFailed) {
+		return
+	}
+	token := r.PostFormValue("token")
+	if token == "" {
+		renderResult(w, httpstatus.BadRequest, locale, i18n.MsgWebVerifyEmailFailed, resultData{
+			Message: linktoken.ErrInvalid.Message().Localize(locale),
+		})
+		return
+	}
+
+	res := clientapi.SubmitAccountVerification(clientapi.AccountVerificationSubmitRequestParam{Token: token})
+	if res.IsError() {
+		renderResult(w, res.StatusCode, locale, i18n.MsgWebVerifyEmailFailed, resultData{
+			Message: res.Message.Localize(locale),
+		})
+		return
+	}
+	titleID := i18n.MsgWebVerifyEmailSucceeded
+	if !res.Success {
+		titleID = i18n.MsgWebVerifyEmailFailed
+	}
+	renderResult(w, httpstatus.OK, locale, titleID, resultData{
+		Success: res.Success,
+		Message: res.Message.Localize(locale),
+	})
+}
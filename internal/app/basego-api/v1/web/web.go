@@ -0,0 +1,120 @@
+// Package web handles the built-in web pages opened from the email links.
+// The pages call the same logic as the client APIs, and are rendered by package webpage.
+package web
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/webpage"
+	"github.com/jonylim/basego/internal/pkg/common/api"
+	"github.com/jonylim/basego/internal/pkg/common/captcha"
+	"github.com/jonylim/basego/internal/pkg/common/constant/httpstatus"
+	"github.com/jonylim/basego/internal/pkg/common/data/redis"
+	"github.com/jonylim/basego/internal/pkg/common/helper"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+	"github.com/jonylim/basego/internal/pkg/common/ratelimit"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// rateLimitGroup is the API group name of the pages in the rate limit rules and the CAPTCHA endpoints,
+// e.g. "web/reset_password/request".
+const rateLimitGroup = "web"
+
+// resultData is the data of page "result.html".
+type resultData struct {
+	Success  bool
+	Message  string
+	LinkURL  string
+	LinkText string
+}
+
+// Route configures the router for the web pages.
+func Route(router *httprouter.Router) {
+	router.GET(webpage.PathPrefix+webpage.PathVerifyEmail, VerifyEmail)
+	router.POST(webpage.PathPrefix+webpage.PathVerifyEmail, VerifyEmailSubmit)
+	router.GET(webpage.PathPrefix+webpage.PathResetPasswordRequest, ResetPasswordRequest)
+	router.POST(webpage.PathPrefix+webpage.PathResetPasswordRequest, ResetPasswordRequestSubmit)
+	router.GET(webpage.PathPrefix+webpage.PathResetPassword, ResetPassword)
+	router.POST(webpage.PathPrefix+webpage.PathResetPassword, ResetPasswordSubmit)
+	router.ServeFiles(webpage.PathPrefix+"/assets/*filepath", http.Dir(webpage.AssetDir))
+}
+
+func getLocale(r *http.Request) string {
+	return i18n.LocaleFromAcceptLanguage(r.Header.Get("Accept-Language"))
+}
+
+func renderResult(w http.ResponseWriter, statusCode int, locale, titleID string, data resultData) {
+	webpage.Render(w, statusCode, "result.html", webpage.Page{
+		Locale: locale,
+		Title:  i18n.NewMessage(titleID).Localize(locale),
+		Data:   data,
+	})
+}
+
+// allowSubmit checks the rate limit of a form submission by the client's IP address.
+// The boolean is false if the limit is exceeded and the error page has been rendered.
+func allowSubmit(w http.ResponseWriter, r *http.Request, locale, name, titleID string) bool {
+	config := ratelimit.Get()
+	if !config.Enabled {
+		return true
+	}
+	limit, ok := config.Rules.Find(rateLimitGroup, name, ratelimit.ByIP)
+	if !ok || limit.Requests <= 0 {
+		return true
+	}
+
+	// Get the Redis connection and defer closing connection.
+	redisConn := redis.GetConnection()
+	defer redisConn.Close()
+
+	key := fmt.Sprintf("%s/%s:%s:%s", rateLimitGroup, name, ratelimit.ByIP, api.GetClientIPAddress(r))
+	res, err := ratelimit.Allow(redisConn, key, limit, time.Now())
+	if err != nil {
+		logger.Error("web", logger.FromError(err))
+		return true
+	}
+	if res.Allowed {
+		return true
+	}
+	seconds := int(math.Ceil(res.RetryAfter.Seconds()))
+	w.Header().Set("Retry-After", helper.IntToString(seconds))
+	msg := i18n.NewMessageWithParams(i18n.MsgTooManyRequests, i18n.Params{"seconds": seconds})
+	renderResult(w, httpstatus.TooManyRequests, locale, titleID, resultData{Message: msg.Localize(locale)})
+	return false
+}
+
+// captchaWidget returns the CAPTCHA widget of a form, nil if the form doesn't require a token.
+func captchaWidget(name string) *captcha.Widget {
+	config := captcha.Get()
+	if !config.Requires(rateLimitGroup, name) {
+		return nil
+	}
+	widget, _ := config.Widget()
+	return &widget
+}
+
+// checkCaptcha verifies the CAPTCHA token of a form submission if the form requires it, the same as the client APIs.
+// The boolean is false if the verification failed, with the ID of the message shown in the form and the status code.
+func checkCaptcha(r *http.Request, name string) (msgID string, statusCode int, ok bool) {
+	config := captcha.Get()
+	if !config.Requires(rateLimitGroup, name) {
+		return "", httpstatus.OK, true
+	}
+	widget, _ := config.Widget()
+	token := r.PostFormValue(widget.FieldName)
+	err := config.Verifier.Verify(token, api.GetClientIPAddress(r))
+	if err == nil {
+		return "", httpstatus.OK, true
+	} else if err != captcha.ErrFailed {
+		logger.Error("web", logger.FromError(err))
+		return i18n.MsgProcessingFailed, httpstatus.InternalServerError, false
+	} else if token == "" {
+		return i18n.MsgCaptchaRequired, httpstatus.BadRequest, false
+	}
+	return i18n.MsgCaptchaFailed, httpstatus.BadRequest, false
+}
@@ -0,0 +1,130 @@
+// Package apikey manages the API keys: creating, updating, enabling, disabling and rotating their secrets,
+// and verifying the signatures of the requests signed with them.
+//
+// Only the hashes of the secrets are saved. The signing key of a secret is the hex of its SHA-256,
+// derived by the clients from the secret.
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/common/crypto/secrethash"
+	"github.com/jonylim/basego/internal/pkg/common/helper"
+	"github.com/jonylim/basego/internal/pkg/common/platform"
+)
+
+// The domains of the API keys.
+const (
+	// DomainCustomer is the domain of the keys used by the client apps.
+	DomainCustomer = "customer"
+	// DomainInternal is the domain of the keys used by the internal servers, e.g. for the staff APIs.
+	DomainInternal = "internal"
+)
+
+// Lengths of the generated key IDs and secrets in random bytes, hex encoded.
+const (
+	keyIDLength  = 16
+	secretLength = 32
+)
+
+// hashPurpose is the purpose of the secrets' hashes.
+const hashPurpose = "apiKey"
+
+// MaxOverlap is the longest time the replaced secret of a rotation keeps working.
+const MaxOverlap = 30 * 24 * time.Hour
+
+// IsValidDomain checks if the given domain is valid.
+func IsValidDomain(domain string) bool {
+	return domain == DomainCustomer || domain == DomainInternal
+}
+
+// IsValidPlatform checks if an app platform is allowed for the keys of a domain:
+// client platforms for the customer domain, and server for the internal domain.
+func IsValidPlatform(domain, appPlatform string) bool {
+	switch domain {
+	case DomainCustomer:
+		return platform.IsValidClient(appPlatform)
+	case DomainInternal:
+		return platform.IsValidServer(appPlatform)
+	}
+	return false
+}
+
+// Encode returns the API key string sent in the API-Key header, base64 of "<key ID>:<secret>".
+func Encode(keyID, secret string) string {
+	return base64.StdEncoding.EncodeToString([]byte(keyID + ":" + secret))
+}
+
+// HashSecret returns the hash of a secret saved for the key.
+func HashSecret(secret string) string {
+	return secrethash.Sum(hashPurpose, secret)
+}
+
+// SigningKey returns the key signing the requests of a secret.
+func SigningKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// MatchesSecret checks if a secret is the key's secret, or its previous secret before the overlap of the last rotation ends.
+func MatchesSecret(apiKey model.XAPIKey, secret string, now time.Time) bool {
+	if secret == "" {
+		return false
+	}
+	if secrethash.Matches(apiKey.APIKeySecret, hashPurpose, secret) {
+		return true
+	}
+	return isPreviousActive(apiKey, now) && secrethash.Matches(apiKey.PreviousSecret, hashPurpose, secret)
+}
+
+// WebOrigins returns the app identifiers of the enabled and unexpired web API keys, which are the web apps' origins.
+func WebOrigins(items []model.XAPIKey, now time.Time) []string {
+	nowMillis := helper.UnixMillisecond(now)
+	res := make([]string, 0)
+	for _, item := range items {
+		if item.AppPlatform == platform.WEB && item.AppIdentifier != "" && item.IsEnabled && nowMillis < item.ExpiryTime {
+			res = append(res, item.AppIdentifier)
+		}
+	}
+	return res
+}
+
+// signingKeys returns the key's signing keys valid at the time.
+func signingKeys(apiKey model.XAPIKey, now time.Time) []string {
+	keys := make([]string, 0, 2)
+	if apiKey.SigningKey != "" {
+		keys = append(keys, apiKey.SigningKey)
+	}
+	if apiKey.PreviousSigningKey != "" && isPreviousActive(apiKey, now) {
+		keys = append(keys, apiKey.PreviousSigningKey)
+	}
+	return keys
+}
+
+// isPreviousActive checks if the overlap of the key's last rotation hasn't ended.
+func isPreviousActive(apiKey model.XAPIKey, now time.Time) bool {
+	return helper.UnixMillisecond(now) < apiKey.PreviousSecretExpiryTime
+}
+
+// generateKeyID returns a new random key ID.
+func generateKeyID() (string, error) {
+	return randomHex(keyIDLength)
+}
+
+// generateSecret returns a new random secret.
+func generateSecret() (string, error) {
+	return randomHex(secretLength)
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,139 @@
+package apikey
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/common/crypto/secrethash"
+	"github.com/jonylim/basego/internal/pkg/common/helper"
+)
+
+func TestIsValidPlatform(t *testing.T) {
+	var tests = []struct {
+		domain      string
+		appPlatform string
+		expected    bool
+	}{
+		{DomainCustomer, "android", true},
+		{DomainCustomer, "web", true},
+		{DomainCustomer, "server", false},
+		{DomainInternal, "server", true},
+		{DomainInternal, "ios", false},
+		{"other", "server", false},
+	}
+	for _, test := range tests {
+		if res := IsValidPlatform(test.domain, test.appPlatform); res != test.expected {
+			t.Errorf("IsValidPlatform(%q, %q) = %v; expected %v", test.domain, test.appPlatform, res, test.expected)
+		}
+	}
+}
+
+func TestEncode(t *testing.T) {
+	s := Encode("key", "secret")
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || string(b) != "key:secret" {
+		t.Errorf("Encode = %q, decoded to %q; expected base64 of %q", s, b, "key:secret")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	keyID, err := generateKeyID()
+	if err != nil || len(keyID) != keyIDLength*2 {
+		t.Errorf("generateKeyID = %q, %v; expected %d hex characters", keyID, err, keyIDLength*2)
+	}
+	secret, err := generateSecret()
+	if err != nil || len(secret) != secretLength*2 {
+		t.Errorf("generateSecret = %q, %v; expected %d hex characters", secret, err, secretLength*2)
+	}
+	if other, _ := generateSecret(); other == secret {
+		t.Errorf("generateSecret returns the same secret twice")
+	}
+}
+
+func TestMatchesSecret(t *testing.T) {
+	secrethash.SetKey([]byte("0123456789abcdef0123456789abcdef"))
+	now := time.Now()
+	apiKey := model.XAPIKey{
+		APIKeySecret:             HashSecret("new"),
+		PreviousSecret:           "old", // Not migrated yet, compared as plaintext.
+		PreviousSecretExpiryTime: helper.UnixMillisecond(now.Add(time.Hour)),
+	}
+	var tests = []struct {
+		secret   string
+		now      time.Time
+		expected bool
+	}{
+		{"new", now, true},
+		{"old", now, true},
+		{"new", now.Add(2 * time.Hour), true},
+		{"old", now.Add(2 * time.Hour), false},
+		{"other", now, false},
+		{"", now, false},
+	}
+	for _, test := range tests {
+		if res := MatchesSecret(apiKey, test.secret, test.now); res != test.expected {
+			t.Errorf("MatchesSecret(%q, %v) = %v; expected %v", test.secret, test.now, res, test.expected)
+		}
+	}
+
+	// A key without a previous secret doesn't match an empty secret.
+	if MatchesSecret(model.XAPIKey{APIKeySecret: "new"}, "", now) {
+		t.Errorf("MatchesSecret without a previous secret matches an empty secret")
+	}
+	// The hash is never accepted as the secret.
+	if MatchesSecret(apiKey, apiKey.APIKeySecret, now) {
+		t.Errorf("MatchesSecret matches the secret's hash")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Now()
+	future := helper.UnixMillisecond(now.Add(time.Hour))
+	var tests = []struct {
+		domain           string
+		appPlatform      string
+		expiryTime       int64
+		requireSignature bool
+		dailyQuota       int64
+		expected         error
+	}{
+		{DomainCustomer, "ios", future, false, 0, nil},
+		{DomainCustomer, "server", future, false, 0, ErrPlatformInvalid},
+		{DomainInternal, "server", helper.UnixMillisecond(now), false, 0, ErrExpiryInvalid},
+		{DomainInternal, "server", future, true, 0, nil},
+		{DomainCustomer, "android", future, true, 0, ErrSigningPlatform},
+		{DomainCustomer, "web", future, false, 1000, nil},
+		{DomainCustomer, "web", future, false, -1, ErrDailyQuotaInvalid},
+	}
+	for _, test := range tests {
+		apiKey := model.XAPIKey{
+			Domain:           test.domain,
+			AppPlatform:      test.appPlatform,
+			ExpiryTime:       test.expiryTime,
+			RequireSignature: test.requireSignature,
+			DailyQuota:       test.dailyQuota,
+		}
+		if err := validate(apiKey, now); err != test.expected {
+			t.Errorf("validate(%q, %q, %d, %v, %d) = %v; expected %v",
+				test.domain, test.appPlatform, test.expiryTime, test.requireSignature, test.dailyQuota, err, test.expected)
+		}
+	}
+}
+
+func TestWebOrigins(t *testing.T) {
+	now := time.Now()
+	future := helper.UnixMillisecond(now.Add(time.Hour))
+	items := []model.XAPIKey{
+		{AppPlatform: "web", AppIdentifier: "https://app.example.com", IsEnabled: true, ExpiryTime: future},
+		{AppPlatform: "web", AppIdentifier: "https://disabled.example.com", ExpiryTime: future},
+		{AppPlatform: "web", AppIdentifier: "https://expired.example.com", IsEnabled: true, ExpiryTime: helper.UnixMillisecond(now)},
+		{AppPlatform: "web", IsEnabled: true, ExpiryTime: future},
+		{AppPlatform: "android", AppIdentifier: "com.example.app", IsEnabled: true, ExpiryTime: future},
+	}
+	res := WebOrigins(items, now)
+	if len(res) != 1 || res[0] != "https://app.example.com" {
+		t.Errorf("WebOrigins = %v; expected [https://app.example.com]", res)
+	}
+}
@@ -0,0 +1,65 @@
+package apikey
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+)
+
+// Config is the configuration of the signed requests.
+type Config struct {
+	// MaxClockSkew is the longest difference allowed between a signature's timestamp and the server's time.
+	// The nonces are kept for twice as long, covering the signatures in the past and in the future.
+	MaxClockSkew time.Duration
+}
+
+// Default returns the default configuration.
+func Default() Config {
+	return Config{
+		MaxClockSkew: 5 * time.Minute,
+	}
+}
+
+// NonceTTL returns how long the nonces are kept to reject the replays.
+func (c Config) NonceTTL() time.Duration {
+	return 2 * c.MaxClockSkew
+}
+
+var (
+	current = Default()
+	mutex   sync.RWMutex
+)
+
+// Init loads the configuration from environment variables.
+func Init() {
+	c := Default()
+	if s := os.Getenv(envvar.APIKey.SignatureMaxClockSkew); s != "" {
+		d, err := time.ParseDuration(strings.TrimSpace(s))
+		if err != nil || d < time.Second {
+			logger.Println("apikey", fmt.Sprintf("WARN: %s '%s' is invalid, set to '%v' as default", envvar.APIKey.SignatureMaxClockSkew, s, c.MaxClockSkew))
+		} else {
+			c.MaxClockSkew = d
+		}
+	}
+	logger.Println("apikey", fmt.Sprintf("Signature MaxClockSkew = %v", c.MaxClockSkew))
+	Set(c)
+}
+
+// Get returns the active configuration.
+func Get() Config {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return current
+}
+
+// Set replaces the active configuration.
+func Set(c Config) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	current = c
+}
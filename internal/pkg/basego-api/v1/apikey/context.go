@@ -0,0 +1,23 @@
+package apikey
+
+import (
+	"context"
+	"net/http"
+)
+
+type endpointContextKey struct{}
+
+type endpointContext struct {
+	group, endpoint string
+}
+
+// WithEndpoint returns the request calling an endpoint of a group, whose API key must allow the endpoint in its scopes.
+func WithEndpoint(r *http.Request, group, endpoint string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), endpointContextKey{}, endpointContext{group, endpoint}))
+}
+
+// EndpointFromContext returns the endpoint called by a request, and its group. The boolean is false if it's unknown.
+func EndpointFromContext(ctx context.Context) (group, endpoint string, ok bool) {
+	e, ok := ctx.Value(endpointContextKey{}).(endpointContext)
+	return e.group, e.endpoint, ok
+}
@@ -0,0 +1,96 @@
+package apikey
+
+import (
+	"strings"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+)
+
+// ScopeAll is the scope allowing every endpoint.
+const ScopeAll = "*"
+
+// maxScopesLength is the maximum length of a key's scopes joined by commas.
+const maxScopesLength = 1024
+
+// ParseScopes splits scopes separated by commas, removing the spaces and the empty items.
+func ParseScopes(s string) []string {
+	scopes := make([]string, 0)
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			scopes = append(scopes, item)
+		}
+	}
+	return scopes
+}
+
+// JoinScopes joins scopes with commas, as saved for a key.
+func JoinScopes(scopes []string) string {
+	return strings.Join(scopes, ",")
+}
+
+// IsValidScope checks the format of a scope: "*", an API group (e.g. "client"), or an API group and an endpoint name
+// (e.g. "client/server_time"), which may end with "/*" to match the endpoints under it (e.g. "client/reset_password/*").
+func IsValidScope(scope string) bool {
+	if scope == ScopeAll {
+		return true
+	}
+	parts := strings.Split(scope, "/")
+	for i, part := range parts {
+		if part == ScopeAll && i > 0 && i == len(parts)-1 {
+			continue
+		}
+		if part == "" {
+			return false
+		}
+		for _, c := range part {
+			if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// MatchesScope checks if a scope allows an endpoint of an API group.
+func MatchesScope(scope, group, endpoint string) bool {
+	name := group + "/" + endpoint
+	switch {
+	case scope == ScopeAll, scope == group, scope == name:
+		return true
+	case strings.HasSuffix(scope, "/"+ScopeAll):
+		return strings.HasPrefix(name, strings.TrimSuffix(scope, ScopeAll))
+	}
+	return false
+}
+
+// AllowsEndpoint checks if a key may call an endpoint of an API group. A key without scopes may call every endpoint.
+func AllowsEndpoint(apiKey model.XAPIKey, group, endpoint string) bool {
+	scopes := ParseScopes(apiKey.Scopes)
+	if len(scopes) == 0 {
+		return true
+	}
+	for _, scope := range scopes {
+		if MatchesScope(scope, group, endpoint) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeScopes returns scopes as saved for a key, without the spaces and the empty items.
+func normalizeScopes(scopes []string) string {
+	return JoinScopes(ParseScopes(JoinScopes(scopes)))
+}
+
+// validateScopes checks the format of a key's scopes separated by commas.
+func validateScopes(s string) error {
+	for _, scope := range ParseScopes(s) {
+		if !IsValidScope(scope) {
+			return ErrScopesInvalid
+		}
+	}
+	if len(s) > maxScopesLength {
+		return ErrScopesInvalid
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package apikey
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+)
+
+func TestParseScopes(t *testing.T) {
+	var tests = []struct {
+		s        string
+		expected []string
+	}{
+		{"client/server_time, auth/access_token/request", []string{"client/server_time", "auth/access_token/request"}},
+		{" , client ,,", []string{"client"}},
+		{"", []string{}},
+	}
+	for _, test := range tests {
+		if res := ParseScopes(test.s); !reflect.DeepEqual(res, test.expected) {
+			t.Errorf("ParseScopes(%q) = %q; expected %q", test.s, res, test.expected)
+		}
+	}
+}
+
+func TestIsValidScope(t *testing.T) {
+	var tests = []struct {
+		scope    string
+		expected bool
+	}{
+		{"*", true},
+		{"client", true},
+		{"client/*", true},
+		{"client/server_time", true},
+		{"client/reset_password/*", true},
+		{"*/server_time", false},
+		{"client/*/submit", false},
+		{"client/", false},
+		{"Client", false},
+		{"client/server-time", false},
+		{"", false},
+	}
+	for _, test := range tests {
+		if res := IsValidScope(test.scope); res != test.expected {
+			t.Errorf("IsValidScope(%q) = %v; expected %v", test.scope, res, test.expected)
+		}
+	}
+}
+
+func TestAllowsEndpoint(t *testing.T) {
+	partner := model.XAPIKey{Scopes: "client/server_time,auth/access_token/request"}
+	resetPassword := model.XAPIKey{Scopes: "client/reset_password/*"}
+	var tests = []struct {
+		apiKey   model.XAPIKey
+		group    string
+		endpoint string
+		expected bool
+	}{
+		{model.XAPIKey{}, "account", "logout", true},
+		{model.XAPIKey{Scopes: "*"}, "account", "logout", true},
+		{model.XAPIKey{Scopes: "client"}, "client", "register", true},
+		{model.XAPIKey{Scopes: "client/*"}, "client", "account_verification/submit", true},
+		{partner, "client", "server_time", true},
+		{partner, "auth", "access_token/request", true},
+		{partner, "auth", "access_token/refresh", false},
+		{partner, "client", "register", false},
+		{resetPassword, "client", "reset_password/verify_token", true},
+		{resetPassword, "client", "reset_passwords", false},
+		{resetPassword, "account", "reset_password/verify_token", false},
+	}
+	for _, test := range tests {
+		if res := AllowsEndpoint(test.apiKey, test.group, test.endpoint); res != test.expected {
+			t.Errorf("AllowsEndpoint(%q, %q, %q) = %v; expected %v", test.apiKey.Scopes, test.group, test.endpoint, res, test.expected)
+		}
+	}
+}
+
+func TestEndpointFromContext(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/v1/client/server_time", nil)
+	if _, _, ok := EndpointFromContext(r.Context()); ok {
+		t.Errorf("EndpointFromContext() without endpoint = true")
+	}
+	r = WithEndpoint(r, "client", "server_time")
+	if group, endpoint, ok := EndpointFromContext(r.Context()); !ok || group != "client" || endpoint != "server_time" {
+		t.Errorf("EndpointFromContext() = %q, %q, %v", group, endpoint, ok)
+	}
+}
@@ -0,0 +1,255 @@
+package apikey
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/dao"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/redisstore"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/common/data/db"
+	"github.com/jonylim/basego/internal/pkg/common/helper"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+	"github.com/jonylim/basego/internal/pkg/common/platform"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// Errors returned by the service.
+var (
+	ErrNotFound            = i18n.NewError(i18n.MsgAPIKeyNotFound)
+	ErrPlatformInvalid     = i18n.NewError(i18n.MsgAPIKeyPlatformNotAllowed)
+	ErrExpiryInvalid       = i18n.NewError(i18n.MsgAPIKeyExpiryInvalid)
+	ErrSigningPlatform     = i18n.NewError(i18n.MsgAPIKeySigningPlatformInvalid)
+	ErrScopesInvalid       = i18n.NewError(i18n.MsgAPIKeyScopesInvalid)
+	ErrDailyQuotaInvalid   = i18n.NewError(i18n.MsgAPIKeyQuotaInvalid)
+	ErrMonthlyQuotaInvalid = i18n.NewError(i18n.MsgAPIKeyQuotaInvalid)
+	ErrOverlapInvalid      = i18n.NewErrorWithParams(i18n.MsgAPIKeyOverlapInvalid, i18n.Params{"maxHours": int(MaxOverlap.Hours())})
+	ErrDatabase            = i18n.NewError(i18n.MsgProcessingFailed)
+	ErrInternal            = i18n.NewError(i18n.MsgProcessingFailed)
+)
+
+// Settings are the settings of a new API key.
+type Settings struct {
+	AppPlatform   string
+	AppIdentifier string // Empty allows any app identifier.
+	ExpiryTime    int64  // In Unix milliseconds.
+	IsEnabled     bool
+
+	// RequireSignature requires the requests to be signed, only for the server platform.
+	RequireSignature bool
+
+	// Scopes are the patterns of the endpoints the key may call, empty for every endpoint. See IsValidScope.
+	Scopes []string
+
+	// DailyQuota and MonthlyQuota are the requests allowed per UTC day and month, 0 is unlimited.
+	DailyQuota   int64
+	MonthlyQuota int64
+}
+
+// Changes are the changes to an API key's settings, the nil fields are unchanged.
+type Changes struct {
+	AppPlatform   *string
+	AppIdentifier *string
+	ExpiryTime    *int64
+
+	RequireSignature *bool
+	Scopes           *[]string
+	DailyQuota       *int64
+	MonthlyQuota     *int64
+}
+
+// Service manages the API keys of a domain, keeping the Redis cache in sync with the database.
+type Service struct {
+	domain string
+	dao    *dao.XAPIKeyDAO
+	store  *redisstore.XAPIKeyStore
+}
+
+// NewService returns new instance of Service.
+func NewService(redisConn redis.Conn, domain string) *Service {
+	return &Service{
+		domain: domain,
+		dao:    dao.NewXAPIKeyDAO(domain),
+		store:  redisstore.NewXAPIKeyStore(redisConn, domain),
+	}
+}
+
+// List returns the domain's API keys.
+func (s *Service) List() ([]model.XAPIKey, error) {
+	items, err := s.dao.GetList()
+	if err != nil {
+		return nil, ErrDatabase
+	}
+	return items, nil
+}
+
+// Get returns an API key's details by API key ID.
+func (s *Service) Get(keyID string) (model.XAPIKey, error) {
+	apiKey, err := s.dao.GetByAPIKeyID(keyID)
+	if err == sql.ErrNoRows {
+		return apiKey, ErrNotFound
+	} else if err != nil {
+		return apiKey, ErrDatabase
+	}
+	return apiKey, nil
+}
+
+// Create creates an API key with a random key ID and secret.
+// Only the secret's hash is saved, the returned secret must be passed on to the key's user.
+func (s *Service) Create(settings Settings) (apiKey model.XAPIKey, secret string, err error) {
+	apiKey = model.XAPIKey{
+		Domain:           s.domain,
+		AppPlatform:      settings.AppPlatform,
+		AppIdentifier:    settings.AppIdentifier,
+		ExpiryTime:       settings.ExpiryTime,
+		IsEnabled:        settings.IsEnabled,
+		RequireSignature: settings.RequireSignature,
+		Scopes:           normalizeScopes(settings.Scopes),
+		DailyQuota:       settings.DailyQuota,
+		MonthlyQuota:     settings.MonthlyQuota,
+	}
+	if err = validate(apiKey, time.Now()); err != nil {
+		return
+	}
+	if apiKey.APIKeyID, err = generateKeyID(); err != nil {
+		logger.Error("apikey", logger.FromError(err))
+		return apiKey, "", ErrInternal
+	}
+	if secret, err = generateSecret(); err != nil {
+		logger.Error("apikey", logger.FromError(err))
+		return apiKey, "", ErrInternal
+	}
+	apiKey.APIKeySecret = HashSecret(secret)
+	apiKey.SigningKey = SigningKey(secret)
+
+	err = s.inTx(apiKey.APIKeyID, func(tx *sql.Tx) (bool, error) {
+		apiKey.ID, apiKey.CreatedTime, err = s.dao.Insert(tx, apiKey)
+		return err == nil, err
+	})
+	if err != nil {
+		return apiKey, "", err
+	}
+	apiKey.UpdatedTime = apiKey.CreatedTime
+	return apiKey, secret, nil
+}
+
+// Update changes an API key's platform, app identifier, expiry, scopes, quotas, or if signing is required.
+func (s *Service) Update(keyID string, changes Changes) (model.XAPIKey, error) {
+	apiKey, err := s.Get(keyID)
+	if err != nil {
+		return apiKey, err
+	}
+	if changes.AppPlatform != nil {
+		apiKey.AppPlatform = *changes.AppPlatform
+	}
+	if changes.AppIdentifier != nil {
+		apiKey.AppIdentifier = *changes.AppIdentifier
+	}
+	if changes.ExpiryTime != nil {
+		apiKey.ExpiryTime = *changes.ExpiryTime
+	}
+	if changes.RequireSignature != nil {
+		apiKey.RequireSignature = *changes.RequireSignature
+	}
+	if changes.Scopes != nil {
+		apiKey.Scopes = normalizeScopes(*changes.Scopes)
+	}
+	if changes.DailyQuota != nil {
+		apiKey.DailyQuota = *changes.DailyQuota
+	}
+	if changes.MonthlyQuota != nil {
+		apiKey.MonthlyQuota = *changes.MonthlyQuota
+	}
+	if err = validate(apiKey, time.Now()); err != nil {
+		return apiKey, err
+	}
+	err = s.inTx(keyID, func(tx *sql.Tx) (bool, error) {
+		return s.dao.UpdateSettings(tx, apiKey)
+	})
+	if err != nil {
+		return apiKey, err
+	}
+	return s.Get(keyID)
+}
+
+// SetEnabled enables or disables an API key.
+func (s *Service) SetEnabled(keyID string, enabled bool) (model.XAPIKey, error) {
+	err := s.inTx(keyID, func(tx *sql.Tx) (bool, error) {
+		return s.dao.SetEnabled(tx, keyID, enabled)
+	})
+	if err != nil {
+		return model.XAPIKey{}, err
+	}
+	return s.Get(keyID)
+}
+
+// Rotate replaces an API key's secret with a random one. The replaced secret keeps working for the overlap,
+// so the key's users can switch over, and a secret replaced by an earlier rotation stops working immediately.
+// Only the new secret's hash is saved, the returned secret must be passed on to the key's user.
+func (s *Service) Rotate(keyID string, overlap time.Duration) (apiKey model.XAPIKey, secret string, err error) {
+	if overlap < 0 || overlap > MaxOverlap {
+		return apiKey, "", ErrOverlapInvalid
+	}
+	if secret, err = generateSecret(); err != nil {
+		logger.Error("apikey", logger.FromError(err))
+		return apiKey, "", ErrInternal
+	}
+	previousExpiry := helper.UnixMillisecond(time.Now().Add(overlap))
+	err = s.inTx(keyID, func(tx *sql.Tx) (bool, error) {
+		return s.dao.RotateSecret(tx, keyID, HashSecret(secret), SigningKey(secret), previousExpiry)
+	})
+	if err != nil {
+		return apiKey, "", err
+	}
+	if apiKey, err = s.Get(keyID); err != nil {
+		return apiKey, "", err
+	}
+	return apiKey, secret, nil
+}
+
+// inTx runs a change of an API key in a database transaction, then deletes the key from the cache,
+// so the next request loads the change. The change returns false if the key isn't found.
+func (s *Service) inTx(keyID string, change func(tx *sql.Tx) (bool, error)) error {
+	tx, err := db.Get().Begin()
+	if err != nil {
+		logger.Fatal("db.Begin", logger.FromError(err))
+		return ErrDatabase
+	}
+	defer tx.Rollback()
+
+	ok, err := change(tx)
+	if err != nil {
+		return ErrDatabase
+	} else if !ok {
+		return ErrNotFound
+	}
+	if err = tx.Commit(); err != nil {
+		logger.Fatal("tx.Commit", logger.FromError(err))
+		return ErrDatabase
+	}
+	if _, err = s.store.Delete(keyID); err != nil && err != redis.ErrNil {
+		return ErrInternal
+	}
+	return nil
+}
+
+// validate checks the settings of an API key.
+func validate(apiKey model.XAPIKey, now time.Time) error {
+	if !IsValidPlatform(apiKey.Domain, apiKey.AppPlatform) {
+		return ErrPlatformInvalid
+	}
+	if apiKey.ExpiryTime <= helper.UnixMillisecond(now) {
+		return ErrExpiryInvalid
+	}
+	if apiKey.RequireSignature && !platform.IsValidServer(apiKey.AppPlatform) {
+		return ErrSigningPlatform
+	}
+	if apiKey.DailyQuota < 0 {
+		return ErrDailyQuotaInvalid
+	} else if apiKey.MonthlyQuota < 0 {
+		return ErrMonthlyQuotaInvalid
+	}
+	return validateScopes(apiKey.Scopes)
+}
@@ -0,0 +1,113 @@
+package apikey
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
+)
+
+// SignatureHeader is the header of the requests' signatures.
+const SignatureHeader = "Signature"
+
+// Nonce lengths allowed in the signatures.
+const (
+	minNonceLength = 16
+	maxNonceLength = 64
+)
+
+// Errors of the signature verification.
+var (
+	ErrSignatureRequired = i18n.NewError(i18n.MsgAPIKeySignatureRequired)
+	ErrSignatureFormat   = i18n.NewError(i18n.MsgAPIKeySignatureFormatInvalid)
+	ErrSignatureExpired  = i18n.NewError(i18n.MsgAPIKeySignatureExpired)
+	ErrSignatureInvalid  = i18n.NewError(i18n.MsgAPIKeySignatureInvalid)
+	ErrSignatureReplayed = i18n.NewError(i18n.MsgAPIKeySignatureReplayed)
+)
+
+// Signature is a request's signature, sent in the header as "t=<Unix seconds>,n=<nonce>,s=<hex of HMAC-SHA256>".
+// The HMAC is keyed by the signing key over StringToSign.
+type Signature struct {
+	Timestamp int64
+	Nonce     string
+	MAC       string
+}
+
+// ParseSignature parses the Signature header.
+func ParseSignature(header string) (sig Signature, err error) {
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			return sig, ErrSignatureFormat
+		}
+		switch kv[0] {
+		case "t":
+			if sig.Timestamp, err = strconv.ParseInt(kv[1], 10, 64); err != nil {
+				return sig, ErrSignatureFormat
+			}
+		case "n":
+			sig.Nonce = kv[1]
+		case "s":
+			sig.MAC = strings.ToLower(kv[1])
+		}
+	}
+	if sig.Timestamp <= 0 || !isValidNonce(sig.Nonce) || sig.MAC == "" {
+		return sig, ErrSignatureFormat
+	}
+	return sig, nil
+}
+
+// isValidNonce checks if a nonce has the allowed length and only letters, digits, "-" or "_".
+func isValidNonce(nonce string) bool {
+	if len(nonce) < minNonceLength || len(nonce) > maxNonceLength {
+		return false
+	}
+	for _, c := range nonce {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// StringToSign returns the string signed for a request, the lines of the method, the path with the query,
+// the timestamp, the nonce, and the hex of the body's SHA-256.
+func StringToSign(method, path string, timestamp int64, nonce string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	return strings.Join([]string{
+		strings.ToUpper(method),
+		path,
+		strconv.FormatInt(timestamp, 10),
+		nonce,
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")
+}
+
+// Sign returns the hex of the HMAC-SHA256 of a string to sign.
+func Sign(signingKey, stringToSign string) string {
+	mac := hmac.New(sha256.New, []byte(signingKey))
+	mac.Write([]byte(stringToSign))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks if the signature of a request is within the clock skew of now, and signed by the key's signing key,
+// or its previous signing key before the overlap of the last rotation ends.
+// The nonce isn't checked, it must be saved to reject the replays.
+func (sig Signature) Verify(apiKey model.XAPIKey, method, path string, body []byte, now time.Time, maxSkew time.Duration) error {
+	skew := now.Sub(time.Unix(sig.Timestamp, 0))
+	if skew > maxSkew || skew < -maxSkew {
+		return ErrSignatureExpired
+	}
+	stringToSign := StringToSign(method, path, sig.Timestamp, sig.Nonce, body)
+	for _, key := range signingKeys(apiKey, now) {
+		if hmac.Equal([]byte(Sign(key, stringToSign)), []byte(sig.MAC)) {
+			return nil
+		}
+	}
+	return ErrSignatureInvalid
+}
@@ -0,0 +1,80 @@
+package apikey
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/common/helper"
+)
+
+func TestParseSignature(t *testing.T) {
+	var tests = []struct {
+		header   string
+		expected Signature
+		err      error
+	}{
+		{"t=1700000000,n=abcdefghijklmnop,s=ABCDEF", Signature{1700000000, "abcdefghijklmnop", "abcdef"}, nil},
+		{" t=1700000000, n=abcdefgh-ijklmn_op , s=abc ", Signature{1700000000, "abcdefgh-ijklmn_op", "abc"}, nil},
+		{"t=1700000000,n=short,s=abc", Signature{}, ErrSignatureFormat},
+		{"t=1700000000,n=abcdefghijklmnop!,s=abc", Signature{}, ErrSignatureFormat},
+		{"t=now,n=abcdefghijklmnop,s=abc", Signature{}, ErrSignatureFormat},
+		{"t=1700000000,n=abcdefghijklmnop", Signature{}, ErrSignatureFormat},
+		{"abc", Signature{}, ErrSignatureFormat},
+		{"", Signature{}, ErrSignatureFormat},
+	}
+	for _, test := range tests {
+		sig, err := ParseSignature(test.header)
+		if err != test.err || (err == nil && sig != test.expected) {
+			t.Errorf("ParseSignature(%q) = %+v, %v; expected %+v, %v", test.header, sig, err, test.expected, test.err)
+		}
+	}
+}
+
+func TestSignatureVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	apiKey := model.XAPIKey{
+		SigningKey:               SigningKey("new"),
+		PreviousSigningKey:       SigningKey("old"),
+		PreviousSecretExpiryTime: helper.UnixMillisecond(now.Add(time.Hour)),
+	}
+	body := []byte(`{"keyID":"abc"}`)
+	sign := func(secret string, timestamp int64, body []byte) Signature {
+		nonce := "abcdefghijklmnop"
+		mac := Sign(SigningKey(secret), StringToSign("POST", "/v1/staff/api_keys/list", timestamp, nonce, body))
+		sig, err := ParseSignature(fmt.Sprintf("t=%d,n=%s,s=%s", timestamp, nonce, mac))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sig
+	}
+	var tests = []struct {
+		name     string
+		sig      Signature
+		body     []byte
+		now      time.Time
+		expected error
+	}{
+		{"signed", sign("new", now.Unix(), body), body, now, nil},
+		{"within skew", sign("new", now.Unix()-240, body), body, now, nil},
+		{"ahead within skew", sign("new", now.Unix()+240, body), body, now, nil},
+		{"previous key", sign("old", now.Unix(), body), body, now, nil},
+		{"previous key after overlap", sign("old", now.Unix()+7200, body), body, now.Add(2 * time.Hour), ErrSignatureInvalid},
+		{"too old", sign("new", now.Unix()-600, body), body, now, ErrSignatureExpired},
+		{"body changed", sign("new", now.Unix(), body), []byte(`{"keyID":"xyz"}`), now, ErrSignatureInvalid},
+		{"other key", sign("other", now.Unix(), body), body, now, ErrSignatureInvalid},
+	}
+	for _, test := range tests {
+		err := test.sig.Verify(apiKey, "POST", "/v1/staff/api_keys/list", test.body, test.now, 5*time.Minute)
+		if err != test.expected {
+			t.Errorf("Verify %s = %v; expected %v", test.name, err, test.expected)
+		}
+	}
+
+	// The method and the path are signed.
+	sig := sign("new", now.Unix(), body)
+	if err := sig.Verify(apiKey, "POST", "/v1/staff/api_keys/rotate", body, now, 5*time.Minute); err != ErrSignatureInvalid {
+		t.Errorf("Verify with another path = %v; expected %v", err, ErrSignatureInvalid)
+	}
+}
@@ -9,10 +9,11 @@ import (
 
 type cstAccountPhoto struct {
 	Filename, Storage sql.NullString
+	IsPublic          sql.NullBool
 	IsEncrypted       sql.NullBool
 }
 
-// ImageURL generates fullsize & thumbnail image URLs.
-func (img *cstAccountPhoto) ImageURL() model.ImageURL {
-	return storage.GenerateCstAccountPhotoURL(img.Filename.String, img.Storage.String, img.IsEncrypted.Bool)
+// ImageURL generates fullsize & thumbnail image URLs of an account's photo.
+func (img *cstAccountPhoto) ImageURL(accountID int64) model.ImageURL {
+	return storage.GenerateCstAccountPhotoURL(accountID, img.Filename.String, img.Storage.String, img.IsPublic.Bool, img.IsEncrypted.Bool)
 }
@@ -92,6 +92,22 @@ func (instance *CstAccountOTPDAO) GetActiveOTPByAccountAndAction(accountID int64
 	return data, err
 }
 
+// CountSentSince returns the number of OTPs created for an account and action since a time in Unix milliseconds,
+// including the deleted ones, and the created time of the last one.
+func (instance *CstAccountOTPDAO) CountSentSince(accountID int64, action string, sinceMillis int64) (count int, lastCreatedMillis int64, err error) {
+	err = instance.db.QueryRow(`SELECT COUNT(id), `+sqlTimestampToUnixMilliseconds("MAX(created_at)")+`
+			FROM tb_t_cst_account_otp
+			WHERE account_id = $1
+				AND action = $2
+				AND created_at >= TO_TIMESTAMP($3::DOUBLE PRECISION / 1000)
+		`, accountID, action, sinceMillis).
+		Scan(&count, &lastCreatedMillis)
+	if err != nil {
+		logger.Fatal("CstAccountOTPDAO", logger.FromError(err))
+	}
+	return
+}
+
 // IsEmailVerified checks if an email address is already verified.
 func (instance *CstAccountOTPDAO) IsEmailVerified(email string) (bool, error) {
 	var id int64
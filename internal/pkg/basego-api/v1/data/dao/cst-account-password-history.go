@@ -0,0 +1,62 @@
+package dao
+
+import (
+	"database/sql"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/common/data/db"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+)
+
+// CstAccountPasswordHistoryDAO manages database operations for customer accounts' previous passwords.
+type CstAccountPasswordHistoryDAO struct {
+	dao
+	selectColumns string
+}
+
+// NewCstAccountPasswordHistoryDAO returns new instance of CstAccountPasswordHistoryDAO.
+func NewCstAccountPasswordHistoryDAO() *CstAccountPasswordHistoryDAO {
+	return &CstAccountPasswordHistoryDAO{
+		dao: dao{db.Get(), false},
+		selectColumns: `
+				id, account_id, password, password_salt,
+				` + sqlTimestampToUnixMilliseconds("created_at") + ` AS created_time`,
+	}
+}
+
+func (instance *CstAccountPasswordHistoryDAO) scanRow(r SQLRowOrRows) (res model.CstAccountPasswordHistory, err error) {
+	err = r.Scan(&res.ID, &res.AccountID, &res.Password, &res.PasswordSalt, &res.CreatedTime)
+	return
+}
+
+func (instance *CstAccountPasswordHistoryDAO) scanRows(rows *sql.Rows) ([]model.CstAccountPasswordHistory, error) {
+	items := make([]model.CstAccountPasswordHistory, 0)
+	for rows.Next() {
+		res, err := instance.scanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, res)
+	}
+	return items, nil
+}
+
+// GetRecentByAccountID returns a customer account's latest previous passwords, sorted from the newest.
+func (instance *CstAccountPasswordHistoryDAO) GetRecentByAccountID(accountID int64, limit int) ([]model.CstAccountPasswordHistory, error) {
+	rows, err := instance.db.Query(`SELECT `+instance.selectColumns+`
+			FROM tb_t_cst_account_password_history
+			WHERE account_id = $1
+			ORDER BY id DESC
+			LIMIT $2`,
+		accountID, limit)
+	if err != nil {
+		logger.Fatal("CstAccountPasswordHistoryDAO", logger.FromError(err))
+		return nil, err
+	}
+	defer rows.Close()
+	items, err := instance.scanRows(rows)
+	if err != nil {
+		logger.Fatal("CstAccountPasswordHistoryDAO", logger.FromError(err))
+	}
+	return items, err
+}
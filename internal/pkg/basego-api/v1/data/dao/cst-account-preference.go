@@ -0,0 +1,78 @@
+package dao
+
+import (
+	"database/sql"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/common/data/db"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+)
+
+// CstAccountPreferenceDAO manages database operations for customer account preferences.
+type CstAccountPreferenceDAO struct {
+	dao
+	selectColumns string
+}
+
+// NewCstAccountPreferenceDAO returns new instance of CstAccountPreferenceDAO.
+func NewCstAccountPreferenceDAO() *CstAccountPreferenceDAO {
+	return &CstAccountPreferenceDAO{
+		dao: dao{db.Get(), false},
+		selectColumns: `
+				id, account_id, key, value,
+				` + sqlTimestampToUnixMilliseconds("created_at") + ` AS created_time,
+				` + sqlTimestampToUnixMilliseconds("updated_at") + ` AS updated_time`,
+	}
+}
+
+func (instance *CstAccountPreferenceDAO) scanRow(r SQLRowOrRows) (res model.CstAccountPreference, err error) {
+	err = r.Scan(&res.ID, &res.AccountID, &res.Key, &res.Value, &res.CreatedTime, &res.UpdatedTime)
+	return
+}
+
+func (instance *CstAccountPreferenceDAO) scanRows(rows *sql.Rows) ([]model.CstAccountPreference, error) {
+	items := make([]model.CstAccountPreference, 0)
+	for rows.Next() {
+		res, err := instance.scanRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, res)
+	}
+	return items, nil
+}
+
+// GetByAccountID returns all preferences set by a customer account.
+func (instance *CstAccountPreferenceDAO) GetByAccountID(accountID int64) ([]model.CstAccountPreference, error) {
+	rows, err := instance.db.Query(`SELECT `+instance.selectColumns+`
+			FROM tb_m_cst_account_preference
+			WHERE account_id = $1
+			ORDER BY key`,
+		accountID)
+	if err != nil {
+		logger.Fatal("CstAccountPreferenceDAO", logger.FromError(err))
+		return nil, err
+	}
+	defer rows.Close()
+	items, err := instance.scanRows(rows)
+	if err != nil {
+		logger.Fatal("CstAccountPreferenceDAO", logger.FromError(err))
+	}
+	return items, err
+}
+
+// Upsert inserts or updates a customer account's preference value.
+func (instance *CstAccountPreferenceDAO) Upsert(tx *sql.Tx, accountID int64, key, value string) (res model.CstAccountPreference, err error) {
+	row := tx.QueryRow(`INSERT INTO tb_m_cst_account_preference (account_id, key, value)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (account_id, key) DO UPDATE
+				SET value = EXCLUDED.value,
+					updated_at = CURRENT_TIMESTAMP
+			RETURNING `+instance.selectColumns,
+		accountID, key, value)
+	res, err = instance.scanRow(row)
+	if err != nil {
+		logger.Fatal("CstAccountPreferenceDAO", logger.FromError(err))
+	}
+	return
+}
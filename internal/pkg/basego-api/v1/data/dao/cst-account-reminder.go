@@ -0,0 +1,47 @@
+package dao
+
+import (
+	"database/sql"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/common/data/db"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+)
+
+// CstAccountReminderDAO manages database operations for the reminder emails sent to customer accounts.
+type CstAccountReminderDAO struct {
+	dao
+}
+
+// NewCstAccountReminderDAO returns new instance of CstAccountReminderDAO.
+func NewCstAccountReminderDAO() *CstAccountReminderDAO {
+	return &CstAccountReminderDAO{
+		dao: dao{db.Get(), false},
+	}
+}
+
+// Insert records a reminder sent to a customer account. This method requires database transaction to be passed.
+// If the account has been sent the reminder step, e.g. by another instance, inserted returns false.
+func (instance *CstAccountReminderDAO) Insert(tx *sql.Tx, item model.CstAccountReminder) (inserted bool, err error) {
+	var otpID interface{}
+	if item.OTPID != 0 {
+		otpID = item.OTPID
+	}
+	result, err := tx.Exec(`INSERT INTO tb_t_cst_account_reminder (
+				account_id, kind, step, otp_id
+			) VALUES (
+				$1, $2, $3, $4
+			)
+			ON CONFLICT (account_id, kind, step) DO NOTHING`,
+		item.AccountID, item.Kind, item.Step, otpID)
+	if err != nil {
+		logger.Fatal("CstAccountReminderDAO", logger.FromError(err))
+		return false, err
+	}
+	rowCount, err := result.RowsAffected()
+	if err != nil {
+		logger.Fatal("CstAccountReminderDAO", logger.FromError(err))
+		return false, err
+	}
+	return rowCount > 0, nil
+}
@@ -92,14 +92,14 @@ func (instance *CstAccountSessionDAO) InsertSession(tx *sql.Tx, accountID int64,
 }
 
 // InsertSessionToken inserts new access & refresh token for a customer account session.
-// The token expiry time is in milliseconds.
+// The tokens are the hashes of the token strings, and the token expiry time is in milliseconds.
 // This method requires database transaction to be passed.
-func (instance *CstAccountSessionDAO) InsertSessionToken(tx *sql.Tx, sessionID int64, accessToken string, accessTokenExpiry int64, refreshToken string, refreshTokenExpiry int64) (int64, error) {
+func (instance *CstAccountSessionDAO) InsertSessionToken(tx *sql.Tx, sessionID int64, accessTokenHash string, accessTokenExpiry int64, refreshTokenHash string, refreshTokenExpiry int64) (int64, error) {
 	var id int64
 	err := tx.QueryRow(`INSERT INTO tb_t_cst_account_session_token
 			(session_id, access_token, access_token_expiry_time, refresh_token, refresh_token_expiry_time)
 			VALUES ($1, $2, TO_TIMESTAMP($3), $4, TO_TIMESTAMP($5)) RETURNING id
-		`, sessionID, accessToken, accessTokenExpiry/1000, refreshToken, refreshTokenExpiry/1000,
+		`, sessionID, accessTokenHash, accessTokenExpiry/1000, refreshTokenHash, refreshTokenExpiry/1000,
 	).Scan(&id)
 	if err != nil {
 		logger.Fatal("CstAccountSessionDAO", logger.FromError(err))
@@ -28,10 +28,12 @@ func NewCstAccountDAO() *CstAccountDAO {
 				a.country_id, a.country_calling_code,
 				COALESCE(a.phone, '') AS phone, COALESCE(a.phone_with_code, '') AS phone_with_code, a.is_phone_verified,
 				a.password, a.password_salt, a.use_2fa,
-				f.filename AS photo_filename, f.storage AS photo_storage, f.is_encrypted AS photo_is_encrypted, 
+				f.filename AS photo_filename, f.storage AS photo_storage,
+				f.is_public AS photo_is_public, f.is_encrypted AS photo_is_encrypted,
 				` + sqlTimestampToUnixMilliseconds("a.last_login_time") + ` AS last_login_time,
 				` + sqlTimestampToUnixMilliseconds("a.last_activity_time") + ` AS last_activity_time,
 				a.is_password_change_required,
+				` + sqlTimestampToUnixMilliseconds("a.password_changed_at") + ` AS password_changed_time,
 				` + sqlTimestampToUnixMilliseconds("a.created_at") + ` AS created_time,
 				` + sqlTimestampToUnixMilliseconds("a.updated_at") + ` AS updated_time,
 				` + sqlTimestampToUnixMilliseconds("a.deleted_at") + ` AS deleted_time
@@ -52,12 +54,12 @@ func (instance *CstAccountDAO) scanRow(r SQLRowOrRows) (res model.CstAccount, er
 		&res.CountryID, &res.CountryCallingCode,
 		&res.Phone, &res.PhoneWithCode, &res.IsPhoneVerified,
 		&res.Password, &res.PasswordSalt, &res.Use2FA,
-		&photo.Filename, &photo.Storage, &photo.IsEncrypted,
+		&photo.Filename, &photo.Storage, &photo.IsPublic, &photo.IsEncrypted,
 		&res.LastLoginTime, &res.LastActivityTime,
-		&res.RequireChangePassword,
+		&res.RequireChangePassword, &res.PasswordChangedTime,
 		&res.CreatedTime, &res.UpdatedTime, &res.DeletedTime)
 	if err == nil && photo.Filename.String != "" {
-		res.ImageURL = photo.ImageURL()
+		res.ImageURL = photo.ImageURL(res.ID)
 	}
 	return
 }
@@ -207,12 +209,25 @@ func (instance *CstAccountDAO) Insert(tx *sql.Tx, account model.CstAccount) (ins
 }
 
 // ChangePassword updates a customer account's password hash & salt.
+// The replaced password is kept in the password history.
 func (instance *CstAccountDAO) ChangePassword(tx *sql.Tx, accountID int64, passwordHash, passwordSalt string) (updated bool, err error) {
+	_, err = tx.Exec(`INSERT INTO tb_t_cst_account_password_history (account_id, password, password_salt)
+			SELECT id, password, password_salt
+			FROM tb_m_cst_account
+			WHERE id = $1
+				AND deleted_at IS NULL
+		`, accountID)
+	if err != nil {
+		logger.Fatal("CstAccountDAO", logger.FromError(err))
+		return
+	}
+
 	var chkHash, chkSalt string
 	err = tx.QueryRow(`UPDATE tb_m_cst_account
 			SET password = $1,
 				password_salt = $2,
 				is_password_change_required = FALSE,
+				password_changed_at = CURRENT_TIMESTAMP,
 				updated_at = CURRENT_TIMESTAMP
 			WHERE id = $3
 				AND deleted_at IS NULL
@@ -234,6 +249,26 @@ func (instance *CstAccountDAO) ChangePassword(tx *sql.Tx, accountID int64, passw
 	return
 }
 
+// SetPasswordChangeRequired marks a customer account to change the password on the next login.
+func (instance *CstAccountDAO) SetPasswordChangeRequired(tx *sql.Tx, accountID int64) (bool, error) {
+	result, err := tx.Exec(`UPDATE tb_m_cst_account
+			SET is_password_change_required = TRUE,
+				updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1
+				AND deleted_at IS NULL
+		`, accountID)
+	if err != nil {
+		logger.Fatal("CstAccountDAO", logger.FromError(err))
+		return false, err
+	}
+	rowCount, err := result.RowsAffected()
+	if err != nil {
+		logger.Fatal("CstAccountDAO", logger.FromError(err))
+		return false, err
+	}
+	return rowCount > 0, nil
+}
+
 // UpdateLastLogin updates a customer account's last login and last activity time.
 func (instance *CstAccountDAO) UpdateLastLogin(tx *sql.Tx, accountID int64, loginTime time.Time) (bool, error) {
 	result, err := tx.Exec(`UPDATE tb_m_cst_account
@@ -304,3 +339,53 @@ func (instance *CstAccountDAO) SetVerifiedEmail(tx *sql.Tx, id int64) (isVerifie
 	}
 	return
 }
+
+// GetUnverifiedForReminder returns the unverified customer accounts due for a reminder step,
+// i.e. created in the time range in Unix milliseconds and not sent the step or a later step of the reminder kind yet.
+// The range's start is ignored if it's 0.
+func (instance *CstAccountDAO) GetUnverifiedForReminder(kind string, step int, createdAfterMillis, createdBeforeMillis int64, limit int) ([]model.CstAccount, error) {
+	return instance.getListWhere(`WHERE a.is_email_verified = FALSE
+				AND a.deleted_at IS NULL
+				AND a.created_at <= TO_TIMESTAMP($3::DOUBLE PRECISION / 1000)
+				AND ($2 = 0 OR a.created_at > TO_TIMESTAMP($2::DOUBLE PRECISION / 1000))
+				AND NOT EXISTS (
+					SELECT 1 FROM tb_t_cst_account_reminder r
+					WHERE r.account_id = a.id
+						AND r.kind = $4
+						AND r.step >= $5
+				) `,
+		"ORDER BY a.created_at, a.id ",
+		"LIMIT $1",
+		limit, createdAfterMillis, createdBeforeMillis, kind, step)
+}
+
+// GetUnverifiedCreatedBefore returns the unverified customer accounts created before a time in Unix milliseconds.
+func (instance *CstAccountDAO) GetUnverifiedCreatedBefore(createdBeforeMillis int64, limit int) ([]model.CstAccount, error) {
+	return instance.getListWhere(`WHERE a.is_email_verified = FALSE
+				AND a.deleted_at IS NULL
+				AND a.created_at <= TO_TIMESTAMP($2::DOUBLE PRECISION / 1000) `,
+		"ORDER BY a.created_at, a.id ",
+		"LIMIT $1",
+		limit, createdBeforeMillis)
+}
+
+// SoftDeleteUnverified marks a customer account as deleted if its email address is still unverified.
+func (instance *CstAccountDAO) SoftDeleteUnverified(tx *sql.Tx, id int64) (deleted bool, err error) {
+	result, err := tx.Exec(`UPDATE tb_m_cst_account
+			SET deleted_at = CURRENT_TIMESTAMP,
+				updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1
+				AND is_email_verified = FALSE
+				AND deleted_at IS NULL
+		`, id)
+	if err != nil {
+		logger.Fatal("CstAccountDAO", logger.FromError(err))
+		return false, err
+	}
+	rowCount, err := result.RowsAffected()
+	if err != nil {
+		logger.Fatal("CstAccountDAO", logger.FromError(err))
+		return false, err
+	}
+	return rowCount > 0, nil
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
 	"github.com/jonylim/basego/internal/pkg/common/data/db"
 	"github.com/jonylim/basego/internal/pkg/common/logger"
+
+	"github.com/lib/pq"
 )
 
 // FileDAO manages database operations for uploaded file data.
@@ -22,23 +24,35 @@ func NewFileDAO() *FileDAO {
 				id, owner_type, owner_id, category, filename, original_filename,
 				media_type, file_ext, file_size, width, height,
 				thumbnail_media_type, thumbnail_file_ext, thumbnail_file_size, thumbnail_width, thumbnail_height,
-				storage, is_encrypted, encrypt_key, uploader,
+				storage, is_public, is_encrypted, encrypt_key, uploader, variants, blurhash,
 				` + sqlTimestampToUnixMilliseconds("created_at") + ` AS created_time,
 				` + sqlTimestampToUnixMilliseconds("updated_at") + ` AS updated_time,
 				` + sqlTimestampToUnixMilliseconds("deleted_at") + ` AS deleted_time`,
 	}
 }
 
-func (instance *FileDAO) scanRow(row *sql.Row) (res model.File, err error) {
-	err = row.Scan(
+func (instance *FileDAO) scanRow(r SQLRowOrRows) (res model.File, err error) {
+	err = r.Scan(
 		&res.ID, &res.OwnerType, &res.OwnerID, &res.Category, &res.Filename, &res.OriginalFilename,
 		&res.MediaType, &res.FileExt, &res.FileSize, &res.Width, &res.Height,
 		&res.ThumbMediaType, &res.ThumbFileExt, &res.ThumbFileSize, &res.ThumbWidth, &res.ThumbHeight,
-		&res.Storage, &res.IsEncrypted, &res.EncryptKey, &res.Uploader,
+		&res.Storage, &res.IsPublic, &res.IsEncrypted, &res.EncryptKey, &res.Uploader, &res.Variants, &res.Blurhash,
 		&res.CreatedTime, &res.UpdatedTime, &res.DeletedTime)
 	return
 }
 
+func (instance *FileDAO) scanRows(rows *sql.Rows) ([]model.File, error) {
+	items := make([]model.File, 0)
+	for rows.Next() {
+		res, err := instance.scanRow(rows)
+		if err != nil {
+			return items, err
+		}
+		items = append(items, res)
+	}
+	return items, rows.Err()
+}
+
 func (instance *FileDAO) getWhere(sqlWhere string, params ...interface{}) (res model.File, err error) {
 	row := instance.db.QueryRow(`SELECT `+instance.selectColumns+`
 			FROM tb_m_file
@@ -83,6 +97,51 @@ func (instance *FileDAO) GetByOwnerTypeAndCategoryAndFilename(ownerType, categor
 	return instance.getWhere(where, ownerType, category, filename)
 }
 
+// GetListByStorage returns up to `limit` files in a storage whose IDs are greater than `afterID`, ordered by ID.
+func (instance *FileDAO) GetListByStorage(storage string, afterID int64, limit int) ([]model.File, error) {
+	where := `WHERE storage = $1
+				AND id > $2 `
+	if !instance.withDeleted {
+		where += `
+				AND deleted_at IS NULL `
+	}
+	rows, err := instance.db.Query(`SELECT `+instance.selectColumns+`
+			FROM tb_m_file
+			`+where+`
+			ORDER BY id
+			LIMIT $3`, storage, afterID, limit)
+	if err != nil {
+		logger.Fatal("FileDAO", logger.FromError(err))
+		return nil, err
+	}
+	defer rows.Close()
+	items, err := instance.scanRows(rows)
+	if err != nil {
+		logger.Fatal("FileDAO", logger.FromError(err))
+	}
+	return items, err
+}
+
+// GetStorages returns the storages of the files, including the deleted files.
+func (instance *FileDAO) GetStorages() ([]string, error) {
+	rows, err := instance.db.Query(`SELECT DISTINCT storage FROM tb_m_file ORDER BY storage`)
+	if err != nil {
+		logger.Fatal("FileDAO", logger.FromError(err))
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]string, 0)
+	for rows.Next() {
+		var item string
+		if err = rows.Scan(&item); err != nil {
+			logger.Fatal("FileDAO", logger.FromError(err))
+			return items, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
 func (instance *FileDAO) existsWhere(tx *sql.Tx, sqlWhere string, params ...interface{}) (bool, error) {
 	rows, err := tx.Query(`SELECT 1 AS exists FROM tb_m_file `+sqlWhere, params...)
 	if err != nil {
@@ -122,14 +181,14 @@ func (instance *FileDAO) Insert(tx *sql.Tx, item model.File) (id, createdMillis
 				owner_type, owner_id, category, filename, original_filename,
 				media_type, file_ext, file_size, width, height,
 				thumbnail_media_type, thumbnail_file_ext, thumbnail_file_size, thumbnail_width, thumbnail_height,
-				storage, is_encrypted, encrypt_key, uploader
+				storage, is_public, is_encrypted, encrypt_key, uploader, variants, blurhash
 			)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)
 			RETURNING id, `+sqlTimestampToUnixMilliseconds("created_at"),
 		item.OwnerType, item.OwnerID, item.Category, item.Filename, item.OriginalFilename,
 		item.MediaType, item.FileExt, item.FileSize, item.Width, item.Height,
 		item.ThumbMediaType, item.ThumbFileExt, item.ThumbFileSize, item.ThumbWidth, item.ThumbHeight,
-		item.Storage, item.IsEncrypted, item.EncryptKey, item.Uploader,
+		item.Storage, item.IsPublic, item.IsEncrypted, item.EncryptKey, item.Uploader, item.Variants, item.Blurhash,
 	).Scan(&id, &createdMillis)
 	if err != nil {
 		logger.Fatal("FileDAO", logger.FromError(err))
@@ -171,3 +230,25 @@ func (instance *FileDAO) DeleteFileByOwnerTypeAndCategoryAndFilename(tx *sql.Tx,
 				AND deleted_at IS NULL
 		`, ownerType, category, filename)
 }
+
+// UpdateStorageByIDs moves files to another storage by IDs, only if they're still in the `from` storage.
+// If successful, returns the updated files' details.
+func (instance *FileDAO) UpdateStorageByIDs(tx *sql.Tx, ids []int64, from, to string) ([]model.File, error) {
+	rows, err := tx.Query(`UPDATE tb_m_file
+			SET storage = $1,
+				updated_at = CURRENT_TIMESTAMP
+			WHERE id = ANY($2)
+				AND storage = $3
+			RETURNING `+instance.selectColumns,
+		to, pq.Array(ids), from)
+	if err != nil {
+		logger.Fatal("FileDAO", logger.FromError(err))
+		return nil, err
+	}
+	defer rows.Close()
+	items, err := instance.scanRows(rows)
+	if err != nil {
+		logger.Fatal("FileDAO", logger.FromError(err))
+	}
+	return items, err
+}
@@ -0,0 +1,100 @@
+package dao
+
+import (
+	"database/sql"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/common/data/db"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+)
+
+// Granularities of the API key usage series.
+const (
+	UsageByHour  = "hour"
+	UsageByDay   = "day"
+	UsageByMonth = "month"
+)
+
+// IsValidUsageGranularity checks if a granularity of the API key usage series is supported.
+func IsValidUsageGranularity(v string) bool {
+	switch v {
+	case UsageByHour, UsageByDay, UsageByMonth:
+		return true
+	}
+	return false
+}
+
+// XAPIKeyUsageDAO manages database operations for the usage rollup of API keys.
+type XAPIKeyUsageDAO struct {
+	dao
+	domain string
+}
+
+// NewXAPIKeyUsageDAO returns new instance of XAPIKeyUsageDAO.
+func NewXAPIKeyUsageDAO(domain string) *XAPIKeyUsageDAO {
+	return &XAPIKeyUsageDAO{
+		dao:    dao{db.Get(), false},
+		domain: domain,
+	}
+}
+
+// Add adds the request counts to the rollup, creating the missing rows. The items' domains are ignored.
+// This method requires database transaction to be passed.
+func (instance *XAPIKeyUsageDAO) Add(tx *sql.Tx, items []model.XAPIKeyUsage) error {
+	stmt, err := tx.Prepare(`INSERT INTO tb_x_api_key_usage (
+				domain, api_key_id, period_start, endpoint, status_class, request_count
+			) VALUES (
+				$1, $2, ` + sqlUnixMillisecondsToTimestamp("$3") + `, $4, $5, $6
+			)
+			ON CONFLICT (domain, api_key_id, period_start, endpoint, status_class) DO UPDATE
+			SET request_count = tb_x_api_key_usage.request_count + EXCLUDED.request_count,
+				updated_at = CURRENT_TIMESTAMP`)
+	if err != nil {
+		logger.Fatal("XAPIKeyUsageDAO", logger.FromError(err))
+		return err
+	}
+	defer stmt.Close()
+	for _, item := range items {
+		_, err = stmt.Exec(instance.domain, item.APIKeyID, item.PeriodStart, item.Endpoint, item.StatusClass, item.RequestCount)
+		if err != nil {
+			logger.Fatal("XAPIKeyUsageDAO", logger.FromError(err))
+			return err
+		}
+	}
+	return nil
+}
+
+// GetSeries returns an API key's request counts from fromMillis until before toMillis, summed by the granularity's
+// UTC periods, endpoint and status class, ordered by period. An empty endpoint returns every endpoint.
+func (instance *XAPIKeyUsageDAO) GetSeries(apiKeyID string, fromMillis, toMillis int64, granularity, endpoint string) ([]model.XAPIKeyUsage, error) {
+	if !IsValidUsageGranularity(granularity) {
+		granularity = UsageByHour
+	}
+	sqlPeriod := `DATE_TRUNC('` + granularity + `', period_start AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'`
+	rows, err := instance.db.Query(`SELECT
+				`+sqlTimestampToUnixMilliseconds(sqlPeriod)+` AS period, endpoint, status_class, SUM(request_count)::BIGINT
+			FROM tb_x_api_key_usage
+			WHERE domain = $1
+				AND api_key_id = $2
+				AND period_start >= `+sqlUnixMillisecondsToTimestamp("$3")+`
+				AND period_start < `+sqlUnixMillisecondsToTimestamp("$4")+`
+				AND ($5 = '' OR endpoint = $5)
+			GROUP BY period, endpoint, status_class
+			ORDER BY period, endpoint, status_class`,
+		instance.domain, apiKeyID, fromMillis, toMillis, endpoint)
+	if err != nil {
+		logger.Fatal("XAPIKeyUsageDAO", logger.FromError(err))
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]model.XAPIKeyUsage, 0)
+	for rows.Next() {
+		res := model.XAPIKeyUsage{Domain: instance.domain, APIKeyID: apiKeyID}
+		if err = rows.Scan(&res.PeriodStart, &res.Endpoint, &res.StatusClass, &res.RequestCount); err != nil {
+			logger.Fatal("XAPIKeyUsageDAO", logger.FromError(err))
+			return items, err
+		}
+		items = append(items, res)
+	}
+	return items, nil
+}
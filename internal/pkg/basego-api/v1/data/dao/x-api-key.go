@@ -11,7 +11,8 @@ import (
 // XAPIKeyDAO manages database operations for API key data.
 type XAPIKeyDAO struct {
 	dao
-	domain string
+	domain        string
+	sqlSelectFrom string
 }
 
 // NewXAPIKeyDAO returns new instance of XAPIKeyDAO.
@@ -19,30 +20,45 @@ func NewXAPIKeyDAO(domain string) *XAPIKeyDAO {
 	return &XAPIKeyDAO{
 		dao:    dao{db.Get(), false},
 		domain: domain,
+		sqlSelectFrom: `SELECT
+				id, api_key_id, api_key_secret,
+				domain, app_platform, app_identifier,
+				` + sqlTimestampToUnixMilliseconds("expiry_time") + ` AS expiry_time, is_enabled,
+				COALESCE(previous_api_key_secret, '') AS previous_api_key_secret,
+				` + sqlTimestampToUnixMilliseconds("previous_secret_expiry_time") + ` AS previous_secret_expiry_time,
+				require_signature, COALESCE(signing_key, '') AS signing_key,
+				COALESCE(previous_signing_key, '') AS previous_signing_key, scopes,
+				daily_quota, monthly_quota,
+				` + sqlTimestampToUnixMilliseconds("created_at") + ` AS created_time,
+				` + sqlTimestampToUnixMilliseconds("updated_at") + ` AS updated_time,
+				` + sqlTimestampToUnixMilliseconds("deleted_at") + ` AS deleted_time
+			FROM tb_x_api_key
+			`,
 	}
 }
 
+func (instance *XAPIKeyDAO) scanRow(r SQLRowOrRows) (res model.XAPIKey, err error) {
+	err = r.Scan(&res.ID, &res.APIKeyID, &res.APIKeySecret,
+		&res.Domain, &res.AppPlatform, &res.AppIdentifier,
+		&res.ExpiryTime, &res.IsEnabled,
+		&res.PreviousSecret, &res.PreviousSecretExpiryTime,
+		&res.RequireSignature, &res.SigningKey, &res.PreviousSigningKey, &res.Scopes,
+		&res.DailyQuota, &res.MonthlyQuota,
+		&res.CreatedTime, &res.UpdatedTime, &res.DeletedTime)
+	return
+}
+
 func (instance *XAPIKeyDAO) getByFieldAndValue(field string, value interface{}) (res model.XAPIKey, err error) {
 	var sqlWhereDeleted string
 	if !instance.withDeleted {
 		sqlWhereDeleted = `AND deleted_at IS NULL`
 	}
-	err = instance.db.QueryRow(`SELECT
-				id, api_key_id, api_key_secret,
-				domain, app_platform, app_identifier,
-				`+sqlTimestampToUnixMilliseconds("expiry_time")+` AS expiry_time, is_enabled,
-				`+sqlTimestampToUnixMilliseconds("created_at")+` AS created_time,
-				`+sqlTimestampToUnixMilliseconds("updated_at")+` AS updated_time,
-				`+sqlTimestampToUnixMilliseconds("deleted_at")+` AS deleted_time
-			FROM tb_x_api_key 
+	row := instance.db.QueryRow(instance.sqlSelectFrom+`
 			WHERE domain = $1
 				AND `+field+` = $2
 				`+sqlWhereDeleted,
-		instance.domain, value).
-		Scan(&res.ID, &res.APIKeyID, &res.APIKeySecret,
-			&res.Domain, &res.AppPlatform, &res.AppIdentifier,
-			&res.ExpiryTime, &res.IsEnabled,
-			&res.CreatedTime, &res.UpdatedTime, &res.DeletedTime)
+		instance.domain, value)
+	res, err = instance.scanRow(row)
 	if err != nil && err != sql.ErrNoRows {
 		logger.Fatal("XAPIKeyDAO", logger.FromError(err))
 	}
@@ -58,3 +74,107 @@ func (instance *XAPIKeyDAO) GetByID(id int64) (model.XAPIKey, error) {
 func (instance *XAPIKeyDAO) GetByAPIKeyID(appKeyID string) (model.XAPIKey, error) {
 	return instance.getByFieldAndValue("api_key_id", appKeyID)
 }
+
+// GetList returns the domain's API keys, ordered by creation.
+func (instance *XAPIKeyDAO) GetList() ([]model.XAPIKey, error) {
+	var sqlWhereDeleted string
+	if !instance.withDeleted {
+		sqlWhereDeleted = `AND deleted_at IS NULL`
+	}
+	rows, err := instance.db.Query(instance.sqlSelectFrom+`
+			WHERE domain = $1
+				`+sqlWhereDeleted+`
+			ORDER BY id`,
+		instance.domain)
+	if err != nil {
+		logger.Fatal("XAPIKeyDAO", logger.FromError(err))
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]model.XAPIKey, 0)
+	for rows.Next() {
+		res, err := instance.scanRow(rows)
+		if err != nil {
+			logger.Fatal("XAPIKeyDAO", logger.FromError(err))
+			return items, err
+		}
+		items = append(items, res)
+	}
+	return items, nil
+}
+
+// Insert inserts a new API key for the domain, the secret must be hashed.
+// This method requires database transaction to be passed.
+func (instance *XAPIKeyDAO) Insert(tx *sql.Tx, item model.XAPIKey) (insertedID int32, createdMillis int64, err error) {
+	err = tx.QueryRow(`INSERT INTO tb_x_api_key (
+				api_key_id, api_key_secret, domain,
+				app_platform, app_identifier, expiry_time, is_enabled,
+				require_signature, signing_key, scopes,
+				daily_quota, monthly_quota
+			) VALUES (
+				$1, $2, $3,
+				$4, $5, `+sqlUnixMillisecondsToTimestamp("$6")+`, $7,
+				$8, $9, $10,
+				$11, $12
+			) RETURNING id, `+sqlTimestampToUnixMilliseconds("created_at"),
+		item.APIKeyID, item.APIKeySecret, instance.domain,
+		item.AppPlatform, item.AppIdentifier, item.ExpiryTime, item.IsEnabled,
+		item.RequireSignature, item.SigningKey, item.Scopes,
+		item.DailyQuota, item.MonthlyQuota,
+	).Scan(&insertedID, &createdMillis)
+	if err != nil {
+		logger.Fatal("XAPIKeyDAO", logger.FromError(err))
+	}
+	return
+}
+
+func (instance *XAPIKeyDAO) update(tx *sql.Tx, apiKeyID, sqlSet string, params ...interface{}) (updated bool, err error) {
+	params = append([]interface{}{instance.domain, apiKeyID}, params...)
+	result, err := tx.Exec(`UPDATE tb_x_api_key
+			SET `+sqlSet+`,
+				updated_at = CURRENT_TIMESTAMP
+			WHERE domain = $1
+				AND api_key_id = $2
+				AND deleted_at IS NULL
+		`, params...)
+	if err != nil {
+		logger.Fatal("XAPIKeyDAO", logger.FromError(err))
+		return false, err
+	}
+	rowCount, err := result.RowsAffected()
+	if err != nil {
+		logger.Fatal("XAPIKeyDAO", logger.FromError(err))
+		return false, err
+	}
+	return rowCount > 0, nil
+}
+
+// UpdateSettings updates an API key's platform, app identifier, expiry, scopes, quotas and if signing is required.
+// This method requires database transaction to be passed.
+func (instance *XAPIKeyDAO) UpdateSettings(tx *sql.Tx, item model.XAPIKey) (bool, error) {
+	return instance.update(tx, item.APIKeyID, `app_platform = $3,
+				app_identifier = $4,
+				expiry_time = `+sqlUnixMillisecondsToTimestamp("$5")+`,
+				require_signature = $6,
+				scopes = $7,
+				daily_quota = $8,
+				monthly_quota = $9`,
+		item.AppPlatform, item.AppIdentifier, item.ExpiryTime, item.RequireSignature, item.Scopes,
+		item.DailyQuota, item.MonthlyQuota)
+}
+
+// SetEnabled enables or disables an API key. This method requires database transaction to be passed.
+func (instance *XAPIKeyDAO) SetEnabled(tx *sql.Tx, apiKeyID string, enabled bool) (bool, error) {
+	return instance.update(tx, apiKeyID, `is_enabled = $3`, enabled)
+}
+
+// RotateSecret replaces an API key's secret hash and signing key, keeping the replaced ones valid until previousExpiryMillis.
+// This method requires database transaction to be passed.
+func (instance *XAPIKeyDAO) RotateSecret(tx *sql.Tx, apiKeyID, secretHash, signingKey string, previousExpiryMillis int64) (bool, error) {
+	return instance.update(tx, apiKeyID, `previous_api_key_secret = api_key_secret,
+				previous_signing_key = signing_key,
+				previous_secret_expiry_time = `+sqlUnixMillisecondsToTimestamp("$5")+`,
+				api_key_secret = $3,
+				signing_key = $4`,
+		secretHash, signingKey, previousExpiryMillis)
+}
@@ -0,0 +1,77 @@
+package redisstore
+
+import (
+	"fmt"
+
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// CstAccountPreferenceStore manages Redis operations for customer account preferences.
+// The preferences of an account are stored as a hash of key-value pairs.
+type CstAccountPreferenceStore struct {
+	redisStore
+	ttl         int
+	byAccountID string
+}
+
+const keyRedisNil = "redisNil"
+
+// NewCstAccountPreferenceStore returns new instance to manage customer account preferences.
+func NewCstAccountPreferenceStore(conn redis.Conn) *CstAccountPreferenceStore {
+	return &CstAccountPreferenceStore{
+		redisStore: redisStore{
+			conn:    conn,
+			baseKey: "cstAccPref",
+		},
+		ttl:         3600, // 1 hour
+		byAccountID: "accountID",
+	}
+}
+
+// GetByAccountID returns a customer account's preference values.
+func (store *CstAccountPreferenceStore) GetByAccountID(accountID int64) (map[string]string, error) {
+	values, err := redis.StringMap(store.conn.Do("HGETALL", store.generateStoreKeyByAccountID(accountID)))
+	if err != nil {
+		logger.Error("CstAccountPreferenceStore", logger.FromError(err))
+		return nil, err
+	} else if len(values) == 0 {
+		return nil, redis.ErrNil
+	}
+	delete(values, keyRedisNil)
+	return values, nil
+}
+
+// Save saves a customer account's preference values, replacing the existing ones.
+func (store *CstAccountPreferenceStore) Save(accountID int64, values map[string]string) error {
+	key := store.generateStoreKeyByAccountID(accountID)
+	if _, err := store.DoDEL(key); err != nil {
+		logger.Fatal("CstAccountPreferenceStore", logger.FromError(err))
+		return err
+	}
+	var err error
+	if len(values) == 0 {
+		err = store.DoHMSET(key, emptyItem, store.ttl)
+	} else {
+		err = store.DoHMSET(key, values, store.ttl)
+	}
+	if err != nil {
+		logger.Fatal("CstAccountPreferenceStore", logger.FromError(err))
+	}
+	return err
+}
+
+// DeleteByAccountID deletes a customer account's preference values.
+func (store *CstAccountPreferenceStore) DeleteByAccountID(accountID int64) (bool, error) {
+	count, err := store.DoDEL(store.generateStoreKeyByAccountID(accountID))
+	if err != nil {
+		logger.Fatal("CstAccountPreferenceStore", logger.FromError(err))
+		return false, err
+	}
+	return count != 0, nil
+}
+
+func (store *CstAccountPreferenceStore) generateStoreKeyByAccountID(accountID int64) string {
+	return fmt.Sprintf("%s:%s:%v", store.baseKey, store.byAccountID, accountID)
+}
@@ -37,6 +37,7 @@ type cstAccountStoreModel struct {
 	LastLoginTime         int64  `redis:"lastLoginTime"`
 	LastActivityTime      int64  `redis:"lastActivityTime"`
 	RequireChangePassword bool   `redis:"requireChangePassword"`
+	PasswordChangedTime   int64  `redis:"passwordChangedTime"`
 	CreatedTime           int64  `redis:"createdTime"`
 	UpdatedTime           int64  `redis:"updatedTime"`
 	DeletedTime           int64  `redis:"deletedTime"`
@@ -65,6 +66,7 @@ func (src cstAccountStoreModel) Inflate() (res model.CstAccount) {
 			LastLoginTime:         src.LastLoginTime,
 			LastActivityTime:      src.LastActivityTime,
 			RequireChangePassword: src.RequireChangePassword,
+			PasswordChangedTime:   src.PasswordChangedTime,
 			CreatedTime:           src.CreatedTime,
 			UpdatedTime:           src.UpdatedTime,
 			DeletedTime:           src.DeletedTime,
@@ -92,6 +94,7 @@ func toCstAccountStoreModel(src model.CstAccount) cstAccountStoreModel {
 		LastLoginTime:         src.LastLoginTime,
 		LastActivityTime:      src.LastActivityTime,
 		RequireChangePassword: src.RequireChangePassword,
+		PasswordChangedTime:   src.PasswordChangedTime,
 		CreatedTime:           src.CreatedTime,
 		UpdatedTime:           src.UpdatedTime,
 		DeletedTime:           src.DeletedTime,
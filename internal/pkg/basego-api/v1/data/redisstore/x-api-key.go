@@ -2,6 +2,7 @@ package redisstore
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
 	"github.com/jonylim/basego/internal/pkg/common/logger"
@@ -62,6 +63,20 @@ func (store *XAPIKeyStore) Delete(apiKeyID string) (bool, error) {
 	return (count != 0), nil
 }
 
+// SaveNonce saves the nonce of an API key's signed request until the TTL.
+// If the nonce has been saved, i.e. the request is a replay, saved returns false.
+func (store *XAPIKeyStore) SaveNonce(apiKeyID, nonce string, ttl time.Duration) (saved bool, err error) {
+	key := fmt.Sprintf("%s:%s:%s:nonce:%s", store.baseKey, store.domain, apiKeyID, nonce)
+	_, err = redis.String(store.conn.Do("SET", key, 1, "NX", "PX", int64(ttl/time.Millisecond)))
+	if err == redis.ErrNil {
+		return false, nil
+	} else if err != nil {
+		logger.Error("XAPIKeyStore", logger.FromError(err))
+		return false, err
+	}
+	return true, nil
+}
+
 func (store *XAPIKeyStore) generateStoreKey(apiKeyID string) string {
 	return fmt.Sprintf("%s:%s:%s", store.baseKey, store.domain, apiKeyID)
 }
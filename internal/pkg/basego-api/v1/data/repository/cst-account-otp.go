@@ -6,7 +6,6 @@ import (
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/dao"
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/redisstore"
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
-	"github.com/jonylim/basego/internal/pkg/basego-api/v1/token/otp"
 
 	"github.com/gomodule/redigo/redis"
 )
@@ -18,18 +17,20 @@ type CstAccountOTPRepo struct {
 
 	redisConn redis.Conn
 	store     *redisstore.CstAccountOTPStore
-	cacheTTL  int
+	cacheTTL  func(action string) int
 }
 
 // NewCstAccountOTPRepo returns new instance of CstAccountOTPRepo.
-func NewCstAccountOTPRepo(redisConn redis.Conn) *CstAccountOTPRepo {
+// The OTPs are cached in Redis for cacheTTL(action) seconds, which should outlive the OTPs of the action.
+// The missing OTPs by ID have no action, they're cached for cacheTTL("").
+func NewCstAccountOTPRepo(redisConn redis.Conn, cacheTTL func(action string) int) *CstAccountOTPRepo {
 	return &CstAccountOTPRepo{
 		ErrNotFound: errNotFound,
 		ErrDatabase: errDatabase,
 
 		redisConn: redisConn,
 		store:     redisstore.NewCstAccountOTPStore(redisConn),
-		cacheTTL:  otp.TTL + 300,
+		cacheTTL:  cacheTTL,
 	}
 }
 
@@ -50,13 +51,13 @@ func (instance *CstAccountOTPRepo) GetOTPByID(id int64) (model.CstAccountOTP, er
 		if err != nil {
 			if err == sql.ErrNoRows {
 				// Save nil to Redis.
-				instance.store.SaveNilByID(id, instance.cacheTTL)
+				instance.store.SaveNilByID(id, instance.cacheTTL(""))
 				return otp, instance.ErrNotFound
 			}
 			return otp, instance.ErrDatabase
 		}
 		// Save to Redis.
-		instance.store.SaveOTPByID(otp, instance.cacheTTL)
+		instance.store.SaveOTPByID(otp, instance.cacheTTL(otp.Action))
 	}
 	if instance.exists(otp) {
 		return otp, nil
@@ -76,13 +77,13 @@ func (instance *CstAccountOTPRepo) GetActiveOTPByAccountAndAction(accountID int6
 		if err != nil {
 			if err == sql.ErrNoRows {
 				// Save nil to Redis.
-				instance.store.SaveNilByAccountAndAction(accountID, action, instance.cacheTTL)
+				instance.store.SaveNilByAccountAndAction(accountID, action, instance.cacheTTL(action))
 				return otp, instance.ErrNotFound
 			}
 			return otp, instance.ErrDatabase
 		}
 		// Save to Redis.
-		instance.store.SaveOTPByAccountAndAction(otp, instance.cacheTTL)
+		instance.store.SaveOTPByAccountAndAction(otp, instance.cacheTTL(otp.Action))
 	}
 	if instance.exists(otp) {
 		return otp, nil
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/dao"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/redisstore"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// CstAccountPreferenceRepo manages data operations for customer account preferences, especially cache operations.
+type CstAccountPreferenceRepo struct {
+	ErrDatabase error
+
+	redisConn redis.Conn
+	store     *redisstore.CstAccountPreferenceStore
+}
+
+// NewCstAccountPreferenceRepo returns new instance of CstAccountPreferenceRepo.
+func NewCstAccountPreferenceRepo(redisConn redis.Conn) *CstAccountPreferenceRepo {
+	return &CstAccountPreferenceRepo{
+		ErrDatabase: errDatabase,
+
+		redisConn: redisConn,
+		store:     redisstore.NewCstAccountPreferenceStore(redisConn),
+	}
+}
+
+// RedisStore returns Redis store used by the repository.
+func (instance *CstAccountPreferenceRepo) RedisStore() *redisstore.CstAccountPreferenceStore {
+	return instance.store
+}
+
+// GetByAccountID returns the preference values set by a customer account.
+// Keys which are never set by the account are not included.
+func (instance *CstAccountPreferenceRepo) GetByAccountID(accountID int64) (map[string]string, error) {
+	// Get from Redis.
+	values, err := instance.store.GetByAccountID(accountID)
+	if err == nil {
+		return values, nil
+	}
+	return instance.SyncByAccountID(accountID)
+}
+
+// SyncByAccountID syncs a customer account's preference values to Redis.
+func (instance *CstAccountPreferenceRepo) SyncByAccountID(accountID int64) (map[string]string, error) {
+	// Get from database.
+	items, err := dao.NewCstAccountPreferenceDAO().GetByAccountID(accountID)
+	if err != nil {
+		// Delete from Redis.
+		instance.store.DeleteByAccountID(accountID)
+		return nil, instance.ErrDatabase
+	}
+	values := make(map[string]string, len(items))
+	for _, it := range items {
+		values[it.Key] = it.Value
+	}
+	// Save to Redis.
+	instance.store.Save(accountID, values)
+	return values, nil
+}
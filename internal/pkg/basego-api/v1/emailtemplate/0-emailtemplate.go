@@ -4,11 +4,46 @@ import (
 	"bytes"
 	"fmt"
 	"html/template"
+	"os"
 	"sync"
+	"time"
 
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/preference"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
 	"github.com/jonylim/basego/internal/pkg/common/logger"
 )
 
+// baseDir is the directory of the email templates. Templates for a locale other than the default
+// are stored in a subdirectory named by the locale, e.g. "id/reset-password.html".
+const baseDir = "assets/templates/email/"
+
+// timeFormat is the format of times shown in emails.
+const timeFormat = "02 Jan 2006 15:04 MST"
+
+// Recipient contains the details of an email's recipient used to generate the email.
+type Recipient struct {
+	Name     string
+	Locale   string
+	Location *time.Location
+}
+
+// NewRecipient returns a recipient using the locale and time zone of the preferences.
+func NewRecipient(name string, prefs preference.Preferences) Recipient {
+	return Recipient{
+		Name:     name,
+		Locale:   prefs.Locale(),
+		Location: prefs.Location(),
+	}
+}
+
+func (r Recipient) formatTime(t time.Time) string {
+	loc := r.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	return t.In(loc).Format(timeFormat)
+}
+
 type parsedTemplate struct {
 	tpl *template.Template
 	err error
@@ -23,13 +58,24 @@ func getByFilename(filename string) (*template.Template, error) {
 
 	t, ok := mapTemplates[filename]
 	if t == nil || !ok {
-		tpl, err := template.ParseFiles("assets/templates/email/" + filename)
+		tpl, err := template.ParseFiles(baseDir + filename)
 		t = &parsedTemplate{tpl, err}
 		mapTemplates[filename] = t
 	}
 	return t.tpl, t.err
 }
 
+// getByLocale returns the template for a locale, or the default template if the locale has none.
+func getByLocale(locale, filename string) (*template.Template, error) {
+	if locale != "" && locale != i18n.DefaultLocale {
+		localized := locale + "/" + filename
+		if _, err := os.Stat(baseDir + localized); err == nil {
+			return getByFilename(localized)
+		}
+	}
+	return getByFilename(filename)
+}
+
 func generateFromTemplate(templateFilename string, data interface{}) (body string, err error) {
 	var t *template.Template
 	t, err = getByFilename(templateFilename)
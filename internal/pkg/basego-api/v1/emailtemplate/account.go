@@ -4,25 +4,28 @@ import (
 	"bytes"
 	"fmt"
 	"html/template"
+	"time"
 
 	"github.com/jonylim/basego/internal/pkg/common/helper"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
 	"github.com/jonylim/basego/internal/pkg/common/logger"
 )
 
 // VerifyEmailAddress returns template for email "Verify Email Address".
-func VerifyEmailAddress(accountName, link, otpCode string, ttlHours int) (subject, body string, err error) {
+func VerifyEmailAddress(to Recipient, link, otpCode string, ttlHours int) (subject, body string, err error) {
 	var t *template.Template
-	t, err = getByFilename("verify-email-address.html")
+	t, err = getByLocale(to.Locale, "verify-email-address.html")
 	if err != nil {
 		logger.Fatal("emailtemplate", fmt.Sprintf("VerifyEmailAddress: %v", logger.FromError(err)))
 		return
 	}
-	data := struct{ Title, Name, Code, Link, TTLHours string }{
-		Title:    "Please verify your email address",
-		Name:     accountName,
-		Link:     link,
-		Code:     otpCode,
-		TTLHours: helper.IntToString(ttlHours),
+	data := struct{ Title, Name, Code, Link, TTLHours, ExpiryTime string }{
+		Title:      i18n.NewMessage(i18n.MsgEmailSubjectVerifyEmailAddress).Localize(to.Locale),
+		Name:       to.Name,
+		Link:       link,
+		Code:       otpCode,
+		TTLHours:   helper.IntToString(ttlHours),
+		ExpiryTime: to.formatTime(time.Now().Add(time.Duration(ttlHours) * time.Hour)),
 	}
 	var buf bytes.Buffer
 	if err = t.Execute(&buf, data); err != nil {
@@ -34,19 +37,20 @@ func VerifyEmailAddress(accountName, link, otpCode string, ttlHours int) (subjec
 }
 
 // ResetPassword returns template for email "Reset Password Email".
-func ResetPassword(accountName, link, otpCode string, ttlHours int) (subject, body string, err error) {
+func ResetPassword(to Recipient, link, otpCode string, ttlHours int) (subject, body string, err error) {
 	var t *template.Template
-	t, err = getByFilename("reset-password.html")
+	t, err = getByLocale(to.Locale, "reset-password.html")
 	if err != nil {
 		logger.Fatal("emailtemplate", fmt.Sprintf("ResetPassword: %v", logger.FromError(err)))
 		return
 	}
-	data := struct{ Title, Name, Code, Link, TTLHours string }{
-		Title:    "Reset your password",
-		Name:     accountName,
-		Link:     link,
-		Code:     otpCode,
-		TTLHours: helper.IntToString(ttlHours),
+	data := struct{ Title, Name, Code, Link, TTLHours, ExpiryTime string }{
+		Title:      i18n.NewMessage(i18n.MsgEmailSubjectResetPassword).Localize(to.Locale),
+		Name:       to.Name,
+		Link:       link,
+		Code:       otpCode,
+		TTLHours:   helper.IntToString(ttlHours),
+		ExpiryTime: to.formatTime(time.Now().Add(time.Duration(ttlHours) * time.Hour)),
 	}
 	var buf bytes.Buffer
 	if err = t.Execute(&buf, data); err != nil {
@@ -56,3 +60,25 @@ func ResetPassword(accountName, link, otpCode string, ttlHours int) (subject, bo
 	subject, body = data.Title, string(buf.Bytes())
 	return
 }
+
+// PasswordChanged returns template for email "Password Changed" security notification.
+func PasswordChanged(to Recipient, changedTime time.Time) (subject, body string, err error) {
+	var t *template.Template
+	t, err = getByLocale(to.Locale, "password-changed.html")
+	if err != nil {
+		logger.Fatal("emailtemplate", fmt.Sprintf("PasswordChanged: %v", logger.FromError(err)))
+		return
+	}
+	data := struct{ Title, Name, ChangedTime string }{
+		Title:       i18n.NewMessage(i18n.MsgEmailSubjectPasswordChanged).Localize(to.Locale),
+		Name:        to.Name,
+		ChangedTime: to.formatTime(changedTime),
+	}
+	var buf bytes.Buffer
+	if err = t.Execute(&buf, data); err != nil {
+		logger.Fatal("emailtemplate", fmt.Sprintf("PasswordChanged: %v", logger.FromError(err)))
+		return
+	}
+	subject, body = data.Title, string(buf.Bytes())
+	return
+}
@@ -0,0 +1,98 @@
+package filegc
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+)
+
+// Config is the file GC's configuration.
+type Config struct {
+	Enabled  bool
+	Interval time.Duration
+
+	// Retention is how long the objects of the deleted files are kept.
+	Retention time.Duration
+
+	// OrphanGracePeriod is how old the objects without files must be to be orphans, as an upload stores
+	// its objects before inserting its file.
+	OrphanGracePeriod time.Duration
+
+	// Delete deletes the orphans and the expired objects, otherwise they're only reported.
+	Delete bool
+}
+
+// Default returns the default configuration.
+func Default() Config {
+	return Config{
+		Enabled:           false,
+		Interval:          24 * time.Hour,
+		Retention:         30 * 24 * time.Hour,
+		OrphanGracePeriod: 24 * time.Hour,
+		Delete:            false,
+	}
+}
+
+var (
+	current = Default()
+	mutex   sync.RWMutex
+)
+
+// Init loads the configuration from environment variables.
+func Init() {
+	c := Default()
+	c.Enabled = getEnvBool(envvar.FileGC.Enabled, c.Enabled)
+	c.Interval = getEnvDuration(envvar.FileGC.Interval, c.Interval, time.Minute)
+	c.Retention = getEnvDuration(envvar.FileGC.Retention, c.Retention, 0)
+	c.OrphanGracePeriod = getEnvDuration(envvar.FileGC.OrphanGracePeriod, c.OrphanGracePeriod, 0)
+	c.Delete = getEnvBool(envvar.FileGC.Delete, c.Delete)
+	logger.Println("filegc", fmt.Sprintf("Enabled = %v, Interval = %v, Retention = %v, OrphanGracePeriod = %v, Delete = %v",
+		c.Enabled, c.Interval, c.Retention, c.OrphanGracePeriod, c.Delete))
+	Set(c)
+}
+
+// Get returns the active configuration.
+func Get() Config {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return current
+}
+
+// Set replaces the active configuration.
+func Set(c Config) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	current = c
+}
+
+func getEnvBool(key string, def bool) bool {
+	s := os.Getenv(key)
+	if s == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(strings.TrimSpace(s))
+	if err != nil {
+		logger.Println("filegc", fmt.Sprintf("WARN: %s '%s' is invalid, set to '%v' as default", key, s, def))
+		return def
+	}
+	return b
+}
+
+func getEnvDuration(key string, def, min time.Duration) time.Duration {
+	s := os.Getenv(key)
+	if s == "" {
+		return def
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(s))
+	if err != nil || d < min {
+		logger.Println("filegc", fmt.Sprintf("WARN: %s '%s' is invalid, set to '%v' as default", key, s, def))
+		return def
+	}
+	return d
+}
@@ -0,0 +1,275 @@
+// Package filegc reconciles the files' records with the objects in their storages, and deletes the orphaned objects
+// and the objects of the files deleted for longer than the retention.
+package filegc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/dao"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/common/data/redis"
+	"github.com/jonylim/basego/internal/pkg/common/helper"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+	"github.com/jonylim/basego/internal/pkg/common/storage"
+)
+
+// lockKey is the Redis key of the lock, so only one instance runs the GC at a time.
+const lockKey = "lock:fileGC"
+
+// batchSize is the number of files loaded at a time.
+const batchSize = 1000
+
+// Statuses of the reported objects.
+const (
+	StatusOrphan   = "orphan"   // No file has the object.
+	StatusExpired  = "expired"  // The object's files were deleted before the retention.
+	StatusRetained = "retained" // The object's files were deleted within the retention.
+	StatusMissing  = "missing"  // An existing file's object isn't in its storage.
+)
+
+// Options are the options of a GC run.
+type Options struct {
+	Now               time.Time
+	Retention         time.Duration
+	OrphanGracePeriod time.Duration
+	Delete            bool
+}
+
+// Entry is a reported object.
+type Entry struct {
+	Filepath string
+	Status   string
+
+	// FileID is the latest file of the object, 0 for the orphans.
+	FileID       int64
+	Size         int64
+	ModifiedTime time.Time
+
+	// Deleted is whether the object has been deleted, Err is the error deleting it.
+	Deleted bool
+	Err     error
+}
+
+// Report is the result of reconciling a storage.
+type Report struct {
+	Storage string
+	Entries []Entry
+
+	// Referenced is the number of objects of the existing files, Recent is the number of objects without files
+	// within the orphan grace period. They aren't in the entries.
+	Referenced, Recent int
+
+	// Err is the error which stopped reconciling the storage.
+	Err error
+}
+
+// Count returns the number of entries with the status, and the number of them deleted.
+func (r Report) Count(status string) (count, deleted int) {
+	for _, entry := range r.Entries {
+		if entry.Status == status {
+			count++
+			if entry.Deleted {
+				deleted++
+			}
+		}
+	}
+	return
+}
+
+// Run runs the GC every interval until stop is closed. It returns immediately if the GC is disabled.
+func Run(stop <-chan struct{}) {
+	c := Get()
+	if !c.Enabled {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stop
+		cancel()
+	}()
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			c = Get()
+			opts := Options{Now: now, Retention: c.Retention, OrphanGracePeriod: c.OrphanGracePeriod, Delete: c.Delete}
+			reports, _, err := RunOnce(ctx, opts)
+			if err != nil {
+				logger.Error("filegc", logger.FromError(err))
+			}
+			for _, r := range reports {
+				logReport(r)
+			}
+		}
+	}
+}
+
+// RunOnce reconciles every storage of the files, and the default storage, if no other instance is running the GC.
+// ran is false if another instance is running it.
+func RunOnce(ctx context.Context, opts Options) (reports []Report, ran bool, err error) {
+	redisConn := redis.GetConnection()
+	defer redisConn.Close()
+
+	// The lock expires after an interval, in case this instance stops while holding it.
+	token, err := redis.TryLock(redisConn, lockKey, Get().Interval)
+	if err != nil || token == "" {
+		return nil, false, err
+	}
+	defer redis.Unlock(redisConn, lockKey, token)
+
+	storages, err := dao.NewFileDAO().GetStorages()
+	if err != nil {
+		return nil, true, err
+	}
+	// The default storage may have orphans of failed uploads without any file.
+	def, hasDefault := storage.GetDefaultStorage(), false
+	for _, name := range storages {
+		hasDefault = hasDefault || name == def
+	}
+	if !hasDefault {
+		storages = append(storages, def)
+	}
+	for _, name := range storages {
+		if err = ctx.Err(); err != nil {
+			return reports, true, err
+		}
+		reports = append(reports, reconcileStorage(ctx, name, opts))
+	}
+	return reports, true, nil
+}
+
+// reconcileStorage loads the files of a storage, including the deleted ones, and reconciles it.
+func reconcileStorage(ctx context.Context, name string, opts Options) Report {
+	st, err := storage.GetObjectStorageInstance(name)
+	if err != nil {
+		return Report{Storage: name, Err: err}
+	}
+	fileDAO := dao.NewFileDAO()
+	fileDAO.WithDeleted()
+	files := make([]model.File, 0)
+	var afterID int64
+	for {
+		batch, err := fileDAO.GetListByStorage(name, afterID, batchSize)
+		if err != nil {
+			return Report{Storage: name, Err: err}
+		} else if len(batch) == 0 {
+			break
+		}
+		files = append(files, batch...)
+		afterID = batch[len(batch)-1].ID
+	}
+	return Reconcile(ctx, name, st, files, opts)
+}
+
+// objectRef is the reference of an object by its files.
+type objectRef struct {
+	fileID      int64
+	exists      bool
+	deletedTime int64 // Of the latest deleted file, if no existing file has the object.
+}
+
+// Reconcile compares the files in a storage, including the deleted ones, with the objects under the files'
+// prefixes, and deletes the orphans and the expired objects if opts.Delete is set.
+// The objects are deleted after listing them, so a file uploaded in the meantime is within the grace period.
+func Reconcile(ctx context.Context, name string, st storage.ObjectStorage, files []model.File, opts Options) (report Report) {
+	report.Storage = name
+	refs := make(map[string]*objectRef)
+	for _, file := range files {
+		// The objects of the unknown files aren't under the listed prefixes.
+		filepaths, err := storage.GetFileObjectFilepaths(file)
+		if err != nil {
+			continue
+		}
+		for _, p := range filepaths {
+			ref := refs[p]
+			if ref == nil {
+				ref = &objectRef{}
+				refs[p] = ref
+			}
+			if file.DeletedTime == 0 {
+				ref.fileID, ref.exists = file.ID, true
+			} else if !ref.exists && file.DeletedTime >= ref.deletedTime {
+				ref.fileID, ref.deletedTime = file.ID, file.DeletedTime
+			}
+		}
+	}
+
+	expiredBefore := helper.UnixMillisecond(opts.Now.Add(-opts.Retention))
+	orphanBefore := opts.Now.Add(-opts.OrphanGracePeriod)
+	listed := make(map[string]bool)
+	for _, prefix := range storage.GetFileObjectPrefixes() {
+		report.Err = storage.ListAllObjects(ctx, st, prefix, func(info storage.ObjectInfo) error {
+			listed[info.Filepath] = true
+			entry := Entry{Filepath: info.Filepath, Size: info.Size, ModifiedTime: info.ModifiedTime}
+			ref := refs[info.Filepath]
+			switch {
+			case ref == nil && info.ModifiedTime.After(orphanBefore):
+				report.Recent++
+				return nil
+			case ref == nil:
+				entry.Status = StatusOrphan
+			case ref.exists:
+				report.Referenced++
+				return nil
+			case ref.deletedTime < expiredBefore:
+				entry.Status, entry.FileID = StatusExpired, ref.fileID
+			default:
+				entry.Status, entry.FileID = StatusRetained, ref.fileID
+			}
+			report.Entries = append(report.Entries, entry)
+			return nil
+		})
+		if report.Err != nil {
+			return
+		}
+	}
+
+	missing := make([]string, 0)
+	for p, ref := range refs {
+		if ref.exists && !listed[p] {
+			missing = append(missing, p)
+		}
+	}
+	sort.Strings(missing)
+	for _, p := range missing {
+		report.Entries = append(report.Entries, Entry{Filepath: p, Status: StatusMissing, FileID: refs[p].fileID})
+	}
+
+	if !opts.Delete {
+		return
+	}
+	for i, entry := range report.Entries {
+		if entry.Status != StatusOrphan && entry.Status != StatusExpired {
+			continue
+		}
+		if report.Err = ctx.Err(); report.Err != nil {
+			return
+		}
+		code, err := st.DeleteObjectContext(ctx, entry.Filepath)
+		report.Entries[i].Deleted = err == nil || code == storage.ErrNotFound
+		if !report.Entries[i].Deleted {
+			report.Entries[i].Err = err
+		}
+	}
+	return
+}
+
+func logReport(r Report) {
+	if r.Err != nil {
+		logger.Error("filegc", fmt.Sprintf("Storage '%s': %v", r.Storage, r.Err))
+	}
+	orphans, deletedOrphans := r.Count(StatusOrphan)
+	expired, deletedExpired := r.Count(StatusExpired)
+	retained, _ := r.Count(StatusRetained)
+	missing, _ := r.Count(StatusMissing)
+	if orphans != 0 || expired != 0 || missing != 0 {
+		logger.Println("filegc", fmt.Sprintf("Storage '%s': %d orphans (%d deleted), %d expired (%d deleted), %d retained, %d missing",
+			r.Storage, orphans, deletedOrphans, expired, deletedExpired, retained, missing))
+	}
+}
@@ -0,0 +1,108 @@
+package filegc
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/common/constant"
+	"github.com/jonylim/basego/internal/pkg/common/helper"
+	"github.com/jonylim/basego/internal/pkg/common/storage"
+)
+
+func photo(id int64, filename string, deletedTime time.Time) model.File {
+	file := model.File{ID: id, OwnerType: constant.FileOwnerTypeCstAccount, Category: constant.FileCategoryPhoto, Filename: filename}
+	if !deletedTime.IsZero() {
+		file.DeletedTime = helper.UnixMillisecond(deletedTime)
+	}
+	return file
+}
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+	st := storage.NewMemory()
+	for _, filename := range []string{"live", "old", "recent", "reused", "orphan"} {
+		full, thumb := storage.GetCstAccountPhotoFilepath(filename)
+		for _, p := range []string{full, thumb} {
+			if err := st.StoreObjectContext(ctx, p, strings.NewReader(filename), storage.StoreOptions{}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	st.StoreObjectContext(ctx, "other/file", strings.NewReader("other"), storage.StoreOptions{})
+
+	now := time.Now().Add(48 * time.Hour)
+	files := []model.File{
+		photo(1, "live", time.Time{}),
+		photo(2, "old", now.Add(-40*24*time.Hour)),
+		photo(3, "recent", now.Add(-time.Hour)),
+		photo(4, "reused", now.Add(-40*24*time.Hour)),
+		photo(5, "reused", time.Time{}),
+		photo(6, "missing", time.Time{}),
+		{ID: 7, OwnerType: constant.FileOwnerTypeCstAccount, Category: constant.FileCategoryAvatar, Filename: "unknown"},
+	}
+	opts := Options{Now: now, Retention: 30 * 24 * time.Hour, OrphanGracePeriod: 24 * time.Hour}
+
+	statuses := func(r Report) map[string]string {
+		res := make(map[string]string)
+		for _, e := range r.Entries {
+			res[e.Filepath] = e.Status
+		}
+		return res
+	}
+	path := func(filename, variant string) string {
+		return "cst_acc/photo/" + filename + "-" + variant
+	}
+	expected := map[string]string{
+		path("old", "full"): StatusExpired, path("old", "thumb"): StatusExpired,
+		path("recent", "full"): StatusRetained, path("recent", "thumb"): StatusRetained,
+		path("orphan", "full"): StatusOrphan, path("orphan", "thumb"): StatusOrphan,
+		path("missing", "full"): StatusMissing, path("missing", "thumb"): StatusMissing,
+	}
+
+	// The objects are only reported without opts.Delete.
+	r := Reconcile(ctx, "memory", st, files, opts)
+	if r.Err != nil || r.Referenced != 4 || r.Recent != 0 || !reflect.DeepEqual(statuses(r), expected) {
+		t.Errorf("Reconcile() = %v referenced, %v recent, %v, %v; expected 4, 0, %v", r.Referenced, r.Recent, statuses(r), r.Err, expected)
+	}
+	if n := len(st.Filepaths()); n != 11 {
+		t.Errorf("Reconcile() without delete left %d objects; expected 11", n)
+	}
+
+	// The objects within the grace period aren't orphans yet.
+	r = Reconcile(ctx, "memory", st, files, Options{Now: time.Now(), Retention: opts.Retention, OrphanGracePeriod: time.Hour})
+	if orphans, _ := r.Count(StatusOrphan); orphans != 0 || r.Recent != 2 {
+		t.Errorf("Reconcile() within the grace period = %d orphans, %d recent; expected 0, 2", orphans, r.Recent)
+	}
+
+	opts.Delete = true
+	r = Reconcile(ctx, "memory", st, files, opts)
+	orphans, deletedOrphans := r.Count(StatusOrphan)
+	expired, deletedExpired := r.Count(StatusExpired)
+	if r.Err != nil || orphans != 2 || deletedOrphans != 2 || expired != 2 || deletedExpired != 2 {
+		t.Errorf("Reconcile() with delete = %d/%d orphans, %d/%d expired deleted, %v", deletedOrphans, orphans, deletedExpired, expired, r.Err)
+	}
+	left := []string{
+		path("live", "full"), path("live", "thumb"), path("recent", "full"), path("recent", "thumb"),
+		path("reused", "full"), path("reused", "thumb"), "other/file",
+	}
+	if res := st.Filepaths(); !reflect.DeepEqual(res, left) {
+		t.Errorf("Reconcile() with delete left %v; expected %v", res, left)
+	}
+
+	// A failing deletion is reported, and the object isn't deleted.
+	st.StoreObjectContext(ctx, path("orphan", "full"), strings.NewReader("orphan"), storage.StoreOptions{})
+	st.InjectFault(storage.MemoryFault{Op: "DeleteObject", Err: storage.ErrMemoryFault})
+	r = Reconcile(ctx, "memory", st, files, opts)
+	if len(r.Entries) == 0 {
+		t.Fatal("Reconcile() reports nothing")
+	}
+	for _, e := range r.Entries {
+		if e.Status == StatusOrphan && (e.Deleted || e.Err != storage.ErrMemoryFault) {
+			t.Errorf("Reconcile() failing to delete %s = %v, %v; expected false, %v", e.Filepath, e.Deleted, e.Err, storage.ErrMemoryFault)
+		}
+	}
+}
@@ -1,6 +1,6 @@
 package model
 
-// CstAccountOTP contains an OTP's information.
+// CstAccountOTP contains an OTP's information. Code is the hash of the code, see package secrethash.
 type CstAccountOTP struct {
 	RedisNil           bool   `json:"-" redis:"redisNil"`
 	ID                 int64  `json:"-" redis:"id"`
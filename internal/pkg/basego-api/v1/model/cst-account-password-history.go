@@ -0,0 +1,10 @@
+package model
+
+// CstAccountPasswordHistory contains a customer account's previous password.
+type CstAccountPasswordHistory struct {
+	ID           int64  `json:"-"`
+	AccountID    int64  `json:"-"`
+	Password     string `json:"-"`
+	PasswordSalt string `json:"-"`
+	CreatedTime  int64  `json:"-"`
+}
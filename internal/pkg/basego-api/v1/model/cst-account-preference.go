@@ -0,0 +1,11 @@
+package model
+
+// CstAccountPreference contains a customer account's preference value.
+type CstAccountPreference struct {
+	ID          int64  `json:"-"`
+	AccountID   int64  `json:"-"`
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	CreatedTime int64  `json:"-"`
+	UpdatedTime int64  `json:"updatedTime"`
+}
@@ -0,0 +1,12 @@
+package model
+
+// CstAccountReminder contains a reminder email sent to a customer account.
+// Step is the reminder's position in its kind's schedule, starting from 1.
+type CstAccountReminder struct {
+	ID          int64  `json:"-"`
+	AccountID   int64  `json:"-"`
+	Kind        string `json:"-"`
+	Step        int    `json:"-"`
+	OTPID       int64  `json:"-"`
+	CreatedTime int64  `json:"-"`
+}
@@ -17,6 +17,7 @@ type CstAccountSession struct {
 }
 
 // CstAccountSessionToken contains details of an access token & refresh token.
+// AccessToken and RefreshToken are the hashes of the token strings, see package secrethash.
 type CstAccountSessionToken struct {
 	RedisNil           bool   `redis:"redisNil"`
 	ID                 int64  `redis:"id"`
@@ -18,6 +18,7 @@ type CstAccount struct {
 	LastLoginTime         int64    `json:"lastLoginTime"`
 	LastActivityTime      int64    `json:"lastActivityTime"`
 	RequireChangePassword bool     `json:"requireChangePassword"`
+	PasswordChangedTime   int64    `json:"-"`
 	CreatedTime           int64    `json:"createdTime"`
 	UpdatedTime           int64    `json:"updatedTime"`
 	DeletedTime           int64    `json:"deletedTime"`
@@ -1,5 +1,11 @@
 package model
 
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
 // File contains a file's information.
 type File struct {
 	RedisNil         bool   `redis:"redisNil"`
@@ -20,10 +26,85 @@ type File struct {
 	ThumbWidth       int    `redis:"thumbWidth"`
 	ThumbHeight      int    `redis:"thumbHeight"`
 	Storage          string `redis:"storage"`
+	IsPublic         bool   `redis:"isPublic"`
 	IsEncrypted      bool   `redis:"isEncrypted"`
 	EncryptKey       string `redis:"encryptKey"`
 	Uploader         string `redis:"uploader"`
 	CreatedTime      int64  `redis:"createdTime"`
 	UpdatedTime      int64  `redis:"updatedTime"`
 	DeletedTime      int64  `redis:"deletedTime"`
+
+	// Variants are the image's variants, e.g. its thumbnail, whose objects are suffixed by their names.
+	// The older images have none, only their fullsize and thumbnail images.
+	Variants FileVariants `redis:"variants"`
+	Blurhash string       `redis:"blurhash"`
+}
+
+// FileVariant contains the information of a variant of an image file.
+type FileVariant struct {
+	Name      string `json:"name"`
+	MediaType string `json:"mediaType"`
+	FileExt   string `json:"fileExt"`
+	FileSize  int64  `json:"fileSize"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+}
+
+// FileVariants are the variants of an image file, saved as JSON in the database and Redis.
+type FileVariants []FileVariant
+
+// Get returns the variant by name.
+func (v FileVariants) Get(name string) (FileVariant, bool) {
+	for _, item := range v {
+		if item.Name == name {
+			return item, true
+		}
+	}
+	return FileVariant{}, false
+}
+
+// Value implements driver.Valuer.
+func (v FileVariants) Value() (driver.Value, error) {
+	if v == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+// Scan implements sql.Scanner.
+func (v *FileVariants) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*v = nil
+		return nil
+	case []byte:
+		return v.unmarshal(src)
+	case string:
+		return v.unmarshal([]byte(src))
+	}
+	return fmt.Errorf("Can't scan %T into FileVariants", src)
+}
+
+// RedisArg implements redis.Argument.
+func (v FileVariants) RedisArg() interface{} {
+	s, _ := v.Value()
+	return s
+}
+
+// RedisScan implements redis.Scanner.
+func (v *FileVariants) RedisScan(src interface{}) error {
+	return v.Scan(src)
+}
+
+func (v *FileVariants) unmarshal(b []byte) error {
+	var res FileVariants
+	if err := json.Unmarshal(b, &res); err != nil {
+		return err
+	}
+	if len(res) == 0 {
+		res = nil
+	}
+	*v = res
+	return nil
 }
@@ -0,0 +1,11 @@
+package model
+
+// XAPIKeyUsage contains the number of requests made with an API key to an endpoint in a period, by status class.
+type XAPIKeyUsage struct {
+	Domain       string
+	APIKeyID     string
+	PeriodStart  int64  // In Unix milliseconds.
+	Endpoint     string // The API group and name, e.g. "client/register".
+	StatusClass  string // e.g. "2xx".
+	RequestCount int64
+}
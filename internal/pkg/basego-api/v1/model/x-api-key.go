@@ -1,6 +1,7 @@
 package model
 
 // XAPIKey contains details of an API key for validating API requests.
+// The secrets are hashes (see package secrethash), the signing keys are derived from the secrets to verify the signatures.
 type XAPIKey struct {
 	ID            int32  `redis:"id"`
 	APIKeyID      string `redis:"keyID"`
@@ -13,4 +14,20 @@ type XAPIKey struct {
 	CreatedTime   int64  `redis:"createdTime"`
 	UpdatedTime   int64  `redis:"updatedTime"`
 	DeletedTime   int64  `redis:"deletedTime"`
+
+	// PreviousSecret is the secret replaced by the last rotation, accepted until PreviousSecretExpiryTime.
+	PreviousSecret           string `redis:"prevKeySec"`
+	PreviousSecretExpiryTime int64  `redis:"prevKeySecExpiryTime"`
+
+	// RequireSignature requires the requests to be signed with the signing key.
+	RequireSignature   bool   `redis:"requireSig"`
+	SigningKey         string `redis:"sigKey"`
+	PreviousSigningKey string `redis:"prevSigKey"`
+
+	// Scopes are the patterns of the endpoints the key may call separated by commas, empty for every endpoint.
+	Scopes string `redis:"scopes"`
+
+	// DailyQuota and MonthlyQuota are the requests allowed per UTC day and month, 0 is unlimited.
+	DailyQuota   int64 `redis:"dailyQuota"`
+	MonthlyQuota int64 `redis:"monthlyQuota"`
 }
@@ -0,0 +1,30 @@
+// Package notification sends notifications to customer accounts, respecting their preferences.
+package notification
+
+import (
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/emailtemplate"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/preference"
+	"github.com/jonylim/basego/internal/pkg/common/data/redis"
+	"github.com/jonylim/basego/internal/pkg/common/send/email"
+)
+
+// SendPasswordChanged sends the "Password Changed" security email to an account,
+// unless the account has disabled security email notifications.
+func SendPasswordChanged(account model.CstAccount, changedTime time.Time) {
+	redisConn := redis.GetConnection()
+	prefs := preference.GetOrDefault(redisConn, account.ID)
+	redisConn.Close()
+
+	if !prefs.Bool(preference.KeyEmailNotificationSecurity) {
+		return
+	}
+	subject, body, err := emailtemplate.PasswordChanged(emailtemplate.NewRecipient(account.FullName, prefs), changedTime)
+	if err == nil {
+		email.Send(email.NewHTMLMessage(subject, body), email.Recipients{
+			To: []string{account.Email},
+		})
+	}
+}
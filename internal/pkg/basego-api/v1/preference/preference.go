@@ -0,0 +1,233 @@
+// Package preference defines the known customer account preferences, their defaults and validation.
+package preference
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/repository"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// Defines the known preference keys.
+const (
+	KeyLocale                    = "locale"
+	KeyTimeZone                  = "timeZone"
+	KeyEmailNotificationSecurity = "emailNotification.security"
+)
+
+// Defines the value types of preferences.
+const (
+	TypeString = "string"
+	TypeBool   = "bool"
+)
+
+// Errors returned when validating a preference value.
+var (
+	ErrUnknownKey      = i18n.NewError(i18n.MsgPreferenceUnknown)
+	ErrInvalidType     = i18n.NewError(i18n.MsgPreferenceTypeInvalid)
+	ErrInvalidLocale   = i18n.NewError(i18n.MsgPreferenceLocaleUnsupported)
+	ErrInvalidTimeZone = i18n.NewError(i18n.MsgPreferenceTimeZoneInvalid)
+	ErrDatabase        = i18n.NewError(i18n.MsgProcessingFailed)
+)
+
+// Definition describes a known preference.
+type Definition struct {
+	Key     string
+	Type    string
+	Default string
+
+	// normalize validates a value and returns its stored form.
+	normalize func(ctx context.Context, redisConn redis.Conn, value string) (string, error)
+}
+
+var definitions = map[string]Definition{
+	KeyLocale: {
+		Key:       KeyLocale,
+		Type:      TypeString,
+		Default:   i18n.DefaultLocale,
+		normalize: normalizeLocale,
+	},
+	KeyTimeZone: {
+		Key:       KeyTimeZone,
+		Type:      TypeString,
+		Default:   "UTC",
+		normalize: normalizeTimeZone,
+	},
+	KeyEmailNotificationSecurity: {
+		Key:     KeyEmailNotificationSecurity,
+		Type:    TypeBool,
+		Default: "true",
+	},
+}
+
+// Lookup returns the definition of a preference key.
+func Lookup(key string) (Definition, bool) {
+	def, ok := definitions[key]
+	return def, ok
+}
+
+// Keys returns the known preference keys, sorted by name.
+func Keys() []string {
+	keys := make([]string, 0, len(definitions))
+	for k := range definitions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Normalize validates a value from a JSON request for the specified key and returns its stored form.
+func Normalize(ctx context.Context, redisConn redis.Conn, key string, value interface{}) (string, error) {
+	def, ok := definitions[key]
+	if !ok {
+		return "", ErrUnknownKey
+	}
+	var s string
+	switch def.Type {
+	case TypeBool:
+		b, ok := value.(bool)
+		if !ok {
+			return "", ErrInvalidType
+		}
+		s = strconv.FormatBool(b)
+	default:
+		v, ok := value.(string)
+		if !ok {
+			return "", ErrInvalidType
+		}
+		s = strings.TrimSpace(v)
+	}
+	if def.normalize != nil {
+		return def.normalize(ctx, redisConn, s)
+	}
+	return s, nil
+}
+
+func normalizeLocale(_ context.Context, _ redis.Conn, value string) (string, error) {
+	if locale, ok := i18n.MatchLocale(value); ok {
+		return locale, nil
+	}
+	return "", ErrInvalidLocale
+}
+
+func normalizeTimeZone(ctx context.Context, redisConn redis.Conn, value string) (string, error) {
+	if value == "" {
+		return "", ErrInvalidTimeZone
+	}
+	tzRepo := repository.NewPgTimeZoneRepo(redisConn)
+	tz, err := tzRepo.GetByName(ctx, value)
+	if err != nil {
+		if err == tzRepo.ErrNotFound {
+			return "", ErrInvalidTimeZone
+		}
+		return "", ErrDatabase
+	}
+	// Use the canonical name, the lookup is case-insensitive.
+	return tz.Name, nil
+}
+
+// Preferences contains an account's preference values, falling back to the defaults for keys which aren't set.
+type Preferences struct {
+	values map[string]string
+	set    map[string]bool
+}
+
+// New returns preferences from stored values. Unknown keys are ignored.
+func New(values map[string]string) Preferences {
+	p := Preferences{make(map[string]string, len(definitions)), make(map[string]bool, len(values))}
+	for k, def := range definitions {
+		p.values[k] = def.Default
+	}
+	for k, v := range values {
+		if _, ok := definitions[k]; ok {
+			p.values[k] = v
+			p.set[k] = true
+		}
+	}
+	return p
+}
+
+// Default returns the default preferences.
+func Default() Preferences {
+	return New(nil)
+}
+
+// IsSet checks if a preference is set by the account, instead of using the default value.
+func (p Preferences) IsSet(key string) bool {
+	return p.set[key]
+}
+
+// String returns a preference's value.
+func (p Preferences) String(key string) string {
+	if v, ok := p.values[key]; ok {
+		return v
+	}
+	return definitions[key].Default
+}
+
+// Bool returns a boolean preference's value.
+func (p Preferences) Bool(key string) bool {
+	b, _ := strconv.ParseBool(p.String(key))
+	return b
+}
+
+// Locale returns the preferred locale.
+func (p Preferences) Locale() string {
+	if l, ok := i18n.MatchLocale(p.String(KeyLocale)); ok {
+		return l
+	}
+	return i18n.DefaultLocale
+}
+
+// TimeZone returns the preferred time zone's name.
+func (p Preferences) TimeZone() string {
+	return p.String(KeyTimeZone)
+}
+
+// Location returns the preferred time zone's location, or UTC if it can't be loaded.
+func (p Preferences) Location() *time.Location {
+	loc, err := time.LoadLocation(p.TimeZone())
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Values returns the preference values typed for JSON responses.
+func (p Preferences) Values() map[string]interface{} {
+	res := make(map[string]interface{}, len(definitions))
+	for k, def := range definitions {
+		switch def.Type {
+		case TypeBool:
+			res[k] = p.Bool(k)
+		default:
+			res[k] = p.String(k)
+		}
+	}
+	return res
+}
+
+// Get returns a customer account's preferences.
+func Get(redisConn redis.Conn, accountID int64) (Preferences, error) {
+	values, err := repository.NewCstAccountPreferenceRepo(redisConn).GetByAccountID(accountID)
+	if err != nil {
+		return Default(), err
+	}
+	return New(values), nil
+}
+
+// GetOrDefault returns a customer account's preferences, or the defaults if they can't be loaded.
+func GetOrDefault(redisConn redis.Conn, accountID int64) Preferences {
+	p, err := Get(redisConn, accountID)
+	if err != nil {
+		logger.Error("preference", logger.FromError(err))
+	}
+	return p
+}
@@ -0,0 +1,53 @@
+package preference
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewUsesDefaults(t *testing.T) {
+	p := New(map[string]string{
+		KeyLocale:   "id",
+		"unknown":   "value",
+		KeyTimeZone: "Asia/Jakarta",
+	})
+	values := p.Values()
+	if _, ok := values["unknown"]; ok {
+		t.Errorf(`Values() contains unknown key`)
+	}
+	if !p.IsSet(KeyLocale) || p.IsSet(KeyEmailNotificationSecurity) {
+		t.Errorf(`IsSet() doesn't match the stored values`)
+	}
+	if out := p.Locale(); out != "id" {
+		t.Errorf(`Locale() = "%v"; expected "id"`, out)
+	}
+	if out := values[KeyEmailNotificationSecurity]; out != true {
+		t.Errorf(`Values()["%v"] = %v; expected true`, KeyEmailNotificationSecurity, out)
+	}
+	if out := Default().Location(); out != time.UTC {
+		t.Errorf(`Default().Location() = %v; expected UTC`, out)
+	}
+}
+
+func TestNormalizeWithoutLookup(t *testing.T) {
+	var tests = []struct {
+		key      string
+		value    interface{}
+		expected string
+		err      error
+	}{
+		{KeyLocale, "id-ID", "id", nil},
+		{KeyLocale, "fr", "", ErrInvalidLocale},
+		{KeyLocale, true, "", ErrInvalidType},
+		{KeyEmailNotificationSecurity, false, "false", nil},
+		{KeyEmailNotificationSecurity, "false", "", ErrInvalidType},
+		{KeyTimeZone, "", "", ErrInvalidTimeZone},
+		{"unknown", "x", "", ErrUnknownKey},
+	}
+	for _, test := range tests {
+		out, err := Normalize(nil, nil, test.key, test.value)
+		if out != test.expected || err != test.err {
+			t.Errorf(`Normalize("%v", %v) = "%v", %v; expected "%v", %v`, test.key, test.value, out, err, test.expected, test.err)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+// Package storagemigration moves the uploaded files from a storage to another.
+package storagemigration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/dao"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/redisstore"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/common/constant"
+	"github.com/jonylim/basego/internal/pkg/common/data/db"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+	"github.com/jonylim/basego/internal/pkg/common/storage"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// Default options.
+const (
+	DefaultConcurrency = 4
+	DefaultBatchSize   = 100
+)
+
+// Statuses of the migrated files.
+const (
+	StatusMigrated = "migrated" // Copied and moved to the target storage.
+	StatusPending  = "pending"  // Would be migrated, in a dry run.
+	StatusFailed   = "failed"
+)
+
+// Options are the options of a migration.
+type Options struct {
+	From, To string
+
+	// DryRun checks the files' objects without copying them or updating the files.
+	DryRun bool
+
+	// Concurrency is the number of files copied at once, BatchSize is the number of files moved in a transaction.
+	Concurrency int
+	BatchSize   int
+
+	// OnFile is called with the result of every file, in the order of their IDs.
+	OnFile func(FileResult)
+}
+
+// FileResult is the result of migrating a file.
+type FileResult struct {
+	File   model.File
+	Status string
+
+	// Copied is the number of objects copied, the rest were already in the target storage.
+	Copied int
+	Size   int64
+	Err    error
+}
+
+// Summary counts the migrated files by status.
+type Summary struct {
+	Migrated, Pending, Failed int
+	Copied                    int
+	Size                      int64
+}
+
+func (s *Summary) add(res FileResult) {
+	switch res.Status {
+	case StatusMigrated:
+		s.Migrated++
+	case StatusPending:
+		s.Pending++
+	case StatusFailed:
+		s.Failed++
+	}
+	s.Copied += res.Copied
+	s.Size += res.Size
+}
+
+// Migrator migrates the files of a storage to another.
+type Migrator struct {
+	opts       Options
+	fileDAO    *dao.FileDAO
+	fileStore  *redisstore.FileStore
+	cstAccount *redisstore.CstAccountStore
+
+	// newStorage returns a new instance of a storage, so each file can set whether its objects are public.
+	newStorage func(string) (storage.ObjectStorage, error)
+}
+
+// NewMigrator returns a new Migrator. The storages must be initialized.
+func NewMigrator(redisConn redis.Conn, opts Options) (*Migrator, error) {
+	if opts.From == opts.To {
+		return nil, errors.New("The source and target storages are the same")
+	}
+	for _, st := range []string{opts.From, opts.To} {
+		if _, err := storage.GetObjectStorageInstance(st); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultConcurrency
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	return &Migrator{
+		opts:       opts,
+		fileDAO:    dao.NewFileDAO(),
+		fileStore:  redisstore.NewFileStore(redisConn),
+		cstAccount: redisstore.NewCstAccountStore(redisConn),
+		newStorage: storage.GetObjectStorageInstance,
+	}, nil
+}
+
+// Run migrates the files batch by batch, until every file has been tried or the context is done.
+// The files which failed stay in the source storage, so running it again resumes the migration.
+// The deleted files aren't migrated.
+func (m *Migrator) Run(ctx context.Context) (summary Summary, err error) {
+	var afterID int64
+	for {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		var files []model.File
+		if files, err = m.fileDAO.GetListByStorage(m.opts.From, afterID, m.opts.BatchSize); err != nil {
+			return
+		} else if len(files) == 0 {
+			return
+		}
+		afterID = files[len(files)-1].ID
+
+		results := m.migrateObjects(ctx, files)
+		if !m.opts.DryRun {
+			if err = m.moveFiles(results); err != nil {
+				return
+			}
+		}
+		for _, res := range results {
+			summary.add(res)
+			if m.opts.OnFile != nil {
+				m.opts.OnFile(res)
+			}
+		}
+	}
+}
+
+// migrateObjects copies the objects of the files concurrently.
+func (m *Migrator) migrateObjects(ctx context.Context, files []model.File) []FileResult {
+	results := make([]FileResult, len(files))
+	sem := make(chan struct{}, m.opts.Concurrency)
+	var wg sync.WaitGroup
+	for i := range files {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i] = m.migrateFileObjects(ctx, files[i])
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+func (m *Migrator) migrateFileObjects(ctx context.Context, file model.File) (res FileResult) {
+	res.File = file
+	fail := func(err error) FileResult {
+		res.Status, res.Err = StatusFailed, err
+		return res
+	}
+	filepaths, err := storage.GetFileObjectFilepaths(file)
+	if err != nil {
+		return fail(err)
+	}
+	src, err := m.newStorage(m.opts.From)
+	if err != nil {
+		return fail(err)
+	}
+	dst, err := m.newStorage(m.opts.To)
+	if err != nil {
+		return fail(err)
+	}
+	dst.ShouldMakePublic(file.IsPublic && !file.IsEncrypted)
+	for _, p := range filepaths {
+		copied, size, err := copyObject(ctx, src, dst, p, m.opts.DryRun)
+		if copied {
+			res.Copied++
+		}
+		if err != nil {
+			return fail(fmt.Errorf("%s: %v", p, err))
+		}
+		res.Size += size
+	}
+	if m.opts.DryRun {
+		res.Status = StatusPending
+	} else {
+		res.Status = StatusMigrated
+	}
+	return res
+}
+
+// moveFiles updates the storage of the files whose objects have been copied, then deletes their cached details.
+// A file changed in the meantime, e.g. deleted, isn't updated and is reported as failed.
+func (m *Migrator) moveFiles(results []FileResult) error {
+	ids := make([]int64, 0, len(results))
+	for _, res := range results {
+		if res.Status == StatusMigrated {
+			ids = append(ids, res.File.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	tx, err := db.Get().Begin()
+	if err != nil {
+		logger.Fatal("db.Begin", logger.FromError(err))
+		return err
+	}
+	defer tx.Rollback()
+	updatedFiles, err := m.fileDAO.UpdateStorageByIDs(tx, ids, m.opts.From, m.opts.To)
+	if err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		logger.Fatal("tx.Commit", logger.FromError(err))
+		return err
+	}
+
+	updated := make(map[int64]bool, len(updatedFiles))
+	for _, file := range updatedFiles {
+		updated[file.ID] = true
+		m.fileStore.Delete(file)
+		// The account's cached photo URLs depend on the storage.
+		if file.OwnerType == constant.FileOwnerTypeCstAccount {
+			m.cstAccount.DeleteByID(file.OwnerID)
+		}
+	}
+	for i, res := range results {
+		if res.Status == StatusMigrated && !updated[res.File.ID] {
+			results[i].Status, results[i].Err = StatusFailed, errors.New("File has been changed during the migration")
+		}
+	}
+	return nil
+}
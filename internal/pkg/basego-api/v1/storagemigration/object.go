@@ -0,0 +1,82 @@
+package storagemigration
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io"
+
+	"github.com/jonylim/basego/internal/pkg/common/storage"
+)
+
+// Errors of copying the objects.
+var (
+	ErrSourceNotFound   = errors.New("Object is not found in the source storage")
+	ErrChecksumMismatch = errors.New("Object's checksum in the target storage doesn't match the source")
+)
+
+// copyObject copies an object from the source to the target storage with its metadata, and verifies the copy's
+// SHA-256 checksum. The contents are copied as they're stored, so the encrypted objects stay encrypted.
+// The object isn't copied again if the target already has it, e.g. when resuming, and isn't copied in a dry run.
+func copyObject(ctx context.Context, src, dst storage.ObjectStorage, objFilepath string, dryRun bool) (copied bool, size int64, err error) {
+	srcInfo, code, err := src.StatObject(ctx, objFilepath)
+	if code == storage.ErrNotFound {
+		return false, 0, ErrSourceNotFound
+	} else if err != nil {
+		return false, 0, err
+	}
+
+	// Check whether the target already has the object, it's only trusted if the checksums match.
+	if dstInfo, code, err := dst.StatObject(ctx, objFilepath); err == nil && dstInfo.Size == srcInfo.Size {
+		srcSum, _, err := checksum(ctx, src, objFilepath)
+		if err != nil {
+			return false, 0, err
+		}
+		if dstSum, _, err := checksum(ctx, dst, objFilepath); err == nil && bytes.Equal(srcSum, dstSum) {
+			return false, srcInfo.Size, nil
+		}
+	} else if err != nil && code != storage.ErrNotFound {
+		return false, 0, err
+	}
+	if dryRun {
+		return false, srcInfo.Size, nil
+	}
+
+	reader, _, err := src.FetchObjectContext(ctx, objFilepath)
+	if err != nil {
+		return false, 0, err
+	}
+	defer reader.Close()
+	hash := sha256.New()
+	err = dst.StoreObjectContext(ctx, objFilepath, io.TeeReader(reader, hash), storage.StoreOptions{
+		ContentType:  srcInfo.ContentType,
+		CacheControl: srcInfo.CacheControl,
+		Metadata:     srcInfo.Metadata,
+	})
+	if err != nil {
+		return false, 0, err
+	}
+
+	dstSum, size, err := checksum(ctx, dst, objFilepath)
+	if err != nil {
+		return true, 0, err
+	} else if !bytes.Equal(hash.Sum(nil), dstSum) {
+		return true, 0, ErrChecksumMismatch
+	}
+	return true, size, nil
+}
+
+// checksum reads an object and returns its SHA-256 checksum and size.
+func checksum(ctx context.Context, st storage.ObjectStorage, objFilepath string) (sum []byte, size int64, err error) {
+	reader, _, err := st.FetchObjectContext(ctx, objFilepath)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer reader.Close()
+	hash := sha256.New()
+	if size, err = io.Copy(hash, reader); err != nil {
+		return nil, 0, err
+	}
+	return hash.Sum(nil), size, nil
+}
@@ -0,0 +1,107 @@
+package storagemigration
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/common/constant"
+	"github.com/jonylim/basego/internal/pkg/common/storage"
+)
+
+func storeObject(t *testing.T, st storage.ObjectStorage, p, contents string, opts storage.StoreOptions) {
+	if err := st.StoreObjectContext(context.Background(), p, strings.NewReader(contents), opts); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCopyObject(t *testing.T) {
+	ctx := context.Background()
+	src, dst := storage.NewMemory(), storage.NewMemory()
+	opts := storage.StoreOptions{ContentType: "image/jpeg", CacheControl: "max-age=60", Metadata: map[string]string{"k": "v"}}
+	storeObject(t, src, "a/b", "contents", opts)
+
+	// A dry run only checks the source.
+	if copied, size, err := copyObject(ctx, src, dst, "a/b", true); copied || size != 8 || err != nil {
+		t.Errorf("copyObject() dry run = %v, %d, %v; expected false, 8, nil", copied, size, err)
+	}
+	if len(dst.Filepaths()) != 0 {
+		t.Errorf("copyObject() dry run stored %v", dst.Filepaths())
+	}
+
+	dst.ShouldMakePublic(true)
+	if copied, size, err := copyObject(ctx, src, dst, "a/b", false); !copied || size != 8 || err != nil {
+		t.Errorf("copyObject() = %v, %d, %v; expected true, 8, nil", copied, size, err)
+	}
+	obj, ok := dst.Object("a/b")
+	if !ok || string(obj.Data) != "contents" || obj.ContentType != opts.ContentType ||
+		obj.CacheControl != opts.CacheControl || obj.Metadata["k"] != "v" || !obj.Public {
+		t.Errorf("copied object = %+v, %v", obj, ok)
+	}
+
+	// Resuming doesn't copy an object again, unless the target's copy is different.
+	if copied, _, err := copyObject(ctx, src, dst, "a/b", false); copied || err != nil {
+		t.Errorf("copyObject() again = %v, %v; expected false, nil", copied, err)
+	}
+	storeObject(t, dst, "a/b", "CONTENTS", opts)
+	if copied, _, err := copyObject(ctx, src, dst, "a/b", false); !copied || err != nil {
+		t.Errorf("copyObject() over a different copy = %v, %v; expected true, nil", copied, err)
+	}
+	if obj, _ := dst.Object("a/b"); !bytes.Equal(obj.Data, []byte("contents")) {
+		t.Errorf("copied object = %q; expected %q", obj.Data, "contents")
+	}
+
+	if _, _, err := copyObject(ctx, src, dst, "a/missing", false); err != ErrSourceNotFound {
+		t.Errorf("copyObject() of a missing object error = %v; expected %v", err, ErrSourceNotFound)
+	}
+
+	dst.InjectFault(storage.MemoryFault{Op: "StoreObject", Path: "c/*", Err: storage.ErrMemoryFault})
+	storeObject(t, src, "c/d", "contents", opts)
+	if copied, _, err := copyObject(ctx, src, dst, "c/d", false); copied || err != storage.ErrMemoryFault {
+		t.Errorf("copyObject() failing to store = %v, %v; expected false, %v", copied, err, storage.ErrMemoryFault)
+	}
+}
+
+func TestMigrateFileObjects(t *testing.T) {
+	src, dst := storage.NewMemory(), storage.NewMemory()
+	m := &Migrator{
+		opts: Options{From: "src", To: "dst"},
+		newStorage: func(st string) (storage.ObjectStorage, error) {
+			if st == "src" {
+				return src, nil
+			}
+			return dst, nil
+		},
+	}
+	file := model.File{ID: 1, OwnerType: constant.FileOwnerTypeCstAccount, OwnerID: 2, Category: constant.FileCategoryPhoto, Filename: "abc", IsPublic: true}
+	fullFilepath, thumbFilepath := storage.GetCstAccountPhotoFilepath(file.Filename)
+	storeObject(t, src, fullFilepath, "full", storage.StoreOptions{})
+
+	// The thumbnail is missing, so the file fails after its fullsize image is copied.
+	if res := m.migrateFileObjects(context.Background(), file); res.Status != StatusFailed || res.Copied != 1 || res.Err == nil {
+		t.Errorf("migrateFileObjects() without thumbnail = %+v; expected failed after 1 copied", res)
+	}
+
+	storeObject(t, src, thumbFilepath, "thumb", storage.StoreOptions{})
+	if res := m.migrateFileObjects(context.Background(), file); res.Status != StatusMigrated || res.Copied != 1 || res.Size != 9 || res.Err != nil {
+		t.Errorf("migrateFileObjects() = %+v; expected migrated with 1 copied, 9 bytes", res)
+	}
+	if obj, _ := dst.Object(thumbFilepath); !obj.Public {
+		t.Errorf("migrated object of a public file isn't public")
+	}
+
+	// The encrypted files are never public.
+	file.IsEncrypted = true
+	dst.Reset()
+	m.migrateFileObjects(context.Background(), file)
+	if obj, _ := dst.Object(fullFilepath); obj.Public {
+		t.Errorf("migrated object of an encrypted file is public")
+	}
+
+	file.Category = constant.FileCategoryAvatar
+	if res := m.migrateFileObjects(context.Background(), file); res.Status != StatusFailed || res.Err == nil {
+		t.Errorf("migrateFileObjects() of an unknown file = %+v; expected failed", res)
+	}
+}
@@ -0,0 +1,118 @@
+package linktoken
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
+	"github.com/jonylim/basego/internal/pkg/common/helper"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+)
+
+// KeySize is the size of a key in bytes.
+const KeySize = 32
+
+type key struct {
+	id     string
+	secret []byte
+}
+
+// keys is a list of keys, the first one is used to encode new tokens.
+type keys []key
+
+var (
+	current keys
+	mutex   sync.RWMutex
+)
+
+var errNoKey = errors.New("linktoken: no key is configured")
+
+// Init loads the keys from environment variables. The app exits if none is configured, as the tokens issued by an
+// instance must be valid on the others, except in a local environment, where a random key is generated,
+// so the tokens are only valid until the app restarts.
+func Init() {
+	s := os.Getenv(envvar.LinkToken.Keys)
+	if s != "" {
+		k, err := parseKeys(s)
+		if err == nil {
+			logger.Println("linktoken", fmt.Sprintf("Keys = %d, ActiveKeyID = '%s'", len(k), k[0].id))
+			setKeys(k)
+			return
+		}
+		logger.Println("linktoken", fmt.Sprintf("WARN: %s is invalid: %v", envvar.LinkToken.Keys, err))
+	}
+	if !helper.IsLocalEnvironment() {
+		logger.Println("linktoken", fmt.Sprintf("ERROR: %s is required outside a local environment", envvar.LinkToken.Keys))
+		os.Exit(1)
+	}
+	secret := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, secret); err != nil {
+		logger.Fatal("linktoken", logger.FromError(err))
+		return
+	}
+	logger.Println("linktoken", fmt.Sprintf("WARN: %s is empty, using a random key until the app restarts", envvar.LinkToken.Keys))
+	setKeys(keys{{"tmp", secret}})
+}
+
+// parseKeys parses keys in the format "<keyID>:<base64 of 32 bytes>", separated by commas.
+func parseKeys(s string) (keys, error) {
+	res := make(keys, 0)
+	ids := map[string]bool{}
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.SplitN(item, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || strings.Contains(parts[0], ".") {
+			return nil, fmt.Errorf("key #%d must be in the format '<keyID>:<secret>'", len(res)+1)
+		}
+		if ids[parts[0]] {
+			return nil, fmt.Errorf("key ID '%s' is duplicated", parts[0])
+		}
+		secret, err := base64.StdEncoding.DecodeString(strings.TrimSpace(parts[1]))
+		if err != nil || len(secret) != KeySize {
+			return nil, fmt.Errorf("key '%s' must be %d bytes encoded in base64", parts[0], KeySize)
+		}
+		ids[parts[0]] = true
+		res = append(res, key{parts[0], secret})
+	}
+	if len(res) == 0 {
+		return nil, errNoKey
+	}
+	return res, nil
+}
+
+func (k keys) active() (key, error) {
+	if len(k) == 0 {
+		return key{}, errNoKey
+	}
+	return k[0], nil
+}
+
+func (k keys) find(id string) ([]byte, bool) {
+	for _, it := range k {
+		if it.id == id {
+			return it.secret, true
+		}
+	}
+	return nil, false
+}
+
+func getKeys() keys {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return current
+}
+
+func setKeys(k keys) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	current = k
+}
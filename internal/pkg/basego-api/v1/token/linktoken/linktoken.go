@@ -0,0 +1,114 @@
+// Package linktoken creates and reads the tokens put in email links.
+//
+// A token is "v1.<keyID>.<data>", where data is the base64url encoded nonce and AES-256-GCM ciphertext of
+// the claims. The version and key ID are authenticated too, so the token can't be read or altered without the key.
+package linktoken
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/common/helper"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
+)
+
+// Version is the current token format's version.
+const Version = "v1"
+
+// Errors returned when reading a token.
+var (
+	ErrInvalid = i18n.NewError(i18n.MsgLinkTokenInvalid)
+	ErrExpired = i18n.NewError(i18n.MsgLinkTokenExpired)
+)
+
+type claims struct {
+	Purpose   string          `json:"pur"`
+	ExpiresAt int64           `json:"exp"`
+	Data      json.RawMessage `json:"dat"`
+}
+
+// Encode creates a token for a purpose containing the data, which must be JSON encodable.
+// The expiry time is in Unix milliseconds.
+func Encode(purpose string, expiresAt int64, data interface{}) (string, error) {
+	key, err := getKeys().active()
+	if err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := json.Marshal(claims{purpose, expiresAt, b})
+	if err != nil {
+		return "", err
+	}
+	aead, err := newAEAD(key.secret)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	header := Version + "." + key.id
+	sealed := aead.Seal(nonce, nonce, plaintext, []byte(header))
+	return header + "." + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// Decode reads a token into the data after checking its key, purpose and expiry time.
+// ErrInvalid is returned if the token can't be authenticated or is for another purpose.
+func Decode(token, purpose string, now time.Time, data interface{}) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != Version {
+		return ErrInvalid
+	}
+	secret, ok := getKeys().find(parts[1])
+	if !ok {
+		return ErrInvalid
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return ErrInvalid
+	}
+	aead, err := newAEAD(secret)
+	if err != nil {
+		return err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return ErrInvalid
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(parts[0]+"."+parts[1]))
+	if err != nil {
+		return ErrInvalid
+	}
+
+	var c claims
+	if err := json.Unmarshal(plaintext, &c); err != nil {
+		return ErrInvalid
+	}
+	if c.Purpose != purpose {
+		return ErrInvalid
+	}
+	if helper.UnixMillisecond(now) >= c.ExpiresAt {
+		return ErrExpired
+	}
+	if err := json.Unmarshal(c.Data, data); err != nil {
+		return ErrInvalid
+	}
+	return nil
+}
+
+func newAEAD(secret []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(secret)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,95 @@
+package linktoken
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/common/helper"
+)
+
+type testData struct {
+	ID    int64  `json:"id"`
+	Email string `json:"email"`
+}
+
+func TestEncodeDecode(t *testing.T) {
+	oldKey := key{"k1", []byte(strings.Repeat("a", KeySize))}
+	newKey := key{"k2", []byte(strings.Repeat("b", KeySize))}
+	now := time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := helper.UnixMillisecond(now.Add(time.Hour))
+	data := testData{128, "john@doe.com"}
+
+	setKeys(keys{oldKey})
+	oldToken, err := Encode("verifyEmail", expiresAt, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	setKeys(keys{newKey, oldKey})
+	token, err := Encode("verifyEmail", expiresAt, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(token, Version+".k2.") {
+		t.Errorf(`Encode() = "%v"; expected prefix "%v"`, token, Version+".k2.")
+	}
+
+	parts := strings.Split(token, ".")
+	sealed, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	sealed[len(sealed)-1] ^= 1
+	tampered := parts[0] + "." + parts[1] + "." + base64.RawURLEncoding.EncodeToString(sealed)
+
+	var tests = []struct {
+		name     string
+		token    string
+		purpose  string
+		now      time.Time
+		expected error
+	}{
+		{"valid", token, "verifyEmail", now, nil},
+		{"rotated key", oldToken, "verifyEmail", now, nil},
+		{"wrong purpose", token, "resetPassword", now, ErrInvalid},
+		{"expired", token, "verifyEmail", now.Add(time.Hour), ErrExpired},
+		{"tampered", tampered, "verifyEmail", now, ErrInvalid},
+		{"swapped key ID", parts[0] + ".k1." + parts[2], "verifyEmail", now, ErrInvalid},
+		{"unknown key ID", parts[0] + ".k3." + parts[2], "verifyEmail", now, ErrInvalid},
+		{"unknown version", "v0." + parts[1] + "." + parts[2], "verifyEmail", now, ErrInvalid},
+		{"malformed", "eyJvdHBJRCI6MTI4fQ==", "verifyEmail", now, ErrInvalid},
+	}
+	for _, test := range tests {
+		var out testData
+		err := Decode(test.token, test.purpose, test.now, &out)
+		if err != test.expected {
+			t.Errorf(`Decode() %s = %v; expected %v`, test.name, err, test.expected)
+		} else if err == nil && out != data {
+			t.Errorf(`Decode() %s data = %v; expected %v`, test.name, out, data)
+		}
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	secret := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("a", KeySize)))
+	var tests = []struct {
+		input    string
+		expected []string
+	}{
+		{"k1:" + secret, []string{"k1"}},
+		{" k2:" + secret + ", k1:" + secret, []string{"k2", "k1"}},
+		{"", nil},
+		{"k1", nil},
+		{"k.1:" + secret, nil},
+		{"k1:" + secret + ",k1:" + secret, nil},
+		{"k1:" + base64.StdEncoding.EncodeToString([]byte("short")), nil},
+	}
+	for _, test := range tests {
+		out, err := parseKeys(test.input)
+		ids := make([]string, len(out))
+		for i, it := range out {
+			ids[i] = it.id
+		}
+		if (err == nil) != (test.expected != nil) || strings.Join(ids, ",") != strings.Join(test.expected, ",") {
+			t.Errorf(`parseKeys("%v") = %v, %v; expected %v`, test.input, ids, err, test.expected)
+		}
+	}
+}
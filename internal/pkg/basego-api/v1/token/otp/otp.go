@@ -1,12 +1,10 @@
 package otp
 
 import (
-	"fmt"
-	"math/rand"
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
 	"strings"
-	"time"
-
-	"github.com/jonylim/basego/internal/pkg/common/crypto/hash"
 )
 
 // Defines OTP methods.
@@ -23,45 +21,36 @@ const (
 	ActionResetPassword = "resetPassword"
 )
 
-// TTL defines TTL duration of an OTP, in seconds.
-const TTL = 600
-
-// Length defines OTP code's length.
-const Length = 6
+// keySize is the number of random bytes of an OTP key, which is hex encoded.
+const keySize = 16
 
 const (
 	charsNumeric      = "0123456789"
 	charsAlphanumeric = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
 )
 
-var incrementSeed int64
-
-// GenerateAlphanumeric returns a new OTP key and code. The code is a alphanumeric string.
-func GenerateAlphanumeric() (otpKey, otpCode string) {
-	otpKey, otpCode = generate(charsAlphanumeric)
-	return
-}
-
-// GenerateNumeric returns a new OTP key and code. The code is a numeric string.
-func GenerateNumeric() (otpKey, otpCode string) {
-	otpKey, otpCode = generate(charsNumeric)
-	return
-}
+// Generate returns a new OTP key and code using the policy's code length and charset.
+// Both are read from a cryptographically secure random source.
+func Generate(p Policy) (otpKey, otpCode string, err error) {
+	b := make([]byte, keySize)
+	if _, err = rand.Read(b); err != nil {
+		return
+	}
+	otpKey = hex.EncodeToString(b)
 
-func generate(chars string) (otpKey, otpCode string) {
-	incrementSeed++
-	if incrementSeed > 9 {
-		incrementSeed = 0
+	chars := charsAlphanumeric
+	if p.Charset == CharsetNumeric {
+		chars = charsNumeric
 	}
-	nowNano := time.Now().UnixNano()
-	rand.Seed((nowNano/10)*10 + incrementSeed)
-	max := len(chars)
+	max := big.NewInt(int64(len(chars)))
 	var sb strings.Builder
-	for i := 0; i < Length; i++ {
-		c := rand.Intn(max)
-		sb.WriteString(string(chars[c]))
+	for i := 0; i < p.Length; i++ {
+		var n *big.Int
+		if n, err = rand.Int(rand.Reader, max); err != nil {
+			return "", "", err
+		}
+		sb.WriteByte(chars[n.Int64()])
 	}
 	otpCode = sb.String()
-	otpKey = hash.MD5inHex(fmt.Sprintf("%v+%v=%s", nowNano, incrementSeed, otpCode))
 	return
 }
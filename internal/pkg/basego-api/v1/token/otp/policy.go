@@ -0,0 +1,181 @@
+package otp
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+)
+
+// Defines the charsets of OTP codes.
+const (
+	CharsetNumeric      = "numeric"
+	CharsetAlphanumeric = "alphanumeric"
+)
+
+// ActionAll is the action matching all actions in the policy overrides.
+const ActionAll = "*"
+
+// Policy defines how the OTPs of an action are generated, sent and verified.
+type Policy struct {
+	TTL     time.Duration
+	Length  int
+	Charset string
+
+	// MaxAttempts is the number of incorrect submissions after which the OTP is invalidated, 0 is unlimited.
+	MaxAttempts int
+
+	// ResendCooldown is the minimum duration between two OTPs of an account and action.
+	ResendCooldown time.Duration
+
+	// MaxSendsPerDay is the number of OTPs of an account and action in the last 24 hours, 0 is unlimited.
+	MaxSendsPerDay int
+}
+
+// Policies maps the actions to their policies.
+type Policies map[string]Policy
+
+// DefaultPolicies returns the default policies of the known actions.
+func DefaultPolicies() Policies {
+	short := Policy{
+		TTL:            10 * time.Minute,
+		Length:         6,
+		Charset:        CharsetNumeric,
+		MaxAttempts:    5,
+		ResendCooldown: time.Minute,
+		MaxSendsPerDay: 10,
+	}
+	verifyEmail := short
+	verifyEmail.TTL = 24 * time.Hour
+	verifyEmail.Charset = CharsetAlphanumeric
+	resetPassword := short
+	resetPassword.TTL = time.Hour
+	resetPassword.Charset = CharsetAlphanumeric
+	return Policies{
+		ActionLogin:         short,
+		ActionVerifyPhone:   short,
+		ActionVerifyEmail:   verifyEmail,
+		ActionResetPassword: resetPassword,
+	}
+}
+
+// Get returns the policy of an action. Unknown actions use the login policy.
+func (policies Policies) Get(action string) Policy {
+	if p, ok := policies[action]; ok {
+		return p
+	}
+	return policies[ActionLogin]
+}
+
+// ParsePolicies applies overrides in the format "<action>:<setting>=<value>", separated by commas,
+// to a copy of the base policies, e.g. "resetPassword:ttl=30m, *:maxAttempts=3".
+func ParsePolicies(s string, base Policies) (Policies, error) {
+	res := make(Policies, len(base))
+	for k, v := range base {
+		res[k] = v
+	}
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.SplitN(item, "=", 2)
+		target := strings.SplitN(parts[0], ":", 2)
+		if len(parts) != 2 || len(target) != 2 {
+			return nil, fmt.Errorf("override '%s' must be in the format '<action>:<setting>=<value>'", item)
+		}
+		action, setting, value := strings.TrimSpace(target[0]), strings.TrimSpace(target[1]), strings.TrimSpace(parts[1])
+		if _, ok := res[action]; !ok && action != ActionAll {
+			return nil, fmt.Errorf("override '%s' has unknown action '%s'", item, action)
+		}
+		for k, p := range res {
+			if action != ActionAll && action != k {
+				continue
+			}
+			if err := p.set(setting, value); err != nil {
+				return nil, fmt.Errorf("override '%s': %v", item, err)
+			}
+			res[k] = p
+		}
+	}
+	return res, nil
+}
+
+func (p *Policy) set(setting, value string) error {
+	switch setting {
+	case "ttl", "resendCooldown":
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 || (setting == "ttl" && d < time.Minute) {
+			return fmt.Errorf("%s '%s' is invalid", setting, value)
+		}
+		if setting == "ttl" {
+			p.TTL = d
+		} else {
+			p.ResendCooldown = d
+		}
+	case "length", "maxAttempts", "maxSendsPerDay":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 || (setting == "length" && (n < 4 || n > 32)) {
+			return fmt.Errorf("%s '%s' is invalid", setting, value)
+		}
+		switch setting {
+		case "length":
+			p.Length = n
+		case "maxAttempts":
+			p.MaxAttempts = n
+		default:
+			p.MaxSendsPerDay = n
+		}
+	case "charset":
+		if value != CharsetNumeric && value != CharsetAlphanumeric {
+			return fmt.Errorf("charset '%s' is invalid", value)
+		}
+		p.Charset = value
+	default:
+		return fmt.Errorf("unknown setting '%s'", setting)
+	}
+	return nil
+}
+
+var (
+	current = DefaultPolicies()
+	mutex   sync.RWMutex
+)
+
+// Init loads the policy overrides from environment variables.
+func Init() {
+	policies := DefaultPolicies()
+	if s := os.Getenv(envvar.OTP.Policies); s != "" {
+		res, err := ParsePolicies(s, policies)
+		if err != nil {
+			logger.Println("otp", fmt.Sprintf("WARN: %s is invalid, using the default policies: %v", envvar.OTP.Policies, err))
+		} else {
+			policies = res
+		}
+	}
+	for _, action := range []string{ActionVerifyEmail, ActionResetPassword, ActionLogin, ActionVerifyPhone} {
+		p := policies[action]
+		logger.Println("otp", fmt.Sprintf("%s: TTL = %v, Length = %d, Charset = %s, MaxAttempts = %d, ResendCooldown = %v, MaxSendsPerDay = %d",
+			action, p.TTL, p.Length, p.Charset, p.MaxAttempts, p.ResendCooldown, p.MaxSendsPerDay))
+	}
+	SetPolicies(policies)
+}
+
+// GetPolicy returns the active policy of an action.
+func GetPolicy(action string) Policy {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return current.Get(action)
+}
+
+// SetPolicies replaces the active policies.
+func SetPolicies(policies Policies) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	current = policies
+}
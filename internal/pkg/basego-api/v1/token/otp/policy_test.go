@@ -0,0 +1,104 @@
+package otp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParsePolicies(t *testing.T) {
+	base := DefaultPolicies()
+	var tests = []struct {
+		s        string
+		action   string
+		expected func(p Policy) Policy
+	}{
+		{"", ActionResetPassword, func(p Policy) Policy { return p }},
+		{"resetPassword:ttl=30m", ActionResetPassword, func(p Policy) Policy { p.TTL = 30 * time.Minute; return p }},
+		{"resetPassword:ttl=30m", ActionVerifyEmail, func(p Policy) Policy { return p }},
+		{"*:maxAttempts=3, verifyEmail:maxAttempts=0", ActionLogin, func(p Policy) Policy { p.MaxAttempts = 3; return p }},
+		{"*:maxAttempts=3, verifyEmail:maxAttempts=0", ActionVerifyEmail, func(p Policy) Policy { p.MaxAttempts = 0; return p }},
+		{"login:length=8, login:charset=alphanumeric", ActionLogin, func(p Policy) Policy { p.Length, p.Charset = 8, CharsetAlphanumeric; return p }},
+		{"verifyPhone:resendCooldown=0s, verifyPhone:maxSendsPerDay=3", ActionVerifyPhone, func(p Policy) Policy { p.ResendCooldown, p.MaxSendsPerDay = 0, 3; return p }},
+	}
+	for _, test := range tests {
+		res, err := ParsePolicies(test.s, base)
+		if err != nil {
+			t.Errorf("ParsePolicies(%q) returns error %v", test.s, err)
+			continue
+		}
+		if expected := test.expected(base.Get(test.action)); res.Get(test.action) != expected {
+			t.Errorf("ParsePolicies(%q).Get(%q) = %+v; expected %+v", test.s, test.action, res.Get(test.action), expected)
+		}
+	}
+	if base.Get(ActionResetPassword).TTL != time.Hour {
+		t.Errorf("ParsePolicies changed the base policies")
+	}
+}
+
+func TestParsePoliciesInvalid(t *testing.T) {
+	var tests = []string{
+		"resetPassword",
+		"resetPassword=30m",
+		"unknown:ttl=30m",
+		"resetPassword:unknown=1",
+		"resetPassword:ttl=abc",
+		"resetPassword:ttl=10s",
+		"resetPassword:length=2",
+		"resetPassword:maxAttempts=-1",
+		"resetPassword:charset=hex",
+	}
+	for _, s := range tests {
+		if _, err := ParsePolicies(s, DefaultPolicies()); err == nil {
+			t.Errorf("ParsePolicies(%q) = nil error; expected error", s)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	var tests = []struct {
+		p     Policy
+		chars string
+	}{
+		{Policy{Length: 6, Charset: CharsetNumeric}, charsNumeric},
+		{Policy{Length: 8, Charset: CharsetAlphanumeric}, charsAlphanumeric},
+	}
+	for _, test := range tests {
+		key, code, err := Generate(test.p)
+		if err != nil {
+			t.Errorf("Generate(%+v) returns error %v", test.p, err)
+			continue
+		}
+		if len(key) != keySize*2 {
+			t.Errorf("Generate(%+v) key length = %d; expected %d", test.p, len(key), keySize*2)
+		}
+		if len(code) != test.p.Length {
+			t.Errorf("Generate(%+v) code length = %d; expected %d", test.p, len(code), test.p.Length)
+		}
+		for _, c := range code {
+			if !strings.ContainsRune(test.chars, c) {
+				t.Errorf("Generate(%+v) code = %s; expected only %s", test.p, code, test.chars)
+				break
+			}
+		}
+	}
+	key1, _, _ := Generate(Policy{Length: 6})
+	key2, _, _ := Generate(Policy{Length: 6})
+	if key1 == key2 {
+		t.Errorf("Generate returns the same key twice: %s", key1)
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	defer SetPolicies(DefaultPolicies())
+
+	// The OTPs are cached a bit longer than their action's TTL, which can be overridden.
+	policies, _ := ParsePolicies("resetPassword:ttl=2h", DefaultPolicies())
+	SetPolicies(policies)
+	if res := cacheTTL(ActionResetPassword); res != 2*3600+300 {
+		t.Errorf("cacheTTL(%s) = %d; expected %d", ActionResetPassword, res, 2*3600+300)
+	}
+	if res := cacheTTL(ActionVerifyEmail); res != 24*3600+300 {
+		t.Errorf("cacheTTL(%s) = %d; expected %d", ActionVerifyEmail, res, 24*3600+300)
+	}
+}
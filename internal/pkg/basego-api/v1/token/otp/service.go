@@ -0,0 +1,260 @@
+package otp
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"math"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/dao"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/repository"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/common/crypto/secrethash"
+	"github.com/jonylim/basego/internal/pkg/common/data/db"
+	"github.com/jonylim/basego/internal/pkg/common/helper"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// Errors returned by the service.
+var (
+	ErrNotFound        = i18n.NewError(i18n.MsgVerificationNotFound)
+	ErrIDInvalid       = i18n.NewError(i18n.MsgVerificationIDInvalid)
+	ErrKeyInvalid      = i18n.NewError(i18n.MsgVerificationKeyInvalid)
+	ErrCodeIncorrect   = i18n.NewError(i18n.MsgVerificationCodeIncorrect)
+	ErrEmailChanged    = i18n.NewError(i18n.MsgVerificationEmailChanged)
+	ErrTooManyAttempts = i18n.NewError(i18n.MsgOTPTooManyAttempts)
+	ErrSendLimit       = i18n.NewError(i18n.MsgOTPSendLimitReached)
+	ErrDatabase        = i18n.NewError(i18n.MsgProcessingFailed)
+)
+
+// CooldownError is returned when a new OTP is requested before the policy's resend cooldown has passed.
+type CooldownError struct {
+	RetryAfter time.Duration
+}
+
+// Seconds returns the time to wait in whole seconds, rounded up.
+func (e *CooldownError) Seconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+// Message returns the error's message.
+func (e *CooldownError) Message() i18n.Message {
+	return i18n.NewMessageWithParams(i18n.MsgOTPResendCooldown, i18n.Params{"seconds": e.Seconds()})
+}
+
+func (e *CooldownError) Error() string {
+	return e.Message().String()
+}
+
+// hashPurpose is the purpose of the OTP codes' hashes, only the hash of a code is saved.
+const hashPurpose = "otp"
+
+// Submission is an OTP submitted for verification.
+type Submission struct {
+	ID   int64
+	Key  string
+	Code string
+}
+
+// Service issues and verifies the OTPs of customer accounts following the actions' policies,
+// keeping the database and the Redis cache in sync.
+type Service struct {
+	repo *repository.CstAccountOTPRepo
+}
+
+// NewService returns new instance of Service.
+func NewService(redisConn redis.Conn) *Service {
+	return &Service{
+		repo: repository.NewCstAccountOTPRepo(redisConn, cacheTTL),
+	}
+}
+
+// Issue creates a new OTP sent by email for an account and action, replacing the active one.
+// The OTP has the code's hash, so the code to send is returned separately.
+// It fails if the policy's resend cooldown or daily limit is exceeded.
+// This method requires database transaction to be passed, call Save after the transaction is committed.
+func (s *Service) Issue(tx *sql.Tx, account model.CstAccount, action string) (otpData model.CstAccountOTP, otpCode string, err error) {
+	p := GetPolicy(action)
+	now := time.Now()
+	nowMillis := helper.UnixMillisecond(now)
+
+	// Check the resend cooldown and the daily limit.
+	otpDAO := dao.NewCstAccountOTPDAO()
+	count, lastCreatedMillis, err := otpDAO.CountSentSince(account.ID, action, helper.UnixMillisecond(now.Add(-24*time.Hour)))
+	if err != nil {
+		return otpData, "", ErrDatabase
+	}
+	if p.ResendCooldown > 0 && lastCreatedMillis != 0 {
+		if wait := lastCreatedMillis + int64(p.ResendCooldown/time.Millisecond) - nowMillis; wait > 0 {
+			return otpData, "", &CooldownError{time.Duration(wait) * time.Millisecond}
+		}
+	}
+	if p.MaxSendsPerDay > 0 && count >= p.MaxSendsPerDay {
+		return otpData, "", ErrSendLimit
+	}
+
+	otpKey, otpCode, err := Generate(p)
+	if err != nil {
+		logger.Fatal("otp", logger.FromError(err))
+		return otpData, "", err
+	}
+
+	// Replace the active OTP, continuing its send count.
+	_, lastSendCount, err := otpDAO.DeleteActiveOTPByAccountAndAction(tx, account.ID, action)
+	if err != nil {
+		return otpData, "", ErrDatabase
+	}
+	otpData = model.CstAccountOTP{
+		AccountID:  account.ID,
+		Key:        otpKey,
+		Code:       secrethash.Sum(hashPurpose, otpCode),
+		Action:     action,
+		Method:     MethodEmail,
+		Email:      account.Email,
+		ExpiryTime: helper.UnixMillisecond(now.Add(p.TTL)),
+		SendCount:  lastSendCount + 1,
+	}
+	otpData.ID, otpData.CreatedTime, err = otpDAO.InsertOTP(tx, otpData)
+	if err != nil {
+		return otpData, "", ErrDatabase
+	}
+	return otpData, otpCode, nil
+}
+
+// Resend is the same as Issue, but in its own database transaction.
+func (s *Service) Resend(account model.CstAccount, action string) (model.CstAccountOTP, string, error) {
+	tx, err := db.Get().Begin()
+	if err != nil {
+		logger.Fatal("db.Begin", logger.FromError(err))
+		return model.CstAccountOTP{}, "", ErrDatabase
+	}
+	defer tx.Rollback()
+
+	otpData, otpCode, err := s.Issue(tx, account, action)
+	if err != nil {
+		return otpData, "", err
+	}
+	if err = tx.Commit(); err != nil {
+		logger.Fatal("tx.Commit", logger.FromError(err))
+		return otpData, "", ErrDatabase
+	}
+	s.Save(otpData)
+	return otpData, otpCode, nil
+}
+
+// Verify checks a submitted OTP against the active OTP of an account and action.
+// An incorrect submission counts as an attempt, and the OTP is invalidated after the policy's max attempts.
+// A correct OTP isn't used up until it's passed to Consume.
+func (s *Service) Verify(account model.CstAccount, action string, sub Submission) (model.CstAccountOTP, error) {
+	otpData, err := s.repo.GetActiveOTPByAccountAndAction(account.ID, action)
+	if err != nil {
+		if err == s.repo.ErrNotFound {
+			return otpData, ErrNotFound
+		}
+		return otpData, ErrDatabase
+	}
+
+	// A submission for another OTP, e.g. the one replaced by a resend, isn't counted as an attempt.
+	if otpData.ID != sub.ID {
+		return otpData, ErrIDInvalid
+	}
+
+	p := GetPolicy(action)
+	if p.MaxAttempts > 0 && otpData.AttemptCount >= p.MaxAttempts {
+		// The policy has been lowered since the OTP was issued.
+		return s.recordFailedAttempt(otpData, p, ErrTooManyAttempts)
+	}
+	if subtle.ConstantTimeCompare([]byte(otpData.Key), []byte(sub.Key)) != 1 {
+		return s.recordFailedAttempt(otpData, p, ErrKeyInvalid)
+	}
+	if !secrethash.Matches(otpData.Code, hashPurpose, sub.Code) {
+		return s.recordFailedAttempt(otpData, p, ErrCodeIncorrect)
+	}
+	if otpData.Email != account.Email {
+		return s.recordFailedAttempt(otpData, p, ErrEmailChanged)
+	}
+	return otpData, nil
+}
+
+// recordFailedAttempt increments the attempt count of an OTP, invalidating it if the max attempts is reached.
+func (s *Service) recordFailedAttempt(otpData model.CstAccountOTP, p Policy, cause error) (model.CstAccountOTP, error) {
+	tx, err := db.Get().Begin()
+	if err != nil {
+		logger.Fatal("db.Begin", logger.FromError(err))
+		return otpData, ErrDatabase
+	}
+	defer tx.Rollback()
+
+	otpDAO := dao.NewCstAccountOTPDAO()
+	attemptCount, err := otpDAO.IncrementAttemptCountByID(tx, otpData.ID)
+	if err != nil {
+		s.Forget(otpData)
+		return otpData, ErrDatabase
+	} else if attemptCount == 0 {
+		// The OTP is no longer active, e.g. it has just been replaced.
+		s.Forget(otpData)
+		return otpData, ErrNotFound
+	}
+	exceeded := p.MaxAttempts > 0 && attemptCount >= p.MaxAttempts
+	if exceeded {
+		if _, err = otpDAO.DeleteOTPByID(tx, otpData.ID); err != nil {
+			s.Forget(otpData)
+			return otpData, ErrDatabase
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		logger.Fatal("tx.Commit", logger.FromError(err))
+		s.Forget(otpData)
+		return otpData, ErrDatabase
+	}
+
+	if exceeded {
+		s.Forget(otpData)
+		return otpData, ErrTooManyAttempts
+	}
+	otpData.AttemptCount = attemptCount
+	otpData.UpdatedTime = helper.UnixMillisecond(time.Now())
+	s.Save(otpData)
+	return otpData, cause
+}
+
+// Consume marks a verified OTP as used, so it can't be submitted again.
+// This method requires database transaction to be passed, call Save after the transaction is committed.
+func (s *Service) Consume(tx *sql.Tx, otpData model.CstAccountOTP) (model.CstAccountOTP, error) {
+	attemptCount, isVerified, err := dao.NewCstAccountOTPDAO().SetVerified(tx, otpData.ID)
+	if err != nil {
+		s.Forget(otpData)
+		return otpData, ErrDatabase
+	} else if attemptCount == 0 {
+		s.Forget(otpData)
+		return otpData, ErrNotFound
+	}
+	otpData.AttemptCount = attemptCount
+	otpData.IsVerified = isVerified
+	otpData.UpdatedTime = helper.UnixMillisecond(time.Now())
+	return otpData, nil
+}
+
+// Save updates an OTP in Redis after its changes are committed to database.
+func (s *Service) Save(otpData model.CstAccountOTP) {
+	// Delete the cached OTP of the account and action first, in case it's the one replaced by this OTP.
+	store := s.repo.RedisStore()
+	store.DeleteOTPByAccountAndAction(otpData.AccountID, otpData.Action)
+	store.SaveOTPByAccountAndAction(otpData, cacheTTL(otpData.Action))
+}
+
+// Forget deletes an OTP from Redis, so it's read again from database.
+func (s *Service) Forget(otpData model.CstAccountOTP) {
+	store := s.repo.RedisStore()
+	store.DeleteOTPByID(otpData.ID)
+	store.DeleteOTPByAccountAndAction(otpData.AccountID, otpData.Action)
+}
+
+// cacheTTL returns the TTL of an action's OTPs in Redis, in seconds. It outlives the OTPs a bit,
+// the store checks the expiry time anyway.
+func cacheTTL(action string) int {
+	return int(GetPolicy(action).TTL/time.Second) + 300
+}
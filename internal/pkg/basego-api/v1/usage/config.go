@@ -0,0 +1,60 @@
+package usage
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+)
+
+// Config is the usage metering's configuration.
+type Config struct {
+	// FlushInterval is how often the counts in Redis are flushed to the rollup table,
+	// which is how far the usage series lag behind.
+	FlushInterval time.Duration
+}
+
+// Default returns the default configuration.
+func Default() Config {
+	return Config{
+		FlushInterval: time.Minute,
+	}
+}
+
+var (
+	current = Default()
+	mutex   sync.RWMutex
+)
+
+// Init loads the configuration from environment variables.
+func Init() {
+	c := Default()
+	if s := os.Getenv(envvar.APIKey.UsageFlushInterval); s != "" {
+		d, err := time.ParseDuration(strings.TrimSpace(s))
+		if err != nil || d < time.Second {
+			logger.Println("usage", fmt.Sprintf("WARN: %s '%s' is invalid, set to '%v' as default", envvar.APIKey.UsageFlushInterval, s, c.FlushInterval))
+		} else {
+			c.FlushInterval = d
+		}
+	}
+	logger.Println("usage", fmt.Sprintf("FlushInterval = %v", c.FlushInterval))
+	Set(c)
+}
+
+// Get returns the active configuration.
+func Get() Config {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return current
+}
+
+// Set replaces the active configuration.
+func Set(c Config) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	current = c
+}
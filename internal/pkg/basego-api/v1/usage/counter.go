@@ -0,0 +1,130 @@
+package usage
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/common/helper"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	bucketPrefix = "apiKeyUsage:hour:"
+	pendingKey   = "apiKeyUsage:pending"
+
+	// bucketPeriod is the period of the buckets, which is also the finest granularity of the rollup.
+	bucketPeriod = time.Hour
+
+	// bucketTTL keeps the buckets for a while if they can't be flushed, e.g. while the database is down.
+	bucketTTL = 7 * 24 * time.Hour
+
+	// lateWriteGrace is how long after a bucket's period a request may still be recorded into it,
+	// covering the clock differences between the instances.
+	lateWriteGrace = 5 * time.Minute
+)
+
+// recordScript counts a request in a bucket and marks the bucket as pending to be flushed.
+// KEYS[1] is the bucket's key, KEYS[2] is the pending set's key.
+// ARGV[1] is the request's field, ARGV[2] is the bucket's TTL in seconds, ARGV[3] is the bucket's start.
+var recordScript = redis.NewScript(2, `
+redis.call("HINCRBY", KEYS[1], ARGV[1], 1)
+redis.call("EXPIRE", KEYS[1], ARGV[2])
+redis.call("SADD", KEYS[2], ARGV[3])
+return 1
+`)
+
+// takeScript returns a bucket's counts and deletes it, so the requests recorded meanwhile go to a new bucket.
+var takeScript = redis.NewScript(1, `
+local counts = redis.call("HGETALL", KEYS[1])
+redis.call("DEL", KEYS[1])
+return counts
+`)
+
+// Record counts a request made with an API key to an endpoint, e.g. "client/register".
+func Record(conn redis.Conn, domain, apiKeyID, endpoint string, statusCode int, now time.Time) error {
+	start := bucketStart(now)
+	_, err := recordScript.Do(conn, bucketKey(start), pendingKey,
+		bucketField(domain, apiKeyID, endpoint, StatusClass(statusCode)), int64(bucketTTL/time.Second), start)
+	return err
+}
+
+// bucketStart returns the start of the bucket of a time in Unix milliseconds.
+func bucketStart(t time.Time) int64 {
+	return helper.UnixMillisecond(t.UTC().Truncate(bucketPeriod))
+}
+
+func bucketKey(start int64) string {
+	return bucketPrefix + strconv.FormatInt(start, 10)
+}
+
+// bucketField returns the field of a bucket counting the requests of a key, endpoint and status class.
+// None of them contain "|": the domains and key IDs are generated, and the endpoints are the routes' names.
+func bucketField(domain, apiKeyID, endpoint, statusClass string) string {
+	return domain + "|" + apiKeyID + "|" + endpoint + "|" + statusClass
+}
+
+// parseBucketField parses a bucket's field and count into the rollup's item.
+func parseBucketField(start int64, field, count string) (model.XAPIKeyUsage, bool) {
+	parts := strings.Split(field, "|")
+	n, err := strconv.ParseInt(count, 10, 64)
+	if len(parts) != 4 || err != nil || n <= 0 {
+		return model.XAPIKeyUsage{}, false
+	}
+	return model.XAPIKeyUsage{
+		Domain:       parts[0],
+		APIKeyID:     parts[1],
+		PeriodStart:  start,
+		Endpoint:     parts[2],
+		StatusClass:  parts[3],
+		RequestCount: n,
+	}, true
+}
+
+// pendingBuckets returns the starts of the buckets pending to be flushed, ascending.
+func pendingBuckets(conn redis.Conn) ([]int64, error) {
+	starts, err := redis.Int64s(conn.Do("SMEMBERS", pendingKey))
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i] < starts[j] })
+	return starts, nil
+}
+
+// takeBucket returns a bucket's counts and deletes it. The bucket is removed from the pending set
+// once no request can be recorded into it anymore.
+func takeBucket(conn redis.Conn, start int64, now time.Time) ([]model.XAPIKeyUsage, error) {
+	values, err := redis.Strings(takeScript.Do(conn, bucketKey(start)))
+	if err != nil {
+		return nil, err
+	}
+	items := make([]model.XAPIKeyUsage, 0, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		if item, ok := parseBucketField(start, values[i], values[i+1]); ok {
+			items = append(items, item)
+		}
+	}
+	if helper.UnixMillisecond(now.Add(-lateWriteGrace-bucketPeriod)) >= start {
+		if _, err = conn.Do("SREM", pendingKey, start); err != nil {
+			return items, err
+		}
+	}
+	return items, nil
+}
+
+// restoreBucket adds the counts taken from a bucket back, e.g. when they fail to be saved.
+func restoreBucket(conn redis.Conn, start int64, items []model.XAPIKeyUsage) error {
+	for _, item := range items {
+		conn.Send("HINCRBY", bucketKey(start), bucketField(item.Domain, item.APIKeyID, item.Endpoint, item.StatusClass), item.RequestCount)
+	}
+	conn.Send("EXPIRE", bucketKey(start), int64(bucketTTL/time.Second))
+	conn.Send("SADD", pendingKey, start)
+	if _, err := conn.Do(""); err != nil {
+		return fmt.Errorf("restore bucket %d: %v", start, err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package usage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/dao"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/common/data/db"
+	"github.com/jonylim/basego/internal/pkg/common/data/redis"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+
+	redigo "github.com/gomodule/redigo/redis"
+)
+
+// lockKey is the Redis key of the lock, so only one instance flushes the counts at a time.
+const lockKey = "lock:apiKeyUsage"
+
+// Run flushes the counts every interval until stop is closed.
+// The counts left in Redis when the instances stop are flushed by the next instance running.
+func Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(Get().FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			FlushOnce(now)
+		}
+	}
+}
+
+// FlushOnce flushes the pending buckets to the rollup table, if no other instance is flushing them.
+func FlushOnce(now time.Time) {
+	redisConn := redis.GetConnection()
+	defer redisConn.Close()
+
+	// The lock expires after an interval, in case this instance stops while holding it.
+	token, err := redis.TryLock(redisConn, lockKey, Get().FlushInterval)
+	if err != nil {
+		logger.Error("usage", logger.FromError(err))
+		return
+	} else if token == "" {
+		return
+	}
+	defer redis.Unlock(redisConn, lockKey, token)
+
+	starts, err := pendingBuckets(redisConn)
+	if err != nil {
+		logger.Error("usage", logger.FromError(err))
+		return
+	}
+	for _, start := range starts {
+		if !flushBucket(redisConn, start, now) {
+			return
+		}
+	}
+}
+
+// flushBucket saves a bucket's counts to the rollup table, restoring them in Redis if they fail to be saved.
+func flushBucket(redisConn redigo.Conn, start int64, now time.Time) bool {
+	items, err := takeBucket(redisConn, start, now)
+	if err != nil {
+		logger.Error("usage", logger.FromError(err))
+	}
+	if len(items) == 0 {
+		return err == nil
+	}
+	if err = save(items); err != nil {
+		if err = restoreBucket(redisConn, start, items); err != nil {
+			logger.Error("usage", fmt.Sprintf("%d counts lost: %v", len(items), err))
+		}
+		return false
+	}
+	return true
+}
+
+// save adds the counts to the rollup table in a database transaction.
+func save(items []model.XAPIKeyUsage) error {
+	byDomain := make(map[string][]model.XAPIKeyUsage)
+	for _, item := range items {
+		byDomain[item.Domain] = append(byDomain[item.Domain], item)
+	}
+
+	tx, err := db.Get().Begin()
+	if err != nil {
+		logger.Fatal("db.Begin", logger.FromError(err))
+		return err
+	}
+	defer tx.Rollback()
+	for domain, domainItems := range byDomain {
+		if err = dao.NewXAPIKeyUsageDAO(domain).Add(tx, domainItems); err != nil {
+			return err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		logger.Fatal("tx.Commit", logger.FromError(err))
+		return err
+	}
+	return nil
+}
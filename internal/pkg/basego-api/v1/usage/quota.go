@@ -0,0 +1,103 @@
+package usage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// Periods of the quotas.
+const (
+	PeriodDay   = "day"
+	PeriodMonth = "month"
+)
+
+const quotaPrefix = "apiKeyQuota"
+
+// QuotaResult is the result of a quota check.
+type QuotaResult struct {
+	Allowed bool
+
+	// Period and Limit are of the quota used up, if the request isn't allowed.
+	Period string
+	Limit  int64
+
+	// DayCount and MonthCount are the requests counted in the current UTC day and month.
+	DayCount, MonthCount int64
+
+	// RetryAfter is the duration until the used up quota resets. Zero if the request is allowed.
+	RetryAfter time.Duration
+}
+
+// quotaScript counts a request in the day's and month's counters if neither quota is used up.
+// KEYS[1] is the day's counter, KEYS[2] is the month's counter.
+// ARGV[1] and ARGV[2] are the daily and monthly quotas (0 is unlimited), ARGV[3] and ARGV[4] are the counters' TTLs in milliseconds.
+// It returns {allowed, day count, month count, 1 if the daily or 2 if the monthly quota is used up}.
+var quotaScript = redis.NewScript(2, `
+local day = tonumber(redis.call("GET", KEYS[1]) or "0")
+local month = tonumber(redis.call("GET", KEYS[2]) or "0")
+if tonumber(ARGV[1]) > 0 and day >= tonumber(ARGV[1]) then
+	return {0, day, month, 1}
+end
+if tonumber(ARGV[2]) > 0 and month >= tonumber(ARGV[2]) then
+	return {0, day, month, 2}
+end
+day = redis.call("INCR", KEYS[1])
+redis.call("PEXPIRE", KEYS[1], ARGV[3])
+month = redis.call("INCR", KEYS[2])
+redis.call("PEXPIRE", KEYS[2], ARGV[4])
+return {1, day, month, 0}
+`)
+
+// CheckQuota checks if a request made with an API key is within the key's quotas, and counts it if it is.
+// The requests are counted even if the key has no quotas, so a quota set later includes the earlier requests.
+func CheckQuota(conn redis.Conn, apiKey model.XAPIKey, now time.Time) (QuotaResult, error) {
+	dayEnd, monthEnd := QuotaResetTimes(now)
+	dayKey, monthKey := quotaKeys(apiKey.Domain, apiKey.APIKeyID, now)
+	values, err := redis.Int64s(quotaScript.Do(conn, dayKey, monthKey,
+		apiKey.DailyQuota, apiKey.MonthlyQuota,
+		ttlMillis(dayEnd.Sub(now)), ttlMillis(monthEnd.Sub(now))))
+	if err != nil {
+		return QuotaResult{Allowed: true}, err
+	}
+	res := QuotaResult{Allowed: values[0] == 1, DayCount: values[1], MonthCount: values[2]}
+	switch values[3] {
+	case 1:
+		res.Period, res.Limit, res.RetryAfter = PeriodDay, apiKey.DailyQuota, dayEnd.Sub(now)
+	case 2:
+		res.Period, res.Limit, res.RetryAfter = PeriodMonth, apiKey.MonthlyQuota, monthEnd.Sub(now)
+	}
+	return res, nil
+}
+
+// GetQuotaCounts returns the requests counted against an API key's quotas in the current UTC day and month.
+func GetQuotaCounts(conn redis.Conn, domain, apiKeyID string, now time.Time) (dayCount, monthCount int64, err error) {
+	dayKey, monthKey := quotaKeys(domain, apiKeyID, now)
+	values, err := redis.Int64s(conn.Do("MGET", dayKey, monthKey))
+	if err != nil {
+		return 0, 0, err
+	}
+	return values[0], values[1], nil
+}
+
+// QuotaResetTimes returns the ends of the UTC day and month of a time, when the quotas reset.
+func QuotaResetTimes(now time.Time) (dayEnd, monthEnd time.Time) {
+	t := now.UTC()
+	dayEnd = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+	monthEnd = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	return
+}
+
+func quotaKeys(domain, apiKeyID string, now time.Time) (dayKey, monthKey string) {
+	t := now.UTC()
+	prefix := fmt.Sprintf("%s:%s:%s:", quotaPrefix, domain, apiKeyID)
+	return prefix + t.Format("20060102"), prefix + t.Format("200601")
+}
+
+// ttlMillis returns the TTL of a counter expiring after d, kept for a day longer so late requests still find it.
+func ttlMillis(d time.Duration) int64 {
+	return int64((d + 24*time.Hour) / time.Millisecond)
+}
@@ -0,0 +1,38 @@
+package usage
+
+import (
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/data/dao"
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
+)
+
+// Granularities of the usage series.
+const (
+	ByHour  = dao.UsageByHour
+	ByDay   = dao.UsageByDay
+	ByMonth = dao.UsageByMonth
+)
+
+// MaxSeriesRange is the longest time range of a usage series.
+const MaxSeriesRange = 366 * 24 * time.Hour
+
+// ErrDatabase is returned if the usage series fails to be loaded.
+var ErrDatabase = i18n.NewError(i18n.MsgProcessingFailed)
+
+// IsValidGranularity checks if a granularity of the usage series is supported.
+func IsValidGranularity(granularity string) bool {
+	return dao.IsValidUsageGranularity(granularity)
+}
+
+// GetSeries returns an API key's request counts from fromMillis until before toMillis by the granularity's UTC periods,
+// endpoint and status class. An empty endpoint returns every endpoint.
+// The requests of the last flush interval aren't included yet.
+func GetSeries(domain, apiKeyID string, fromMillis, toMillis int64, granularity, endpoint string) ([]model.XAPIKeyUsage, error) {
+	items, err := dao.NewXAPIKeyUsageDAO(domain).GetSeries(apiKeyID, fromMillis, toMillis, granularity, endpoint)
+	if err != nil {
+		return nil, ErrDatabase
+	}
+	return items, nil
+}
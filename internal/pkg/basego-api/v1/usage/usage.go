@@ -0,0 +1,78 @@
+// Package usage meters the requests made with each API key and enforces the keys' daily and monthly quotas.
+//
+// The requests are counted in hourly buckets in Redis by key, endpoint and status class, and the buckets
+// are flushed to the rollup table periodically, so recording a request costs a single Redis call.
+package usage
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+// Tracker records the status and API key of a request, so its usage is recorded once the response is sent.
+type Tracker struct {
+	http.ResponseWriter
+	status   int
+	domain   string
+	apiKeyID string
+}
+
+// WriteHeader records the status code.
+func (t *Tracker) WriteHeader(statusCode int) {
+	if t.status == 0 {
+		t.status = statusCode
+	}
+	t.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write records the status code as 200 OK if it hasn't been written.
+func (t *Tracker) Write(b []byte) (int, error) {
+	if t.status == 0 {
+		t.status = http.StatusOK
+	}
+	return t.ResponseWriter.Write(b)
+}
+
+// Status returns the response's status code, 200 OK if nothing has been written.
+func (t *Tracker) Status() int {
+	if t.status == 0 {
+		return http.StatusOK
+	}
+	return t.status
+}
+
+// APIKey returns the domain and ID of the API key authenticating the request, empty if none did.
+func (t *Tracker) APIKey() (domain, apiKeyID string) {
+	return t.domain, t.apiKeyID
+}
+
+type contextKey struct{}
+
+// Track returns the response writer and request tracking the request's usage.
+func Track(w http.ResponseWriter, r *http.Request) (http.ResponseWriter, *http.Request) {
+	t := &Tracker{ResponseWriter: w}
+	return t, r.WithContext(context.WithValue(r.Context(), contextKey{}, t))
+}
+
+// FromContext returns the tracker of a request's context, nil if the request isn't tracked.
+func FromContext(ctx context.Context) *Tracker {
+	t, _ := ctx.Value(contextKey{}).(*Tracker)
+	return t
+}
+
+// SetAPIKey records the API key authenticating a tracked request. Only the authenticated keys are recorded,
+// so the requests with made-up key IDs don't add to the usage.
+func SetAPIKey(ctx context.Context, domain, apiKeyID string) {
+	if t := FromContext(ctx); t != nil {
+		t.domain, t.apiKeyID = domain, apiKeyID
+	}
+}
+
+// StatusClass returns the class of a status code, e.g. "4xx" for 404.
+func StatusClass(statusCode int) string {
+	if statusCode < 100 || statusCode > 599 {
+		return "5xx"
+	}
+	return strconv.Itoa(statusCode/100) + "xx"
+}
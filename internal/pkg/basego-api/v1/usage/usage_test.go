@@ -0,0 +1,102 @@
+package usage
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestStatusClass(t *testing.T) {
+	var tests = []struct {
+		statusCode int
+		expected   string
+	}{
+		{200, "2xx"},
+		{204, "2xx"},
+		{304, "3xx"},
+		{404, "4xx"},
+		{429, "4xx"},
+		{500, "5xx"},
+		{0, "5xx"},
+		{999, "5xx"},
+	}
+	for _, test := range tests {
+		if res := StatusClass(test.statusCode); res != test.expected {
+			t.Errorf("StatusClass(%d) = %q; expected %q", test.statusCode, res, test.expected)
+		}
+	}
+}
+
+func TestTrack(t *testing.T) {
+	w, r := Track(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/v1/client/register", nil))
+	tracker := FromContext(r.Context())
+	if tracker == nil || tracker != w {
+		t.Fatalf("FromContext = %v; expected the tracking writer", tracker)
+	}
+	if domain, apiKeyID := tracker.APIKey(); domain != "" || apiKeyID != "" {
+		t.Errorf("APIKey = %q, %q; expected none before SetAPIKey", domain, apiKeyID)
+	}
+	SetAPIKey(r.Context(), "customer", "key")
+	w.WriteHeader(http.StatusTooManyRequests)
+	w.Write([]byte("{}"))
+	if domain, apiKeyID := tracker.APIKey(); domain != "customer" || apiKeyID != "key" {
+		t.Errorf("APIKey = %q, %q; expected %q, %q", domain, apiKeyID, "customer", "key")
+	}
+	if status := tracker.Status(); status != http.StatusTooManyRequests {
+		t.Errorf("Status = %d; expected %d", status, http.StatusTooManyRequests)
+	}
+
+	// The requests which aren't tracked are ignored.
+	SetAPIKey(httptest.NewRequest(http.MethodPost, "/", nil).Context(), "customer", "key")
+}
+
+func TestBucketField(t *testing.T) {
+	now := time.Date(2026, 3, 14, 15, 9, 26, 0, time.UTC)
+	start := bucketStart(now)
+	if expected := time.Date(2026, 3, 14, 15, 0, 0, 0, time.UTC).UnixNano() / int64(time.Millisecond); start != expected {
+		t.Errorf("bucketStart(%v) = %d; expected %d", now, start, expected)
+	}
+
+	field := bucketField("customer", "key", "client/register", "2xx")
+	item, ok := parseBucketField(start, field, "42")
+	if !ok || item.Domain != "customer" || item.APIKeyID != "key" || item.Endpoint != "client/register" ||
+		item.StatusClass != "2xx" || item.RequestCount != 42 || item.PeriodStart != start {
+		t.Errorf("parseBucketField(%q) = %+v, %v", field, item, ok)
+	}
+	for _, test := range []struct{ field, count string }{
+		{"customer|key|client/register", "1"},
+		{field, "x"},
+		{field, "0"},
+	} {
+		if _, ok := parseBucketField(start, test.field, test.count); ok {
+			t.Errorf("parseBucketField(%q, %q) = ok; expected not ok", test.field, test.count)
+		}
+	}
+}
+
+func TestQuotaResetTimes(t *testing.T) {
+	var tests = []struct {
+		now              time.Time
+		dayEnd, monthEnd time.Time
+	}{
+		{time.Date(2026, 3, 14, 15, 9, 26, 0, time.UTC), time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC), time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2026, 12, 31, 23, 59, 59, 0, time.UTC), time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		// The periods are in UTC regardless of the time's location.
+		{time.Date(2026, 3, 1, 5, 0, 0, 0, time.FixedZone("WIB", 7*3600)), time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		dayEnd, monthEnd := QuotaResetTimes(test.now)
+		if !dayEnd.Equal(test.dayEnd) || !monthEnd.Equal(test.monthEnd) {
+			t.Errorf("QuotaResetTimes(%v) = %v, %v; expected %v, %v", test.now, dayEnd, monthEnd, test.dayEnd, test.monthEnd)
+		}
+	}
+}
+
+func TestQuotaKeys(t *testing.T) {
+	now := time.Date(2026, 3, 1, 5, 0, 0, 0, time.FixedZone("WIB", 7*3600))
+	dayKey, monthKey := quotaKeys("customer", "key", now)
+	if dayKey != "apiKeyQuota:customer:key:20260228" || monthKey != "apiKeyQuota:customer:key:202602" {
+		t.Errorf("quotaKeys(%v) = %q, %q", now, dayKey, monthKey)
+	}
+}
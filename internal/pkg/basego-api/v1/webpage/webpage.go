@@ -0,0 +1,151 @@
+// Package webpage renders the built-in web pages, e.g. the pages opened from the verification and reset password emails.
+package webpage
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+)
+
+// baseDir is the directory of the page templates. Every page is rendered inside "layout.html".
+const baseDir = "web/templates/"
+
+// AssetDir is the directory of the pages' theme, e.g. the stylesheet and images.
+const AssetDir = "assets/web/"
+
+// PathPrefix is the path prefix of the web pages' URLs.
+const PathPrefix = "/web"
+
+// Defines the paths of the pages, relative to PathPrefix.
+const (
+	PathVerifyEmail          = "/verify-email"
+	PathResetPasswordRequest = "/reset-password/request"
+	PathResetPassword        = "/reset-password"
+)
+
+var (
+	enabled    bool
+	backendURL string
+)
+
+// Init loads the configurations from environment variables.
+func Init() {
+	enabled = false
+	if s := os.Getenv(envvar.Web.PagesEnabled); s != "" {
+		b, err := strconv.ParseBool(strings.TrimSpace(s))
+		if err != nil {
+			logger.Println("webpage", fmt.Sprintf("WARN: %s '%s' is invalid, set to 'false' as default", envvar.Web.PagesEnabled, s))
+		} else {
+			enabled = b
+		}
+	}
+	backendURL = strings.TrimRight(os.Getenv(envvar.BackendURL), "/")
+	if enabled && backendURL == "" {
+		logger.Println("webpage", "WARN: BackendURL is empty, the links to the web pages are relative")
+	}
+	logger.Println("webpage", fmt.Sprintf("Enabled = %v", enabled))
+}
+
+// Enabled checks if the web pages are served.
+func Enabled() bool {
+	return enabled
+}
+
+// URL returns the absolute URL of a web page.
+func URL(path string, query url.Values) string {
+	u := backendURL + PathPrefix + path
+	if len(query) != 0 {
+		u += "?" + query.Encode()
+	}
+	return u
+}
+
+// Page contains the data for rendering a page.
+type Page struct {
+	Locale string
+	Title  string
+	Data   interface{}
+
+	// ExternalOrigins are the origins of the scripts and frames embedded in the page, e.g. of a CAPTCHA widget.
+	ExternalOrigins []string
+}
+
+type parsedTemplate struct {
+	tpl *template.Template
+	err error
+}
+
+var mapTemplates = make(map[string]*parsedTemplate)
+var mutexTemplate sync.Mutex
+
+// placeholderFuncs are replaced when a page is rendered, they're only needed to parse the templates.
+var placeholderFuncs = template.FuncMap{
+	"t": func(id string) string { return id },
+}
+
+func getByFilename(filename string) (*template.Template, error) {
+	mutexTemplate.Lock()
+	defer mutexTemplate.Unlock()
+
+	t, ok := mapTemplates[filename]
+	if t == nil || !ok {
+		tpl, err := template.New("layout.html").Funcs(placeholderFuncs).ParseFiles(baseDir+"layout.html", baseDir+filename)
+		t = &parsedTemplate{tpl, err}
+		mapTemplates[filename] = t
+	}
+	return t.tpl, t.err
+}
+
+// Render writes a page using the template file with the status code.
+// The templates can call "t" with a message ID to show the message in the page's locale.
+func Render(w http.ResponseWriter, statusCode int, filename string, page Page) {
+	tpl, err := getByFilename(filename)
+	if err == nil {
+		tpl, err = tpl.Clone()
+	}
+	if err != nil {
+		logger.Fatal("webpage", fmt.Sprintf("Render: %v", logger.FromError(err)))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	tpl.Funcs(template.FuncMap{
+		"t": func(id string) string { return i18n.NewMessage(id).Localize(page.Locale) },
+	})
+
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, page); err != nil {
+		logger.Fatal("webpage", fmt.Sprintf("Render: %v", logger.FromError(err)))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	// The pages may have a token in the URL, so don't leak it or let the pages be embedded.
+	h := w.Header()
+	h.Set("Content-Type", "text/html; charset=utf-8")
+	h.Set("Cache-Control", "no-store")
+	h.Set("Referrer-Policy", "no-referrer")
+	h.Set("X-Frame-Options", "DENY")
+	h.Set("Content-Security-Policy", contentSecurityPolicy(page.ExternalOrigins))
+	w.WriteHeader(statusCode)
+	w.Write(buf.Bytes())
+}
+
+// contentSecurityPolicy only allows the page's own resources, and the scripts and frames of the external origins.
+func contentSecurityPolicy(externalOrigins []string) string {
+	if len(externalOrigins) == 0 {
+		return "default-src 'self'; form-action 'self'; frame-ancestors 'none'"
+	}
+	origins := strings.Join(externalOrigins, " ")
+	return fmt.Sprintf("default-src 'self'; script-src 'self' %[1]s; frame-src %[1]s; connect-src 'self' %[1]s; "+
+		"form-action 'self'; frame-ancestors 'none'", origins)
+}
@@ -4,6 +4,8 @@ package errcode
 const (
 	ReqHeaderValidationFailed    = "40001"
 	ReqParamValidationFailed     = "40002"
+	OTPAttemptsExceeded          = "40003"
+	CaptchaFailed                = "40004"
 	AuthorizationEmpty           = "40101"
 	AuthorizationFormatInvalid   = "40102"
 	AuthorizationTokenInvalid    = "40103"
@@ -14,6 +16,9 @@ const (
 	PermissionDenied             = "40301"
 	ItemNotFound                 = "40401"
 	FileNotFound                 = "40401"
+	TooManyRequests              = "42901"
+	OTPResendCooldown            = "42902"
+	OTPSendLimitExceeded         = "42903"
 
 	APIKeyEmpty                = "49101"
 	APIKeyInvalid              = "49102"
@@ -22,6 +27,10 @@ const (
 	APIKeyAppIdentifierInvalid = "49105"
 	APIKeyExpired              = "49106"
 	APIKeyDisabled             = "49107"
+	APIKeySignatureRequired    = "49108"
+	APIKeySignatureInvalid     = "49109"
+	APIKeyScopeNotAllowed      = "49110"
+	APIKeyQuotaExceeded        = "49111"
 
 	InternalAPIKeyValidationFailed = "50001"
 	InternalIllegalArgument        = "50002"
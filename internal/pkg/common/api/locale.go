@@ -0,0 +1,38 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
+)
+
+// localeResponseWriter is an http.ResponseWriter which carries the locale of the request,
+// so API responses are localized without passing the locale to every response.
+type localeResponseWriter struct {
+	http.ResponseWriter
+	locale string
+}
+
+// WithLocale returns an http.ResponseWriter which sends API responses in a locale.
+func WithLocale(w http.ResponseWriter, locale string) http.ResponseWriter {
+	if lw, ok := w.(*localeResponseWriter); ok {
+		lw.locale = locale
+		return lw
+	}
+	return &localeResponseWriter{w, locale}
+}
+
+// SetLocale changes the locale of an http.ResponseWriter returned by WithLocale.
+func SetLocale(w http.ResponseWriter, locale string) {
+	if lw, ok := w.(*localeResponseWriter); ok {
+		lw.locale = locale
+	}
+}
+
+// GetLocale returns the locale of an http.ResponseWriter, or the default locale if it has none.
+func GetLocale(w http.ResponseWriter) string {
+	if lw, ok := w.(*localeResponseWriter); ok && lw.locale != "" {
+		return lw.locale
+	}
+	return i18n.DefaultLocale
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/jonylim/basego/internal/pkg/common/constant/httpstatus"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
 	"github.com/jonylim/basego/internal/pkg/common/logger"
 )
 
@@ -14,6 +15,9 @@ type Response struct {
 	ReqID  string        `json:"reqID"`
 	Err    ResponseError `json:"error"`
 	Data   interface{}   `json:"data"`
+
+	// errMsg is localized into Err.Message when the response is sent.
+	errMsg i18n.Message
 }
 
 // ResponseError is an error in an API request.
@@ -38,22 +42,24 @@ func NewAPIResponse(reqID string) *Response {
 }
 
 // NewAPIResponseWithError returns new instance of API response with error.
-func NewAPIResponseWithError(reqID, code, msg string) *Response {
+func NewAPIResponseWithError(reqID, code string, msg i18n.Message) *Response {
 	return &Response{
 		Status: httpstatus.OK,
 		ReqID:  reqID,
-		Err:    ResponseError{code, msg, ""},
+		Err:    ResponseError{code, msg.String(), ""},
 		Data:   ResponseData{},
+		errMsg: msg,
 	}
 }
 
 // NewAPIResponseWithErrorField returns new instance of API response with error.
-func NewAPIResponseWithErrorField(reqID, code, msg, field string) *Response {
+func NewAPIResponseWithErrorField(reqID, code string, msg i18n.Message, field string) *Response {
 	return &Response{
 		Status: httpstatus.OK,
 		ReqID:  reqID,
-		Err:    ResponseError{code, msg, field},
+		Err:    ResponseError{code, msg.String(), field},
 		Data:   ResponseData{},
+		errMsg: msg,
 	}
 }
 
@@ -63,13 +69,15 @@ func (r *Response) SetStatus(status int) {
 }
 
 // SetError sets the error message to the API response.
-func (r *Response) SetError(code, msg string) {
-	r.Err = ResponseError{code, msg, ""}
+func (r *Response) SetError(code string, msg i18n.Message) {
+	r.Err = ResponseError{code, msg.String(), ""}
+	r.errMsg = msg
 }
 
 // SetErrorField sets the error message to the API response.
-func (r *Response) SetErrorField(code, msg, field string) {
-	r.Err = ResponseError{code, msg, field}
+func (r *Response) SetErrorField(code string, msg i18n.Message, field string) {
+	r.Err = ResponseError{code, msg.String(), field}
+	r.errMsg = msg
 }
 
 // SetData sets the API response data. This will overwrite previously set data.
@@ -77,8 +85,16 @@ func (r *Response) SetData(data interface{}) {
 	r.Data = data
 }
 
+// localize sets the error message in the locale of the request.
+func (r *Response) localize(w http.ResponseWriter) {
+	if !r.errMsg.IsEmpty() {
+		r.Err.Message = r.errMsg.Localize(GetLocale(w))
+	}
+}
+
 // SendResponseJSON writes API response into JSON.
 func SendResponseJSON(w http.ResponseWriter, r *Response) error {
+	r.localize(w)
 	w.Header().Set("Content-Type", "application/json")
 	err := json.NewEncoder(w).Encode(r)
 	if err != nil {
@@ -89,6 +105,7 @@ func SendResponseJSON(w http.ResponseWriter, r *Response) error {
 
 // SendResponseJSONWithStatusCode writes API response into JSON.
 func SendResponseJSONWithStatusCode(w http.ResponseWriter, r *Response, statusCode int) error {
+	r.localize(w)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
 	r.Status = statusCode
@@ -0,0 +1,41 @@
+// Package captcha verifies the CAPTCHA / bot challenge tokens sent by clients to the public endpoints.
+package captcha
+
+import "errors"
+
+// Defines the supported providers.
+const (
+	ProviderReCAPTCHA = "recaptcha"
+	ProviderHCaptcha  = "hcaptcha"
+	ProviderTurnstile = "turnstile"
+	ProviderFake      = "fake"
+)
+
+// Defines where the clients send the tokens, either in the request header or in the JSON request body.
+const (
+	HeaderName = "Captcha-Token"
+	FieldName  = "captchaToken"
+)
+
+// ErrFailed is returned when a token is missing, invalid, expired or already used.
+var ErrFailed = errors.New("CAPTCHA verification failed")
+
+// Verifier verifies a challenge token solved by a client.
+type Verifier interface {
+	// Verify returns nil if the token is valid, ErrFailed if it isn't,
+	// or another error if the verification couldn't be done.
+	Verify(token, remoteIP string) error
+}
+
+// Fake accepts only its token, for tests and local development.
+type Fake struct {
+	Token string
+}
+
+// Verify checks if the token is the fake's token.
+func (f Fake) Verify(token, remoteIP string) error {
+	if token == "" || token != f.Token {
+		return ErrFailed
+	}
+	return nil
+}
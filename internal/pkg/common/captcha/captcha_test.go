@@ -0,0 +1,112 @@
+package captcha
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestFake(t *testing.T) {
+	var tests = []struct {
+		fake     Fake
+		token    string
+		expected error
+	}{
+		{Fake{Token: "pass"}, "pass", nil},
+		{Fake{Token: "pass"}, "fail", ErrFailed},
+		{Fake{Token: "pass"}, "", ErrFailed},
+		{Fake{}, "", ErrFailed},
+	}
+	for _, test := range tests {
+		if err := test.fake.Verify(test.token, ""); err != test.expected {
+			t.Errorf("%+v.Verify(%q) = %v; expected %v", test.fake, test.token, err, test.expected)
+		}
+	}
+}
+
+func TestSiteVerifier(t *testing.T) {
+	// The server answers like the providers' siteverify endpoints, using the token as the scenario.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.PostFormValue("secret") != "secret" {
+			w.Write([]byte(`{"success": false, "error-codes": ["invalid-input-secret"]}`))
+			return
+		}
+		switch r.PostFormValue("response") {
+		case "valid":
+			w.Write([]byte(`{"success": true}`))
+		case "high":
+			w.Write([]byte(`{"success": true, "score": 0.9}`))
+		case "low":
+			w.Write([]byte(`{"success": true, "score": 0.1}`))
+		case "down":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte(`{"success": false, "error-codes": ["invalid-input-response"]}`))
+		}
+	}))
+	defer server.Close()
+
+	var tests = []struct {
+		secret   string
+		minScore float64
+		token    string
+		ok       bool
+		failed   bool
+	}{
+		{"secret", 0, "valid", true, false},
+		{"secret", 0, "invalid", false, true},
+		{"secret", 0, "", false, true},
+		{"secret", 0.5, "valid", true, false},
+		{"secret", 0.5, "high", true, false},
+		{"secret", 0.5, "low", false, true},
+		{"secret", 0, "low", true, false},
+		{"secret", 0, "down", false, false},
+		{"wrong", 0, "valid", false, false},
+	}
+	for _, test := range tests {
+		err := NewSiteVerifier(server.URL, test.secret, test.minScore).Verify(test.token, "127.0.0.1")
+		if (err == nil) != test.ok || (err == ErrFailed) != test.failed {
+			t.Errorf("Verify(%q) with secret %q and min score %v = %v; expected ok %v, failed %v",
+				test.token, test.secret, test.minScore, err, test.ok, test.failed)
+		}
+	}
+}
+
+func TestConfigRequires(t *testing.T) {
+	c := Config{Verifier: Fake{Token: "pass"}, Endpoints: ParseEndpoints("client/register, /auth/ ,")}
+	if expected := []string{"client/register", "auth"}; !reflect.DeepEqual(c.Endpoints, expected) {
+		t.Errorf("ParseEndpoints = %v; expected %v", c.Endpoints, expected)
+	}
+	var tests = []struct {
+		group, endpoint string
+		expected        bool
+	}{
+		{"client", "register", true},
+		{"client", "server_time", false},
+		{"auth", "access_token/request", true},
+		{"account", "register", false},
+	}
+	for _, test := range tests {
+		if res := c.Requires(test.group, test.endpoint); res != test.expected {
+			t.Errorf("Requires(%q, %q) = %v; expected %v", test.group, test.endpoint, res, test.expected)
+		}
+	}
+	if (Config{Endpoints: c.Endpoints}).Requires("client", "register") {
+		t.Errorf("Requires without a verifier = true; expected false")
+	}
+}
+
+func TestConfigWidget(t *testing.T) {
+	c := Config{Verifier: NewSiteVerifier(TurnstileVerifyURL, "secret", 0), Provider: ProviderTurnstile, SiteKey: "site"}
+	if w, ok := c.Widget(); !ok || w.SiteKey != "site" || w.FieldName != "cf-turnstile-response" || w.ScriptURL == "" {
+		t.Errorf("Widget() of turnstile = %+v, %v", w, ok)
+	}
+	c = Config{Verifier: Fake{Token: "pass"}, Provider: ProviderFake}
+	if w, ok := c.Widget(); !ok || w.FieldName != FieldName || w.ScriptURL != "" {
+		t.Errorf("Widget() of fake = %+v, %v; expected the %s field without a script", w, ok, FieldName)
+	}
+	if _, ok := (Config{Provider: ProviderFake}).Widget(); ok {
+		t.Errorf("Widget() without a verifier = true; expected false")
+	}
+}
@@ -0,0 +1,130 @@
+package captcha
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+)
+
+// Config is the CAPTCHA verification's configuration.
+type Config struct {
+	// Verifier is nil if the verification is disabled.
+	Verifier Verifier
+
+	// Endpoints are the API groups (e.g. "client") or API groups and endpoint names (e.g. "client/register")
+	// requiring a token.
+	Endpoints []string
+
+	// Provider and SiteKey render the widget of the web forms requiring a token.
+	Provider string
+	SiteKey  string
+}
+
+// Requires checks if an API requires a token.
+func (c Config) Requires(group, endpoint string) bool {
+	if c.Verifier == nil {
+		return false
+	}
+	for _, s := range c.Endpoints {
+		if s == group || s == group+"/"+endpoint {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	current Config
+	mutex   sync.RWMutex
+)
+
+// Init loads the configuration from environment variables.
+// The verification is disabled unless a provider is configured, the endpoints default to defaultEndpoints.
+func Init(defaultEndpoints []string) {
+	c := Config{Endpoints: defaultEndpoints}
+	if s := os.Getenv(envvar.Captcha.Endpoints); s != "" {
+		c.Endpoints = ParseEndpoints(s)
+	}
+
+	provider := strings.ToLower(strings.TrimSpace(os.Getenv(envvar.Captcha.Provider)))
+	if provider == "" {
+		logger.Println("captcha", "Disabled, no provider is configured")
+		Set(Config{})
+		return
+	}
+	secret := os.Getenv(envvar.Captcha.Secret)
+	if secret == "" {
+		logger.Println("captcha", fmt.Sprintf("WARN: %s is empty, the verification is disabled", envvar.Captcha.Secret))
+		Set(Config{})
+		return
+	}
+	var minScore float64
+	if s := os.Getenv(envvar.Captcha.MinScore); s != "" {
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil || f < 0 || f > 1 {
+			logger.Println("captcha", fmt.Sprintf("WARN: %s '%s' is invalid, the score is ignored", envvar.Captcha.MinScore, s))
+		} else {
+			minScore = f
+		}
+	}
+
+	verifyURL := os.Getenv(envvar.Captcha.VerifyURL)
+	switch provider {
+	case ProviderReCAPTCHA, ProviderHCaptcha, ProviderTurnstile:
+		if verifyURL == "" {
+			verifyURL = map[string]string{
+				ProviderReCAPTCHA: ReCAPTCHAVerifyURL,
+				ProviderHCaptcha:  HCaptchaVerifyURL,
+				ProviderTurnstile: TurnstileVerifyURL,
+			}[provider]
+		}
+		c.Verifier = NewSiteVerifier(verifyURL, secret, minScore)
+		if c.SiteKey = os.Getenv(envvar.Captcha.SiteKey); c.SiteKey == "" {
+			logger.Println("captcha", fmt.Sprintf("WARN: %s is empty, the web forms can't show the widget", envvar.Captcha.SiteKey))
+		}
+	case ProviderFake:
+		// The secret is the only accepted token.
+		if os.Getenv(envvar.Environment) == "production" {
+			logger.Println("captcha", "WARN: The fake provider is used in production")
+		}
+		c.Verifier = Fake{Token: secret}
+	default:
+		logger.Println("captcha", fmt.Sprintf("WARN: %s '%s' is invalid, the verification is disabled", envvar.Captcha.Provider, provider))
+		Set(Config{})
+		return
+	}
+	c.Provider = provider
+	logger.Println("captcha", fmt.Sprintf("Provider = %s, VerifyURL = %s, MinScore = %v, Endpoints = %v",
+		provider, verifyURL, minScore, c.Endpoints))
+	Set(c)
+}
+
+// ParseEndpoints parses the endpoints separated by commas, e.g. "client/register, client/reset_password/request_token".
+func ParseEndpoints(s string) []string {
+	res := make([]string, 0)
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.Trim(strings.TrimSpace(item), "/"); item != "" {
+			res = append(res, item)
+		}
+	}
+	return res
+}
+
+// Get returns the active configuration.
+func Get() Config {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return current
+}
+
+// Set replaces the active configuration.
+func Set(c Config) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	current = c
+}
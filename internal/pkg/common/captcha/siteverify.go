@@ -0,0 +1,85 @@
+package captcha
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Defines the verification URLs of the providers.
+const (
+	ReCAPTCHAVerifyURL = "https://www.google.com/recaptcha/api/siteverify"
+	HCaptchaVerifyURL  = "https://api.hcaptcha.com/siteverify"
+	TurnstileVerifyURL = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+)
+
+// SiteVerifier verifies tokens by posting them to a "siteverify" HTTP endpoint,
+// the API shared by reCAPTCHA, hCaptcha and Turnstile.
+type SiteVerifier struct {
+	URL    string
+	Secret string
+
+	// MinScore is the minimum score of reCAPTCHA v3 & hCaptcha Enterprise responses, 0 ignores the score.
+	MinScore float64
+
+	Client *http.Client
+}
+
+type siteVerifyResponse struct {
+	Success    bool     `json:"success"`
+	Score      *float64 `json:"score"`
+	ErrorCodes []string `json:"error-codes"`
+}
+
+// NewSiteVerifier returns new instance of SiteVerifier.
+func NewSiteVerifier(verifyURL, secret string, minScore float64) *SiteVerifier {
+	return &SiteVerifier{
+		URL:      verifyURL,
+		Secret:   secret,
+		MinScore: minScore,
+		Client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Verify posts the token to the verification URL.
+func (v *SiteVerifier) Verify(token, remoteIP string) error {
+	if token == "" {
+		return ErrFailed
+	}
+	form := url.Values{
+		"secret":   {v.Secret},
+		"response": {token},
+	}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+	resp, err := v.Client.PostForm(v.URL, form)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("siteverify returns HTTP status %d", resp.StatusCode)
+	}
+
+	var res siteVerifyResponse
+	if err = json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return fmt.Errorf("siteverify response is invalid: %v", err)
+	}
+	if !res.Success {
+		// A wrong secret is a misconfiguration, not the client's fault.
+		for _, code := range res.ErrorCodes {
+			if strings.HasPrefix(code, "missing-input-secret") || strings.HasPrefix(code, "invalid-input-secret") {
+				return fmt.Errorf("siteverify rejects the secret: %s", code)
+			}
+		}
+		return ErrFailed
+	}
+	if v.MinScore > 0 && res.Score != nil && *res.Score < v.MinScore {
+		return ErrFailed
+	}
+	return nil
+}
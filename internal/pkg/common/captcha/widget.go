@@ -0,0 +1,47 @@
+package captcha
+
+// Widget is the challenge shown in a web form. The provider's script renders it into the element of its class,
+// and the solved token is submitted in the form field FieldName.
+type Widget struct {
+	ScriptURL string
+	Class     string
+	SiteKey   string
+	FieldName string
+
+	// Origins are the origins of the provider's scripts and frames, allowed by the page's content security policy.
+	Origins []string
+}
+
+// widgets are the providers' widgets, without the site keys.
+var widgets = map[string]Widget{
+	ProviderReCAPTCHA: {
+		ScriptURL: "https://www.google.com/recaptcha/api.js",
+		Class:     "g-recaptcha",
+		FieldName: "g-recaptcha-response",
+		Origins:   []string{"https://www.google.com", "https://www.gstatic.com"},
+	},
+	ProviderHCaptcha: {
+		ScriptURL: "https://js.hcaptcha.com/1/api.js",
+		Class:     "h-captcha",
+		FieldName: "h-captcha-response",
+		Origins:   []string{"https://hcaptcha.com", "https://*.hcaptcha.com"},
+	},
+	ProviderTurnstile: {
+		ScriptURL: "https://challenges.cloudflare.com/turnstile/v0/api.js",
+		Class:     "cf-turnstile",
+		FieldName: "cf-turnstile-response",
+		Origins:   []string{"https://challenges.cloudflare.com"},
+	},
+	// The fake has no script, its token is typed in a text field.
+	ProviderFake: {FieldName: FieldName},
+}
+
+// Widget returns the widget of the configured provider. The boolean is false if the verification is disabled.
+func (c Config) Widget() (Widget, bool) {
+	if c.Verifier == nil {
+		return Widget{}, false
+	}
+	w, ok := widgets[c.Provider]
+	w.SiteKey = c.SiteKey
+	return w, ok
+}
@@ -59,9 +59,17 @@ var Asset = struct{ BaseStaticAssetURL string }{
 }
 
 // Storage Configs
-var Storage = struct{ DefaultStorage, LocalDirPath string }{
-	DefaultStorage: withAppPrefix("DEFAULT_STORAGE"),
-	LocalDirPath:   withAppPrefix("LOCAL_STORAGE_DIRPATH"),
+var Storage = struct {
+	DefaultStorage, SignedURLExpiry, LocalDirPath, LocalPublicURL, LocalSigningKey string
+	MemoryLatency, MemoryFailPaths                                                 string
+}{
+	DefaultStorage:  withAppPrefix("DEFAULT_STORAGE"),
+	SignedURLExpiry: withAppPrefix("STORAGE_SIGNED_URL_EXPIRY"),
+	LocalDirPath:    withAppPrefix("LOCAL_STORAGE_DIRPATH"),
+	LocalPublicURL:  withAppPrefix("LOCAL_STORAGE_PUBLIC_URL"),
+	LocalSigningKey: withAppPrefix("LOCAL_STORAGE_SIGNING_KEY"),
+	MemoryLatency:   withAppPrefix("MEMORY_STORAGE_LATENCY"),
+	MemoryFailPaths: withAppPrefix("MEMORY_STORAGE_FAIL_PATHS"),
 }
 
 // Google Cloud Storage
@@ -73,6 +81,17 @@ var Google = struct{ AppCredentials, ProjectID, StorageBucket, StorageClass, Sto
 	StorageLocation: withGooglePrefix("STORAGE_LOCATION"),
 }
 
+// S3-compatible Storage
+var S3 = struct{ Endpoint, Region, Bucket, PathStyle, AccessKeyID, SecretAccessKey, SessionToken string }{
+	Endpoint:        withAppPrefix("S3_ENDPOINT"),
+	Region:          withAppPrefix("S3_REGION"),
+	Bucket:          withAppPrefix("S3_BUCKET"),
+	PathStyle:       withAppPrefix("S3_PATH_STYLE"),
+	AccessKeyID:     withAppPrefix("S3_ACCESS_KEY_ID"),
+	SecretAccessKey: withAppPrefix("S3_SECRET_ACCESS_KEY"),
+	SessionToken:    withAppPrefix("S3_SESSION_TOKEN"),
+}
+
 // SMTP Configs
 var SMTP = struct{ Host, Port, Username, Password, FromEmail, FromName string }{
 	Host:      withAppPrefix("SMTP_HOST"),
@@ -83,6 +102,119 @@ var SMTP = struct{ Host, Port, Username, Password, FromEmail, FromName string }{
 	FromName:  withAppPrefix("SMTP_FROM_NAME"),
 }
 
+// Password Policy Configs
+var PasswordPolicy = struct {
+	MinLength, MaxLength                                              string
+	RequireLowercase, RequireUppercase, RequireNumber, RequireSpecial string
+	HistoryCount, MaxAgeDays, BreachedDirPath                         string
+}{
+	MinLength:        withAppPrefix("PASSWORD_MIN_LENGTH"),
+	MaxLength:        withAppPrefix("PASSWORD_MAX_LENGTH"),
+	RequireLowercase: withAppPrefix("PASSWORD_REQUIRE_LOWERCASE"),
+	RequireUppercase: withAppPrefix("PASSWORD_REQUIRE_UPPERCASE"),
+	RequireNumber:    withAppPrefix("PASSWORD_REQUIRE_NUMBER"),
+	RequireSpecial:   withAppPrefix("PASSWORD_REQUIRE_SPECIAL"),
+	HistoryCount:     withAppPrefix("PASSWORD_HISTORY_COUNT"),
+	MaxAgeDays:       withAppPrefix("PASSWORD_MAX_AGE_DAYS"),
+	BreachedDirPath:  withAppPrefix("PASSWORD_BREACHED_DIRPATH"),
+}
+
+// Rate Limit Configs
+var RateLimit = struct{ Enabled, Rules string }{
+	Enabled: withAppPrefix("RATE_LIMIT_ENABLED"),
+	Rules:   withAppPrefix("RATE_LIMIT_RULES"),
+}
+
+// Web Page Configs
+var Web = struct{ PagesEnabled string }{
+	PagesEnabled: withAppPrefix("WEB_PAGES_ENABLED"),
+}
+
+// Link Token Configs
+var LinkToken = struct{ Keys string }{
+	Keys: withAppPrefix("LINK_TOKEN_KEYS"),
+}
+
+// OTP Configs
+var OTP = struct{ Policies string }{
+	Policies: withAppPrefix("OTP_POLICIES"),
+}
+
+// Secret Hash Configs
+var SecretHash = struct{ Key string }{
+	Key: withAppPrefix("SECRET_HASH_KEY"),
+}
+
+// Email Vetting Configs
+var EmailVetting = struct{ DisposableListPath, SuggestTypos, CheckDNS string }{
+	DisposableListPath: withAppPrefix("EMAIL_DISPOSABLE_LIST_PATH"),
+	SuggestTypos:       withAppPrefix("EMAIL_SUGGEST_TYPOS"),
+	CheckDNS:           withAppPrefix("EMAIL_CHECK_DNS"),
+}
+
+// CAPTCHA Configs
+var Captcha = struct{ Provider, Secret, SiteKey, VerifyURL, MinScore, Endpoints string }{
+	Provider:  withAppPrefix("CAPTCHA_PROVIDER"),
+	Secret:    withAppPrefix("CAPTCHA_SECRET"),
+	SiteKey:   withAppPrefix("CAPTCHA_SITE_KEY"),
+	VerifyURL: withAppPrefix("CAPTCHA_VERIFY_URL"),
+	MinScore:  withAppPrefix("CAPTCHA_MIN_SCORE"),
+	Endpoints: withAppPrefix("CAPTCHA_ENDPOINTS"),
+}
+
+// Reminder Configs
+var Reminder = struct {
+	Enabled, Interval, VerifyEmailDelays, MaxSendCount, DeleteUnverifiedAfterDays string
+}{
+	Enabled:                   withAppPrefix("REMINDER_ENABLED"),
+	Interval:                  withAppPrefix("REMINDER_INTERVAL"),
+	VerifyEmailDelays:         withAppPrefix("REMINDER_VERIFY_EMAIL_DELAYS"),
+	MaxSendCount:              withAppPrefix("REMINDER_MAX_SEND_COUNT"),
+	DeleteUnverifiedAfterDays: withAppPrefix("REMINDER_DELETE_UNVERIFIED_AFTER_DAYS"),
+}
+
+// Image Pipeline Configs
+var Image = struct{ Variants, Formats, Quality, Blurhash, MaxPixels string }{
+	Variants:  withAppPrefix("IMAGE_VARIANTS"),
+	Formats:   withAppPrefix("IMAGE_FORMATS"),
+	Quality:   withAppPrefix("IMAGE_QUALITY"),
+	Blurhash:  withAppPrefix("IMAGE_BLURHASH"),
+	MaxPixels: withAppPrefix("IMAGE_MAX_PIXELS"),
+}
+
+// File GC Configs
+var FileGC = struct{ Enabled, Interval, Retention, OrphanGracePeriod, Delete string }{
+	Enabled:           withAppPrefix("FILE_GC_ENABLED"),
+	Interval:          withAppPrefix("FILE_GC_INTERVAL"),
+	Retention:         withAppPrefix("FILE_GC_RETENTION"),
+	OrphanGracePeriod: withAppPrefix("FILE_GC_ORPHAN_GRACE_PERIOD"),
+	Delete:            withAppPrefix("FILE_GC_DELETE"),
+}
+
+// API Key Configs
+var APIKey = struct{ SignatureMaxClockSkew, UsageFlushInterval string }{
+	SignatureMaxClockSkew: withAppPrefix("API_KEY_SIGNATURE_MAX_CLOCK_SKEW"),
+	UsageFlushInterval:    withAppPrefix("API_KEY_USAGE_FLUSH_INTERVAL"),
+}
+
+// CORS Configs
+var CORS = struct {
+	AllowedOrigins, AllowedMethods, AllowedHeaders, ExposedHeaders, AllowCredentials, MaxAge, APIKeyOriginsRefresh string
+}{
+	AllowedOrigins:       withAppPrefix("CORS_ALLOWED_ORIGINS"),
+	AllowedMethods:       withAppPrefix("CORS_ALLOWED_METHODS"),
+	AllowedHeaders:       withAppPrefix("CORS_ALLOWED_HEADERS"),
+	ExposedHeaders:       withAppPrefix("CORS_EXPOSED_HEADERS"),
+	AllowCredentials:     withAppPrefix("CORS_ALLOW_CREDENTIALS"),
+	MaxAge:               withAppPrefix("CORS_MAX_AGE"),
+	APIKeyOriginsRefresh: withAppPrefix("CORS_API_KEY_ORIGINS_REFRESH"),
+}
+
+// File Encryption Configs
+var FileEncryption = struct{ Keys string }{
+	Keys: withAppPrefix("FILE_ENCRYPTION_KEYS"),
+}
+
 func withAppPrefix(key string) string {
 	return appPrefix + key
 }
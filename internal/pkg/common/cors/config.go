@@ -0,0 +1,155 @@
+package cors
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+)
+
+// Config is the CORS policy's configuration.
+type Config struct {
+	// AllowedOrigins are the exact origins (e.g. "https://app.example.com"), the wildcard subdomains
+	// (e.g. "https://*.example.com"), or "*" for any origin. See MatchOrigin.
+	AllowedOrigins []string
+
+	// AllowedMethods and AllowedHeaders are the methods and request headers the preflight requests may ask for.
+	AllowedMethods []string
+	AllowedHeaders []string
+
+	// ExposedHeaders are the response headers readable by the browsers' scripts.
+	ExposedHeaders []string
+
+	// AllowCredentials allows the requests with cookies or HTTP authentication.
+	AllowCredentials bool
+
+	// MaxAge is how long the browsers may cache the preflight responses, 0 to not send the header.
+	MaxAge time.Duration
+
+	// APIKeyOriginsRefresh is how often the origins of the web API keys are reloaded, 0 to not allow them.
+	APIKeyOriginsRefresh time.Duration
+}
+
+// Default returns the default configuration, allowing only the frontend URL's origin.
+func Default() Config {
+	c := Config{
+		AllowedOrigins:       []string{},
+		AllowedMethods:       []string{http.MethodPost},
+		AllowedHeaders:       []string{},
+		ExposedHeaders:       []string{},
+		MaxAge:               10 * time.Minute,
+		APIKeyOriginsRefresh: time.Minute,
+	}
+	if origin, ok := NormalizeOrigin(os.Getenv(envvar.FrontendURL)); ok {
+		c.AllowedOrigins = append(c.AllowedOrigins, origin)
+	}
+	return c
+}
+
+var (
+	current = Default()
+	mutex   sync.RWMutex
+)
+
+// Init loads the configuration from environment variables.
+// The allowed methods and headers and the exposed headers default to the ones of defaults, the others to Default.
+// The origins of the web API keys are loaded from source, nil to not allow them.
+func Init(defaults Config, source OriginSource) {
+	c := Default()
+	if len(defaults.AllowedMethods) > 0 {
+		c.AllowedMethods = defaults.AllowedMethods
+	}
+	c.AllowedHeaders = defaults.AllowedHeaders
+	c.ExposedHeaders = defaults.ExposedHeaders
+
+	if s := os.Getenv(envvar.CORS.AllowedOrigins); s != "" {
+		c.AllowedOrigins = make([]string, 0)
+		for _, item := range ParseList(s) {
+			if item == "*" {
+				c.AllowedOrigins = append(c.AllowedOrigins, item)
+			} else if origin, ok := NormalizeOrigin(item); ok {
+				c.AllowedOrigins = append(c.AllowedOrigins, origin)
+			} else {
+				logger.Println("cors", fmt.Sprintf("WARN: Origin '%s' in %s is invalid, it's ignored", item, envvar.CORS.AllowedOrigins))
+			}
+		}
+	}
+	if s := os.Getenv(envvar.CORS.AllowedMethods); s != "" {
+		c.AllowedMethods = make([]string, 0)
+		for _, item := range ParseList(s) {
+			c.AllowedMethods = append(c.AllowedMethods, strings.ToUpper(item))
+		}
+	}
+	if s := os.Getenv(envvar.CORS.AllowedHeaders); s != "" {
+		c.AllowedHeaders = ParseList(s)
+	}
+	if s := os.Getenv(envvar.CORS.ExposedHeaders); s != "" {
+		c.ExposedHeaders = ParseList(s)
+	}
+	if s := os.Getenv(envvar.CORS.AllowCredentials); s != "" {
+		if b, err := strconv.ParseBool(strings.TrimSpace(s)); err != nil {
+			logger.Println("cors", fmt.Sprintf("WARN: %s '%s' is invalid, set to '%v' as default", envvar.CORS.AllowCredentials, s, c.AllowCredentials))
+		} else {
+			c.AllowCredentials = b
+		}
+	}
+	if s := os.Getenv(envvar.CORS.MaxAge); s != "" {
+		if d, err := time.ParseDuration(strings.TrimSpace(s)); err != nil || d < 0 {
+			logger.Println("cors", fmt.Sprintf("WARN: %s '%s' is invalid, set to '%v' as default", envvar.CORS.MaxAge, s, c.MaxAge))
+		} else {
+			c.MaxAge = d
+		}
+	}
+	if s := os.Getenv(envvar.CORS.APIKeyOriginsRefresh); s != "" {
+		if d, err := time.ParseDuration(strings.TrimSpace(s)); err != nil || d < 0 {
+			logger.Println("cors", fmt.Sprintf("WARN: %s '%s' is invalid, set to '%v' as default", envvar.CORS.APIKeyOriginsRefresh, s, c.APIKeyOriginsRefresh))
+		} else {
+			c.APIKeyOriginsRefresh = d
+		}
+	}
+
+	if len(c.AllowedOrigins) == 0 {
+		logger.Println("cors", "WARN: No origin is allowed, only the origins of the web API keys are")
+	}
+	if c.AllowCredentials && c.allowsAnyOrigin() {
+		logger.Println("cors", "WARN: Any origin is allowed to send credentials")
+	}
+	logger.Println("cors", fmt.Sprintf("AllowedOrigins = %v, AllowedMethods = %v, AllowedHeaders = %v, ExposedHeaders = %v, AllowCredentials = %v, MaxAge = %v, APIKeyOriginsRefresh = %v",
+		c.AllowedOrigins, c.AllowedMethods, c.AllowedHeaders, c.ExposedHeaders, c.AllowCredentials, c.MaxAge, c.APIKeyOriginsRefresh))
+	Set(c)
+	if c.APIKeyOriginsRefresh == 0 {
+		source = nil
+	}
+	setOriginSource(source, c.APIKeyOriginsRefresh)
+}
+
+// ParseList parses the items separated by commas, e.g. "Content-Type, API-Key".
+func ParseList(s string) []string {
+	res := make([]string, 0)
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			res = append(res, item)
+		}
+	}
+	return res
+}
+
+// Get returns the active configuration.
+func Get() Config {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return current
+}
+
+// Set replaces the active configuration.
+func Set(c Config) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	current = c
+}
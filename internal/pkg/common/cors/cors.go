@@ -0,0 +1,170 @@
+// Package cors applies the CORS policy of the APIs, echoing back the allowed origins so several web apps can call them.
+package cors
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/common/constant/httpstatus"
+)
+
+// NormalizeOrigin returns the origin of a URL in lowercase without the scheme's default port,
+// e.g. "https://app.example.com" for "HTTPS://App.Example.com:443/".
+// The boolean is false if the URL isn't an HTTP(S) origin, having a path, query or user info.
+func NormalizeOrigin(s string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil || u.Host == "" || u.User != nil || (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+		return "", false
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return "", false
+	}
+	host, port := strings.ToLower(u.Hostname()), u.Port()
+	if (scheme == "http" && port == "80") || (scheme == "https" && port == "443") {
+		port = ""
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if port != "" {
+		host += ":" + port
+	}
+	return scheme + "://" + host, true
+}
+
+// MatchOrigin checks if a normalized origin matches a pattern from AllowedOrigins.
+// "*" matches any origin, "https://*.example.com" matches the subdomains of example.com at any depth
+// with the same scheme and port, but not example.com itself.
+func MatchOrigin(pattern, origin string) bool {
+	if pattern == "*" || pattern == origin {
+		return true
+	}
+	i := strings.Index(pattern, "://*.")
+	if i < 0 {
+		return false
+	}
+	scheme, suffix := pattern[:i+3], pattern[i+4:]
+	if !strings.HasPrefix(origin, scheme) {
+		return false
+	}
+	host := origin[len(scheme):]
+	return len(host) > len(suffix) && strings.HasSuffix(host, suffix)
+}
+
+// AllowsOrigin checks if a normalized origin matches any of the allowed origins.
+func (c Config) AllowsOrigin(origin string) bool {
+	for _, pattern := range c.AllowedOrigins {
+		if MatchOrigin(pattern, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Config) allowsAnyOrigin() bool {
+	for _, pattern := range c.AllowedOrigins {
+		if pattern == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsMethod checks if the method asked by a preflight request is allowed.
+func (c Config) AllowsMethod(method string) bool {
+	return containsFold(c.AllowedMethods, method)
+}
+
+// AllowsHeaders checks if all the request headers asked by a preflight request are allowed.
+func (c Config) AllowsHeaders(requestHeaders string) bool {
+	for _, name := range ParseList(requestHeaders) {
+		if !containsFold(c.AllowedHeaders, name) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply sets the CORS headers of an actual request's response if the request's origin is allowed.
+// The response is sent as usual either way, the browsers keep it from the disallowed origins' scripts.
+func Apply(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Vary", "Origin")
+	origin, ok := allowedOrigin(r)
+	if !ok {
+		return
+	}
+	c := Get()
+	h := w.Header()
+	h.Set("Access-Control-Allow-Origin", origin)
+	if c.AllowCredentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+	if len(c.ExposedHeaders) != 0 {
+		h.Set("Access-Control-Expose-Headers", strings.Join(c.ExposedHeaders, ", "))
+	}
+}
+
+// Preflight responds to an OPTIONS request. The preflight requests from a disallowed origin,
+// or asking for a disallowed method or header, are responded with 403 Forbidden without the CORS headers.
+func Preflight(w http.ResponseWriter, r *http.Request) {
+	method := r.Header.Get("Access-Control-Request-Method")
+	if method == "" {
+		// Not a preflight request.
+		Apply(w, r)
+		w.WriteHeader(httpstatus.NoContent)
+		return
+	}
+
+	h := w.Header()
+	h.Add("Vary", "Origin")
+	h.Add("Vary", "Access-Control-Request-Method")
+	h.Add("Vary", "Access-Control-Request-Headers")
+	c := Get()
+	origin, ok := allowedOrigin(r)
+	if !ok || !c.AllowsMethod(method) || !c.AllowsHeaders(r.Header.Get("Access-Control-Request-Headers")) {
+		w.WriteHeader(httpstatus.Forbidden)
+		return
+	}
+	h.Set("Access-Control-Allow-Origin", origin)
+	if c.AllowCredentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+	h.Set("Access-Control-Allow-Methods", strings.Join(c.AllowedMethods, ", "))
+	if len(c.AllowedHeaders) != 0 {
+		h.Set("Access-Control-Allow-Headers", strings.Join(c.AllowedHeaders, ", "))
+	}
+	if c.MaxAge > 0 {
+		h.Set("Access-Control-Max-Age", strconv.FormatInt(int64(c.MaxAge/time.Second), 10))
+	}
+	w.WriteHeader(httpstatus.NoContent)
+}
+
+// allowedOrigin returns the request's origin, the boolean is false if it's missing or not allowed.
+// The origin is returned as sent, since the browsers compare it exactly.
+func allowedOrigin(r *http.Request) (string, bool) {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return "", false
+	}
+	normalized, ok := NormalizeOrigin(origin)
+	if !ok {
+		return "", false
+	}
+	if Get().AllowsOrigin(normalized) || allowsAPIKeyOrigin(normalized, time.Now()) {
+		return origin, true
+	}
+	return "", false
+}
@@ -0,0 +1,152 @@
+package cors
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNormalizeOrigin(t *testing.T) {
+	var tests = []struct {
+		s        string
+		expected string
+		ok       bool
+	}{
+		{"https://app.example.com", "https://app.example.com", true},
+		{"HTTPS://App.Example.com:443/", "https://app.example.com", true},
+		{"http://localhost:3000", "http://localhost:3000", true},
+		{"http://example.com:80", "http://example.com", true},
+		{"https://example.com:80", "https://example.com:80", true},
+		{"https://example.com/path", "", false},
+		{"https://example.com?q=1", "", false},
+		{"https://user@example.com", "", false},
+		{"ftp://example.com", "", false},
+		{"example.com", "", false},
+		{"", "", false},
+	}
+	for _, test := range tests {
+		if res, ok := NormalizeOrigin(test.s); res != test.expected || ok != test.ok {
+			t.Errorf("NormalizeOrigin(%q) = %q, %v; expected %q, %v", test.s, res, ok, test.expected, test.ok)
+		}
+	}
+}
+
+func TestMatchOrigin(t *testing.T) {
+	var tests = []struct {
+		pattern, origin string
+		expected        bool
+	}{
+		{"https://app.example.com", "https://app.example.com", true},
+		{"https://app.example.com", "https://other.example.com", false},
+		{"https://app.example.com", "http://app.example.com", false},
+		{"*", "https://anything.test", true},
+		{"https://*.example.com", "https://app.example.com", true},
+		{"https://*.example.com", "https://a.b.example.com", true},
+		{"https://*.example.com", "https://example.com", false},
+		{"https://*.example.com", "https://evilexample.com", false},
+		{"https://*.example.com", "https://app.example.com.evil.test", false},
+		{"https://*.example.com", "http://app.example.com", false},
+		{"https://*.example.com", "https://app.example.com:8443", false},
+		{"https://*.example.com:8443", "https://app.example.com:8443", true},
+	}
+	for _, test := range tests {
+		if res := MatchOrigin(test.pattern, test.origin); res != test.expected {
+			t.Errorf("MatchOrigin(%q, %q) = %v; expected %v", test.pattern, test.origin, res, test.expected)
+		}
+	}
+}
+
+func TestConfigAllowsHeaders(t *testing.T) {
+	c := Config{AllowedHeaders: []string{"API-Key", "Content-Type"}}
+	var tests = []struct {
+		requestHeaders string
+		expected       bool
+	}{
+		{"", true},
+		{"api-key", true},
+		{"api-key, content-type", true},
+		{"api-key, x-custom", false},
+	}
+	for _, test := range tests {
+		if res := c.AllowsHeaders(test.requestHeaders); res != test.expected {
+			t.Errorf("AllowsHeaders(%q) = %v; expected %v", test.requestHeaders, res, test.expected)
+		}
+	}
+}
+
+func TestPreflight(t *testing.T) {
+	defer Set(Get())
+	defer setOriginSource(nil, 0)
+	Set(Config{
+		AllowedOrigins:   []string{"https://app.example.com", "https://*.example.org"},
+		AllowedMethods:   []string{http.MethodPost},
+		AllowedHeaders:   []string{"API-Key", "Content-Type"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	})
+	setOriginSource(func() ([]string, error) {
+		return []string{"https://web.example.net"}, nil
+	}, time.Minute)
+
+	var tests = []struct {
+		origin, method, headers string
+		expected                int
+	}{
+		{"https://app.example.com", "POST", "api-key,content-type", http.StatusNoContent},
+		{"https://shop.example.org", "POST", "", http.StatusNoContent},
+		{"https://web.example.net", "POST", "api-key", http.StatusNoContent},
+		{"https://evil.test", "POST", "api-key", http.StatusForbidden},
+		{"https://app.example.com", "DELETE", "", http.StatusForbidden},
+		{"https://app.example.com", "POST", "x-custom", http.StatusForbidden},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest(http.MethodOptions, "/v1/client/register", nil)
+		r.Header.Set("Origin", test.origin)
+		r.Header.Set("Access-Control-Request-Method", test.method)
+		r.Header.Set("Access-Control-Request-Headers", test.headers)
+		w := httptest.NewRecorder()
+		Preflight(w, r)
+
+		allowOrigin := w.Header().Get("Access-Control-Allow-Origin")
+		if w.Code != test.expected {
+			t.Errorf("Preflight(%q, %q, %q) = %d; expected %d", test.origin, test.method, test.headers, w.Code, test.expected)
+		} else if w.Code == http.StatusNoContent {
+			if allowOrigin != test.origin || w.Header().Get("Access-Control-Allow-Headers") != "API-Key, Content-Type" ||
+				w.Header().Get("Access-Control-Max-Age") != "600" || w.Header().Get("Access-Control-Allow-Credentials") != "true" {
+				t.Errorf("Preflight(%q) headers = %v", test.origin, w.Header())
+			}
+		} else if allowOrigin != "" {
+			t.Errorf("Preflight(%q) Access-Control-Allow-Origin = %q; expected none", test.origin, allowOrigin)
+		}
+	}
+}
+
+func TestOriginCache(t *testing.T) {
+	calls := 0
+	var failure error
+	c := &originCache{
+		source: func() ([]string, error) {
+			calls++
+			if failure != nil {
+				return nil, failure
+			}
+			return []string{"HTTPS://App.Example.com"}, nil
+		},
+		refresh: time.Minute,
+	}
+	now := time.Now()
+	if !c.contains("https://app.example.com", now) || calls != 1 {
+		t.Errorf("contains = false or %d calls; expected true after 1 call", calls)
+	}
+	if c.contains("https://other.example.com", now.Add(time.Second)) || calls != 1 {
+		t.Errorf("contains = true or %d calls; expected false from the cache", calls)
+	}
+
+	// The last origins are kept if the source fails.
+	failure = errors.New("failed")
+	if !c.contains("https://app.example.com", now.Add(time.Minute)) || calls != 2 {
+		t.Errorf("contains = false or %d calls; expected true after 2 calls", calls)
+	}
+}
@@ -0,0 +1,66 @@
+package cors
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+)
+
+// OriginSource returns the origins allowed in addition to the configured ones, e.g. the web API keys' app identifiers.
+type OriginSource func() ([]string, error)
+
+// originCache keeps the origins of a source, reloading them when they're older than the refresh interval.
+// Each instance reloads on its own, so the changes take up to the interval to be applied.
+type originCache struct {
+	mutex    sync.Mutex
+	source   OriginSource
+	refresh  time.Duration
+	origins  map[string]bool
+	loadedAt time.Time
+}
+
+var apiKeyOrigins struct {
+	sync.RWMutex
+	cache *originCache
+}
+
+// setOriginSource replaces the source of the API keys' origins, nil to not allow them.
+func setOriginSource(source OriginSource, refresh time.Duration) {
+	apiKeyOrigins.Lock()
+	defer apiKeyOrigins.Unlock()
+	if source == nil {
+		apiKeyOrigins.cache = nil
+		return
+	}
+	apiKeyOrigins.cache = &originCache{source: source, refresh: refresh, origins: map[string]bool{}}
+}
+
+// allowsAPIKeyOrigin checks if a normalized origin is one of the API keys' origins.
+func allowsAPIKeyOrigin(origin string, now time.Time) bool {
+	apiKeyOrigins.RLock()
+	cache := apiKeyOrigins.cache
+	apiKeyOrigins.RUnlock()
+	return cache != nil && cache.contains(origin, now)
+}
+
+func (c *originCache) contains(origin string, now time.Time) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.loadedAt.IsZero() || now.Sub(c.loadedAt) >= c.refresh {
+		// The last origins are kept if the source fails, it's retried after the interval.
+		c.loadedAt = now
+		if items, err := c.source(); err != nil {
+			logger.Error("cors", logger.FromError(err))
+		} else {
+			origins := make(map[string]bool, len(items))
+			for _, item := range items {
+				if normalized, ok := NormalizeOrigin(item); ok {
+					origins[normalized] = true
+				}
+			}
+			c.origins = origins
+		}
+	}
+	return c.origins[origin]
+}
@@ -0,0 +1,169 @@
+// Package envelope encrypts files with envelope encryption: each file is encrypted by its own random data key,
+// and the data key is wrapped by a master key and saved with the file's record.
+//
+// A wrapped key is "e1:<master key ID>:<base64url of the nonce and the AES-256-GCM ciphertext of the data key>".
+// The master keys have IDs, so a new key can wrap the new files' data keys while the old keys still unwrap
+// the data keys wrapped before.
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+)
+
+// Prefix is the prefix of the wrapped keys, versioning their format.
+const Prefix = "e1:"
+
+// KeySize is the size of the master and data keys in bytes, for AES-256.
+const KeySize = 32
+
+// Errors of the keys.
+var (
+	ErrNotConfigured = errors.New("File encryption keys are not configured")
+	ErrUnknownKey    = errors.New("Master key of the file is not configured")
+	ErrInvalidKey    = errors.New("Encrypt key is invalid")
+)
+
+var (
+	currentID  string
+	masterKeys map[string][]byte
+	mutex      sync.RWMutex
+)
+
+// Init loads the master keys from environment variables. Without keys, the files aren't encrypted.
+func Init() {
+	s := os.Getenv(envvar.FileEncryption.Keys)
+	if s == "" {
+		logger.Println("envelope", fmt.Sprintf("WARN: %s is empty, the files won't be encrypted", envvar.FileEncryption.Keys))
+		return
+	}
+	id, keys, err := ParseKeys(s)
+	if err != nil {
+		logger.Println("envelope", fmt.Sprintf("WARN: %s is invalid, the files won't be encrypted: %v", envvar.FileEncryption.Keys, err))
+		return
+	}
+	SetKeys(id, keys)
+	logger.Println("envelope", fmt.Sprintf("Master key set to '%s', with %d keys to decrypt", id, len(keys)))
+}
+
+// ParseKeys parses the master keys "<ID>:<base64 key>" separated by commas. The first key is the current key.
+func ParseKeys(s string) (currentID string, keys map[string][]byte, err error) {
+	keys = make(map[string][]byte)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		i := strings.Index(item, ":")
+		if i <= 0 {
+			return "", nil, errors.New("a key has no ID")
+		}
+		id := item[:i]
+		key, errDecode := base64.StdEncoding.DecodeString(item[i+1:])
+		if errDecode != nil || len(key) != KeySize {
+			return "", nil, fmt.Errorf("key '%s' must be %d bytes encoded in base64", id, KeySize)
+		} else if _, ok := keys[id]; ok {
+			return "", nil, fmt.Errorf("key '%s' is duplicated", id)
+		}
+		if currentID == "" {
+			currentID = id
+		}
+		keys[id] = key
+	}
+	if currentID == "" {
+		return "", nil, errors.New("no keys")
+	}
+	return
+}
+
+// SetKeys replaces the master keys. The current key wraps the new data keys.
+func SetKeys(id string, keys map[string][]byte) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	currentID, masterKeys = id, keys
+}
+
+// Enabled returns true if the master keys are configured, so the new files are encrypted.
+func Enabled() bool {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return currentID != ""
+}
+
+// NewDataKey returns a new random data key, and the key wrapped by the current master key.
+func NewDataKey() (dataKey []byte, wrapped string, err error) {
+	mutex.RLock()
+	id, masterKey := currentID, masterKeys[currentID]
+	mutex.RUnlock()
+	if id == "" {
+		return nil, "", ErrNotConfigured
+	}
+
+	dataKey = make([]byte, KeySize)
+	if _, err = io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, "", err
+	}
+	aead, err := newGCM(masterKey)
+	if err != nil {
+		return nil, "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, "", err
+	}
+	sealed := aead.Seal(nonce, nonce, dataKey, []byte(Prefix+id))
+	return dataKey, Prefix + id + ":" + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// UnwrapDataKey returns the data key of a wrapped key, unwrapped by the master key of its ID.
+func UnwrapDataKey(wrapped string) ([]byte, error) {
+	if !strings.HasPrefix(wrapped, Prefix) {
+		return nil, ErrInvalidKey
+	}
+	parts := strings.SplitN(strings.TrimPrefix(wrapped, Prefix), ":", 2)
+	if len(parts) != 2 {
+		return nil, ErrInvalidKey
+	}
+	mutex.RLock()
+	masterKey, ok := masterKeys[parts[0]]
+	mutex.RUnlock()
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	sealed, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+	aead, err := newGCM(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, ErrInvalidKey
+	}
+	dataKey, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(Prefix+parts[0]))
+	if err != nil || len(dataKey) != KeySize {
+		return nil, ErrInvalidKey
+	}
+	return dataKey, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,143 @@
+package envelope
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, KeySize)
+}
+
+func TestParseKeys(t *testing.T) {
+	k1, k2 := base64.StdEncoding.EncodeToString(testKey(1)), base64.StdEncoding.EncodeToString(testKey(2))
+	id, keys, err := ParseKeys(" 2026-10:" + k2 + ", 2025-01:" + k1 + ",")
+	if err != nil || id != "2026-10" || len(keys) != 2 || !bytes.Equal(keys["2025-01"], testKey(1)) {
+		t.Errorf("ParseKeys() = %q, %v, %v", id, keys, err)
+	}
+
+	for _, s := range []string{"", k1, "a:" + k1 + ",a:" + k2, "a:" + base64.StdEncoding.EncodeToString([]byte("short")), "a:%%%"} {
+		if _, _, err := ParseKeys(s); err == nil {
+			t.Errorf("ParseKeys(%q) = nil error; expected an error", s)
+		}
+	}
+}
+
+func TestDataKey(t *testing.T) {
+	SetKeys("", nil)
+	if _, _, err := NewDataKey(); err != ErrNotConfigured {
+		t.Errorf("NewDataKey() without keys error = %v; expected %v", err, ErrNotConfigured)
+	}
+
+	SetKeys("old", map[string][]byte{"old": testKey(1)})
+	dataKey, wrapped, err := NewDataKey()
+	if err != nil || len(dataKey) != KeySize || !strings.HasPrefix(wrapped, Prefix+"old:") {
+		t.Fatalf("NewDataKey() = %x, %q, %v", dataKey, wrapped, err)
+	}
+
+	// After a rotation, the old key still unwraps the data keys it wrapped.
+	SetKeys("new", map[string][]byte{"new": testKey(2), "old": testKey(1)})
+	if res, err := UnwrapDataKey(wrapped); err != nil || !bytes.Equal(res, dataKey) {
+		t.Errorf("UnwrapDataKey() = %x, %v; expected %x", res, err, dataKey)
+	}
+	if _, wrapped, _ := NewDataKey(); !strings.HasPrefix(wrapped, Prefix+"new:") {
+		t.Errorf("NewDataKey() = %q; expected wrapped by the new key", wrapped)
+	}
+
+	// The key ID is authenticated, so a wrapped key can't be moved to another master key.
+	moved := strings.Replace(wrapped, Prefix+"old:", Prefix+"new:", 1)
+	var tests = []struct {
+		wrapped  string
+		expected error
+	}{
+		{moved, ErrInvalidKey},
+		{Prefix + "gone:" + strings.SplitN(wrapped, ":", 3)[2], ErrUnknownKey},
+		{"plain", ErrInvalidKey},
+		{Prefix + "old", ErrInvalidKey},
+		{Prefix + "old:AAAA", ErrInvalidKey},
+	}
+	for _, test := range tests {
+		if _, err := UnwrapDataKey(test.wrapped); err != test.expected {
+			t.Errorf("UnwrapDataKey(%q) error = %v; expected %v", test.wrapped, err, test.expected)
+		}
+	}
+	SetKeys("", nil)
+}
+
+func encrypt(t *testing.T, plain, dataKey []byte) []byte {
+	r, err := NewEncryptReader(bytes.NewReader(plain), dataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sealed
+}
+
+func decrypt(sealed, dataKey []byte) ([]byte, error) {
+	r, err := NewDecryptReader(bytes.NewReader(sealed), dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(r)
+}
+
+func TestStream(t *testing.T) {
+	dataKey := testKey(7)
+	for _, size := range []int{0, 1, ChunkSize - 1, ChunkSize, ChunkSize + 1, 3*ChunkSize + 100} {
+		plain := make([]byte, size)
+		io.ReadFull(rand.Reader, plain)
+		sealed := encrypt(t, plain, dataKey)
+		chunks := size/ChunkSize + 1
+		if size > 0 && size%ChunkSize == 0 {
+			chunks--
+		}
+		if expected := headerSize + size + chunks*16; len(sealed) != expected {
+			t.Errorf("size of %d bytes encrypted = %d; expected %d", size, len(sealed), expected)
+		}
+		if res, err := decrypt(sealed, dataKey); err != nil || !bytes.Equal(res, plain) {
+			t.Errorf("decrypt %d bytes = %d bytes, %v", size, len(res), err)
+		}
+	}
+}
+
+func TestStreamCorrupted(t *testing.T) {
+	dataKey := testKey(7)
+	plain := bytes.Repeat([]byte("x"), 2*ChunkSize+10)
+	sealed := encrypt(t, plain, dataKey)
+	chunk := ChunkSize + 16
+
+	flipped := append([]byte{}, sealed...)
+	flipped[headerSize+chunk+5] ^= 1
+	swapped := append([]byte{}, sealed[:headerSize]...)
+	swapped = append(swapped, sealed[headerSize+chunk:headerSize+2*chunk]...)
+	swapped = append(swapped, sealed[headerSize:headerSize+chunk]...)
+	swapped = append(swapped, sealed[headerSize+2*chunk:]...)
+
+	var tests = []struct {
+		name   string
+		sealed []byte
+		key    []byte
+	}{
+		{"wrong key", sealed, testKey(8)},
+		{"modified", flipped, dataKey},
+		{"reordered", swapped, dataKey},
+		{"truncated at a chunk", sealed[:headerSize+2*chunk], dataKey},
+		{"truncated in a chunk", sealed[:len(sealed)-1], dataKey},
+		{"only header", sealed[:headerSize], dataKey},
+		{"no header", sealed[:3], dataKey},
+		{"appended", append(append([]byte{}, sealed...), 0), dataKey},
+	}
+	for _, test := range tests {
+		if _, err := decrypt(test.sealed, test.key); err != ErrCorrupted {
+			t.Errorf("decrypt %s error = %v; expected %v", test.name, err, ErrCorrupted)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package envelope
+
+import (
+	"bufio"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// The encrypted stream is a header of the magic and a random nonce prefix, followed by the chunks of up to
+// ChunkSize bytes, each sealed with AES-256-GCM. A chunk's nonce is the prefix, the chunk's index and a flag
+// of the last chunk, so the chunks can't be reordered, and a truncated stream fails to decrypt.
+// The header is the chunks' additional data.
+
+// ChunkSize is the size of the plaintext of every chunk but the last one.
+const ChunkSize = 64 * 1024
+
+const (
+	noncePrefixSize = 7
+	headerSize      = len(streamMagic) + noncePrefixSize
+)
+
+const streamMagic = "BGE1"
+
+// ErrCorrupted is returned when an encrypted stream fails to decrypt, e.g. it's truncated or modified.
+var ErrCorrupted = errors.New("Encrypted file is corrupted")
+
+type chunkStream struct {
+	src    *bufio.Reader
+	aead   cipher.AEAD
+	header []byte
+	index  uint32
+	chunk  []byte
+	out    []byte
+	done   bool
+	err    error
+}
+
+func (s *chunkStream) nonce(last bool) []byte {
+	nonce := make([]byte, 0, s.aead.NonceSize())
+	nonce = append(nonce, s.header[len(streamMagic):]...)
+	nonce = append(nonce, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(nonce[noncePrefixSize:], s.index)
+	if last {
+		return append(nonce, 1)
+	}
+	return append(nonce, 0)
+}
+
+// readChunk reads a chunk of up to size bytes, and returns true if it's the last one.
+func (s *chunkStream) readChunk(size int) (n int, last bool, err error) {
+	n, err = io.ReadFull(s.src, s.chunk[:size])
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return n, true, nil
+	} else if err != nil {
+		return n, false, err
+	}
+	if _, err = s.src.Peek(1); err == io.EOF {
+		return n, true, nil
+	}
+	return n, false, err
+}
+
+// read copies the pending output, then calls next to produce more until the stream is done.
+func (s *chunkStream) read(p []byte, next func() error) (int, error) {
+	for len(s.out) == 0 {
+		if s.err != nil {
+			return 0, s.err
+		} else if s.done {
+			return 0, io.EOF
+		}
+		if s.err = next(); s.err != nil && len(s.out) == 0 {
+			return 0, s.err
+		}
+	}
+	n := copy(p, s.out)
+	s.out = s.out[n:]
+	return n, nil
+}
+
+type encryptReader struct {
+	chunkStream
+	sealed []byte
+}
+
+// NewEncryptReader returns a reader of the stream encrypting src with a data key.
+func NewEncryptReader(src io.Reader, dataKey []byte) (io.Reader, error) {
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, headerSize)
+	copy(header, streamMagic)
+	if _, err = io.ReadFull(rand.Reader, header[len(streamMagic):]); err != nil {
+		return nil, err
+	}
+	r := &encryptReader{
+		chunkStream: chunkStream{
+			src:    bufio.NewReader(src),
+			aead:   aead,
+			header: header,
+			chunk:  make([]byte, ChunkSize),
+		},
+		sealed: make([]byte, 0, ChunkSize+aead.Overhead()),
+	}
+	r.out = header
+	return r, nil
+}
+
+func (r *encryptReader) Read(p []byte) (int, error) {
+	return r.read(p, func() error {
+		n, last, err := r.readChunk(ChunkSize)
+		if err != nil {
+			return err
+		}
+		r.sealed = r.aead.Seal(r.sealed[:0], r.nonce(last), r.chunk[:n], r.header)
+		r.out = r.sealed
+		if r.index++; last {
+			r.done = true
+		} else if r.index == 0 {
+			return errors.New("Encrypted file is too large")
+		}
+		return nil
+	})
+}
+
+type decryptReader struct {
+	chunkStream
+	opened []byte
+}
+
+// NewDecryptReader returns a reader of the stream decrypting src, which is encrypted with a data key.
+func NewDecryptReader(src io.Reader, dataKey []byte) (io.Reader, error) {
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, headerSize)
+	if _, err = io.ReadFull(src, header); err != nil || string(header[:len(streamMagic)]) != streamMagic {
+		return nil, ErrCorrupted
+	}
+	return &decryptReader{
+		chunkStream: chunkStream{
+			src:    bufio.NewReader(src),
+			aead:   aead,
+			header: header,
+			chunk:  make([]byte, ChunkSize+aead.Overhead()),
+		},
+		opened: make([]byte, 0, ChunkSize),
+	}, nil
+}
+
+func (r *decryptReader) Read(p []byte) (int, error) {
+	return r.read(p, func() error {
+		n, last, err := r.readChunk(ChunkSize + r.aead.Overhead())
+		if err != nil {
+			return err
+		}
+		if r.opened, err = r.aead.Open(r.opened[:0], r.nonce(last), r.chunk[:n], r.header); err != nil {
+			return ErrCorrupted
+		}
+		r.out = r.opened
+		r.index++
+		r.done = last
+		return nil
+	})
+}
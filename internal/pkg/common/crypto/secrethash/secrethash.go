@@ -0,0 +1,113 @@
+// Package secrethash creates the keyed hashes of secrets saved to database and Redis, e.g. OTP codes and session tokens,
+// so a dump of either doesn't leak usable credentials.
+//
+// A hash is "h1:<hex of HMAC-SHA256(key, purpose + ":" + secret)>". The purpose keeps a secret's hash from matching
+// where another kind of secret is expected.
+package secrethash
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
+	"github.com/jonylim/basego/internal/pkg/common/helper"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+)
+
+// Prefix is the prefix of the hashes, telling them from the plaintext saved before the secrets were hashed.
+const Prefix = "h1:"
+
+// MinKeySize is the minimum size of the key in bytes.
+const MinKeySize = 32
+
+var (
+	current  []byte
+	isRandom bool
+	mutex    sync.RWMutex
+)
+
+// Init loads the key from environment variables. The app exits if it's not configured, as every instance must hash
+// with the same key, except in a local environment, where a random key is generated, so the saved secrets only match
+// until the app restarts.
+func Init() {
+	if s := os.Getenv(envvar.SecretHash.Key); s != "" {
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+		if err == nil && len(key) >= MinKeySize {
+			SetKey(key)
+			logger.Println("secrethash", "Key is configured")
+			return
+		}
+		logger.Println("secrethash", fmt.Sprintf("WARN: %s must be at least %d bytes encoded in base64", envvar.SecretHash.Key, MinKeySize))
+	}
+	if !helper.IsLocalEnvironment() {
+		logger.Println("secrethash", fmt.Sprintf("ERROR: %s is required outside a local environment", envvar.SecretHash.Key))
+		os.Exit(1)
+	}
+	key := make([]byte, MinKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		logger.Fatal("secrethash", logger.FromError(err))
+		return
+	}
+	logger.Println("secrethash", fmt.Sprintf("WARN: %s is empty, using a random key until the app restarts", envvar.SecretHash.Key))
+	setKey(key, true)
+}
+
+// SetKey replaces the key.
+func SetKey(key []byte) {
+	setKey(key, false)
+}
+
+func setKey(key []byte, random bool) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	current, isRandom = key, random
+}
+
+// IsConfigured checks if the key is set and not the random key of Init, which only this process knows.
+// The hashes saved by a command must be made with the configured key to match on the servers.
+func IsConfigured() bool {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return len(current) != 0 && !isRandom
+}
+
+func getKey() []byte {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return current
+}
+
+// Sum returns the hash of a secret for a purpose.
+func Sum(purpose, secret string) string {
+	mac := hmac.New(sha256.New, getKey())
+	mac.Write([]byte(purpose + ":" + secret))
+	return Prefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// IsHashed checks if a saved value is a hash, not the plaintext saved before the secrets were hashed.
+func IsHashed(saved string) bool {
+	return strings.HasPrefix(saved, Prefix)
+}
+
+// Matches compares a secret with its saved value in constant time.
+// A saved value without the prefix is the plaintext of a row not migrated yet, which is compared as is.
+// An empty saved value never matches.
+func Matches(saved, purpose, secret string) bool {
+	if saved == "" {
+		return false
+	}
+	expected := secret
+	if IsHashed(saved) {
+		expected = Sum(purpose, secret)
+	}
+	return subtle.ConstantTimeCompare([]byte(saved), []byte(expected)) == 1
+}
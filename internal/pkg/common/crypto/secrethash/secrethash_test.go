@@ -0,0 +1,50 @@
+package secrethash
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSum(t *testing.T) {
+	SetKey(bytes.Repeat([]byte{1}, MinKeySize))
+	h := Sum("otp", "123456")
+	if !strings.HasPrefix(h, Prefix) || len(h) != len(Prefix)+64 {
+		t.Errorf("Sum(otp, 123456) = %s; expected %s followed by 64 hex characters", h, Prefix)
+	}
+	if h2 := Sum("otp", "123456"); h2 != h {
+		t.Errorf("Sum(otp, 123456) = %s then %s; expected the same hash", h, h2)
+	}
+	if h2 := Sum("accessToken", "123456"); h2 == h {
+		t.Errorf("Sum(accessToken, 123456) = %s; expected a different hash from purpose otp", h2)
+	}
+	SetKey(bytes.Repeat([]byte{2}, MinKeySize))
+	if h2 := Sum("otp", "123456"); h2 == h {
+		t.Errorf("Sum(otp, 123456) = %s with another key; expected a different hash", h2)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	SetKey(bytes.Repeat([]byte{1}, MinKeySize))
+	hashed := Sum("otp", "123456")
+	var tests = []struct {
+		saved    string
+		purpose  string
+		secret   string
+		expected bool
+	}{
+		{hashed, "otp", "123456", true},
+		{hashed, "otp", "654321", false},
+		{hashed, "accessToken", "123456", false},
+		{hashed, "otp", hashed, false},
+		{"123456", "otp", "123456", true},
+		{"123456", "otp", "654321", false},
+		{"", "otp", "", false},
+		{hashed, "otp", "", false},
+	}
+	for _, test := range tests {
+		if res := Matches(test.saved, test.purpose, test.secret); res != test.expected {
+			t.Errorf("Matches(%q, %q, %q) = %v; expected %v", test.saved, test.purpose, test.secret, res, test.expected)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package redis
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// unlockScript deletes a lock only if it's still held by the token, so an expired lock taken by another holder is kept.
+var unlockScript = redis.NewScript(1, `if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// TryLock acquires a lock shared by all instances, expiring after the TTL in case the holder stops.
+// The token must be passed to Unlock, it's empty if the lock is held by someone else.
+func TryLock(conn redis.Conn, key string, ttl time.Duration) (token string, err error) {
+	b := make([]byte, 16)
+	if _, err = rand.Read(b); err != nil {
+		return "", err
+	}
+	token = hex.EncodeToString(b)
+	_, err = redis.String(conn.Do("SET", key, token, "NX", "PX", int64(ttl/time.Millisecond)))
+	if err == redis.ErrNil {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Unlock releases a lock acquired by TryLock.
+func Unlock(conn redis.Conn, key, token string) error {
+	_, err := unlockScript.Do(conn, key, token)
+	return err
+}
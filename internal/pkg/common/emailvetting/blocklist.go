@@ -0,0 +1,45 @@
+package emailvetting
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// LoadBlocklist reads the disposable domains from a file with a domain per line.
+// Empty lines and lines starting with "#" are ignored.
+func LoadBlocklist(path string) (map[string]bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	blocklist := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		blocklist[Normalize("@" + line)[1:]] = true
+	}
+	return blocklist, scanner.Err()
+}
+
+// IsDisposable checks if a domain or one of its parent domains is in the blocklist.
+func (c Config) IsDisposable(domain string) bool {
+	if len(c.Blocklist) == 0 {
+		return false
+	}
+	for {
+		if c.Blocklist[domain] {
+			return true
+		}
+		i := strings.IndexByte(domain, '.')
+		if i < 0 {
+			return false
+		}
+		domain = domain[i+1:]
+	}
+}
@@ -0,0 +1,202 @@
+// Package emailvetting checks the quality of new email addresses beyond their format,
+// e.g. disposable domains, domains which can't receive emails, and typos of common providers.
+package emailvetting
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
+	"github.com/jonylim/basego/internal/pkg/common/helper"
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+
+	"golang.org/x/net/idna"
+)
+
+// Defines reasons of rejected email addresses.
+const (
+	ReasonFormat        = "format"
+	ReasonTypo          = "typo"
+	ReasonDisposable    = "disposable"
+	ReasonDomainInvalid = "domainInvalid"
+)
+
+// Resolver looks up the DNS records of email domains. *net.Resolver implements it.
+type Resolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// Config defines the checks of new email addresses.
+type Config struct {
+	// Blocklist contains the disposable domains, which also block their subdomains.
+	Blocklist map[string]bool
+
+	// SuggestTypos rejects the domains one typo away from a common provider, suggesting the provider,
+	// unless the user confirms the address.
+	SuggestTypos bool
+
+	// Resolver checks if a domain has MX or A/AAAA records, nil skips the check.
+	Resolver   Resolver
+	DNSTimeout time.Duration
+}
+
+// Rejection describes why an email address is rejected.
+type Rejection struct {
+	Reason     string `json:"reason"`
+	Suggestion string `json:"suggestion,omitempty"`
+	Message    string `json:"message"`
+
+	msg i18n.Message
+}
+
+// Msg returns the rejection's message.
+func (r *Rejection) Msg() i18n.Message {
+	return r.msg
+}
+
+// Localize returns a copy of the rejection with the message in a locale.
+func (r Rejection) Localize(locale string) Rejection {
+	r.Message = r.msg.Localize(locale)
+	return r
+}
+
+func newRejection(reason, suggestion string, msg i18n.Message) *Rejection {
+	return &Rejection{reason, suggestion, msg.String(), msg}
+}
+
+var (
+	current = Config{SuggestTypos: true, DNSTimeout: 3 * time.Second}
+	mutex   sync.RWMutex
+)
+
+// Init loads the configuration from environment variables.
+func Init() {
+	c := Config{SuggestTypos: true, DNSTimeout: 3 * time.Second}
+	if path := os.Getenv(envvar.EmailVetting.DisposableListPath); path != "" {
+		blocklist, err := LoadBlocklist(path)
+		if err != nil {
+			logger.Println("emailvetting", fmt.Sprintf("WARN: Failed to load the disposable domains, the check is disabled: %v", err))
+		} else {
+			c.Blocklist = blocklist
+		}
+	} else {
+		logger.Println("emailvetting", fmt.Sprintf("WARN: %s is empty, disposable domain check is disabled", envvar.EmailVetting.DisposableListPath))
+	}
+	c.SuggestTypos = getEnvBool(envvar.EmailVetting.SuggestTypos, c.SuggestTypos)
+	if getEnvBool(envvar.EmailVetting.CheckDNS, false) {
+		c.Resolver = net.DefaultResolver
+	}
+	logger.Println("emailvetting", fmt.Sprintf("DisposableDomains = %d, SuggestTypos = %v, CheckDNS = %v",
+		len(c.Blocklist), c.SuggestTypos, c.Resolver != nil))
+	Set(c)
+}
+
+// Get returns the active configuration.
+func Get() Config {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return current
+}
+
+// Set replaces the active configuration.
+func Set(c Config) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	current = c
+}
+
+// Normalize returns the form of an email address used to save it and to check its uniqueness:
+// lowercase, and with an internationalized domain in punycode.
+// If the domain can't be converted, it's only lowercased, so the format validation rejects it.
+func Normalize(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	i := strings.LastIndexByte(email, '@')
+	if i < 0 {
+		return email
+	}
+	domain, err := idna.Lookup.ToASCII(strings.TrimSuffix(email[i+1:], "."))
+	if err != nil {
+		return email
+	}
+	return email[:i+1] + domain
+}
+
+// Vet normalizes a new email address and checks it. The rejection is nil if the address is accepted.
+// typoConfirmed accepts a domain suggested as a typo, after the user is shown the suggestion and keeps the address,
+// as the real domains may be one edit away from a provider too, e.g. "yahoo.co.in".
+func (c Config) Vet(ctx context.Context, email string, typoConfirmed bool) (string, *Rejection) {
+	email = Normalize(email)
+	if err := helper.ValidateEmailFormat(email); err != nil {
+		return email, newRejection(ReasonFormat, "", i18n.FromError(err))
+	}
+	i := strings.LastIndexByte(email, '@')
+	local, domain := email[:i], email[i+1:]
+
+	if c.SuggestTypos && !typoConfirmed {
+		if provider := SuggestProvider(domain); provider != "" {
+			suggestion := local + "@" + provider
+			return email, newRejection(ReasonTypo, suggestion, i18n.NewMessageWithParams(i18n.MsgEmailTypo, i18n.Params{"suggestion": suggestion}))
+		}
+	}
+	if c.IsDisposable(domain) {
+		return email, newRejection(ReasonDisposable, "", i18n.NewMessage(i18n.MsgEmailDisposable))
+	}
+	if c.Resolver != nil && !c.canReceive(ctx, domain) {
+		return email, newRejection(ReasonDomainInvalid, "", i18n.NewMessage(i18n.MsgEmailDomainInvalid))
+	}
+	return email, nil
+}
+
+// canReceive checks if a domain has MX records, or A/AAAA records used when there is no MX record.
+// Lookup failures other than a missing domain don't reject the address.
+func (c Config) canReceive(ctx context.Context, domain string) bool {
+	if c.DNSTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.DNSTimeout)
+		defer cancel()
+	}
+	mxs, err := c.Resolver.LookupMX(ctx, domain)
+	if err == nil && len(mxs) != 0 {
+		// A null MX record ("." with preference 0) means the domain doesn't accept emails.
+		return !(len(mxs) == 1 && mxs[0].Host == ".")
+	}
+	if err != nil && !isNotFound(err) {
+		logger.Error("emailvetting", logger.FromError(err))
+		return true
+	}
+	hosts, err := c.Resolver.LookupHost(ctx, domain)
+	if err != nil {
+		if isNotFound(err) {
+			return false
+		}
+		logger.Error("emailvetting", logger.FromError(err))
+		return true
+	}
+	return len(hosts) != 0
+}
+
+func isNotFound(err error) bool {
+	dnsErr, ok := err.(*net.DNSError)
+	return ok && dnsErr.IsNotFound
+}
+
+func getEnvBool(key string, def bool) bool {
+	s := os.Getenv(key)
+	if s == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(strings.TrimSpace(s))
+	if err != nil {
+		logger.Println("emailvetting", fmt.Sprintf("WARN: %s '%s' is invalid, set to '%v' as default", key, s, def))
+		return def
+	}
+	return b
+}
@@ -0,0 +1,158 @@
+package emailvetting
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// fakeResolver answers from maps, a domain missing from both is not found.
+type fakeResolver struct {
+	mx    map[string][]*net.MX
+	hosts map[string][]string
+	err   error
+}
+
+func (r fakeResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if mxs, ok := r.mx[name]; ok {
+		return mxs, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (r fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if hosts, ok := r.hosts[host]; ok {
+		return hosts, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func TestNormalize(t *testing.T) {
+	var tests = []struct {
+		email    string
+		expected string
+	}{
+		{"John@Example.COM", "john@example.com"},
+		{" john@example.com. ", "john@example.com"},
+		{"john@bücher.de", "john@xn--bcher-kva.de"},
+		{"JOHN@BÜCHER.DE", "john@xn--bcher-kva.de"},
+		{"john", "john"},
+		{"", ""},
+	}
+	for _, test := range tests {
+		if res := Normalize(test.email); res != test.expected {
+			t.Errorf("Normalize(%q) = %q; expected %q", test.email, res, test.expected)
+		}
+	}
+}
+
+func TestSuggestProvider(t *testing.T) {
+	var tests = []struct {
+		domain   string
+		expected string
+	}{
+		{"gmial.com", "gmail.com"},
+		{"gmail.co", "gmail.com"},
+		{"gmaill.com", "gmail.com"},
+		{"hotmial.com", "hotmail.com"},
+		{"yaho.com", "yahoo.com"},
+		{"gmail.com", ""},
+		{"email.com", ""},
+		{"ge.com", ""},
+		{"ms.com", ""},
+		{"aon.com", ""},
+		{"example.com", ""},
+		{"gmali.cmo", ""},
+	}
+	for _, test := range tests {
+		if res := SuggestProvider(test.domain); res != test.expected {
+			t.Errorf("SuggestProvider(%q) = %q; expected %q", test.domain, res, test.expected)
+		}
+	}
+}
+
+func TestLoadBlocklist(t *testing.T) {
+	dir, err := ioutil.TempDir("", "emailvetting")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "disposable.txt")
+	if err = ioutil.WriteFile(path, []byte("# Disposable domains\nMailinator.com\n\n  trashmail.de  \n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	blocklist, err := LoadBlocklist(path)
+	if err != nil {
+		t.Fatalf("LoadBlocklist returns error %v", err)
+	}
+	if expected := map[string]bool{"mailinator.com": true, "trashmail.de": true}; !reflect.DeepEqual(blocklist, expected) {
+		t.Errorf("LoadBlocklist = %v; expected %v", blocklist, expected)
+	}
+}
+
+func TestVet(t *testing.T) {
+	c := Config{
+		Blocklist:    map[string]bool{"trashmail.de": true},
+		SuggestTypos: true,
+		Resolver: fakeResolver{
+			mx: map[string][]*net.MX{
+				"example.com":      {{Host: "mx.example.com.", Pref: 10}},
+				"gmail.com":        {{Host: "gmail-smtp-in.l.google.com.", Pref: 5}},
+				"nomail.example":   {{Host: ".", Pref: 0}},
+				"xn--bcher-kva.de": {{Host: "mx.xn--bcher-kva.de.", Pref: 10}},
+			},
+			hosts: map[string][]string{"a-only.example": {"192.0.2.1"}},
+		},
+	}
+	var tests = []struct {
+		email      string
+		normalized string
+		reason     string
+		suggestion string
+	}{
+		{"John@Example.com", "john@example.com", "", ""},
+		{"john@bücher.de", "john@xn--bcher-kva.de", "", ""},
+		{"john@a-only.example", "john@a-only.example", "", ""},
+		{"john@", "john@", ReasonFormat, ""},
+		{"John@gmial.com", "john@gmial.com", ReasonTypo, "john@gmail.com"},
+		{"john@trashmail.de", "john@trashmail.de", ReasonDisposable, ""},
+		{"john@mx.trashmail.de", "john@mx.trashmail.de", ReasonDisposable, ""},
+		{"john@nomail.example", "john@nomail.example", ReasonDomainInvalid, ""},
+		{"john@unknown.example", "john@unknown.example", ReasonDomainInvalid, ""},
+	}
+	for _, test := range tests {
+		email, rejection := c.Vet(context.Background(), test.email, false)
+		var reason, suggestion string
+		if rejection != nil {
+			reason, suggestion = rejection.Reason, rejection.Suggestion
+		}
+		if email != test.normalized || reason != test.reason || suggestion != test.suggestion {
+			t.Errorf("Vet(%q) = %q, %q, %q; expected %q, %q, %q",
+				test.email, email, reason, suggestion, test.normalized, test.reason, test.suggestion)
+		}
+	}
+
+	// The addresses aren't rejected if the DNS lookup fails.
+	c.Resolver = fakeResolver{err: &net.DNSError{Err: "timeout", IsTimeout: true}}
+	if _, rejection := c.Vet(context.Background(), "john@unknown.example", false); rejection != nil {
+		t.Errorf("Vet with failing DNS = %+v; expected nil", rejection)
+	}
+
+	// The user keeps an address after its typo rejection.
+	if _, rejection := c.Vet(context.Background(), "john@yahoo.co.in", false); rejection == nil || rejection.Reason != ReasonTypo {
+		t.Errorf("Vet(john@yahoo.co.in) = %+v; expected a typo rejection", rejection)
+	}
+	if _, rejection := c.Vet(context.Background(), "john@yahoo.co.in", true); rejection != nil {
+		t.Errorf("Vet(john@yahoo.co.in) confirmed = %+v; expected nil", rejection)
+	}
+}
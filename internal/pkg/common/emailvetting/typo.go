@@ -0,0 +1,68 @@
+package emailvetting
+
+import "strings"
+
+// commonProviders are the domains of the common email providers, suggested for their typos.
+var commonProviders = []string{
+	"gmail.com", "googlemail.com",
+	"yahoo.com", "yahoo.co.id", "ymail.com",
+	"hotmail.com", "outlook.com", "live.com", "msn.com",
+	"icloud.com", "me.com",
+	"aol.com", "mail.com", "email.com", "gmx.com",
+	"protonmail.com", "proton.me", "yandex.com", "zoho.com",
+}
+
+// minProviderNameLength is the length of the shortest provider name, the domain's first label, whose typos are
+// suggested. The shorter names, e.g. "me.com" and "aol.com", are one edit away from too many real domains.
+const minProviderNameLength = 5
+
+// SuggestProvider returns the common provider a domain is a typo of, i.e. one insertion, deletion, substitution,
+// or transposition of adjacent characters away, or an empty string.
+func SuggestProvider(domain string) string {
+	for _, p := range commonProviders {
+		if domain == p {
+			return ""
+		}
+	}
+	for _, p := range commonProviders {
+		if strings.IndexByte(p, '.') >= minProviderNameLength && isOneEditAway(domain, p) {
+			return p
+		}
+	}
+	return ""
+}
+
+// isOneEditAway checks if the optimal string alignment distance of two different ASCII strings is 1.
+func isOneEditAway(a, b string) bool {
+	la, lb := len(a), len(b)
+	switch {
+	case la == lb:
+		diff := -1
+		for i := 0; i < la; i++ {
+			if a[i] == b[i] {
+				continue
+			}
+			if diff >= 0 {
+				// A second difference is only allowed as the swap of the first one.
+				return i == diff+1 && a[diff] == b[i] && a[i] == b[diff] && a[i+1:] == b[i+1:]
+			}
+			diff = i
+		}
+		return diff >= 0
+	case la == lb+1:
+		return isOneInsertionAway(b, a)
+	case lb == la+1:
+		return isOneInsertionAway(a, b)
+	}
+	return false
+}
+
+// isOneInsertionAway checks if the longer string is the shorter one with a character inserted.
+func isOneInsertionAway(shorter, longer string) bool {
+	for i := 0; i < len(shorter); i++ {
+		if shorter[i] != longer[i] {
+			return shorter[i:] == longer[i+1:]
+		}
+	}
+	return true
+}
@@ -2,10 +2,11 @@ package helper
 
 import (
 	"errors"
-	"fmt"
 	"math"
 	"regexp"
 	"strings"
+
+	"github.com/jonylim/basego/internal/pkg/common/i18n"
 )
 
 // Defines phone number's min & max length.
@@ -23,16 +24,16 @@ const (
 // ValidatePhoneFormat checks if a phone number's format is valid.
 func ValidatePhoneFormat(countryCallingCode, phone string) error {
 	if phone == "" {
-		return errors.New("Phone number is empty")
+		return i18n.NewError(i18n.MsgPhoneEmpty)
 	} else if !IsNumericString(phone) {
-		return errors.New("Phone number must be numeric")
+		return i18n.NewError(i18n.MsgPhoneNotNumeric)
 	} else if strings.HasPrefix(phone, "0") {
-		return errors.New("Phone number can't start with '0'")
+		return i18n.NewError(i18n.MsgPhoneLeadingZero)
 	}
 	lenCC, lenPhone := len(countryCallingCode), len(phone)
 	min, max := PhoneMinLength-lenCC, PhoneMaxLength-lenCC
 	if lenPhone < min || lenPhone > max {
-		return fmt.Errorf("Phone number's length must be %d-%d digits", min, max)
+		return i18n.NewErrorWithParams(i18n.MsgPhoneLengthInvalid, i18n.Params{"min": min, "max": max})
 	}
 	return nil
 }
@@ -40,25 +41,25 @@ func ValidatePhoneFormat(countryCallingCode, phone string) error {
 // ValidateEmailFormat checks if an email's format is valid.
 func ValidateEmailFormat(email string) error {
 	if email == "" {
-		return errors.New("Email address is empty")
+		return i18n.NewError(i18n.MsgEmailEmpty)
 	}
 	re := regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
 	if re.MatchString(email) {
 		// TODO: Temporary. Check the domain.
 		if strings.HasSuffix(strings.ToLower(email), "@mailinator.com") {
-			return errors.New("Email address is invalid")
+			return i18n.NewError(i18n.MsgEmailInvalid)
 		}
 		return nil
 	}
-	return errors.New("Email address format is invalid")
+	return i18n.NewError(i18n.MsgEmailFormatInvalid)
 }
 
 // ValidatePasswordFormat checks if a password's format is valid.
 func ValidatePasswordFormat(password string) error {
 	if password == "" {
-		return errors.New("Password is empty")
+		return i18n.NewError(i18n.MsgPasswordEmpty)
 	} else if l := len(password); l < PasswordMinLength || l > PasswordMaxLength {
-		return fmt.Errorf("Password's length must be %d-%d characters", PasswordMinLength, PasswordMaxLength)
+		return i18n.NewErrorWithParams(i18n.MsgPasswordLength, i18n.Params{"min": PasswordMinLength, "max": PasswordMaxLength})
 	}
 	countUpper, countLower, countNumber, countSpecial := 0, 0, 0, 0
 	for _, c := range password {
@@ -73,7 +74,7 @@ func ValidatePasswordFormat(password string) error {
 		}
 	}
 	if countUpper == 0 || countLower == 0 || countNumber == 0 || countSpecial == 0 {
-		return errors.New("Password must contain at least 1 lowercase, uppercase, and special characters and 1 number")
+		return i18n.NewError(i18n.MsgPasswordFormat)
 	}
 	return nil
 }
@@ -0,0 +1,24 @@
+package helper
+
+import (
+	"os"
+	"strings"
+
+	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
+)
+
+// localEnvironments are the values of BASEGO_ENV of the environments run by a single developer,
+// where the configurations shared by the instances, e.g. the keys, may be left empty.
+var localEnvironments = []string{"local", "dev", "development", "test"}
+
+// IsLocalEnvironment checks if the app runs in a local or development environment.
+// An unset environment isn't, so a deployment missing BASEGO_ENV gets the production checks.
+func IsLocalEnvironment() bool {
+	env := strings.ToLower(strings.TrimSpace(os.Getenv(envvar.Environment)))
+	for _, s := range localEnvironments {
+		if env == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package helper
+
+import (
+	"os"
+	"testing"
+
+	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
+)
+
+func TestIsLocalEnvironment(t *testing.T) {
+	defer os.Unsetenv(envvar.Environment)
+	var tests = []struct {
+		env      string
+		expected bool
+	}{
+		{"local", true},
+		{" Development ", true},
+		{"test", true},
+		{"staging", false},
+		{"production", false},
+		{"", false},
+	}
+	for _, test := range tests {
+		os.Setenv(envvar.Environment, test.env)
+		if res := IsLocalEnvironment(); res != test.expected {
+			t.Errorf("IsLocalEnvironment() with %q = %v; expected %v", test.env, res, test.expected)
+		}
+	}
+}
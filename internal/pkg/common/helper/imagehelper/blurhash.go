@@ -0,0 +1,117 @@
+package imagehelper
+
+import (
+	"errors"
+	"image"
+	"math"
+)
+
+// Blurhash components, the detail of the placeholders.
+const (
+	BlurhashXComponents = 4
+	BlurhashYComponents = 3
+)
+
+// blurhashMaxSize is the size the images are scaled down to before encoding, as the placeholders are blurry anyway.
+const blurhashMaxSize = 32
+
+const base83Chars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// EncodeBlurhash returns the blurhash of an image, a short string which clients decode to a blurred placeholder
+// while the image is loading. See https://blurha.sh.
+func EncodeBlurhash(img image.Image, xComponents, yComponents int) (string, error) {
+	if xComponents < 1 || xComponents > 9 || yComponents < 1 || yComponents > 9 {
+		return "", errors.New("Blurhash components must be from 1 to 9")
+	}
+	img = Resize(img, blurhashMaxSize, blurhashMaxSize, ModeFit)
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w == 0 || h == 0 {
+		return "", errors.New("Image is empty")
+	}
+
+	// Convert the pixels to linear RGB once.
+	pixels := make([][3]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			r, g, bl, _ := img.At(b.Min.X+x, b.Min.Y+y).RGBA()
+			pixels[y*w+x] = [3]float64{sRGBToLinear(r >> 8), sRGBToLinear(g >> 8), sRGBToLinear(bl >> 8)}
+		}
+	}
+
+	factors := make([][3]float64, 0, xComponents*yComponents)
+	for j := 0; j < yComponents; j++ {
+		for i := 0; i < xComponents; i++ {
+			normalization := 2.0
+			if i == 0 && j == 0 {
+				normalization = 1
+			}
+			var factor [3]float64
+			for y := 0; y < h; y++ {
+				for x := 0; x < w; x++ {
+					basis := normalization * math.Cos(math.Pi*float64(i*x)/float64(w)) * math.Cos(math.Pi*float64(j*y)/float64(h))
+					for c := range factor {
+						factor[c] += basis * pixels[y*w+x][c]
+					}
+				}
+			}
+			for c := range factor {
+				factor[c] /= float64(w * h)
+			}
+			factors = append(factors, factor)
+		}
+	}
+
+	hash := encodeBase83((xComponents-1)+(yComponents-1)*9, 1)
+	maxValue := 1.0
+	if ac := factors[1:]; len(ac) > 0 {
+		actualMax := 0.0
+		for _, f := range ac {
+			actualMax = math.Max(actualMax, math.Max(math.Abs(f[0]), math.Max(math.Abs(f[1]), math.Abs(f[2]))))
+		}
+		quantizedMax := int(math.Max(0, math.Min(82, math.Floor(actualMax*166-0.5))))
+		maxValue = float64(quantizedMax+1) / 166
+		hash += encodeBase83(quantizedMax, 1)
+	} else {
+		hash += encodeBase83(0, 1)
+	}
+
+	dc := factors[0]
+	hash += encodeBase83(linearToSRGB(dc[0])<<16+linearToSRGB(dc[1])<<8+linearToSRGB(dc[2]), 4)
+	for _, f := range factors[1:] {
+		quantize := func(v float64) int {
+			return int(math.Max(0, math.Min(18, math.Floor(signPow(v/maxValue, 0.5)*9+9.5))))
+		}
+		hash += encodeBase83(quantize(f[0])*19*19+quantize(f[1])*19+quantize(f[2]), 2)
+	}
+	return hash, nil
+}
+
+func encodeBase83(value, length int) string {
+	res := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		res[i] = base83Chars[value%83]
+		value /= 83
+	}
+	return string(res)
+}
+
+func sRGBToLinear(v uint32) float64 {
+	f := float64(v) / 255
+	if f <= 0.04045 {
+		return f / 12.92
+	}
+	return math.Pow((f+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(v float64) int {
+	v = math.Max(0, math.Min(1, v))
+	if v <= 0.0031308 {
+		return int(v*12.92*255 + 0.5)
+	}
+	return int((1.055*math.Pow(v, 1/2.4)-0.055)*255 + 0.5)
+}
+
+func signPow(v, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(v), exp), v)
+}
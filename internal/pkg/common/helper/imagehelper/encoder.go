@@ -7,21 +7,85 @@ import (
 	"image/gif"
 	"image/jpeg"
 	"image/png"
+	"io"
+	"sync"
 )
 
+// Media types of the images.
+const (
+	MediaTypeJPEG = "image/jpeg"
+	MediaTypePNG  = "image/png"
+	MediaTypeGIF  = "image/gif"
+	MediaTypeWebP = "image/webp"
+	MediaTypeAVIF = "image/avif"
+)
+
+// DefaultQuality is the quality of the lossy formats if it's not set.
+const DefaultQuality = 85
+
+// Encoder encodes an image. The quality, from 1 to 100, only applies to the lossy formats.
+type Encoder func(w io.Writer, img image.Image, quality int) error
+
+type encoderEntry struct {
+	encode Encoder
+	ext    string
+}
+
+var (
+	encoders = map[string]encoderEntry{
+		MediaTypeJPEG: {func(w io.Writer, img image.Image, quality int) error {
+			return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+		}, "jpg"},
+		MediaTypePNG: {func(w io.Writer, img image.Image, quality int) error {
+			return png.Encode(w, img)
+		}, "png"},
+		MediaTypeGIF: {func(w io.Writer, img image.Image, quality int) error {
+			return gif.Encode(w, img, nil)
+		}, "gif"},
+	}
+	encodersMutex sync.RWMutex
+)
+
+// RegisterEncoder adds an encoder of a media type with its file extension, e.g. a WebP or AVIF encoder,
+// which aren't built in. It replaces the encoder of the media type if there's one.
+func RegisterEncoder(mediaType, fileExt string, enc Encoder) {
+	encodersMutex.Lock()
+	defer encodersMutex.Unlock()
+	encoders[mediaType] = encoderEntry{enc, fileExt}
+}
+
+// CanEncode returns whether an image can be encoded as the media type.
+func CanEncode(mediaType string) bool {
+	encodersMutex.RLock()
+	defer encodersMutex.RUnlock()
+	_, ok := encoders[mediaType]
+	return ok
+}
+
+// GetFileExt returns the file extension of an encodable media type, without the dot.
+func GetFileExt(mediaType string) string {
+	encodersMutex.RLock()
+	defer encodersMutex.RUnlock()
+	return encoders[mediaType].ext
+}
+
 // EncodeImage encodes the given image as the specified media type.
 func EncodeImage(img image.Image, mediaType string) ([]byte, error) {
-	var buf bytes.Buffer
-	var err error
-	switch mediaType {
-	case "image/jpeg":
-		err = jpeg.Encode(&buf, img, nil)
-	case "image/png":
-		err = png.Encode(&buf, img)
-	case "image/gif":
-		err = gif.Encode(&buf, img, nil)
-	default:
-		err = fmt.Errorf("Media type '%s' is not invalid", mediaType)
+	return EncodeImageWithQuality(img, mediaType, jpeg.DefaultQuality)
+}
+
+// EncodeImageWithQuality encodes the given image as the specified media type, with the quality if it's lossy.
+func EncodeImageWithQuality(img image.Image, mediaType string, quality int) ([]byte, error) {
+	encodersMutex.RLock()
+	entry, ok := encoders[mediaType]
+	encodersMutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("Media type '%s' is not invalid", mediaType)
 	}
+	if quality < 1 || quality > 100 {
+		quality = DefaultQuality
+	}
+	var buf bytes.Buffer
+	err := entry.encode(&buf, img, quality)
 	return buf.Bytes(), err
 }
@@ -0,0 +1,169 @@
+package imagehelper
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"io"
+	"testing"
+)
+
+// jpegWithOrientation returns the start of a JPEG with an Exif segment having the orientation.
+func jpegWithOrientation(order binary.ByteOrder, orientation uint16) []byte {
+	tiff := make([]byte, 26)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+	order.PutUint16(tiff[8:], 1)
+	order.PutUint16(tiff[10:], 0x0112)
+	order.PutUint16(tiff[12:], 3)
+	order.PutUint32(tiff[14:], 1)
+	order.PutUint16(tiff[18:], orientation)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	data := []byte{0xFF, 0xD8, 0xFF, 0xE0, 0, 4, 0, 0, 0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(data[10:], uint16(len(segment)+2))
+	return append(append(data, segment...), 0xFF, 0xDA)
+}
+
+func TestGetOrientation(t *testing.T) {
+	var tests = []struct {
+		data     []byte
+		expected int
+	}{
+		{jpegWithOrientation(binary.BigEndian, 6), 6},
+		{jpegWithOrientation(binary.LittleEndian, 8), 8},
+		{jpegWithOrientation(binary.LittleEndian, 9), 1},
+		{jpegWithOrientation(binary.LittleEndian, 3)[:20], 1},
+		{[]byte{0x89, 'P', 'N', 'G'}, 1},
+		{nil, 1},
+	}
+	for i, test := range tests {
+		if res := GetOrientation(test.data); res != test.expected {
+			t.Errorf("GetOrientation(#%d) = %d; expected %d", i, res, test.expected)
+		}
+	}
+}
+
+// testImage returns a 3x2 image whose pixels have distinct red values, 10*y+x.
+func testImage() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 3; x++ {
+			img.Set(x, y, color.NRGBA{uint8(10*y + x), 0, 0, 255})
+		}
+	}
+	return img
+}
+
+// rows returns the red values of an image by rows.
+func rows(img image.Image) [][]uint8 {
+	b := img.Bounds()
+	res := make([][]uint8, b.Dy())
+	for y := range res {
+		for x := 0; x < b.Dx(); x++ {
+			res[y] = append(res[y], color.NRGBAModel.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.NRGBA).R)
+		}
+	}
+	return res
+}
+
+func TestApplyOrientation(t *testing.T) {
+	var tests = []struct {
+		orientation int
+		expected    [][]uint8
+	}{
+		{1, [][]uint8{{0, 1, 2}, {10, 11, 12}}},
+		{2, [][]uint8{{2, 1, 0}, {12, 11, 10}}},
+		{3, [][]uint8{{12, 11, 10}, {2, 1, 0}}},
+		{4, [][]uint8{{10, 11, 12}, {0, 1, 2}}},
+		{5, [][]uint8{{0, 10}, {1, 11}, {2, 12}}},
+		{6, [][]uint8{{10, 0}, {11, 1}, {12, 2}}},
+		{7, [][]uint8{{12, 2}, {11, 1}, {10, 0}}},
+		{8, [][]uint8{{2, 12}, {1, 11}, {0, 10}}},
+	}
+	for _, test := range tests {
+		res := rows(ApplyOrientation(testImage(), test.orientation))
+		if len(res) != len(test.expected) || !bytes.Equal(bytes.Join(res, nil), bytes.Join(test.expected, nil)) {
+			t.Errorf("ApplyOrientation(%d) = %v; expected %v", test.orientation, res, test.expected)
+		}
+	}
+}
+
+func TestResize(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 400, 200))
+	var tests = []struct {
+		width, height int
+		mode          string
+		expected      image.Point
+	}{
+		{100, 100, ModeFit, image.Pt(100, 50)},
+		{100, 100, ModeFill, image.Pt(100, 100)},
+		{100, 0, ModeFit, image.Pt(100, 50)},
+		{0, 50, ModeFit, image.Pt(100, 50)},
+		{800, 800, ModeFit, image.Pt(400, 200)},
+		{800, 800, ModeFill, image.Pt(200, 200)},
+		{300, 50, ModeFill, image.Pt(300, 50)},
+		{0, 0, ModeFit, image.Pt(400, 200)},
+	}
+	for _, test := range tests {
+		if res := Resize(img, test.width, test.height, test.mode).Bounds().Size(); res != test.expected {
+			t.Errorf("Resize(400x200, %d, %d, %s) = %v; expected %v", test.width, test.height, test.mode, res, test.expected)
+		}
+	}
+}
+
+func TestEncodeBlurhash(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 48))
+	for i := range img.Pix {
+		img.Pix[i] = 255
+	}
+
+	// The hash starts with the components ("L" is 4x3), the maximum AC value, then the average color.
+	white, err := EncodeBlurhash(img, 4, 3)
+	if err != nil || len(white) != 28 || white[:1] != "L" || white[2:6] != "TSUA" {
+		t.Errorf("EncodeBlurhash(white) = %q, %v; expected 28 characters of L?TSUA...", white, err)
+	}
+	if res, _ := EncodeBlurhash(img, 1, 1); res != "00TSUA" {
+		t.Errorf("EncodeBlurhash(white, 1, 1) = %q; expected %q", res, "00TSUA")
+	}
+
+	for x := 0; x < 64; x++ {
+		for y := 0; y < 48; y++ {
+			img.Set(x, y, color.NRGBA{uint8(x * 4), 0, uint8(y * 5), 255})
+		}
+	}
+	if res, err := EncodeBlurhash(img, 4, 3); err != nil || len(res) != 28 || res == white {
+		t.Errorf("EncodeBlurhash(gradient) = %q, %v; expected 28 characters", res, err)
+	}
+	if _, err := EncodeBlurhash(img, 0, 3); err == nil {
+		t.Errorf("EncodeBlurhash() with 0 components returns nil error")
+	}
+}
+
+func TestRegisterEncoder(t *testing.T) {
+	const mediaType = "image/x-test"
+	if CanEncode(mediaType) {
+		t.Fatalf("CanEncode(%q) = true before registering", mediaType)
+	}
+	RegisterEncoder(mediaType, "test", func(w io.Writer, img image.Image, quality int) error {
+		_, err := w.Write([]byte{byte(quality)})
+		return err
+	})
+	defer func() {
+		encodersMutex.Lock()
+		delete(encoders, mediaType)
+		encodersMutex.Unlock()
+	}()
+	if res, err := EncodeImageWithQuality(testImage(), mediaType, 70); err != nil || !bytes.Equal(res, []byte{70}) || GetFileExt(mediaType) != "test" {
+		t.Errorf("EncodeImageWithQuality() with the registered encoder = %v, %v", res, err)
+	}
+	if res, _ := EncodeImageWithQuality(testImage(), mediaType, 0); !bytes.Equal(res, []byte{DefaultQuality}) {
+		t.Errorf("EncodeImageWithQuality() with quality 0 = %v; expected the default quality", res)
+	}
+}
@@ -0,0 +1,103 @@
+package imagehelper
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/draw"
+)
+
+// GetOrientation returns the EXIF orientation of a JPEG image, from 1 to 8, or 1 if it has none.
+func GetOrientation(data []byte) int {
+	// Find the Exif APP1 segment before the image data.
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(data) && data[i] == 0xFF; {
+		marker, size := data[i+1], int(binary.BigEndian.Uint16(data[i+2:]))
+		if marker == 0xDA || size < 2 || i+2+size > len(data) {
+			break
+		}
+		if segment := data[i+4 : i+2+size]; marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return getTIFFOrientation(segment[6:])
+		}
+		i += 2 + size
+	}
+	return 1
+}
+
+// getTIFFOrientation returns the orientation tag of the first IFD of the TIFF data in an Exif segment.
+func getTIFFOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			break
+		}
+		// The orientation is a SHORT (type 3) value.
+		if order.Uint16(tiff[entry:]) == 0x0112 && order.Uint16(tiff[entry+2:]) == 3 {
+			if v := int(order.Uint16(tiff[entry+8:])); v >= 1 && v <= 8 {
+				return v
+			}
+			break
+		}
+	}
+	return 1
+}
+
+// ApplyOrientation rotates and flips an image as its EXIF orientation, so it's displayed upright without it.
+func ApplyOrientation(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+	b := img.Bounds()
+	src := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(src, src.Bounds(), img, b.Min, draw.Src)
+	w, h := b.Dx(), b.Dy()
+
+	// The orientations from 5 to 8 swap the width and height.
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // Displayed flipped horizontally.
+				sx, sy = w-1-x, y
+			case 3: // Displayed rotated 180°.
+				sx, sy = w-1-x, h-1-y
+			case 4: // Displayed flipped vertically.
+				sx, sy = x, h-1-y
+			case 5: // Displayed transposed.
+				sx, sy = y, x
+			case 6: // Displayed rotated 90° clockwise.
+				sx, sy = y, h-1-x
+			case 7: // Displayed transversed.
+				sx, sy = w-1-y, h-1-x
+			case 8: // Displayed rotated 90° counterclockwise.
+				sx, sy = w-1-y, x
+			}
+			copy(dst.Pix[dst.PixOffset(x, y):dst.PixOffset(x, y)+4], src.Pix[src.PixOffset(sx, sy):src.PixOffset(sx, sy)+4])
+		}
+	}
+	return dst
+}
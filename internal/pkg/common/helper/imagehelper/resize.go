@@ -0,0 +1,81 @@
+package imagehelper
+
+import (
+	"image"
+
+	"golang.org/x/image/draw"
+)
+
+// Resize modes.
+const (
+	// ModeFit scales an image down to fit in the size, keeping its aspect ratio.
+	ModeFit = "fit"
+
+	// ModeFill scales an image down to cover the size and crops it in the center, so it has the size's aspect ratio.
+	ModeFill = "fill"
+)
+
+// Resize scales an image down by the mode to the width and height, where 0 is unbounded. The images smaller than
+// the size aren't scaled up, so an image filling a larger size is only cropped to the size's aspect ratio.
+func Resize(img image.Image, width, height int, mode string) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w == 0 || h == 0 || (width <= 0 && height <= 0) {
+		return img
+	}
+	if width <= 0 {
+		width = w * height / h
+	} else if height <= 0 {
+		height = h * width / w
+	}
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+
+	src := b
+	var dw, dh int
+	if mode == ModeFill {
+		// Crop the source to the size's aspect ratio, then scale it down if it's larger.
+		if w*height > h*width {
+			cw := h * width / height
+			if cw < 1 {
+				cw = 1
+			}
+			src = image.Rect(b.Min.X+(w-cw)/2, b.Min.Y, b.Min.X+(w-cw)/2+cw, b.Max.Y)
+		} else {
+			ch := w * height / width
+			if ch < 1 {
+				ch = 1
+			}
+			src = image.Rect(b.Min.X, b.Min.Y+(h-ch)/2, b.Max.X, b.Min.Y+(h-ch)/2+ch)
+		}
+		dw, dh = src.Dx(), src.Dy()
+		if dw > width {
+			dw, dh = width, height
+		}
+	} else {
+		dw, dh = w, h
+		if dw > width {
+			dw, dh = width, h*width/w
+		}
+		if dh > height {
+			dw, dh = w*height/h, height
+		}
+	}
+	if dw < 1 {
+		dw = 1
+	}
+	if dh < 1 {
+		dh = 1
+	}
+	if src == b && dw == w && dh == h {
+		return img
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, src, draw.Src, nil)
+	return dst
+}
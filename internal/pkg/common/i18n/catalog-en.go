@@ -0,0 +1,156 @@
+package i18n
+
+var catalogEnglish = map[string]string{
+	MsgProcessingFailed:     "An error occurred while processing your request",
+	MsgRequestBodyInvalid:   "Request body format is invalid",
+	MsgRequestInvalid:       "Invalid request",
+	MsgRequestHeadersEmpty:  "Request headers are required ({headers})",
+	MsgRequestHeaderInvalid: "Request header is invalid ({header})",
+	MsgTooManyRequests:      "Too many requests, please try again in {seconds} seconds",
+
+	MsgCaptchaRequired: "CAPTCHA is required",
+	MsgCaptchaFailed:   "CAPTCHA verification failed, please try again",
+
+	MsgAPIKeyRequired:             "API-Key is required",
+	MsgAPIKeyParseFailed:          "API-Key failed to parse",
+	MsgAPIKeyFormatInvalid:        "API-Key format is invalid",
+	MsgAPIKeyNotFound:             "API-Key is not found",
+	MsgAPIKeyValidationFailed:     "An error occurred while validating API-Key",
+	MsgAPIKeyInvalid:              "API-Key is invalid",
+	MsgAPIKeyAppPlatformInvalid:   "API-Key is invalid for the platform",
+	MsgAPIKeyAppIdentifierInvalid: "API-Key is invalid for the app identifier",
+	MsgAPIKeyExpired:              "API-Key has expired",
+	MsgAPIKeyDisabled:             "API-Key is disabled",
+
+	MsgAPIKeySignatureRequired:      "Signature is required for the API-Key",
+	MsgAPIKeySignatureFormatInvalid: "Signature format is invalid",
+	MsgAPIKeySignatureExpired:       "Signature timestamp is too far from the server time",
+	MsgAPIKeySignatureInvalid:       "Signature is invalid",
+	MsgAPIKeySignatureReplayed:      "Signature has already been used",
+	MsgAPIKeyScopeNotAllowed:        "API-Key is not allowed to call this API",
+	MsgAPIKeyDailyQuotaExceeded:     "API-Key has used up its daily quota of {limit} requests",
+	MsgAPIKeyMonthlyQuotaExceeded:   "API-Key has used up its monthly quota of {limit} requests",
+
+	MsgAPIKeyDomainInvalid:      "API key domain is invalid",
+	MsgAPIKeyIDRequired:         "API key ID is required",
+	MsgAPIKeyPlatformNotAllowed: "The app platform is not allowed for the API key's domain",
+	MsgAPIKeyExpiryInvalid:      "Expiry time must be in the future",
+	MsgAPIKeyOverlapInvalid:     "Overlap must be between 0 and {maxHours} hours",
+
+	MsgAPIKeySigningPlatformInvalid:  "Only the API keys of the server platform can require signatures",
+	MsgAPIKeyScopesInvalid:           "Scopes are invalid, each must be \"*\", an API group, or an API group and endpoint",
+	MsgAPIKeyQuotaInvalid:            "Quota must not be negative",
+	MsgAPIKeyUsageRangeInvalid:       "Time range must be positive and up to {maxDays} days",
+	MsgAPIKeyUsageGranularityInvalid: "Granularity must be hour, day or month",
+
+	MsgAuthorizationRequired:      "Authorization is required",
+	MsgAuthorizationFormatInvalid: "Authorization format is invalid",
+	MsgAuthorizationTypeInvalid:   "Authorization type is invalid or not supported",
+	MsgCredentialsInvalid:         "The credentials are invalid",
+	MsgCredentialsParseFailed:     "Failed to parse the credentials",
+	MsgTokenSignatureInvalid:      "Token signature is invalid, please get a new token",
+	MsgTokenDetailFailed:          "Failed retrieving token detail",
+	MsgTokenParseFailed:           "Failed to parse token",
+	MsgAccessTokenInvalid:         "Access token is invalid",
+	MsgAccessTokenDeviceInvalid:   "Access token does not belong to the device",
+	MsgAccessTokenNotOwner:        "Access token does not belong to the user",
+	MsgAccessTokenExpired:         "Access token is expired",
+	MsgRefreshTokenInvalid:        "Refresh token is invalid",
+	MsgRefreshTokenDeviceInvalid:  "Refresh token does not belong to the device",
+	MsgRefreshTokenNotOwner:       "Refresh token does not belong to the user",
+	MsgRefreshTokenExpired:        "Refresh token is expired",
+	MsgLinkTokenInvalid:           "The link is invalid",
+	MsgLinkTokenExpired:           "The link has expired, please request a new one",
+
+	MsgAccountSessionNotFound:      "Account session is not found",
+	MsgAccountNotFound:             "Account is not found",
+	MsgAccountCredentialsMismatch:  "The email and password does not match",
+	MsgAccountNotVerified:          "Your account has not been verified yet",
+	MsgAccountAlreadyVerified:      "The account has already been verified",
+	MsgAccountVerified:             "Your account verification is successful",
+	MsgAccountEmailRegistered:      "The email address is already registered",
+	MsgAccountEmailNotRegistered:   "The email address is not registered",
+	MsgAccountEmailInvalid:         "The email address is invalid",
+	MsgAccountLoggedOut:            "You have logged out",
+	MsgVerificationNotFound:        "There is no pending verification found, or the code has expired",
+	MsgVerificationIDRequired:      "Verification ID is required",
+	MsgVerificationIDInvalid:       "Verification ID is invalid",
+	MsgVerificationKeyRequired:     "Verification key is required",
+	MsgVerificationKeyInvalid:      "Verification key is invalid",
+	MsgVerificationCodeRequired:    "Verification code is required",
+	MsgVerificationCodeIncorrect:   "Verification code is incorrect",
+	MsgVerificationEmailChanged:    "Email address has changed, you should request a new verification code",
+	MsgResetPasswordNotFound:       "There is no reset password request found, or the code has expired",
+	MsgResetTokenRequired:          "Token is required",
+	MsgResetTokenInvalid:           "Token is invalid",
+	MsgResetTokenIncorrect:         "Token is incorrect",
+	MsgOTPTooManyAttempts:          "Too many incorrect attempts, please request a new code",
+	MsgOTPResendCooldown:           "Please wait {seconds} seconds before requesting a new code",
+	MsgOTPSendLimitReached:         "Too many codes have been requested today, please try again tomorrow",
+	MsgPasswordChanged:             "Password changed successfully",
+	MsgPasswordChangeFailed:        "Failed to change password",
+	MsgTOSAccepted:                 "Terms of Service is accepted",
+	MsgTOSAlreadyAccepted:          "Terms of Service is already accepted",
+	MsgCreatedTimeRequired:         "Created time is required",
+	MsgCreatedTimeInvalid:          "Created time is invalid",
+	MsgFullNameRequired:            "Full name is required",
+	MsgEmailRequired:               "Email address is required",
+	MsgPasswordRequired:            "Password is required",
+	MsgCurrentPasswordRequired:     "Current password is required",
+	MsgCurrentPasswordInvalid:      "Current password is invalid",
+	MsgNewPasswordRequired:         "New password is required",
+	MsgPreferencesRequired:         "Preferences are required",
+	MsgPreferenceUnknown:           "Preference is unknown",
+	MsgPreferenceTypeInvalid:       "Preference value type is invalid",
+	MsgPreferenceLocaleUnsupported: "Locale is not supported",
+	MsgPreferenceTimeZoneInvalid:   "Time zone is invalid",
+	MsgFileNotFound:                "File is not found",
+	MsgFileURLInvalid:              "File URL is invalid or expired",
+
+	MsgPhoneEmpty:         "Phone number is empty",
+	MsgPhoneNotNumeric:    "Phone number must be numeric",
+	MsgPhoneLeadingZero:   "Phone number can't start with '0'",
+	MsgPhoneLengthInvalid: "Phone number's length must be {min}-{max} digits",
+	MsgEmailEmpty:         "Email address is empty",
+	MsgEmailInvalid:       "Email address is invalid",
+	MsgEmailFormatInvalid: "Email address format is invalid",
+	MsgEmailDisposable:    "Disposable email addresses are not allowed",
+	MsgEmailDomainInvalid: "Email address domain can't receive emails",
+	MsgEmailTypo:          "Did you mean {suggestion}?",
+	MsgPasswordEmpty:      "Password is empty",
+	MsgPasswordLength:     "Password's length must be {min}-{max} characters",
+	MsgPasswordFormat:     "Password must contain at least 1 lowercase, uppercase, and special characters and 1 number",
+	MsgPasswordMinLength:  "Password's length must be at least {limit} characters",
+	MsgPasswordMaxLength:  "Password's length must be at most {limit} characters",
+	MsgPasswordLowercase:  "Password must contain at least 1 lowercase character",
+	MsgPasswordUppercase:  "Password must contain at least 1 uppercase character",
+	MsgPasswordNumber:     "Password must contain at least 1 number",
+	MsgPasswordSpecial:    "Password must contain at least 1 special character",
+	MsgPasswordReused:     "Password can't be the same as your last {limit} passwords",
+	MsgPasswordBreached:   "Password has appeared in a data breach, please choose a different password",
+
+	MsgEmailSubjectVerifyEmailAddress: "Please verify your email address",
+	MsgEmailSubjectResetPassword:      "Reset your password",
+	MsgEmailSubjectPasswordChanged:    "Your password was changed",
+
+	MsgWebVerifyEmailTitle:                    "Verify Your Email Address",
+	MsgWebVerifyEmailDescription:              "Click the button below to verify your email address.",
+	MsgWebVerifyEmailSubmit:                   "Verify Email Address",
+	MsgWebVerifyEmailSucceeded:                "Email Address Verified",
+	MsgWebVerifyEmailFailed:                   "Verification Failed",
+	MsgWebResetPasswordRequestTitle:           "Reset Your Password",
+	MsgWebResetPasswordRequestDescription:     "Enter your account's email address and we will send you a link to reset your password.",
+	MsgWebResetPasswordRequestSubmit:          "Send Reset Link",
+	MsgWebResetPasswordRequestSent:            "Check Your Email",
+	MsgWebResetPasswordRequestSentDescription: "If the email address is registered, a link to reset your password has been sent to it.",
+	MsgWebResetPasswordTitle:                  "Set a New Password",
+	MsgWebResetPasswordSubmit:                 "Change Password",
+	MsgWebResetPasswordSucceeded:              "Password Changed",
+	MsgWebResetPasswordFailed:                 "Reset Password Failed",
+	MsgWebRequestNewLink:                      "Request a New Link",
+	MsgWebFieldEmail:                          "Email address",
+	MsgWebFieldNewPassword:                    "New password",
+	MsgWebFieldConfirmPassword:                "Confirm new password",
+	MsgWebFieldCaptcha:                        "CAPTCHA",
+	MsgWebPasswordMismatch:                    "The passwords do not match",
+}
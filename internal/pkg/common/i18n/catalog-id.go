@@ -0,0 +1,156 @@
+package i18n
+
+var catalogIndonesian = map[string]string{
+	MsgProcessingFailed:     "Terjadi kesalahan saat memproses permintaan Anda",
+	MsgRequestBodyInvalid:   "Format isi permintaan tidak valid",
+	MsgRequestInvalid:       "Permintaan tidak valid",
+	MsgRequestHeadersEmpty:  "Header permintaan wajib diisi ({headers})",
+	MsgRequestHeaderInvalid: "Header permintaan tidak valid ({header})",
+	MsgTooManyRequests:      "Terlalu banyak permintaan, silakan coba lagi dalam {seconds} detik",
+
+	MsgCaptchaRequired: "CAPTCHA wajib diisi",
+	MsgCaptchaFailed:   "Verifikasi CAPTCHA gagal, silakan coba lagi",
+
+	MsgAPIKeyRequired:             "API-Key wajib diisi",
+	MsgAPIKeyParseFailed:          "API-Key gagal dibaca",
+	MsgAPIKeyFormatInvalid:        "Format API-Key tidak valid",
+	MsgAPIKeyNotFound:             "API-Key tidak ditemukan",
+	MsgAPIKeyValidationFailed:     "Terjadi kesalahan saat memvalidasi API-Key",
+	MsgAPIKeyInvalid:              "API-Key tidak valid",
+	MsgAPIKeyAppPlatformInvalid:   "API-Key tidak valid untuk platform ini",
+	MsgAPIKeyAppIdentifierInvalid: "API-Key tidak valid untuk identitas aplikasi ini",
+	MsgAPIKeyExpired:              "API-Key sudah kedaluwarsa",
+	MsgAPIKeyDisabled:             "API-Key dinonaktifkan",
+
+	MsgAPIKeySignatureRequired:      "Signature wajib diisi untuk API-Key ini",
+	MsgAPIKeySignatureFormatInvalid: "Format Signature tidak valid",
+	MsgAPIKeySignatureExpired:       "Waktu Signature terlalu jauh dari waktu server",
+	MsgAPIKeySignatureInvalid:       "Signature tidak valid",
+	MsgAPIKeySignatureReplayed:      "Signature sudah pernah digunakan",
+	MsgAPIKeyScopeNotAllowed:        "API-Key tidak diizinkan memanggil API ini",
+	MsgAPIKeyDailyQuotaExceeded:     "API-Key telah menghabiskan kuota harian sebanyak {limit} permintaan",
+	MsgAPIKeyMonthlyQuotaExceeded:   "API-Key telah menghabiskan kuota bulanan sebanyak {limit} permintaan",
+
+	MsgAPIKeyDomainInvalid:      "Domain API key tidak valid",
+	MsgAPIKeyIDRequired:         "ID API key wajib diisi",
+	MsgAPIKeyPlatformNotAllowed: "Platform aplikasi tidak diizinkan untuk domain API key ini",
+	MsgAPIKeyExpiryInvalid:      "Waktu kedaluwarsa harus di masa mendatang",
+	MsgAPIKeyOverlapInvalid:     "Masa tumpang tindih harus antara 0 dan {maxHours} jam",
+
+	MsgAPIKeySigningPlatformInvalid:  "Hanya API key untuk platform server yang dapat mewajibkan Signature",
+	MsgAPIKeyScopesInvalid:           "Scope tidak valid, masing-masing harus \"*\", grup API, atau grup API dan endpoint",
+	MsgAPIKeyQuotaInvalid:            "Kuota tidak boleh negatif",
+	MsgAPIKeyUsageRangeInvalid:       "Rentang waktu harus positif dan paling lama {maxDays} hari",
+	MsgAPIKeyUsageGranularityInvalid: "Granularitas harus hour, day, atau month",
+
+	MsgAuthorizationRequired:      "Authorization wajib diisi",
+	MsgAuthorizationFormatInvalid: "Format Authorization tidak valid",
+	MsgAuthorizationTypeInvalid:   "Tipe Authorization tidak valid atau tidak didukung",
+	MsgCredentialsInvalid:         "Kredensial tidak valid",
+	MsgCredentialsParseFailed:     "Gagal membaca kredensial",
+	MsgTokenSignatureInvalid:      "Tanda tangan token tidak valid, silakan minta token baru",
+	MsgTokenDetailFailed:          "Gagal mengambil detail token",
+	MsgTokenParseFailed:           "Gagal membaca token",
+	MsgAccessTokenInvalid:         "Access token tidak valid",
+	MsgAccessTokenDeviceInvalid:   "Access token bukan milik perangkat ini",
+	MsgAccessTokenNotOwner:        "Access token bukan milik pengguna ini",
+	MsgAccessTokenExpired:         "Access token sudah kedaluwarsa",
+	MsgRefreshTokenInvalid:        "Refresh token tidak valid",
+	MsgRefreshTokenDeviceInvalid:  "Refresh token bukan milik perangkat ini",
+	MsgRefreshTokenNotOwner:       "Refresh token bukan milik pengguna ini",
+	MsgRefreshTokenExpired:        "Refresh token sudah kedaluwarsa",
+	MsgLinkTokenInvalid:           "Tautan tidak valid",
+	MsgLinkTokenExpired:           "Tautan sudah kedaluwarsa, silakan minta tautan baru",
+
+	MsgAccountSessionNotFound:      "Sesi akun tidak ditemukan",
+	MsgAccountNotFound:             "Akun tidak ditemukan",
+	MsgAccountCredentialsMismatch:  "Email dan kata sandi tidak cocok",
+	MsgAccountNotVerified:          "Akun Anda belum diverifikasi",
+	MsgAccountAlreadyVerified:      "Akun sudah diverifikasi",
+	MsgAccountVerified:             "Verifikasi akun Anda berhasil",
+	MsgAccountEmailRegistered:      "Alamat email sudah terdaftar",
+	MsgAccountEmailNotRegistered:   "Alamat email belum terdaftar",
+	MsgAccountEmailInvalid:         "Alamat email tidak valid",
+	MsgAccountLoggedOut:            "Anda telah keluar",
+	MsgVerificationNotFound:        "Tidak ada verifikasi yang tertunda, atau kode sudah kedaluwarsa",
+	MsgVerificationIDRequired:      "ID verifikasi wajib diisi",
+	MsgVerificationIDInvalid:       "ID verifikasi tidak valid",
+	MsgVerificationKeyRequired:     "Kunci verifikasi wajib diisi",
+	MsgVerificationKeyInvalid:      "Kunci verifikasi tidak valid",
+	MsgVerificationCodeRequired:    "Kode verifikasi wajib diisi",
+	MsgVerificationCodeIncorrect:   "Kode verifikasi salah",
+	MsgVerificationEmailChanged:    "Alamat email telah berubah, silakan minta kode verifikasi baru",
+	MsgResetPasswordNotFound:       "Tidak ada permintaan atur ulang kata sandi, atau kode sudah kedaluwarsa",
+	MsgResetTokenRequired:          "Token wajib diisi",
+	MsgResetTokenInvalid:           "Token tidak valid",
+	MsgResetTokenIncorrect:         "Token salah",
+	MsgOTPTooManyAttempts:          "Terlalu banyak percobaan yang salah, silakan minta kode baru",
+	MsgOTPResendCooldown:           "Silakan tunggu {seconds} detik sebelum meminta kode baru",
+	MsgOTPSendLimitReached:         "Terlalu banyak kode yang diminta hari ini, silakan coba lagi besok",
+	MsgPasswordChanged:             "Kata sandi berhasil diubah",
+	MsgPasswordChangeFailed:        "Gagal mengubah kata sandi",
+	MsgTOSAccepted:                 "Syarat dan Ketentuan telah disetujui",
+	MsgTOSAlreadyAccepted:          "Syarat dan Ketentuan sudah disetujui sebelumnya",
+	MsgCreatedTimeRequired:         "Waktu pembuatan wajib diisi",
+	MsgCreatedTimeInvalid:          "Waktu pembuatan tidak valid",
+	MsgFullNameRequired:            "Nama lengkap wajib diisi",
+	MsgEmailRequired:               "Alamat email wajib diisi",
+	MsgPasswordRequired:            "Kata sandi wajib diisi",
+	MsgCurrentPasswordRequired:     "Kata sandi saat ini wajib diisi",
+	MsgCurrentPasswordInvalid:      "Kata sandi saat ini salah",
+	MsgNewPasswordRequired:         "Kata sandi baru wajib diisi",
+	MsgPreferencesRequired:         "Preferensi wajib diisi",
+	MsgPreferenceUnknown:           "Preferensi tidak dikenal",
+	MsgPreferenceTypeInvalid:       "Tipe nilai preferensi tidak valid",
+	MsgPreferenceLocaleUnsupported: "Bahasa tidak didukung",
+	MsgPreferenceTimeZoneInvalid:   "Zona waktu tidak valid",
+	MsgFileNotFound:                "Berkas tidak ditemukan",
+	MsgFileURLInvalid:              "URL berkas tidak valid atau kedaluwarsa",
+
+	MsgPhoneEmpty:         "Nomor telepon kosong",
+	MsgPhoneNotNumeric:    "Nomor telepon harus berupa angka",
+	MsgPhoneLeadingZero:   "Nomor telepon tidak boleh diawali '0'",
+	MsgPhoneLengthInvalid: "Panjang nomor telepon harus {min}-{max} digit",
+	MsgEmailEmpty:         "Alamat email kosong",
+	MsgEmailInvalid:       "Alamat email tidak valid",
+	MsgEmailFormatInvalid: "Format alamat email tidak valid",
+	MsgEmailDisposable:    "Alamat email sementara tidak diperbolehkan",
+	MsgEmailDomainInvalid: "Domain alamat email tidak dapat menerima email",
+	MsgEmailTypo:          "Apakah maksud Anda {suggestion}?",
+	MsgPasswordEmpty:      "Kata sandi kosong",
+	MsgPasswordLength:     "Panjang kata sandi harus {min}-{max} karakter",
+	MsgPasswordFormat:     "Kata sandi harus mengandung minimal 1 huruf kecil, huruf besar, karakter khusus, dan 1 angka",
+	MsgPasswordMinLength:  "Panjang kata sandi minimal {limit} karakter",
+	MsgPasswordMaxLength:  "Panjang kata sandi maksimal {limit} karakter",
+	MsgPasswordLowercase:  "Kata sandi harus mengandung minimal 1 huruf kecil",
+	MsgPasswordUppercase:  "Kata sandi harus mengandung minimal 1 huruf besar",
+	MsgPasswordNumber:     "Kata sandi harus mengandung minimal 1 angka",
+	MsgPasswordSpecial:    "Kata sandi harus mengandung minimal 1 karakter khusus",
+	MsgPasswordReused:     "Kata sandi tidak boleh sama dengan {limit} kata sandi terakhir Anda",
+	MsgPasswordBreached:   "Kata sandi pernah muncul dalam kebocoran data, silakan pilih kata sandi lain",
+
+	MsgEmailSubjectVerifyEmailAddress: "Mohon verifikasi alamat email Anda",
+	MsgEmailSubjectResetPassword:      "Atur ulang kata sandi Anda",
+	MsgEmailSubjectPasswordChanged:    "Kata sandi Anda telah diubah",
+
+	MsgWebVerifyEmailTitle:                    "Verifikasi Alamat Email Anda",
+	MsgWebVerifyEmailDescription:              "Klik tombol di bawah untuk memverifikasi alamat email Anda.",
+	MsgWebVerifyEmailSubmit:                   "Verifikasi Alamat Email",
+	MsgWebVerifyEmailSucceeded:                "Alamat Email Terverifikasi",
+	MsgWebVerifyEmailFailed:                   "Verifikasi Gagal",
+	MsgWebResetPasswordRequestTitle:           "Atur Ulang Kata Sandi Anda",
+	MsgWebResetPasswordRequestDescription:     "Masukkan alamat email akun Anda dan kami akan mengirimkan tautan untuk mengatur ulang kata sandi Anda.",
+	MsgWebResetPasswordRequestSubmit:          "Kirim Tautan",
+	MsgWebResetPasswordRequestSent:            "Periksa Email Anda",
+	MsgWebResetPasswordRequestSentDescription: "Jika alamat email terdaftar, tautan untuk mengatur ulang kata sandi telah dikirim ke alamat tersebut.",
+	MsgWebResetPasswordTitle:                  "Atur Kata Sandi Baru",
+	MsgWebResetPasswordSubmit:                 "Ubah Kata Sandi",
+	MsgWebResetPasswordSucceeded:              "Kata Sandi Diubah",
+	MsgWebResetPasswordFailed:                 "Gagal Mengatur Ulang Kata Sandi",
+	MsgWebRequestNewLink:                      "Minta Tautan Baru",
+	MsgWebFieldEmail:                          "Alamat email",
+	MsgWebFieldNewPassword:                    "Kata sandi baru",
+	MsgWebFieldConfirmPassword:                "Konfirmasi kata sandi baru",
+	MsgWebFieldCaptcha:                        "CAPTCHA",
+	MsgWebPasswordMismatch:                    "Kata sandi tidak sama",
+}
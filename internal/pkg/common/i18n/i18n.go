@@ -0,0 +1,177 @@
+// Package i18n provides message catalogs to localize user-facing messages.
+// Messages are identified by stable IDs, and may have named parameters written as "{name}" in the catalogs.
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Defines the supported locales.
+const (
+	English    = "en"
+	Indonesian = "id"
+)
+
+// DefaultLocale is the locale used when no supported locale is requested, and the fallback for missing messages.
+const DefaultLocale = English
+
+// SupportedLocales is the list of supported locales.
+var SupportedLocales = []string{English, Indonesian}
+
+var catalogs = map[string]map[string]string{
+	English:    catalogEnglish,
+	Indonesian: catalogIndonesian,
+}
+
+// Params contains the named parameters of a message.
+type Params map[string]interface{}
+
+// Message is a localizable message.
+type Message struct {
+	ID     string
+	Params Params
+
+	// text is used as is for messages which aren't in the catalogs, e.g. errors from external libraries.
+	text string
+}
+
+// NewMessage returns a message by its ID.
+func NewMessage(id string) Message {
+	return Message{ID: id}
+}
+
+// NewMessageWithParams returns a message by its ID with parameters.
+func NewMessageWithParams(id string, params Params) Message {
+	return Message{ID: id, Params: params}
+}
+
+// NewRawMessage returns a message which isn't localized.
+func NewRawMessage(text string) Message {
+	return Message{text: text}
+}
+
+// IsEmpty checks if the message has neither an ID nor a text.
+func (m Message) IsEmpty() bool {
+	return m.ID == "" && m.text == ""
+}
+
+// Localize returns the message's text in a locale, falling back to the default locale.
+// The ID is returned if the message is not found in any catalog.
+func (m Message) Localize(locale string) string {
+	if m.ID == "" {
+		return m.text
+	}
+	s, ok := catalogs[locale][m.ID]
+	if !ok {
+		if s, ok = catalogs[DefaultLocale][m.ID]; !ok {
+			return m.ID
+		}
+	}
+	for k, v := range m.Params {
+		s = strings.Replace(s, "{"+k+"}", fmt.Sprint(v), -1)
+	}
+	return s
+}
+
+// String returns the message's text in the default locale.
+func (m Message) String() string {
+	return m.Localize(DefaultLocale)
+}
+
+// Error is an error with a localizable message.
+type Error struct {
+	msg Message
+}
+
+// NewError returns an error by its message ID.
+func NewError(id string) *Error {
+	return &Error{NewMessage(id)}
+}
+
+// NewErrorWithParams returns an error by its message ID with parameters.
+func NewErrorWithParams(id string, params Params) *Error {
+	return &Error{NewMessageWithParams(id, params)}
+}
+
+// Error returns the message's text in the default locale.
+func (e *Error) Error() string {
+	return e.msg.String()
+}
+
+// Message returns the error's message.
+func (e *Error) Message() Message {
+	return e.msg
+}
+
+// FromError returns the message of an error. Errors without a localizable message are returned as is.
+func FromError(err error) Message {
+	if err == nil {
+		return Message{}
+	}
+	if e, ok := err.(*Error); ok {
+		return e.msg
+	}
+	return NewRawMessage(err.Error())
+}
+
+// MatchLocale returns the supported locale matching a language tag, e.g. "id-ID" matches "id".
+func MatchLocale(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	for _, l := range SupportedLocales {
+		if tag == l {
+			return l, true
+		}
+	}
+	return "", false
+}
+
+// ParseAcceptLanguage returns the supported locale with the highest quality in an Accept-Language header value.
+// The boolean is false if none of the languages are supported.
+func ParseAcceptLanguage(header string) (string, bool) {
+	type tag struct {
+		locale string
+		q      float64
+		index  int
+	}
+	tags := make([]tag, 0)
+	for i, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		locale, ok := MatchLocale(fields[0])
+		if !ok {
+			continue
+		}
+		q := 1.0
+		for _, f := range fields[1:] {
+			f = strings.TrimSpace(f)
+			if strings.HasPrefix(f, "q=") {
+				if v, err := strconv.ParseFloat(f[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q > 0 {
+			tags = append(tags, tag{locale, q, i})
+		}
+	}
+	if len(tags) == 0 {
+		return "", false
+	}
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].q > tags[j].q
+	})
+	return tags[0].locale, true
+}
+
+// LocaleFromAcceptLanguage returns the locale requested by an Accept-Language header value, or the default locale.
+func LocaleFromAcceptLanguage(header string) string {
+	if locale, ok := ParseAcceptLanguage(header); ok {
+		return locale
+	}
+	return DefaultLocale
+}
@@ -0,0 +1,75 @@
+package i18n
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCatalogsHaveSameMessages(t *testing.T) {
+	for locale, catalog := range catalogs {
+		for id := range catalogEnglish {
+			if _, ok := catalog[id]; !ok {
+				t.Errorf(`catalog "%v" is missing message "%v"`, locale, id)
+			}
+		}
+		for id := range catalog {
+			if _, ok := catalogEnglish[id]; !ok {
+				t.Errorf(`catalog "%v" has unknown message "%v"`, locale, id)
+			}
+		}
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	var tests = []struct {
+		msg      Message
+		locale   string
+		expected string
+	}{
+		{NewMessage(MsgAccountNotFound), English, "Account is not found"},
+		{NewMessage(MsgAccountNotFound), Indonesian, "Akun tidak ditemukan"},
+		{NewMessage(MsgAccountNotFound), "fr", "Account is not found"},
+		{NewMessageWithParams(MsgPasswordMinLength, Params{"limit": 8}), English, "Password's length must be at least 8 characters"},
+		{NewMessageWithParams(MsgPasswordMinLength, Params{"limit": 8}), Indonesian, "Panjang kata sandi minimal 8 karakter"},
+		{NewMessage("unknown.message"), English, "unknown.message"},
+		{NewRawMessage("Raw text"), Indonesian, "Raw text"},
+	}
+	for _, test := range tests {
+		if out := test.msg.Localize(test.locale); out != test.expected {
+			t.Errorf(`Localize("%v", "%v") = "%v"; expected "%v"`, test.msg.ID, test.locale, out, test.expected)
+		}
+	}
+}
+
+func TestFromError(t *testing.T) {
+	if out := FromError(NewError(MsgAccountNotFound)).Localize(Indonesian); out != "Akun tidak ditemukan" {
+		t.Errorf(`FromError(NewError()) = "%v"; expected "Akun tidak ditemukan"`, out)
+	}
+	if out := FromError(errors.New("plain")).Localize(Indonesian); out != "plain" {
+		t.Errorf(`FromError(errors.New()) = "%v"; expected "plain"`, out)
+	}
+	if out := NewError(MsgAccountNotFound).Error(); out != "Account is not found" {
+		t.Errorf(`NewError().Error() = "%v"; expected "Account is not found"`, out)
+	}
+}
+
+func TestLocaleFromAcceptLanguage(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"", English},
+		{"id", Indonesian},
+		{"id-ID,id;q=0.9,en-US;q=0.8,en;q=0.7", Indonesian},
+		{"en-US,en;q=0.9,id;q=0.8", English},
+		{"fr-FR,fr;q=0.9,id;q=0.5", Indonesian},
+		{"en;q=0.5,id;q=0.8", Indonesian},
+		{"id;q=0,en;q=0.1", English},
+		{"fr, de", English},
+	}
+	for _, test := range tests {
+		if out := LocaleFromAcceptLanguage(test.input); out != test.expected {
+			t.Errorf(`LocaleFromAcceptLanguage("%v") = "%v"; expected "%v"`, test.input, out, test.expected)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package i18n
+
+// Defines the message IDs of general request errors.
+const (
+	MsgProcessingFailed     = "request.processingFailed"
+	MsgRequestBodyInvalid   = "request.bodyInvalid"
+	MsgRequestInvalid       = "request.invalid"
+	MsgRequestHeadersEmpty  = "request.headersRequired"
+	MsgRequestHeaderInvalid = "request.headerInvalid"
+	MsgTooManyRequests      = "request.tooMany"
+)
+
+// Defines the message IDs of CAPTCHA errors.
+const (
+	MsgCaptchaRequired = "captcha.required"
+	MsgCaptchaFailed   = "captcha.failed"
+)
+
+// Defines the message IDs of API key errors.
+const (
+	MsgAPIKeyRequired             = "apiKey.required"
+	MsgAPIKeyParseFailed          = "apiKey.parseFailed"
+	MsgAPIKeyFormatInvalid        = "apiKey.formatInvalid"
+	MsgAPIKeyNotFound             = "apiKey.notFound"
+	MsgAPIKeyValidationFailed     = "apiKey.validationFailed"
+	MsgAPIKeyInvalid              = "apiKey.invalid"
+	MsgAPIKeyAppPlatformInvalid   = "apiKey.appPlatformInvalid"
+	MsgAPIKeyAppIdentifierInvalid = "apiKey.appIdentifierInvalid"
+	MsgAPIKeyExpired              = "apiKey.expired"
+	MsgAPIKeyDisabled             = "apiKey.disabled"
+
+	MsgAPIKeySignatureRequired      = "apiKey.signatureRequired"
+	MsgAPIKeySignatureFormatInvalid = "apiKey.signatureFormatInvalid"
+	MsgAPIKeySignatureExpired       = "apiKey.signatureExpired"
+	MsgAPIKeySignatureInvalid       = "apiKey.signatureInvalid"
+	MsgAPIKeySignatureReplayed      = "apiKey.signatureReplayed"
+	MsgAPIKeyScopeNotAllowed        = "apiKey.scopeNotAllowed"
+	MsgAPIKeyDailyQuotaExceeded     = "apiKey.dailyQuotaExceeded"
+	MsgAPIKeyMonthlyQuotaExceeded   = "apiKey.monthlyQuotaExceeded"
+)
+
+// Defines the message IDs of API key management errors.
+const (
+	MsgAPIKeyDomainInvalid      = "apiKey.domainInvalid"
+	MsgAPIKeyIDRequired         = "apiKey.idRequired"
+	MsgAPIKeyPlatformNotAllowed = "apiKey.platformNotAllowed"
+	MsgAPIKeyExpiryInvalid      = "apiKey.expiryInvalid"
+	MsgAPIKeyOverlapInvalid     = "apiKey.overlapInvalid"
+
+	MsgAPIKeySigningPlatformInvalid  = "apiKey.signingPlatformInvalid"
+	MsgAPIKeyScopesInvalid           = "apiKey.scopesInvalid"
+	MsgAPIKeyQuotaInvalid            = "apiKey.quotaInvalid"
+	MsgAPIKeyUsageRangeInvalid       = "apiKey.usageRangeInvalid"
+	MsgAPIKeyUsageGranularityInvalid = "apiKey.usageGranularityInvalid"
+)
+
+// Defines the message IDs of authorization and token errors.
+const (
+	MsgAuthorizationRequired      = "authorization.required"
+	MsgAuthorizationFormatInvalid = "authorization.formatInvalid"
+	MsgAuthorizationTypeInvalid   = "authorization.typeInvalid"
+	MsgCredentialsInvalid         = "authorization.credentialsInvalid"
+	MsgCredentialsParseFailed     = "authorization.credentialsParseFailed"
+	MsgTokenSignatureInvalid      = "token.signatureInvalid"
+	MsgTokenDetailFailed          = "token.detailFailed"
+	MsgTokenParseFailed           = "token.parseFailed"
+	MsgAccessTokenInvalid         = "accessToken.invalid"
+	MsgAccessTokenDeviceInvalid   = "accessToken.deviceInvalid"
+	MsgAccessTokenNotOwner        = "accessToken.notOwner"
+	MsgAccessTokenExpired         = "accessToken.expired"
+	MsgRefreshTokenInvalid        = "refreshToken.invalid"
+	MsgRefreshTokenDeviceInvalid  = "refreshToken.deviceInvalid"
+	MsgRefreshTokenNotOwner       = "refreshToken.notOwner"
+	MsgRefreshTokenExpired        = "refreshToken.expired"
+	MsgLinkTokenInvalid           = "linkToken.invalid"
+	MsgLinkTokenExpired           = "linkToken.expired"
+)
+
+// Defines the message IDs of customer account messages.
+const (
+	MsgAccountSessionNotFound      = "account.sessionNotFound"
+	MsgAccountNotFound             = "account.notFound"
+	MsgAccountCredentialsMismatch  = "account.credentialsMismatch"
+	MsgAccountNotVerified          = "account.notVerified"
+	MsgAccountAlreadyVerified      = "account.alreadyVerified"
+	MsgAccountVerified             = "account.verified"
+	MsgAccountEmailRegistered      = "account.emailRegistered"
+	MsgAccountEmailNotRegistered   = "account.emailNotRegistered"
+	MsgAccountEmailInvalid         = "account.emailInvalid"
+	MsgAccountLoggedOut            = "account.loggedOut"
+	MsgVerificationNotFound        = "verification.notFound"
+	MsgVerificationIDRequired      = "verification.idRequired"
+	MsgVerificationIDInvalid       = "verification.idInvalid"
+	MsgVerificationKeyRequired     = "verification.keyRequired"
+	MsgVerificationKeyInvalid      = "verification.keyInvalid"
+	MsgVerificationCodeRequired    = "verification.codeRequired"
+	MsgVerificationCodeIncorrect   = "verification.codeIncorrect"
+	MsgVerificationEmailChanged    = "verification.emailChanged"
+	MsgResetPasswordNotFound       = "resetPassword.notFound"
+	MsgResetTokenRequired          = "resetPassword.tokenRequired"
+	MsgResetTokenInvalid           = "resetPassword.tokenInvalid"
+	MsgResetTokenIncorrect         = "resetPassword.tokenIncorrect"
+	MsgOTPTooManyAttempts          = "otp.tooManyAttempts"
+	MsgOTPResendCooldown           = "otp.resendCooldown"
+	MsgOTPSendLimitReached         = "otp.sendLimitReached"
+	MsgPasswordChanged             = "password.changed"
+	MsgPasswordChangeFailed        = "password.changeFailed"
+	MsgTOSAccepted                 = "tos.accepted"
+	MsgTOSAlreadyAccepted          = "tos.alreadyAccepted"
+	MsgCreatedTimeRequired         = "account.createdTimeRequired"
+	MsgCreatedTimeInvalid          = "account.createdTimeInvalid"
+	MsgFullNameRequired            = "account.fullNameRequired"
+	MsgEmailRequired               = "account.emailRequired"
+	MsgPasswordRequired            = "password.required"
+	MsgCurrentPasswordRequired     = "password.currentRequired"
+	MsgCurrentPasswordInvalid      = "password.currentInvalid"
+	MsgNewPasswordRequired         = "password.newRequired"
+	MsgPreferencesRequired         = "preference.required"
+	MsgPreferenceUnknown           = "preference.unknown"
+	MsgPreferenceTypeInvalid       = "preference.typeInvalid"
+	MsgPreferenceLocaleUnsupported = "preference.localeUnsupported"
+	MsgPreferenceTimeZoneInvalid   = "preference.timeZoneInvalid"
+	MsgFileNotFound                = "file.notFound"
+	MsgFileURLInvalid              = "file.urlInvalid"
+)
+
+// Defines the message IDs of data format validation.
+const (
+	MsgPhoneEmpty         = "phone.empty"
+	MsgPhoneNotNumeric    = "phone.notNumeric"
+	MsgPhoneLeadingZero   = "phone.leadingZero"
+	MsgPhoneLengthInvalid = "phone.lengthInvalid"
+	MsgEmailEmpty         = "email.empty"
+	MsgEmailInvalid       = "email.invalid"
+	MsgEmailFormatInvalid = "email.formatInvalid"
+	MsgEmailDisposable    = "email.disposable"
+	MsgEmailDomainInvalid = "email.domainInvalid"
+	MsgEmailTypo          = "email.typo"
+	MsgPasswordEmpty      = "password.empty"
+	MsgPasswordLength     = "password.length"
+	MsgPasswordFormat     = "password.format"
+	MsgPasswordMinLength  = "password.minLength"
+	MsgPasswordMaxLength  = "password.maxLength"
+	MsgPasswordLowercase  = "password.lowercase"
+	MsgPasswordUppercase  = "password.uppercase"
+	MsgPasswordNumber     = "password.number"
+	MsgPasswordSpecial    = "password.special"
+	MsgPasswordReused     = "password.reused"
+	MsgPasswordBreached   = "password.breached"
+)
+
+// Defines the message IDs of email subjects.
+const (
+	MsgEmailSubjectVerifyEmailAddress = "emailSubject.verifyEmailAddress"
+	MsgEmailSubjectResetPassword      = "emailSubject.resetPassword"
+	MsgEmailSubjectPasswordChanged    = "emailSubject.passwordChanged"
+)
+
+// Defines the message IDs of the web pages.
+const (
+	MsgWebVerifyEmailTitle                    = "web.verifyEmail.title"
+	MsgWebVerifyEmailDescription              = "web.verifyEmail.description"
+	MsgWebVerifyEmailSubmit                   = "web.verifyEmail.submit"
+	MsgWebVerifyEmailSucceeded                = "web.verifyEmail.succeeded"
+	MsgWebVerifyEmailFailed                   = "web.verifyEmail.failed"
+	MsgWebResetPasswordRequestTitle           = "web.resetPassword.requestTitle"
+	MsgWebResetPasswordRequestDescription     = "web.resetPassword.requestDescription"
+	MsgWebResetPasswordRequestSubmit          = "web.resetPassword.requestSubmit"
+	MsgWebResetPasswordRequestSent            = "web.resetPassword.requestSent"
+	MsgWebResetPasswordRequestSentDescription = "web.resetPassword.requestSentDescription"
+	MsgWebResetPasswordTitle                  = "web.resetPassword.title"
+	MsgWebResetPasswordSubmit                 = "web.resetPassword.submit"
+	MsgWebResetPasswordSucceeded              = "web.resetPassword.succeeded"
+	MsgWebResetPasswordFailed                 = "web.resetPassword.failed"
+	MsgWebRequestNewLink                      = "web.requestNewLink"
+	MsgWebFieldEmail                          = "web.field.email"
+	MsgWebFieldNewPassword                    = "web.field.newPassword"
+	MsgWebFieldConfirmPassword                = "web.field.confirmPassword"
+	MsgWebFieldCaptcha                        = "web.field.captcha"
+	MsgWebPasswordMismatch                    = "web.passwordMismatch"
+)
@@ -0,0 +1,102 @@
+package imagepipeline
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
+	"github.com/jonylim/basego/internal/pkg/common/helper/imagehelper"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+)
+
+// DefaultMaxPixels is the default limit of the source images' pixels, e.g. 8000x5000.
+const DefaultMaxPixels = 40 * 1000 * 1000
+
+// Default returns the default pipeline: a filled 128px thumbnail, a 512px medium and a fullsize image capped
+// at 2048px, preferring WebP if its encoder is registered, with blurhash placeholders.
+func Default() Pipeline {
+	return Pipeline{
+		Variants: []Variant{
+			{Name: "thumb", Width: 128, Height: 128, Mode: imagehelper.ModeFill},
+			{Name: "medium", Width: 512, Height: 512, Mode: imagehelper.ModeFit},
+			{Name: "full", Width: 2048, Height: 2048, Mode: imagehelper.ModeFit},
+		},
+		Formats:   []string{imagehelper.MediaTypeWebP, imagehelper.MediaTypeJPEG},
+		Quality:   imagehelper.DefaultQuality,
+		Blurhash:  true,
+		MaxPixels: DefaultMaxPixels,
+	}
+}
+
+var (
+	current = Default()
+	mutex   sync.RWMutex
+)
+
+// Init loads the pipeline from environment variables.
+func Init() {
+	p := Default()
+	if s := os.Getenv(envvar.Image.Variants); s != "" {
+		variants, err := ParseVariants(s)
+		if err != nil {
+			logger.Println("imagepipeline", fmt.Sprintf("WARN: %s is invalid, using the default variants: %v", envvar.Image.Variants, err))
+		} else {
+			p.Variants = variants
+		}
+	}
+	if s := os.Getenv(envvar.Image.Formats); s != "" {
+		p.Formats = nil
+		for _, mediaType := range strings.Split(s, ",") {
+			if mediaType = strings.TrimSpace(mediaType); mediaType == "" {
+				continue
+			} else if !imagehelper.CanEncode(mediaType) {
+				logger.Println("imagepipeline", fmt.Sprintf("WARN: Format '%s' has no encoder, it's skipped", mediaType))
+			}
+			p.Formats = append(p.Formats, mediaType)
+		}
+	}
+	if s := os.Getenv(envvar.Image.Quality); s != "" {
+		q, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || q < 1 || q > 100 {
+			logger.Println("imagepipeline", fmt.Sprintf("WARN: %s '%s' is invalid, set to '%d' as default", envvar.Image.Quality, s, p.Quality))
+		} else {
+			p.Quality = q
+		}
+	}
+	if s := os.Getenv(envvar.Image.Blurhash); s != "" {
+		b, err := strconv.ParseBool(strings.TrimSpace(s))
+		if err != nil {
+			logger.Println("imagepipeline", fmt.Sprintf("WARN: %s '%s' is invalid, set to '%v' as default", envvar.Image.Blurhash, s, p.Blurhash))
+		} else {
+			p.Blurhash = b
+		}
+	}
+	if s := os.Getenv(envvar.Image.MaxPixels); s != "" {
+		n, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || n < 0 {
+			logger.Println("imagepipeline", fmt.Sprintf("WARN: %s '%s' is invalid, set to '%d' as default", envvar.Image.MaxPixels, s, p.MaxPixels))
+		} else {
+			p.MaxPixels = n
+		}
+	}
+	logger.Println("imagepipeline", fmt.Sprintf("Variants = %v, Formats = %v, Quality = %d, Blurhash = %v, MaxPixels = %d",
+		p.Variants, p.Formats, p.Quality, p.Blurhash, p.MaxPixels))
+	Set(p)
+}
+
+// Get returns the active pipeline.
+func Get() Pipeline {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return current
+}
+
+// Set replaces the active pipeline.
+func Set(p Pipeline) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	current = p
+}
@@ -0,0 +1,198 @@
+// Package imagepipeline processes the uploaded images into their named variants, e.g. a thumbnail and a fullsize
+// image, upright and without their metadata.
+package imagepipeline
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jonylim/basego/internal/pkg/common/helper/imagehelper"
+)
+
+// Variant is a named variant of the processed images.
+type Variant struct {
+	// Name is the suffix of the variants' filepaths, e.g. "thumb".
+	Name string
+
+	// Width and Height bound the variant by Mode, where 0 is unbounded. The images aren't scaled up.
+	Width, Height int
+	Mode          string
+
+	// Quality of the lossy formats from 1 to 100, 0 is the pipeline's quality.
+	Quality int
+}
+
+// Pipeline processes the images into their variants.
+type Pipeline struct {
+	Variants []Variant
+
+	// Formats are the preferred media types of the variants. The first one which has an encoder and keeps
+	// the image's transparency is used, e.g. "image/webp" once its encoder is registered, else PNG or JPEG.
+	Formats []string
+
+	Quality  int
+	Blurhash bool
+
+	// MaxPixels limits the source images' width times height, so an image declaring a huge size can't use up
+	// the memory when it's decoded. 0 is unlimited.
+	MaxPixels int
+}
+
+// Image is a processed image.
+type Image struct {
+	// Width and Height are the upright source image's size.
+	Width, Height int
+
+	// Blurhash is the placeholder of the image, empty if the pipeline doesn't make it.
+	Blurhash string
+
+	Variants []ImageVariant
+}
+
+// ImageVariant is an encoded variant of a processed image.
+type ImageVariant struct {
+	Name      string
+	MediaType string
+	FileExt   string
+	Width     int
+	Height    int
+	Data      []byte
+}
+
+// ErrInvalidImage is returned when the image can't be decoded.
+var ErrInvalidImage = errors.New("Image is invalid")
+
+// ErrImageTooLarge is returned when the image has more pixels than the pipeline's MaxPixels.
+var ErrImageTooLarge = errors.New("Image is too large")
+
+// Process decodes an image, rotates it upright by its EXIF orientation, and encodes its variants.
+// The variants are newly encoded, so they don't have the source's metadata, e.g. its location.
+func (p Pipeline) Process(data []byte) (res Image, err error) {
+	// Check the size declared by the header before decoding the pixels.
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return res, ErrInvalidImage
+	} else if p.MaxPixels > 0 && int64(cfg.Width)*int64(cfg.Height) > int64(p.MaxPixels) {
+		return res, ErrImageTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return res, ErrInvalidImage
+	}
+	img = imagehelper.ApplyOrientation(img, imagehelper.GetOrientation(data))
+	res.Width, res.Height = img.Bounds().Dx(), img.Bounds().Dy()
+
+	mediaType := p.SelectFormat(img)
+	for _, v := range p.Variants {
+		resized := imagehelper.Resize(img, v.Width, v.Height, v.Mode)
+		quality := v.Quality
+		if quality == 0 {
+			quality = p.Quality
+		}
+		encoded, err := imagehelper.EncodeImageWithQuality(resized, mediaType, quality)
+		if err != nil {
+			return res, err
+		}
+		res.Variants = append(res.Variants, ImageVariant{
+			Name:      v.Name,
+			MediaType: mediaType,
+			FileExt:   imagehelper.GetFileExt(mediaType),
+			Width:     resized.Bounds().Dx(),
+			Height:    resized.Bounds().Dy(),
+			Data:      encoded,
+		})
+	}
+	if p.Blurhash {
+		if res.Blurhash, err = imagehelper.EncodeBlurhash(img, imagehelper.BlurhashXComponents, imagehelper.BlurhashYComponents); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// SelectFormat returns the first preferred format which can be encoded, skipping JPEG if the image has transparency.
+// It falls back to PNG for the transparent images and JPEG for the others.
+func (p Pipeline) SelectFormat(img image.Image) string {
+	transparent := false
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		transparent = !o.Opaque()
+	}
+	for _, mediaType := range p.Formats {
+		if imagehelper.CanEncode(mediaType) && !(transparent && mediaType == imagehelper.MediaTypeJPEG) {
+			return mediaType
+		}
+	}
+	if transparent {
+		return imagehelper.MediaTypePNG
+	}
+	return imagehelper.MediaTypeJPEG
+}
+
+// Variant returns the variant by name.
+func (p Pipeline) Variant(name string) (Variant, bool) {
+	for _, v := range p.Variants {
+		if v.Name == name {
+			return v, true
+		}
+	}
+	return Variant{}, false
+}
+
+var variantNameRegex = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// ParseVariants parses the variants separated by commas, each "name:size[:mode][:quality]" where the size is
+// "WIDTHxHEIGHT" or a number bounding both, e.g. "thumb:128:fill,medium:512,full:2048x2048:fit:90".
+// The mode is fit if it's omitted.
+func ParseVariants(s string) ([]Variant, error) {
+	variants := make([]Variant, 0)
+	names := make(map[string]bool)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.Split(item, ":")
+		if len(parts) < 2 || len(parts) > 4 {
+			return nil, fmt.Errorf("variant '%s' isn't name:size[:mode][:quality]", item)
+		}
+		v := Variant{Name: parts[0], Mode: imagehelper.ModeFit}
+		if !variantNameRegex.MatchString(v.Name) {
+			return nil, fmt.Errorf("variant name '%s' isn't lowercase letters and digits", v.Name)
+		} else if names[v.Name] {
+			return nil, fmt.Errorf("variant name '%s' is duplicated", v.Name)
+		}
+		names[v.Name] = true
+
+		size := strings.SplitN(parts[1], "x", 2)
+		var err error
+		if v.Width, err = strconv.Atoi(size[0]); err != nil || v.Width < 0 {
+			return nil, fmt.Errorf("variant '%s' size is invalid", item)
+		}
+		v.Height = v.Width
+		if len(size) == 2 {
+			if v.Height, err = strconv.Atoi(size[1]); err != nil || v.Height < 0 {
+				return nil, fmt.Errorf("variant '%s' size is invalid", item)
+			}
+		}
+		if len(parts) >= 3 {
+			if v.Mode = parts[2]; v.Mode != imagehelper.ModeFit && v.Mode != imagehelper.ModeFill {
+				return nil, fmt.Errorf("variant '%s' mode isn't fit or fill", item)
+			}
+		}
+		if len(parts) == 4 {
+			if v.Quality, err = strconv.Atoi(parts[3]); err != nil || v.Quality < 1 || v.Quality > 100 {
+				return nil, fmt.Errorf("variant '%s' quality isn't from 1 to 100", item)
+			}
+		}
+		variants = append(variants, v)
+	}
+	if len(variants) == 0 {
+		return nil, errors.New("no variants")
+	}
+	return variants, nil
+}
@@ -0,0 +1,127 @@
+package imagepipeline
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"reflect"
+	"testing"
+
+	"github.com/jonylim/basego/internal/pkg/common/helper/imagehelper"
+)
+
+func TestParseVariants(t *testing.T) {
+	res, err := ParseVariants(" thumb:128:fill, medium:512 ,,full:2048x1024:fit:90")
+	expected := []Variant{
+		{Name: "thumb", Width: 128, Height: 128, Mode: imagehelper.ModeFill},
+		{Name: "medium", Width: 512, Height: 512, Mode: imagehelper.ModeFit},
+		{Name: "full", Width: 2048, Height: 1024, Mode: imagehelper.ModeFit, Quality: 90},
+	}
+	if err != nil || !reflect.DeepEqual(res, expected) {
+		t.Errorf("ParseVariants() = %+v, %v; expected %+v", res, err, expected)
+	}
+
+	for _, s := range []string{"", "thumb", "Thumb:128", "a-b:128", "thumb:128,thumb:256", "thumb:x", "thumb:128x-1",
+		"thumb:128:crop", "thumb:128:fit:0", "thumb:128:fit:101", "thumb:128:fit:90:1"} {
+		if _, err := ParseVariants(s); err == nil {
+			t.Errorf("ParseVariants(%q) = nil error; expected an error", s)
+		}
+	}
+}
+
+// rotatedJPEG encodes a JPEG of the size with an Exif segment having the orientation.
+func rotatedJPEG(t *testing.T, width, height int, orientation uint16) []byte {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height)), nil); err != nil {
+		t.Fatal(err)
+	}
+	tiff := make([]byte, 26)
+	copy(tiff, "MM\x00\x2a\x00\x00\x00\x08\x00\x01\x01\x12\x00\x03\x00\x00\x00\x01")
+	binary.BigEndian.PutUint16(tiff[18:], orientation)
+	segment := append([]byte{0xFF, 0xE1, 0, 0}, "Exif\x00\x00"...)
+	segment = append(segment, tiff...)
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(segment)-2))
+	data := buf.Bytes()
+	return append(append(append([]byte{}, data[:2]...), segment...), data[2:]...)
+}
+
+func TestProcess(t *testing.T) {
+	p := Pipeline{
+		Variants: []Variant{
+			{Name: "thumb", Width: 32, Height: 32, Mode: imagehelper.ModeFill},
+			{Name: "full", Width: 100, Height: 100, Mode: imagehelper.ModeFit},
+		},
+		Formats:  []string{imagehelper.MediaTypeWebP, imagehelper.MediaTypeJPEG},
+		Quality:  80,
+		Blurhash: true,
+	}
+
+	// The 200x100 image is rotated upright to 100x200, and WebP is skipped without an encoder.
+	res, err := p.Process(rotatedJPEG(t, 200, 100, 6))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Width != 100 || res.Height != 200 || len(res.Blurhash) != 28 || len(res.Variants) != 2 {
+		t.Fatalf("Process() = %dx%d, %q, %d variants; expected 100x200 with a blurhash and 2 variants",
+			res.Width, res.Height, res.Blurhash, len(res.Variants))
+	}
+	for i, expected := range []image.Point{image.Pt(32, 32), image.Pt(50, 100)} {
+		v := res.Variants[i]
+		cfg, format, err := image.DecodeConfig(bytes.NewReader(v.Data))
+		if err != nil || format != "jpeg" || v.MediaType != imagehelper.MediaTypeJPEG || v.FileExt != "jpg" ||
+			image.Pt(v.Width, v.Height) != expected || image.Pt(cfg.Width, cfg.Height) != expected {
+			t.Errorf("Process() variant %s = %s %dx%d (%s %dx%d, %v); expected image/jpeg %v",
+				v.Name, v.MediaType, v.Width, v.Height, format, cfg.Width, cfg.Height, err, expected)
+		}
+		// The variants are newly encoded, without the source's Exif segment.
+		if imagehelper.GetOrientation(v.Data) != 1 || bytes.Contains(v.Data, []byte("Exif")) {
+			t.Errorf("Process() variant %s keeps the Exif metadata", v.Name)
+		}
+	}
+
+	if _, err := p.Process([]byte("not an image")); err != ErrInvalidImage {
+		t.Errorf("Process() of an invalid image error = %v; expected %v", err, ErrInvalidImage)
+	}
+
+	// The image's declared size is checked before decoding it.
+	p.MaxPixels = 200*100 - 1
+	if _, err := p.Process(rotatedJPEG(t, 200, 100, 1)); err != ErrImageTooLarge {
+		t.Errorf("Process() of an image over MaxPixels error = %v; expected %v", err, ErrImageTooLarge)
+	}
+	header := append([]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR"), 0, 0, 0xC3, 0x50, 0, 0, 0xC3, 0x50, 8, 6, 0, 0, 0)
+	header = append(header, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(header[len(header)-4:], crc32.ChecksumIEEE(header[12:len(header)-4]))
+	p.MaxPixels = DefaultMaxPixels
+	if _, err := p.Process(header); err != ErrImageTooLarge {
+		t.Errorf("Process() of a 50000x50000 PNG header error = %v; expected %v", err, ErrImageTooLarge)
+	}
+}
+
+func TestSelectFormat(t *testing.T) {
+	opaque := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	for i := range opaque.Pix {
+		opaque.Pix[i] = 255
+	}
+	transparent := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	transparent.Set(0, 0, color.NRGBA{255, 0, 0, 255})
+
+	var tests = []struct {
+		formats  []string
+		img      image.Image
+		expected string
+	}{
+		{[]string{imagehelper.MediaTypeWebP, imagehelper.MediaTypeJPEG}, opaque, imagehelper.MediaTypeJPEG},
+		{[]string{imagehelper.MediaTypeJPEG}, transparent, imagehelper.MediaTypePNG},
+		{[]string{imagehelper.MediaTypeJPEG, imagehelper.MediaTypeGIF}, transparent, imagehelper.MediaTypeGIF},
+		{[]string{imagehelper.MediaTypeAVIF}, opaque, imagehelper.MediaTypeJPEG},
+		{nil, transparent, imagehelper.MediaTypePNG},
+	}
+	for _, test := range tests {
+		if res := (Pipeline{Formats: test.formats}).SelectFormat(test.img); res != test.expected {
+			t.Errorf("SelectFormat(%v) = %s; expected %s", test.formats, res, test.expected)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package passwordpolicy
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// breachedPrefixLength is the length of the SHA-1 hash prefix used as the file name.
+const breachedPrefixLength = 5
+
+// IsBreached checks if a password exists in the breached password list.
+// Only the file for the hash prefix is read, the same k-anonymity range format used by Have I Been Pwned.
+func (p Policy) IsBreached(plain string) (bool, error) {
+	if p.BreachedDirPath == "" {
+		return false, nil
+	}
+	sum := sha1.Sum([]byte(plain))
+	h := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := h[:breachedPrefixLength], h[breachedPrefixLength:]
+
+	f, err := os.Open(filepath.Join(p.BreachedDirPath, prefix))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.IndexByte(line, ':'); i >= 0 {
+			line = line[:i]
+		}
+		if strings.EqualFold(line, suffix) {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
@@ -0,0 +1,211 @@
+package passwordpolicy
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
+	"github.com/jonylim/basego/internal/pkg/common/crypto/password"
+	"github.com/jonylim/basego/internal/pkg/common/helper"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+)
+
+// Policy defines the rules a password must satisfy.
+type Policy struct {
+	MinLength        int
+	MaxLength        int
+	RequireLowercase bool
+	RequireUppercase bool
+	RequireNumber    bool
+	RequireSpecial   bool
+
+	// HistoryCount is the number of previous passwords, including the current one, that can't be reused.
+	HistoryCount int
+
+	// MaxAgeDays is the number of days before a password must be changed. Zero means passwords never expire.
+	MaxAgeDays int
+
+	// BreachedDirPath is the directory containing the breached password hash lists.
+	// Each file is named by the first 5 characters of a password's SHA-1 hash in uppercase hex,
+	// and contains lines of "SUFFIX:COUNT" with the remaining 35 characters of the hash.
+	BreachedDirPath string
+}
+
+// HashedPassword is a password hash and its salt.
+type HashedPassword struct {
+	Hash, Salt string
+}
+
+var (
+	current = Default()
+	mutex   sync.RWMutex
+)
+
+// Default returns the default password policy.
+func Default() Policy {
+	return Policy{
+		MinLength:        helper.PasswordMinLength,
+		MaxLength:        helper.PasswordMaxLength,
+		RequireLowercase: true,
+		RequireUppercase: true,
+		RequireNumber:    true,
+		RequireSpecial:   true,
+	}
+}
+
+// Init loads the password policy from environment variables.
+func Init() {
+	p := Default()
+	p.MinLength = getEnvInt(envvar.PasswordPolicy.MinLength, p.MinLength)
+	p.MaxLength = getEnvInt(envvar.PasswordPolicy.MaxLength, p.MaxLength)
+	p.RequireLowercase = getEnvBool(envvar.PasswordPolicy.RequireLowercase, p.RequireLowercase)
+	p.RequireUppercase = getEnvBool(envvar.PasswordPolicy.RequireUppercase, p.RequireUppercase)
+	p.RequireNumber = getEnvBool(envvar.PasswordPolicy.RequireNumber, p.RequireNumber)
+	p.RequireSpecial = getEnvBool(envvar.PasswordPolicy.RequireSpecial, p.RequireSpecial)
+	p.HistoryCount = getEnvInt(envvar.PasswordPolicy.HistoryCount, p.HistoryCount)
+	p.MaxAgeDays = getEnvInt(envvar.PasswordPolicy.MaxAgeDays, p.MaxAgeDays)
+	p.BreachedDirPath = os.Getenv(envvar.PasswordPolicy.BreachedDirPath)
+
+	if p.MinLength < 1 {
+		logger.Println("passwordpolicy", fmt.Sprintf("WARN: MinLength %d is invalid, set to 1", p.MinLength))
+		p.MinLength = 1
+	}
+	if p.MaxLength < p.MinLength {
+		logger.Println("passwordpolicy", fmt.Sprintf("WARN: MaxLength %d is less than MinLength, set to %d", p.MaxLength, p.MinLength))
+		p.MaxLength = p.MinLength
+	}
+	if p.BreachedDirPath == "" {
+		logger.Println("passwordpolicy", "WARN: BreachedDirPath is empty, breached password check is disabled")
+	}
+	logger.Println("passwordpolicy", fmt.Sprintf("Length = %d-%d, Lowercase = %v, Uppercase = %v, Number = %v, Special = %v, HistoryCount = %d, MaxAgeDays = %d, BreachedDirPath = '%s'",
+		p.MinLength, p.MaxLength, p.RequireLowercase, p.RequireUppercase, p.RequireNumber, p.RequireSpecial, p.HistoryCount, p.MaxAgeDays, p.BreachedDirPath))
+
+	Set(p)
+}
+
+// Get returns the active password policy.
+func Get() Policy {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return current
+}
+
+// Set replaces the active password policy.
+func Set(p Policy) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	current = p
+}
+
+// Validate checks a new password against the format rules and the breached password list.
+func (p Policy) Validate(plain string) Violations {
+	violations := p.ValidateFormat(plain)
+	if len(violations) != 0 {
+		return violations
+	}
+	breached, err := p.IsBreached(plain)
+	if err != nil {
+		// Don't block the user if the list can't be read.
+		logger.Error("passwordpolicy", logger.FromError(err))
+	} else if breached {
+		violations = append(violations, newViolation(RuleBreached, 0))
+	}
+	return violations
+}
+
+// ValidateFormat checks a password's length and character classes.
+func (p Policy) ValidateFormat(plain string) Violations {
+	if plain == "" {
+		return Violations{newViolation(RuleRequired, 0)}
+	}
+	violations := make(Violations, 0)
+	if l := utf8.RuneCountInString(plain); l < p.MinLength {
+		violations = append(violations, newViolation(RuleMinLength, p.MinLength))
+	} else if l > p.MaxLength {
+		violations = append(violations, newViolation(RuleMaxLength, p.MaxLength))
+	}
+	countUpper, countLower, countNumber, countSpecial := 0, 0, 0, 0
+	for _, c := range plain {
+		switch {
+		case unicode.IsDigit(c):
+			countNumber++
+		case unicode.IsLower(c):
+			countLower++
+		case unicode.IsUpper(c):
+			countUpper++
+		default:
+			countSpecial++
+		}
+	}
+	if p.RequireLowercase && countLower == 0 {
+		violations = append(violations, newViolation(RuleLowercase, 1))
+	}
+	if p.RequireUppercase && countUpper == 0 {
+		violations = append(violations, newViolation(RuleUppercase, 1))
+	}
+	if p.RequireNumber && countNumber == 0 {
+		violations = append(violations, newViolation(RuleNumber, 1))
+	}
+	if p.RequireSpecial && countSpecial == 0 {
+		violations = append(violations, newViolation(RuleSpecial, 1))
+	}
+	return violations
+}
+
+// CheckReuse checks if a new password matches one of the previous passwords.
+// The previous passwords must be sorted from the newest, only the first HistoryCount items are checked.
+func (p Policy) CheckReuse(plain string, previous []HashedPassword) Violations {
+	if p.HistoryCount <= 0 {
+		return nil
+	}
+	for i, prev := range previous {
+		if i >= p.HistoryCount {
+			break
+		}
+		if password.HashWithSalt(plain, prev.Salt) == prev.Hash {
+			return Violations{newViolation(RuleReused, p.HistoryCount)}
+		}
+	}
+	return nil
+}
+
+// IsExpired checks if a password changed at the specified Unix milliseconds has exceeded the maximum age.
+func (p Policy) IsExpired(changedMillis int64, now time.Time) bool {
+	if p.MaxAgeDays <= 0 || changedMillis <= 0 {
+		return false
+	}
+	expiry := helper.FromUnixMillisecond(changedMillis).AddDate(0, 0, p.MaxAgeDays)
+	return !now.Before(expiry)
+}
+
+func getEnvInt(key string, def int) int {
+	s := os.Getenv(key)
+	if s == "" {
+		return def
+	}
+	n, err := helper.StringToInt(s)
+	if err != nil {
+		logger.Println("passwordpolicy", fmt.Sprintf("WARN: %s '%s' is invalid, set to '%d' as default", key, s, def))
+		return def
+	}
+	return n
+}
+
+func getEnvBool(key string, def bool) bool {
+	s := os.Getenv(key)
+	if s == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(strings.TrimSpace(s))
+	if err != nil {
+		logger.Println("passwordpolicy", fmt.Sprintf("WARN: %s '%s' is invalid, set to '%v' as default", key, s, def))
+		return def
+	}
+	return b
+}
@@ -0,0 +1,139 @@
+package passwordpolicy
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/common/crypto/password"
+	"github.com/jonylim/basego/internal/pkg/common/helper"
+)
+
+func TestValidateFormat(t *testing.T) {
+	p := Default()
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"", "required"},
+		{"Aa@12", "minLength"},
+		{"Aa@123Aa@123Aa@123Aa@123Aa@123!?x", "maxLength"},
+		{"Aa@123", ""},
+		{"AA@123", "lowercase"},
+		{"ab@123", "uppercase"},
+		{"Abc123", "special"},
+		{"A@bbcc", "number"},
+		{"abcdef", "uppercase,number,special"},
+		{"Ébc@12", ""},
+	}
+	for _, test := range tests {
+		out := strings.Join(p.ValidateFormat(test.input).Rules(), ",")
+		if out != test.expected {
+			t.Errorf(`ValidateFormat("%v") = "%v"; expected "%v"`, test.input, out, test.expected)
+		}
+	}
+}
+
+func TestValidateFormatWithoutCharacterClasses(t *testing.T) {
+	p := Policy{MinLength: 8, MaxLength: 64}
+	var tests = []struct {
+		input    string
+		expected string
+	}{
+		{"abcdefg", "minLength"},
+		{"abcdefgh", ""},
+		{"12345678", ""},
+	}
+	for _, test := range tests {
+		out := strings.Join(p.ValidateFormat(test.input).Rules(), ",")
+		if out != test.expected {
+			t.Errorf(`ValidateFormat("%v") = "%v"; expected "%v"`, test.input, out, test.expected)
+		}
+	}
+}
+
+func TestCheckReuse(t *testing.T) {
+	salt := password.GenerateSalt()
+	previous := []HashedPassword{
+		{password.HashWithSalt("Newest@1", salt), salt},
+		{password.HashWithSalt("Middle@1", salt), salt},
+		{password.HashWithSalt("Oldest@1", salt), salt},
+	}
+	var tests = []struct {
+		historyCount int
+		input        string
+		expected     bool
+	}{
+		{0, "Newest@1", false},
+		{1, "Newest@1", true},
+		{1, "Middle@1", false},
+		{2, "Middle@1", true},
+		{2, "Oldest@1", false},
+		{5, "Oldest@1", true},
+		{5, "Another@1", false},
+	}
+	for _, test := range tests {
+		p := Policy{HistoryCount: test.historyCount}
+		if out := len(p.CheckReuse(test.input, previous)) != 0; out != test.expected {
+			t.Errorf(`CheckReuse("%v") with HistoryCount %d = %v; expected %v`, test.input, test.historyCount, out, test.expected)
+		}
+	}
+}
+
+func TestIsExpired(t *testing.T) {
+	now := time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC)
+	var tests = []struct {
+		maxAgeDays int
+		changed    time.Time
+		expected   bool
+	}{
+		{0, now.AddDate(-5, 0, 0), false},
+		{90, now.AddDate(0, 0, -89), false},
+		{90, now.AddDate(0, 0, -90), true},
+		{90, now.AddDate(0, 0, -91), true},
+	}
+	for _, test := range tests {
+		p := Policy{MaxAgeDays: test.maxAgeDays}
+		if out := p.IsExpired(helper.UnixMillisecond(test.changed), now); out != test.expected {
+			t.Errorf(`IsExpired(%v) with MaxAgeDays %d = %v; expected %v`, test.changed, test.maxAgeDays, out, test.expected)
+		}
+	}
+	if out := (Policy{MaxAgeDays: 1}).IsExpired(0, now); out {
+		t.Errorf(`IsExpired(0) = %v; expected false`, out)
+	}
+}
+
+func TestIsBreached(t *testing.T) {
+	dir, err := ioutil.TempDir("", "passwordpolicy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// SHA-1 of "password" is 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8.
+	content := "003D68EB55068C33ACE09247EE4C639306B:3\r\n1E4C9B93F3F0682250B6CF8331B7EE68FD8:3861493\r\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "5BAA6"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := Policy{BreachedDirPath: dir}
+	var tests = []struct {
+		input    string
+		expected bool
+	}{
+		{"password", true},
+		{"Password", false},
+		{"Aa@123", false},
+	}
+	for _, test := range tests {
+		out, err := p.IsBreached(test.input)
+		if err != nil {
+			t.Errorf(`IsBreached("%v") error: %v`, test.input, err)
+		} else if out != test.expected {
+			t.Errorf(`IsBreached("%v") = %v; expected %v`, test.input, out, test.expected)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package passwordpolicy
+
+import "github.com/jonylim/basego/internal/pkg/common/i18n"
+
+// Defines rule names of password policy violations.
+const (
+	RuleRequired  = "required"
+	RuleMinLength = "minLength"
+	RuleMaxLength = "maxLength"
+	RuleLowercase = "lowercase"
+	RuleUppercase = "uppercase"
+	RuleNumber    = "number"
+	RuleSpecial   = "special"
+	RuleReused    = "reused"
+	RuleBreached  = "breached"
+)
+
+var ruleMessageIDs = map[string]string{
+	RuleRequired:  i18n.MsgPasswordEmpty,
+	RuleMinLength: i18n.MsgPasswordMinLength,
+	RuleMaxLength: i18n.MsgPasswordMaxLength,
+	RuleLowercase: i18n.MsgPasswordLowercase,
+	RuleUppercase: i18n.MsgPasswordUppercase,
+	RuleNumber:    i18n.MsgPasswordNumber,
+	RuleSpecial:   i18n.MsgPasswordSpecial,
+	RuleReused:    i18n.MsgPasswordReused,
+	RuleBreached:  i18n.MsgPasswordBreached,
+}
+
+// Violation describes a password policy rule which is not satisfied.
+type Violation struct {
+	Rule    string `json:"rule"`
+	Limit   int    `json:"limit"`
+	Message string `json:"message"`
+
+	msg i18n.Message
+}
+
+// Violations is a list of password policy violations.
+type Violations []Violation
+
+// Message returns the message of the first violation.
+func (v Violations) Message() i18n.Message {
+	if len(v) == 0 {
+		return i18n.Message{}
+	}
+	return v[0].msg
+}
+
+// Rules returns the rule names of the violations.
+func (v Violations) Rules() []string {
+	rules := make([]string, len(v))
+	for i, it := range v {
+		rules[i] = it.Rule
+	}
+	return rules
+}
+
+// Localize returns a copy of the violations with the messages in a locale.
+func (v Violations) Localize(locale string) Violations {
+	res := make(Violations, len(v))
+	for i, it := range v {
+		it.Message = it.msg.Localize(locale)
+		res[i] = it
+	}
+	return res
+}
+
+func newViolation(rule string, limit int) Violation {
+	msg := i18n.NewMessageWithParams(ruleMessageIDs[rule], i18n.Params{"limit": limit})
+	return Violation{rule, limit, msg.String(), msg}
+}
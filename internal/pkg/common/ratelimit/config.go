@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+)
+
+// Config is the rate limiter's configuration.
+type Config struct {
+	Enabled bool
+	Rules   Rules
+}
+
+var (
+	current = Config{Enabled: false}
+	mutex   sync.RWMutex
+)
+
+// Init loads the configuration from environment variables.
+// The rules from the environment variable are added after the defaults, so they override the defaults of the same scope.
+func Init(defaults Rules) {
+	c := Config{Enabled: true, Rules: append(Rules{}, defaults...)}
+	if s := os.Getenv(envvar.RateLimit.Enabled); s != "" {
+		b, err := strconv.ParseBool(strings.TrimSpace(s))
+		if err != nil {
+			logger.Println("ratelimit", fmt.Sprintf("WARN: %s '%s' is invalid, set to 'true' as default", envvar.RateLimit.Enabled, s))
+		} else {
+			c.Enabled = b
+		}
+	}
+	if s := os.Getenv(envvar.RateLimit.Rules); s != "" {
+		rules, err := ParseRules(s)
+		if err != nil {
+			logger.Println("ratelimit", fmt.Sprintf("WARN: %s is invalid, using the default rules: %v", envvar.RateLimit.Rules, err))
+		} else {
+			c.Rules = append(c.Rules, rules...)
+		}
+	}
+	logger.Println("ratelimit", fmt.Sprintf("Enabled = %v, Rules = %d", c.Enabled, len(c.Rules)))
+	Set(c)
+}
+
+// Get returns the active configuration.
+func Get() Config {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return current
+}
+
+// Set replaces the active configuration.
+func Set(c Config) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	current = c
+}
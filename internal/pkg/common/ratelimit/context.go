@@ -0,0 +1,28 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+)
+
+// AuthenticatedCheck counts a request against the limits of an identity, e.g. ByAPIKey, once the request is
+// authenticated as it. The boolean is false if a limit is exceeded and the response has been sent.
+type AuthenticatedCheck func(by, id string) bool
+
+type contextKey struct{}
+
+// WithAuthenticatedCheck returns the request checking its authenticated identities with the function.
+// The identities are only known once validated, or anyone could use up the limits of another's API key or account.
+func WithAuthenticatedCheck(r *http.Request, check AuthenticatedCheck) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), contextKey{}, check))
+}
+
+// CheckAuthenticated counts a request against the limits of an identity it's authenticated as.
+// The boolean is false if a limit is exceeded and the response has been sent. It's true if the request isn't limited.
+func CheckAuthenticated(ctx context.Context, by, id string) bool {
+	check, _ := ctx.Value(contextKey{}).(AuthenticatedCheck)
+	if check == nil || id == "" {
+		return true
+	}
+	return check(by, id)
+}
@@ -0,0 +1,110 @@
+// Package ratelimit provides a distributed rate limiter backed by Redis.
+//
+// It uses a sliding window counter: the requests are counted in fixed windows, and the count of the
+// previous window is weighted by how much of it still overlaps the sliding window. This needs only
+// 2 counters per key regardless of the limit.
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// Limit is the maximum number of requests allowed in a window.
+type Limit struct {
+	Requests int
+	Window   time.Duration
+}
+
+// String returns the limit in the config format, e.g. "60/1m0s".
+func (l Limit) String() string {
+	return fmt.Sprintf("%d/%v", l.Requests, l.Window)
+}
+
+// Result is the result of a rate limit check.
+type Result struct {
+	Allowed   bool
+	Limit     Limit
+	Remaining int
+
+	// Reset is the duration until the current window ends.
+	Reset time.Duration
+
+	// RetryAfter is the duration until a request would be allowed again. Zero if the request is allowed.
+	RetryAfter time.Duration
+}
+
+const keyPrefix = "rateLimit"
+
+// script increments the counter of the current window if the request is allowed.
+// KEYS[1] is the current window's key, KEYS[2] is the previous window's key.
+// ARGV[1] is the limit, ARGV[2] is the previous window's weight, ARGV[3] is the keys' TTL in milliseconds.
+// It returns {allowed, current count, previous count}.
+var script = redis.NewScript(2, `
+local curr = tonumber(redis.call("GET", KEYS[1]) or "0")
+local prev = tonumber(redis.call("GET", KEYS[2]) or "0")
+if prev * tonumber(ARGV[2]) + curr + 1 > tonumber(ARGV[1]) then
+	return {0, curr, prev}
+end
+curr = redis.call("INCR", KEYS[1])
+redis.call("PEXPIRE", KEYS[1], ARGV[3])
+return {1, curr, prev}
+`)
+
+// Allow checks if a request identified by a key is allowed under a limit, and counts it if allowed.
+func Allow(conn redis.Conn, key string, limit Limit, now time.Time) (Result, error) {
+	windowMillis := int64(limit.Window / time.Millisecond)
+	if limit.Requests <= 0 || windowMillis <= 0 {
+		return Result{Allowed: true, Limit: limit, Remaining: math.MaxInt32}, nil
+	}
+	nowMillis := now.UnixNano() / int64(time.Millisecond)
+	index := nowMillis / windowMillis
+	elapsed := nowMillis - index*windowMillis
+	weight := previousWeight(elapsed, windowMillis)
+
+	values, err := redis.Int64s(script.Do(conn,
+		fmt.Sprintf("%s:%s:%d", keyPrefix, key, index),
+		fmt.Sprintf("%s:%s:%d", keyPrefix, key, index-1),
+		limit.Requests, weight, windowMillis*2))
+	if err != nil {
+		return Result{Allowed: true, Limit: limit}, err
+	}
+	return newResult(values[0] == 1, values[1], values[2], limit, elapsed, windowMillis), nil
+}
+
+// previousWeight returns how much of the previous window overlaps the sliding window.
+func previousWeight(elapsedMillis, windowMillis int64) float64 {
+	return float64(windowMillis-elapsedMillis) / float64(windowMillis)
+}
+
+// newResult calculates the result from the counts of the current and previous windows.
+func newResult(allowed bool, curr, prev int64, limit Limit, elapsedMillis, windowMillis int64) Result {
+	estimate := float64(prev)*previousWeight(elapsedMillis, windowMillis) + float64(curr)
+	res := Result{
+		Allowed:   allowed,
+		Limit:     limit,
+		Remaining: int(math.Max(0, math.Floor(float64(limit.Requests)-estimate))),
+		Reset:     time.Duration(windowMillis-elapsedMillis) * time.Millisecond,
+	}
+	if allowed {
+		return res
+	}
+
+	// Find when the weighted previous count drops enough for 1 more request.
+	var waitMillis int64
+	if float64(curr)+1 > float64(limit.Requests) || prev == 0 {
+		// Only the next window can allow it.
+		waitMillis = windowMillis - elapsedMillis
+	} else {
+		maxWeight := (float64(limit.Requests) - float64(curr) - 1) / float64(prev)
+		waitMillis = int64(math.Ceil(float64(windowMillis)*(1-maxWeight))) - elapsedMillis
+	}
+	if waitMillis < 1 {
+		waitMillis = 1
+	}
+	res.RetryAfter = time.Duration(waitMillis) * time.Millisecond
+	return res
+}
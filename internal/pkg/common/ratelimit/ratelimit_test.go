@@ -0,0 +1,120 @@
+package ratelimit
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseRules(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected Rules
+		isError  bool
+	}{
+		{"", Rules{}, false},
+		{"*:ip=300/1m", Rules{{"*", ByIP, Limit{300, time.Minute}}}, false},
+		{" client/register:ip=10/1h , account:account=0/1s", Rules{
+			{"client/register", ByIP, Limit{10, time.Hour}},
+			{"account", ByAccount, Limit{0, time.Second}},
+		}, false},
+		{"*:ip", nil, true},
+		{"*=10/1m", nil, true},
+		{"*:user=10/1m", nil, true},
+		{"*:ip=-1/1m", nil, true},
+		{"*:ip=10", nil, true},
+		{"*:ip=10/500ms", nil, true},
+	}
+	for _, test := range tests {
+		out, err := ParseRules(test.input)
+		if (err != nil) != test.isError {
+			t.Errorf(`ParseRules("%v") error = %v; expected error %v`, test.input, err, test.isError)
+			continue
+		}
+		if len(out) != len(test.expected) {
+			t.Errorf(`ParseRules("%v") = %v; expected %v`, test.input, out, test.expected)
+			continue
+		}
+		for i := range out {
+			if out[i] != test.expected[i] {
+				t.Errorf(`ParseRules("%v") = %v; expected %v`, test.input, out, test.expected)
+				break
+			}
+		}
+	}
+}
+
+func TestRulesFind(t *testing.T) {
+	rules := Rules{
+		{"*", ByIP, Limit{300, time.Minute}},
+		{"client", ByIP, Limit{100, time.Minute}},
+		{"client/register", ByIP, Limit{10, time.Hour}},
+		{"*", ByIP, Limit{200, time.Minute}},
+		{"account", ByAccount, Limit{60, time.Minute}},
+	}
+	var tests = []struct {
+		group, endpoint, by string
+		expected            Limit
+		found               bool
+	}{
+		{"auth", "access_token/request", ByIP, Limit{200, time.Minute}, true},
+		{"client", "server_time", ByIP, Limit{100, time.Minute}, true},
+		{"client", "register", ByIP, Limit{10, time.Hour}, true},
+		{"account", "logout", ByAccount, Limit{60, time.Minute}, true},
+		{"client", "register", ByAccount, Limit{}, false},
+		{"client", "register", ByAPIKey, Limit{}, false},
+	}
+	for _, test := range tests {
+		out, found := rules.Find(test.group, test.endpoint, test.by)
+		if out != test.expected || found != test.found {
+			t.Errorf(`Find("%v", "%v", "%v") = %v, %v; expected %v, %v`, test.group, test.endpoint, test.by, out, found, test.expected, test.found)
+		}
+	}
+}
+
+func TestNewResult(t *testing.T) {
+	limit := Limit{10, 10 * time.Second}
+	var tests = []struct {
+		allowed       bool
+		curr, prev    int64
+		elapsedMillis int64
+		remaining     int
+		retryAfter    time.Duration
+	}{
+		{true, 1, 0, 0, 9, 0},
+		{true, 5, 10, 5000, 0, 0},
+		{true, 2, 10, 8000, 6, 0},
+		// 10 requests in the current window, only the next window allows more.
+		{false, 10, 0, 4000, 0, 6 * time.Second},
+		// 4 + 10 * 0.6 = 10, allowed again once the weight drops to 0.5 at 5s.
+		{false, 4, 10, 4000, 0, time.Second},
+	}
+	for _, test := range tests {
+		out := newResult(test.allowed, test.curr, test.prev, limit, test.elapsedMillis, 10000)
+		if out.Remaining != test.remaining || out.RetryAfter != test.retryAfter {
+			t.Errorf(`newResult(%v, %d, %d, %d) = %d, %v; expected %d, %v`, test.allowed, test.curr, test.prev, test.elapsedMillis,
+				out.Remaining, out.RetryAfter, test.remaining, test.retryAfter)
+		}
+	}
+}
+
+func TestCheckAuthenticated(t *testing.T) {
+	r := httptest.NewRequest("POST", "/v1/account/profile/get", nil)
+	if !CheckAuthenticated(r.Context(), ByAPIKey, "key") {
+		t.Errorf("CheckAuthenticated() without a check = false; expected true")
+	}
+
+	var checked []string
+	r = WithAuthenticatedCheck(r, func(by, id string) bool {
+		checked = append(checked, by+"="+id)
+		return id != "denied"
+	})
+	if !CheckAuthenticated(r.Context(), ByAPIKey, "key") || CheckAuthenticated(r.Context(), ByAccount, "denied") ||
+		!CheckAuthenticated(r.Context(), ByAccount, "") {
+		t.Errorf("CheckAuthenticated() didn't return the check's results")
+	}
+	if expected := []string{"apiKey=key", "account=denied"}; !reflect.DeepEqual(checked, expected) {
+		t.Errorf("CheckAuthenticated() checked %v; expected %v without the empty identity", checked, expected)
+	}
+}
@@ -0,0 +1,112 @@
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defines what the requests are counted by.
+const (
+	ByIP      = "ip"
+	ByAPIKey  = "apiKey"
+	ByAccount = "account"
+)
+
+// ScopeAll is the scope matching all APIs.
+const ScopeAll = "*"
+
+// Rule is a limit for requests to a scope counted by an identity.
+// The scope is "*", an API group (e.g. "client"), or an API group and endpoint name (e.g. "client/register").
+type Rule struct {
+	Scope string
+	By    string
+	Limit Limit
+}
+
+// Rules is a list of rules. The most specific scope matching a request is used for each identity.
+type Rules []Rule
+
+// Find returns the limit of the most specific rule for an API and identity.
+// The boolean is false if no rule matches.
+func (rules Rules) Find(group, endpoint, by string) (Limit, bool) {
+	var res Limit
+	found, specificity := false, -1
+	for _, r := range rules {
+		if r.By != by {
+			continue
+		}
+		var s int
+		switch r.Scope {
+		case ScopeAll:
+			s = 0
+		case group:
+			s = 1
+		case group + "/" + endpoint:
+			s = 2
+		default:
+			continue
+		}
+		// Later rules override earlier ones with the same scope.
+		if s >= specificity {
+			res, found, specificity = r.Limit, true, s
+		}
+	}
+	return res, found
+}
+
+// ParseRules parses rules in the format "<scope>:<by>=<requests>/<window>", separated by commas,
+// e.g. "*:ip=300/1m, client/register:ip=10/1h". The window is a Go duration of at least 1 second,
+// and 0 requests disables the limit.
+func ParseRules(s string) (Rules, error) {
+	rules := make(Rules, 0)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		rule, err := parseRule(item)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func parseRule(s string) (Rule, error) {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 {
+		return Rule{}, fmt.Errorf("rule '%s' is missing the limit", s)
+	}
+	target := strings.SplitN(strings.TrimSpace(parts[0]), ":", 2)
+	if len(target) != 2 || target[0] == "" {
+		return Rule{}, fmt.Errorf("rule '%s' must be in the format '<scope>:<by>'", s)
+	}
+	by := target[1]
+	if by != ByIP && by != ByAPIKey && by != ByAccount {
+		return Rule{}, fmt.Errorf("rule '%s' has unknown identity '%s'", s, by)
+	}
+	limit, err := parseLimit(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return Rule{}, fmt.Errorf("rule '%s': %v", s, err)
+	}
+	return Rule{target[0], by, limit}, nil
+}
+
+func parseLimit(s string) (Limit, error) {
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) != 2 {
+		return Limit{}, fmt.Errorf("limit '%s' must be in the format '<requests>/<window>'", s)
+	}
+	n, err := strconv.Atoi(parts[0])
+	if err != nil || n < 0 {
+		return Limit{}, fmt.Errorf("requests '%s' is invalid", parts[0])
+	}
+	d, err := time.ParseDuration(parts[1])
+	if err != nil || d < time.Second {
+		return Limit{}, fmt.Errorf("window '%s' is invalid", parts[1])
+	}
+	return Limit{n, d}, nil
+}
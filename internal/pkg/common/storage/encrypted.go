@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"io"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/common/crypto/envelope"
+)
+
+// encryptedContentType is the content type of the encrypted objects. The file's media type is in its record.
+const encryptedContentType = "application/octet-stream"
+
+// PrepareFileEncryption makes a new file encrypted with its own data key if the file encryption is configured.
+// The file's objects must then be stored with StoreFileObject, and it's never public.
+func PrepareFileEncryption(file *model.File) error {
+	if !envelope.Enabled() {
+		return nil
+	}
+	_, wrapped, err := envelope.NewDataKey()
+	if err != nil {
+		return err
+	}
+	file.IsEncrypted, file.EncryptKey, file.IsPublic = true, wrapped, false
+	return nil
+}
+
+// StoreFileObject stores an object of a file, e.g. its fullsize or thumbnail image, public if the file is public.
+// The object is encrypted with the file's data key if the file is encrypted.
+func StoreFileObject(st Storage, file model.File, dstFilepath string, src io.Reader, contentType string) error {
+	st.ShouldMakePublic(file.IsPublic && !file.IsEncrypted)
+	if !file.IsEncrypted {
+		return st.StoreObject(dstFilepath, src, contentType)
+	}
+	dataKey, err := envelope.UnwrapDataKey(file.EncryptKey)
+	if err != nil {
+		return err
+	}
+	encrypted, err := envelope.NewEncryptReader(src, dataKey)
+	if err != nil {
+		return err
+	}
+	return st.StoreObject(dstFilepath, encrypted, encryptedContentType)
+}
+
+// FetchFileObject creates a new reader to read the contents of an object of a file, decrypted if the file is encrypted.
+// The caller must call Close on the returned reader when done reading.
+func FetchFileObject(st Storage, file model.File, srcFilepath string) (reader io.ReadCloser, code int, err error) {
+	reader, code, err = st.FetchObject(srcFilepath)
+	if err != nil || !file.IsEncrypted {
+		return
+	}
+	dataKey, err := envelope.UnwrapDataKey(file.EncryptKey)
+	if err != nil {
+		reader.Close()
+		return nil, ErrOther, err
+	}
+	decrypted, err := envelope.NewDecryptReader(reader, dataKey)
+	if err != nil {
+		reader.Close()
+		return nil, ErrOther, err
+	}
+	return decryptedObject{decrypted, reader}, 0, nil
+}
+
+// decryptedObject reads an object decrypted, and closes the object's reader.
+type decryptedObject struct {
+	io.Reader
+	io.Closer
+}
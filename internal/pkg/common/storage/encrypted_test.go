@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/common/crypto/envelope"
+)
+
+func TestEncryptedFileObject(t *testing.T) {
+	st := NewMemory()
+	content := bytes.Repeat([]byte("photo"), 20000)
+
+	// Without keys, the files aren't encrypted.
+	envelope.SetKeys("", nil)
+	file := model.File{IsPublic: true}
+	if err := PrepareFileEncryption(&file); err != nil || file.IsEncrypted || !file.IsPublic {
+		t.Errorf("PrepareFileEncryption() without keys = %+v, %v; expected not encrypted", file, err)
+	}
+
+	envelope.SetKeys("k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, envelope.KeySize)})
+	defer envelope.SetKeys("", nil)
+	if err := PrepareFileEncryption(&file); err != nil || !file.IsEncrypted || file.IsPublic || file.EncryptKey == "" {
+		t.Fatalf("PrepareFileEncryption() = %+v, %v; expected encrypted and private", file, err)
+	}
+	if err := StoreFileObject(st, file, "photo/a-full", bytes.NewReader(content), "image/jpeg"); err != nil {
+		t.Fatalf("StoreFileObject() error: %v", err)
+	}
+
+	// The stored object is encrypted, and it's read decrypted.
+	stored, _ := st.Object("photo/a-full")
+	if bytes.Contains(stored.Data, []byte("photophoto")) || stored.Public || stored.ContentType != encryptedContentType {
+		t.Errorf("stored object = public %v, %q; expected encrypted and private", stored.Public, stored.ContentType)
+	}
+	reader, code, err := FetchFileObject(st, file, "photo/a-full")
+	if err != nil {
+		t.Fatalf("FetchFileObject() = %d, %v", code, err)
+	}
+	res, err := ioutil.ReadAll(reader)
+	reader.Close()
+	if err != nil || !bytes.Equal(res, content) {
+		t.Errorf("FetchFileObject() read %d bytes, %v; expected %d bytes", len(res), err, len(content))
+	}
+
+	// Without the file's master key, the object can't be read.
+	envelope.SetKeys("k2", map[string][]byte{"k2": bytes.Repeat([]byte{2}, envelope.KeySize)})
+	if _, code, err = FetchFileObject(st, file, "photo/a-full"); code != ErrOther || err != envelope.ErrUnknownKey {
+		t.Errorf("FetchFileObject() without the master key = %d, %v; expected %v", code, err, envelope.ErrUnknownKey)
+	}
+}
@@ -3,14 +3,45 @@ package storage
 import (
 	"fmt"
 
+	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
 	"github.com/jonylim/basego/internal/pkg/common/constant"
 	"github.com/jonylim/basego/internal/pkg/common/storage/basedir"
 )
 
+// Variants of the photos, which are the suffixes of their filepaths.
+const (
+	PhotoVariantFull      = "full"
+	PhotoVariantThumbnail = "thumb"
+)
+
 // GetCstAccountPhotoFilepath returns fullsize & thumbnail filepaths for a customer account's photo.
 func GetCstAccountPhotoFilepath(filename string) (fullFilepath, thumbFilepath string) {
-	dir := basedir.CstAccount(constant.FileCategoryPhoto)
-	fullFilepath = fmt.Sprintf("%s/%s-%s", dir, filename, "full")
-	thumbFilepath = fmt.Sprintf("%s/%s-%s", dir, filename, "thumb")
-	return
+	return GetCstAccountPhotoVariantFilepath(filename, PhotoVariantFull), GetCstAccountPhotoVariantFilepath(filename, PhotoVariantThumbnail)
+}
+
+// GetCstAccountPhotoVariantFilepath returns the filepath of a variant of a customer account's photo.
+func GetCstAccountPhotoVariantFilepath(filename, variant string) string {
+	return fmt.Sprintf("%s/%s-%s", basedir.CstAccount(constant.FileCategoryPhoto), filename, variant)
+}
+
+// GetFileObjectFilepaths returns the filepaths of all objects of a file, which are the same in every storage.
+// The images without variants have a fullsize and a thumbnail image.
+func GetFileObjectFilepaths(file model.File) ([]string, error) {
+	if file.OwnerType == constant.FileOwnerTypeCstAccount && file.Category == constant.FileCategoryPhoto {
+		if len(file.Variants) == 0 {
+			fullFilepath, thumbFilepath := GetCstAccountPhotoFilepath(file.Filename)
+			return []string{fullFilepath, thumbFilepath}, nil
+		}
+		filepaths := make([]string, len(file.Variants))
+		for i, v := range file.Variants {
+			filepaths[i] = GetCstAccountPhotoVariantFilepath(file.Filename, v.Name)
+		}
+		return filepaths, nil
+	}
+	return nil, fmt.Errorf("Objects of the %s files of %s are unknown", file.Category, file.OwnerType)
+}
+
+// GetFileObjectPrefixes returns the prefixes of the filepaths of the files' objects, see GetFileObjectFilepaths.
+func GetFileObjectPrefixes() []string {
+	return []string{basedir.CstAccount(constant.FileCategoryPhoto) + "/"}
 }
@@ -2,16 +2,21 @@ package storage
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
 	"github.com/jonylim/basego/internal/pkg/common/logger"
 
 	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
 )
 
 // GoogleCloudStorageConfig defines configurations for using Google Cloud Storage.
@@ -24,10 +29,21 @@ type tGoogleCloudStorage struct {
 	makePublic                                          bool
 }
 
+// gcsServiceAccount is the service account in the credentials file, which signs the URLs.
+type gcsServiceAccount struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+}
+
 var gcsConfig GoogleCloudStorageConfig
+var gcsSigner gcsServiceAccount
 var gcsInstance *tGoogleCloudStorage
 var errGCS = errors.New("Storage is not initialized")
 
+var gcsClientOnce sync.Once
+var gcsSharedClient *storage.Client
+var errGCSClient error
+
 func initGoogleCloudStorageConfig() {
 	gcsConfig = GoogleCloudStorageConfig{
 		ProjectID:    os.Getenv(envvar.Google.ProjectID),
@@ -67,6 +83,21 @@ func initGoogleCloudStorageConfig() {
 	} else {
 		errGCS = nil
 		gcsInstance, _ = newGoogleCloudStorage()
+		initGoogleCloudStorageSigner()
+	}
+}
+
+// initGoogleCloudStorageSigner reads the service account signing the URLs from the credentials file.
+func initGoogleCloudStorageSigner() {
+	gcsSigner = gcsServiceAccount{}
+	data, err := ioutil.ReadFile(os.Getenv(envvar.Google.AppCredentials))
+	if err == nil {
+		err = json.Unmarshal(data, &gcsSigner)
+	}
+	if err != nil {
+		logger.Println("storage", fmt.Sprintf("WARN: Failed to read the credentials file, signed URLs are unavailable: %v", err))
+	} else if gcsSigner.ClientEmail == "" || gcsSigner.PrivateKey == "" {
+		logger.Println("storage", "WARN: The credentials file isn't a service account's, signed URLs are unavailable")
 	}
 }
 
@@ -95,23 +126,121 @@ func (instance *tGoogleCloudStorage) GetPublicFileURL(objFilepath string) string
 // FetchObject creates a new reader to read the contents of the object specified by the filepath.
 // The caller must call Close on the returned reader when done reading.
 func (instance *tGoogleCloudStorage) FetchObject(srcFilepath string) (reader io.ReadCloser, code int, err error) {
-	return instance.fetchObjectInBucket(instance.bucketName, srcFilepath)
+	return instance.FetchObjectContext(context.Background(), srcFilepath)
 }
 
 // DeleteObject deletes the object specified by the filepath.
 func (instance *tGoogleCloudStorage) DeleteObject(srcFilepath string) (code int, err error) {
-	return instance.deleteObjectInBucket(instance.bucketName, srcFilepath)
+	return instance.DeleteObjectContext(context.Background(), srcFilepath)
 }
 
 // StoreObject saves a file to the destination filepath in a Google Cloud Storage's bucket.
 // The `path` should not starts with file separators or dots.
 func (instance *tGoogleCloudStorage) StoreObject(dstFilepath string, src io.Reader, contentType string) error {
-	return instance.storeObjectInBucket(instance.bucketName, dstFilepath, src, contentType)
+	return instance.StoreObjectContext(context.Background(), dstFilepath, src, StoreOptions{ContentType: contentType})
+}
+
+// FetchObjectContext creates a new reader to read the contents of the object specified by the filepath.
+// The caller must call Close on the returned reader when done reading.
+func (instance *tGoogleCloudStorage) FetchObjectContext(ctx context.Context, srcFilepath string) (reader io.ReadCloser, code int, err error) {
+	return instance.fetchObjectInBucket(ctx, instance.bucketName, srcFilepath)
+}
+
+// DeleteObjectContext deletes the object specified by the filepath.
+func (instance *tGoogleCloudStorage) DeleteObjectContext(ctx context.Context, srcFilepath string) (code int, err error) {
+	return instance.deleteObjectInBucket(ctx, instance.bucketName, srcFilepath)
+}
+
+// StoreObjectContext saves a file to the destination filepath in a Google Cloud Storage's bucket.
+// The `path` should not starts with file separators or dots.
+func (instance *tGoogleCloudStorage) StoreObjectContext(ctx context.Context, dstFilepath string, src io.Reader, opts StoreOptions) error {
+	return instance.storeObjectInBucket(ctx, instance.bucketName, dstFilepath, src, opts)
+}
+
+// StatObject returns the details of the object specified by the filepath without reading its contents.
+func (instance *tGoogleCloudStorage) StatObject(ctx context.Context, objFilepath string) (info ObjectInfo, code int, err error) {
+	objHandle, code, err := instance.objectHandle(instance.bucketName, objFilepath)
+	if err != nil {
+		return
+	}
+	attrs, err := objHandle.Attrs(ctx)
+	if err != nil {
+		return info, gcsErrorCode(err), err
+	}
+	return gcsObjectInfo(attrs), 0, nil
+}
+
+// ListObjects returns a page of the objects whose filepaths start with the prefix, sorted by their filepaths.
+func (instance *tGoogleCloudStorage) ListObjects(ctx context.Context, prefix, pageToken string, pageSize int) (page ObjectPage, err error) {
+	if pageSize <= 0 {
+		pageSize = DefaultListPageSize
+	}
+	client, err := gcsClient()
+	if err != nil {
+		return
+	}
+	query := &storage.Query{Prefix: trimStartingSlashes(filepath.ToSlash(prefix))}
+	if err = query.SetAttrSelection([]string{"Name", "Size", "Etag", "Updated"}); err != nil {
+		return
+	}
+	var items []*storage.ObjectAttrs
+	pager := iterator.NewPager(client.Bucket(instance.bucketName).Objects(ctx, query), pageSize, pageToken)
+	if page.NextPageToken, err = pager.NextPage(&items); err != nil {
+		return
+	}
+	page.Objects = make([]ObjectInfo, 0, len(items))
+	for _, attrs := range items {
+		page.Objects = append(page.Objects, gcsObjectInfo(attrs))
+	}
+	return
+}
+
+// CopyObject copies an object with its metadata in the bucket, rewritten by Google Cloud Storage.
+// The copy is public if the storage should make public.
+func (instance *tGoogleCloudStorage) CopyObject(ctx context.Context, srcFilepath, dstFilepath string) (code int, err error) {
+	srcHandle, code, err := instance.objectHandle(instance.bucketName, srcFilepath)
+	if err != nil {
+		return
+	}
+	dstHandle, code, err := instance.objectHandle(instance.bucketName, dstFilepath)
+	if err != nil {
+		return
+	}
+	copier := dstHandle.CopierFrom(srcHandle)
+	if instance.makePublic {
+		copier.PredefinedACL = "publicRead"
+	}
+	if _, err = copier.Run(ctx); err != nil {
+		return gcsErrorCode(err), err
+	}
+	return 0, nil
 }
 
 // ShouldMakePublic makes the next files stored to the storage either public or not. Only supported for certain storages.
 func (instance *tGoogleCloudStorage) ShouldMakePublic(makePublic bool) {
-	instance.makePublic = true
+	instance.makePublic = makePublic
+}
+
+// GetSignedURL creates a URL granting access to the object specified by the filepath until it expires.
+// The URL is signed with the V4 scheme by the service account of the credentials file.
+func (instance *tGoogleCloudStorage) GetSignedURL(objFilepath, method string, expires time.Duration) (string, error) {
+	objFilepath = filepath.ToSlash(filepath.Clean(trimStartingSlashes(objFilepath)))
+	if objFilepath == "" {
+		return "", errors.New("Path can't be empty")
+	} else if objFilepath[:1] == "." {
+		return "", errors.New("Path can't start with dot")
+	} else if err := validateSignedURLRequest(method, expires); err != nil {
+		return "", err
+	} else if gcsSigner.ClientEmail == "" || gcsSigner.PrivateKey == "" {
+		return "", errors.New("Service account for signing URLs is unavailable")
+	}
+	return storage.SignedURL(instance.bucketName, objFilepath, &storage.SignedURLOptions{
+		GoogleAccessID: gcsSigner.ClientEmail,
+		PrivateKey:     []byte(gcsSigner.PrivateKey),
+		Method:         method,
+		Expires:        time.Now().Add(expires),
+		Scheme:         storage.SigningSchemeV4,
+	})
 }
 
 func (instance *tGoogleCloudStorage) getPublicFileURLInBucket(bucketName, objFilepath string) string {
@@ -124,107 +253,61 @@ func (instance *tGoogleCloudStorage) getPublicFileURLInBucket(bucketName, objFil
 	return fmt.Sprintf("https://storage.googleapis.com/%s/%s", bucketName, objFilepath)
 }
 
-func (instance *tGoogleCloudStorage) fetchObjectInBucket(bucketName, srcFilepath string) (reader io.ReadCloser, code int, err error) {
-	srcFilepath = filepath.ToSlash(filepath.Clean(trimStartingSlashes(srcFilepath)))
-	if srcFilepath == "" {
-		code = ErrOther
-		err = errors.New("Path can't be empty")
-		return
-	} else if srcFilepath[:1] == "." {
-		code = ErrOther
-		err = errors.New("Path can't start with dot")
-		return
+// objectHandle returns the handle of the object specified by the filepath in a bucket.
+func (instance *tGoogleCloudStorage) objectHandle(bucketName, objFilepath string) (objHandle *storage.ObjectHandle, code int, err error) {
+	objFilepath = filepath.ToSlash(filepath.Clean(trimStartingSlashes(objFilepath)))
+	if objFilepath == "" {
+		return nil, ErrOther, errors.New("Path can't be empty")
+	} else if objFilepath[:1] == "." {
+		return nil, ErrOther, errors.New("Path can't start with dot")
 	}
+	client, err := gcsClient()
+	if err != nil {
+		return nil, ErrOther, err
+	}
+	return client.Bucket(bucketName).Object(objFilepath), 0, nil
+}
 
-	// Create a client.
-	ctx := context.Background()
-	client, errClient := storage.NewClient(ctx)
-	if errClient != nil {
-		// logger.Fatal("tGoogleCloudStorage", logger.FromError(err))
-		code = ErrOther
-		err = errClient
+func (instance *tGoogleCloudStorage) fetchObjectInBucket(ctx context.Context, bucketName, srcFilepath string) (reader io.ReadCloser, code int, err error) {
+	objHandle, code, err := instance.objectHandle(bucketName, srcFilepath)
+	if err != nil {
 		return
 	}
 
-	// Create object handle to the destination filepath.
-	objHandle := client.Bucket(bucketName).Object(srcFilepath)
-
 	// Get reader to the object.
 	reader, err = objHandle.NewReader(ctx)
 	if err != nil {
-		// logger.Fatal("tGoogleCloudStorage", logger.FromError(err))
-		if err == storage.ErrObjectNotExist {
-			code = ErrNotFound
-		} else {
-			code = ErrOther
-		}
-		return
+		code = gcsErrorCode(err)
 	}
 	return
 }
 
-func (instance *tGoogleCloudStorage) deleteObjectInBucket(bucketName, srcFilepath string) (code int, err error) {
-	srcFilepath = filepath.ToSlash(filepath.Clean(trimStartingSlashes(srcFilepath)))
-	if srcFilepath == "" {
-		code = ErrOther
-		err = errors.New("Path can't be empty")
-		return
-	} else if srcFilepath[:1] == "." {
-		code = ErrOther
-		err = errors.New("Path can't start with dot")
-		return
-	}
-
-	// Create a client.
-	ctx := context.Background()
-	client, errClient := storage.NewClient(ctx)
-	if errClient != nil {
-		// logger.Fatal("tGoogleCloudStorage", logger.FromError(err))
-		code = ErrOther
-		err = errClient
+func (instance *tGoogleCloudStorage) deleteObjectInBucket(ctx context.Context, bucketName, srcFilepath string) (code int, err error) {
+	objHandle, code, err := instance.objectHandle(bucketName, srcFilepath)
+	if err != nil {
 		return
 	}
 
-	// Create object handle to the destination filepath.
-	objHandle := client.Bucket(bucketName).Object(srcFilepath)
-
 	// Delete the object.
-	err = objHandle.Delete(ctx)
-	if err != nil {
-		// logger.Fatal("tGoogleCloudStorage", logger.FromError(err))
-		if err == storage.ErrObjectNotExist {
-			code = ErrNotFound
-		} else {
-			code = ErrOther
-		}
-		return
+	if err = objHandle.Delete(ctx); err != nil {
+		code = gcsErrorCode(err)
 	}
 	return
 }
 
-func (instance *tGoogleCloudStorage) storeObjectInBucket(bucketName, dstFilepath string, src io.Reader, contentType string) error {
-	dstFilepath = filepath.ToSlash(filepath.Clean(trimStartingSlashes(dstFilepath)))
-	if dstFilepath == "" {
-		return errors.New("Path can't be empty")
-	} else if dstFilepath[:1] == "." {
-		return errors.New("Path can't start with dot")
-	}
-
-	// Create a client.
-	ctx := context.Background()
-	client, err := storage.NewClient(ctx)
+func (instance *tGoogleCloudStorage) storeObjectInBucket(ctx context.Context, bucketName, dstFilepath string, src io.Reader, opts StoreOptions) error {
+	objHandle, _, err := instance.objectHandle(bucketName, dstFilepath)
 	if err != nil {
-		// logger.Fatal("tGoogleCloudStorage", logger.FromError(err))
 		return err
 	}
 
 	// Create bucket handle.
+	client, _ := gcsClient()
 	bucketHandle := client.Bucket(bucketName)
 
 	// Check if the bucket already exists.
 	if _, err := bucketHandle.Attrs(ctx); err != nil {
 		if err != storage.ErrBucketNotExist {
-			// logger.Fatal("tGoogleCloudStorage", logger.FromError(err))
 			return err
 		}
 
@@ -234,42 +317,53 @@ func (instance *tGoogleCloudStorage) storeObjectInBucket(bucketName, dstFilepath
 			Location:     instance.bucketLocation,
 		}
 		if err := bucketHandle.Create(ctx, instance.projectID, bucketAttrs); err != nil {
-			// logger.Fatal("tGoogleCloudStorage", logger.FromError(err))
 			return err
 		}
 	}
 
-	// Create object handle to the destination filepath.
-	objHandle := bucketHandle.Object(dstFilepath)
-
-	// Get writer to the object.
+	// Get writer to the object, with the object's metadata.
 	writer := objHandle.NewWriter(ctx)
+	writer.ContentType = opts.ContentType
+	writer.CacheControl = opts.CacheControl
+	writer.Metadata = opts.Metadata
+	if instance.makePublic {
+		writer.PredefinedACL = "publicRead"
+	}
 
 	// Write the file the destination filepath.
 	if _, err = io.Copy(writer, src); err != nil {
-		// logger.Fatal("tGoogleCloudStorage", logger.FromError(err))
+		writer.CloseWithError(err)
 		return err
 	}
 
 	// Close the writer.
-	if err = writer.Close(); err != nil {
-		// logger.Fatal("tGoogleCloudStorage", logger.FromError(err))
-		return err
-	}
+	return writer.Close()
+}
 
-	// Update the object attributes.
-	objHandle.Update(ctx, storage.ObjectAttrsToUpdate{
-		ContentType: contentType,
+// gcsClient returns the client shared by the storage instances, which is safe for concurrent use.
+func gcsClient() (*storage.Client, error) {
+	gcsClientOnce.Do(func() {
+		gcsSharedClient, errGCSClient = storage.NewClient(context.Background())
 	})
+	return gcsSharedClient, errGCSClient
+}
 
-	// Make public.
-	if instance.makePublic {
-		acl := objHandle.ACL()
-		if err = acl.Set(ctx, storage.AllUsers, storage.RoleReader); err != nil {
-			// Delete the object.
-			objHandle.Delete(ctx)
-		}
+// gcsErrorCode returns the storage error code of an error returned by Google Cloud Storage.
+func gcsErrorCode(err error) int {
+	if err == storage.ErrObjectNotExist {
+		return ErrNotFound
 	}
+	return ErrOther
+}
 
-	return nil
+func gcsObjectInfo(attrs *storage.ObjectAttrs) ObjectInfo {
+	return ObjectInfo{
+		Filepath:     attrs.Name,
+		Size:         attrs.Size,
+		ContentType:  attrs.ContentType,
+		CacheControl: attrs.CacheControl,
+		ETag:         attrs.Etag,
+		ModifiedTime: attrs.Updated,
+		Metadata:     attrs.Metadata,
+	}
 }
@@ -1,11 +1,21 @@
 package storage
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
 	"github.com/jonylim/basego/internal/pkg/common/logger"
@@ -14,10 +24,19 @@ import (
 // LocalStorageConfig defines configurations for using local file storage.
 type LocalStorageConfig struct {
 	BaseDirPath string
+
+	// PublicURL is the base URL of the public files, served by ServeLocalFile.
+	PublicURL string
+
+	// SigningKey signs the URLs of the private files, also served by ServeLocalFile.
+	SigningKey string
 }
 
 type tLocalStorage struct {
-	dirPath string
+	dirPath    string
+	publicURL  string
+	signingKey []byte
+	makePublic bool
 }
 
 var localConfig LocalStorageConfig
@@ -40,6 +59,17 @@ func initLocalStorageConfig() {
 		}
 		logger.Println("storage", fmt.Sprintf("LocalStorageConfig.BaseDirPath set to '%s'", localConfig.BaseDirPath))
 	}
+	if s := strings.TrimRight(os.Getenv(envvar.Storage.LocalPublicURL), "/"); s == "" {
+		logger.Println("storage", "WARN: LocalStorageConfig.PublicURL is empty, the files won't have public URLs")
+	} else if u, err := url.Parse(s); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		logger.Println("storage", fmt.Sprintf("WARN: LocalStorageConfig.PublicURL '%s' is invalid, the files won't have public URLs", s))
+	} else {
+		localConfig.PublicURL = s
+		logger.Println("storage", fmt.Sprintf("LocalStorageConfig.PublicURL set to '%s'", localConfig.PublicURL))
+	}
+	if localConfig.SigningKey = os.Getenv(envvar.Storage.LocalSigningKey); localConfig.SigningKey == "" {
+		logger.Println("storage", "WARN: LocalStorageConfig.SigningKey is empty, the private files won't have signed URLs")
+	}
 
 	// Set default error.
 	if localConfig.BaseDirPath == "" {
@@ -55,16 +85,24 @@ func newLocalStorage() (*tLocalStorage, error) {
 		return nil, errLocal
 	}
 	return &tLocalStorage{
-		dirPath: localConfig.BaseDirPath,
+		dirPath:    localConfig.BaseDirPath,
+		publicURL:  localConfig.PublicURL,
+		signingKey: []byte(localConfig.SigningKey),
+		makePublic: false,
 	}, nil
 }
 
 func getLocalStorageInstance() (*tLocalStorage, error) {
-	return localInstance, errGCS
+	return localInstance, errLocal
 }
 
+// completeFilepath returns the path of a file in the directory. The file's path is cleaned as if it's rooted
+// in the directory, so ".." can't escape it, and it can't start with a dot, which hides the metadata directory.
 func (instance *tLocalStorage) completeFilepath(dir, file string) (fullpath string, code int, err error) {
 	file = trimStartingSlashes(file)
+	if file != "" {
+		file = trimStartingSlashes(filepath.ToSlash(filepath.Clean("/" + filepath.ToSlash(file))))
+	}
 	if file == "" {
 		code = ErrOther
 		err = errors.New("Path can't be empty")
@@ -72,40 +110,222 @@ func (instance *tLocalStorage) completeFilepath(dir, file string) (fullpath stri
 		code = ErrOther
 		err = errors.New("Path can't start with dot")
 	} else {
-		fullpath = filepath.Clean(dir + "/" + file)
+		fullpath = filepath.Join(dir, filepath.FromSlash(file))
 	}
 	return
 }
 
 // GetPublicFileURL creates public file URL of the specified filepath.
 func (instance *tLocalStorage) GetPublicFileURL(objFilepath string) string {
-	if _, _, err := instance.completeFilepath("", objFilepath); err != nil {
+	if instance.publicURL == "" {
 		return ""
 	}
-	// TODO: Set public file URL.
-	logger.Warn("storage", "Public file URL for local storage is not supported yet")
-	return ""
+	p, _, err := instance.completeFilepath("/", objFilepath)
+	if err != nil {
+		return ""
+	}
+	return instance.publicURL + (&url.URL{Path: filepath.ToSlash(p)}).EscapedPath()
 }
 
 // FetchObject creates a new reader to read the contents of the object specified by the filepath.
 // The caller must call Close on the returned reader when done reading.
 func (instance *tLocalStorage) FetchObject(srcFilepath string) (reader io.ReadCloser, code int, err error) {
-	return instance.fetchObjectInDir(instance.dirPath, srcFilepath)
+	return instance.FetchObjectContext(context.Background(), srcFilepath)
 }
 
 // DeleteObject deletes the object specified by the filepath.
 func (instance *tLocalStorage) DeleteObject(srcFilepath string) (code int, err error) {
-	return instance.deleteObjectInDir(instance.dirPath, srcFilepath)
+	return instance.DeleteObjectContext(context.Background(), srcFilepath)
 }
 
 // StoreObject saves a file to the destination filepath in local storage.
 // The `path` should not starts with file separators or dots.
 func (instance *tLocalStorage) StoreObject(dstFilepath string, src io.Reader, contentType string) error {
-	return instance.storeObjectInDir(instance.dirPath, dstFilepath, src, contentType)
+	return instance.StoreObjectContext(context.Background(), dstFilepath, src, StoreOptions{ContentType: contentType})
+}
+
+// FetchObjectContext creates a new reader to read the contents of the object specified by the filepath.
+// The caller must call Close on the returned reader when done reading.
+func (instance *tLocalStorage) FetchObjectContext(ctx context.Context, srcFilepath string) (reader io.ReadCloser, code int, err error) {
+	if err = ctx.Err(); err != nil {
+		return nil, ErrOther, err
+	}
+	return instance.fetchObjectInDir(instance.dirPath, srcFilepath)
+}
+
+// DeleteObjectContext deletes the object specified by the filepath.
+func (instance *tLocalStorage) DeleteObjectContext(ctx context.Context, srcFilepath string) (code int, err error) {
+	if err = ctx.Err(); err != nil {
+		return ErrOther, err
+	}
+	return instance.deleteObjectInDir(instance.dirPath, srcFilepath)
+}
+
+// StoreObjectContext saves a file to the destination filepath in local storage.
+// The `path` should not starts with file separators or dots.
+func (instance *tLocalStorage) StoreObjectContext(ctx context.Context, dstFilepath string, src io.Reader, opts StoreOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return instance.storeObjectInDir(instance.dirPath, dstFilepath, &contextReader{ctx, src}, opts)
+}
+
+// StatObject returns the details of the object specified by the filepath without reading its contents.
+func (instance *tLocalStorage) StatObject(ctx context.Context, objFilepath string) (info ObjectInfo, code int, err error) {
+	if err = ctx.Err(); err != nil {
+		return info, ErrOther, err
+	}
+	fullpath, code, err := instance.completeFilepath(instance.dirPath, objFilepath)
+	if err != nil {
+		return
+	}
+	return instance.statObjectInDir(instance.dirPath, fullpath)
+}
+
+// ListObjects returns a page of the objects whose filepaths start with the prefix, sorted by their filepaths.
+// The page token is the last filepath of the previous page.
+func (instance *tLocalStorage) ListObjects(ctx context.Context, prefix, pageToken string, pageSize int) (page ObjectPage, err error) {
+	if pageSize <= 0 {
+		pageSize = DefaultListPageSize
+	}
+	prefix = trimStartingSlashes(filepath.ToSlash(prefix))
+
+	// Walk the directory of the prefix, as the other directories have no matching files.
+	root := instance.dirPath
+	if i := strings.LastIndex(prefix, "/"); i >= 0 {
+		dir := prefix[:i]
+		if path.Clean("/"+dir) != "/"+dir || dir[:1] == "." {
+			return page, errors.New("Prefix is invalid")
+		}
+		root = filepath.Join(instance.dirPath, filepath.FromSlash(dir))
+	}
+	metaDirPath := filepath.Join(instance.dirPath, localMetaDirName)
+	filepaths := make([]string, 0)
+	err = filepath.Walk(root, func(p string, fi os.FileInfo, errWalk error) error {
+		if errWalk != nil {
+			if p == root && os.IsNotExist(errWalk) {
+				return filepath.SkipDir
+			}
+			return errWalk
+		} else if err := ctx.Err(); err != nil {
+			return err
+		} else if fi.IsDir() {
+			if p == metaDirPath {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(instance.dirPath, p)
+		if err != nil {
+			return err
+		}
+		if rel = filepath.ToSlash(rel); strings.HasPrefix(rel, prefix) && rel > pageToken {
+			filepaths = append(filepaths, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return
+	}
+
+	// Take a page of the sorted filepaths.
+	sort.Strings(filepaths)
+	if len(filepaths) > pageSize {
+		filepaths = filepaths[:pageSize]
+		page.NextPageToken = filepaths[pageSize-1]
+	}
+	page.Objects = make([]ObjectInfo, 0, len(filepaths))
+	for _, rel := range filepaths {
+		fi, errStat := os.Stat(filepath.Join(instance.dirPath, filepath.FromSlash(rel)))
+		if errStat != nil {
+			// The file has been deleted since it was listed.
+			continue
+		}
+		page.Objects = append(page.Objects, ObjectInfo{
+			Filepath:     rel,
+			Size:         fi.Size(),
+			ETag:         localFileETag(fi),
+			ModifiedTime: fi.ModTime(),
+		})
+	}
+	return
+}
+
+// CopyObject copies an object with its metadata in local storage.
+// The copy is public if the storage should make public.
+func (instance *tLocalStorage) CopyObject(ctx context.Context, srcFilepath, dstFilepath string) (code int, err error) {
+	if err = ctx.Err(); err != nil {
+		return ErrOther, err
+	}
+	srcFullpath, code, err := instance.completeFilepath(instance.dirPath, srcFilepath)
+	if err != nil {
+		return
+	}
+	dstFullpath, code, err := instance.completeFilepath(instance.dirPath, dstFilepath)
+	if err != nil {
+		return
+	}
+	meta, err := readLocalObjectMeta(instance.dirPath, srcFullpath)
+	if err != nil && !os.IsNotExist(err) {
+		return ErrOther, err
+	}
+
+	// Copying an object to itself only changes if it's public.
+	if srcFullpath == dstFullpath {
+		if _, err = os.Stat(srcFullpath); err != nil {
+			if os.IsNotExist(err) {
+				return ErrNotFound, err
+			}
+			return ErrOther, err
+		}
+		meta.Public = instance.makePublic
+		if err = writeLocalObjectMeta(instance.dirPath, dstFullpath, meta); err != nil {
+			return ErrOther, err
+		}
+		return 0, nil
+	}
+	reader, code, err := instance.fetchObjectInDir(instance.dirPath, srcFilepath)
+	if err != nil {
+		return
+	}
+	defer reader.Close()
+	opts := StoreOptions{ContentType: meta.ContentType, CacheControl: meta.CacheControl, Metadata: meta.Metadata}
+	if err = instance.storeObjectInDir(instance.dirPath, dstFilepath, &contextReader{ctx, reader}, opts); err != nil {
+		return ErrOther, err
+	}
+	return 0, nil
 }
 
-// ShouldMakePublic do nothing in local storage.
+// ShouldMakePublic makes the next files stored to the storage either public or not. Only supported for certain storages.
 func (instance *tLocalStorage) ShouldMakePublic(makePublic bool) {
+	instance.makePublic = makePublic
+}
+
+// GetSignedURL creates a URL granting access to the object specified by the filepath until it expires.
+// The URL is served by ServeLocalFile, which verifies its signature.
+func (instance *tLocalStorage) GetSignedURL(objFilepath, method string, expires time.Duration) (string, error) {
+	if instance.publicURL == "" || len(instance.signingKey) == 0 {
+		return "", errors.New("Storage is not configured to sign URLs")
+	}
+	p, _, err := instance.completeFilepath("/", objFilepath)
+	if err != nil {
+		return "", err
+	} else if err = validateSignedURLRequest(method, expires); err != nil {
+		return "", err
+	}
+	p = filepath.ToSlash(p)
+	expiresAt := time.Now().Add(expires).Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expiresAt, 10))
+	query.Set("signature", instance.signature(method, p, expiresAt))
+	return instance.publicURL + (&url.URL{Path: p}).EscapedPath() + "?" + query.Encode(), nil
+}
+
+// signature returns the signature of a URL granting the method on a file until the expiry in Unix seconds.
+func (instance *tLocalStorage) signature(method, p string, expiresAt int64) string {
+	mac := hmac.New(sha256.New, instance.signingKey)
+	mac.Write([]byte(method + "\n" + p + "\n" + strconv.FormatInt(expiresAt, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
 }
 
 func (instance *tLocalStorage) fetchObjectInDir(dirPath, srcFilepath string) (reader io.ReadCloser, code int, err error) {
@@ -144,11 +364,46 @@ func (instance *tLocalStorage) deleteObjectInDir(dirPath, srcFilepath string) (c
 		} else {
 			code = ErrOther
 		}
+		return
+	}
+
+	// Remove the file's metadata.
+	if errMeta := os.Remove(localObjectMetaFilepath(dirPath, srcFilepath)); errMeta != nil && !os.IsNotExist(errMeta) {
+		logger.Warn("storage", logger.FromError(errMeta))
 	}
 	return
 }
 
-func (instance *tLocalStorage) storeObjectInDir(dirPath, dstFilepath string, src io.Reader, contentType string) error {
+func (instance *tLocalStorage) statObjectInDir(dirPath, fullpath string) (info ObjectInfo, code int, err error) {
+	fi, err := os.Stat(fullpath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			code = ErrNotFound
+		} else {
+			code = ErrOther
+		}
+		return
+	} else if fi.IsDir() {
+		return info, ErrNotFound, errors.New("Path is a directory")
+	}
+	meta, err := readLocalObjectMeta(dirPath, fullpath)
+	if err != nil && !os.IsNotExist(err) {
+		return info, ErrOther, err
+	}
+	rel, _ := filepath.Rel(dirPath, fullpath)
+	info = ObjectInfo{
+		Filepath:     filepath.ToSlash(rel),
+		Size:         fi.Size(),
+		ContentType:  meta.ContentType,
+		CacheControl: meta.CacheControl,
+		ETag:         localFileETag(fi),
+		ModifiedTime: fi.ModTime(),
+		Metadata:     meta.Metadata,
+	}
+	return info, 0, nil
+}
+
+func (instance *tLocalStorage) storeObjectInDir(dirPath, dstFilepath string, src io.Reader, opts StoreOptions) error {
 	// Create the complete filepath.
 	var err error
 	dstFilepath, _, err = instance.completeFilepath(dirPath, dstFilepath)
@@ -189,6 +444,7 @@ func (instance *tLocalStorage) storeObjectInDir(dirPath, dstFilepath string, src
 	// Write the file the destination filepath.
 	if _, err = io.Copy(f, src); err != nil {
 		// logger.Fatal("tLocalStorage", logger.FromError(err))
+		f.Close()
 		return err
 	}
 
@@ -198,5 +454,24 @@ func (instance *tLocalStorage) storeObjectInDir(dirPath, dstFilepath string, src
 		return err
 	}
 
-	return nil
+	// Save the file's metadata.
+	return writeLocalObjectMeta(dirPath, dstFilepath, localObjectMeta{
+		ContentType:  opts.ContentType,
+		CacheControl: opts.CacheControl,
+		Metadata:     opts.Metadata,
+		Public:       instance.makePublic,
+	})
+}
+
+// contextReader reads from a reader until the context is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *contextReader) Read(b []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(b)
 }
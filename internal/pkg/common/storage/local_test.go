@@ -0,0 +1,288 @@
+package storage
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalCompleteFilepath(t *testing.T) {
+	var tests = []struct {
+		file     string
+		expected string
+	}{
+		{"photo/a.jpg", "/base/photo/a.jpg"},
+		{"//photo/./a.jpg", "/base/photo/a.jpg"},
+		{"photo/../../../etc/passwd", "/base/etc/passwd"},
+		{"../etc/passwd", "/base/etc/passwd"},
+		{".meta/photo/a.jpg.json", ""},
+		{"photo/../.meta/a.json", ""},
+		{"", ""},
+		{"/", ""},
+	}
+	instance := &tLocalStorage{dirPath: "/base"}
+	for _, test := range tests {
+		res, _, err := instance.completeFilepath(instance.dirPath, test.file)
+		if res != test.expected || (err == nil) != (test.expected != "") {
+			t.Errorf("completeFilepath(%q) = %q, %v; expected %q", test.file, res, err, test.expected)
+		}
+	}
+}
+
+func TestLocalGetPublicFileURL(t *testing.T) {
+	instance := &tLocalStorage{dirPath: "/base", publicURL: "https://api.example.com/files"}
+	var tests = []struct {
+		filepath string
+		expected string
+	}{
+		{"/photo/a b.jpg", "https://api.example.com/files/photo/a%20b.jpg"},
+		{"photo/../../a.jpg", "https://api.example.com/files/a.jpg"},
+		{".meta/a.json", ""},
+	}
+	for _, test := range tests {
+		if res := instance.GetPublicFileURL(test.filepath); res != test.expected {
+			t.Errorf("GetPublicFileURL(%q) = %q; expected %q", test.filepath, res, test.expected)
+		}
+	}
+
+	instance.publicURL = ""
+	if res := instance.GetPublicFileURL("photo/a.jpg"); res != "" {
+		t.Errorf("GetPublicFileURL() without public URL = %q; expected none", res)
+	}
+}
+
+func TestServeLocalFile(t *testing.T) {
+	root, err := ioutil.TempDir("", "basego-storage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	dir := filepath.Join(root, "storage")
+	ioutil.WriteFile(filepath.Join(root, "outside.txt"), []byte("outside"), 0666)
+
+	localConfig = LocalStorageConfig{BaseDirPath: dir}
+	errLocal = nil
+	localInstance, _ = newLocalStorage()
+	localInstance.ShouldMakePublic(true)
+	if err = localInstance.StoreObject("photo/public.jpg", strings.NewReader("0123456789"), "image/jpeg"); err != nil {
+		t.Fatalf("StoreObject() error: %v", err)
+	}
+	localInstance.ShouldMakePublic(false)
+	if err = localInstance.StoreObject("photo/private.jpg", strings.NewReader("secret"), "image/jpeg"); err != nil {
+		t.Fatalf("StoreObject() error: %v", err)
+	}
+
+	serve := func(p string, header http.Header) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/files"+p, nil)
+		for name, values := range header {
+			r.Header[name] = values
+		}
+		w := httptest.NewRecorder()
+		ServeLocalFile(w, r, p)
+		return w
+	}
+
+	w := serve("/photo/public.jpg", nil)
+	if w.Code != http.StatusOK || w.Body.String() != "0123456789" || w.Header().Get("Content-Type") != "image/jpeg" {
+		t.Errorf("ServeLocalFile(public) = %d, %q, %q", w.Code, w.Body.String(), w.Header().Get("Content-Type"))
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" || w.Header().Get("Last-Modified") == "" {
+		t.Errorf("ServeLocalFile(public) has no ETag or Last-Modified")
+	}
+
+	if w = serve("/photo/public.jpg", http.Header{"Range": {"bytes=2-4"}}); w.Code != http.StatusPartialContent || w.Body.String() != "234" {
+		t.Errorf("ServeLocalFile(range) = %d, %q; expected %d, %q", w.Code, w.Body.String(), http.StatusPartialContent, "234")
+	}
+	if w = serve("/photo/public.jpg", http.Header{"If-None-Match": {etag}}); w.Code != http.StatusNotModified {
+		t.Errorf("ServeLocalFile(If-None-Match) = %d; expected %d", w.Code, http.StatusNotModified)
+	}
+
+	for _, p := range []string{"/photo/private.jpg", "/photo/missing.jpg", "/photo", "/.meta/photo/public.jpg.json", "/../outside.txt"} {
+		if w = serve(p, nil); w.Code != http.StatusNotFound {
+			t.Errorf("ServeLocalFile(%q) = %d; expected %d", p, w.Code, http.StatusNotFound)
+		}
+	}
+
+	// The files stored before the metadata was kept are public.
+	os.MkdirAll(filepath.Join(dir, "legacy"), 0755)
+	ioutil.WriteFile(filepath.Join(dir, "legacy", "a.jpg"), []byte("legacy"), 0666)
+	if w = serve("/legacy/a.jpg", nil); w.Code != http.StatusOK || w.Body.String() != "legacy" || w.Header().Get("Content-Type") != "image/jpeg" {
+		t.Errorf("ServeLocalFile(without metadata) = %d, %q, %q", w.Code, w.Body.String(), w.Header().Get("Content-Type"))
+	}
+
+	// Deleting a file deletes its metadata too.
+	localInstance.DeleteObject("photo/public.jpg")
+	if _, err = os.Stat(localObjectMetaFilepath(dir, filepath.Join(dir, "photo/public.jpg"))); !os.IsNotExist(err) {
+		t.Errorf("DeleteObject() kept the metadata, error: %v", err)
+	}
+}
+
+func TestLocalSignedURL(t *testing.T) {
+	dir, err := ioutil.TempDir("", "basego-storage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	localConfig = LocalStorageConfig{BaseDirPath: dir, PublicURL: "https://api.example.com/files", SigningKey: "key"}
+	errLocal = nil
+	localInstance, _ = newLocalStorage()
+
+	send := func(method, signedURL, body string) *httptest.ResponseRecorder {
+		u, _ := url.Parse(signedURL)
+		r := httptest.NewRequest(method, u.RequestURI(), strings.NewReader(body))
+		r.Header.Set("Content-Type", "text/plain")
+		w := httptest.NewRecorder()
+		ServeLocalFile(w, r, strings.TrimPrefix(u.Path, "/files"))
+		return w
+	}
+
+	// Upload a private file.
+	putURL, err := localInstance.GetSignedURL("doc/a.txt", http.MethodPut, time.Minute)
+	if err != nil || !strings.HasPrefix(putURL, "https://api.example.com/files/doc/a.txt?") {
+		t.Fatalf("GetSignedURL(PUT) = %q, %v", putURL, err)
+	}
+	if w := send(http.MethodPut, putURL, "content"); w.Code != http.StatusOK {
+		t.Fatalf("PUT with signed URL = %d; expected %d", w.Code, http.StatusOK)
+	}
+	if w := send(http.MethodGet, putURL, ""); w.Code != http.StatusForbidden {
+		t.Errorf("GET with signed PUT URL = %d; expected %d", w.Code, http.StatusForbidden)
+	}
+	if w := send(http.MethodGet, "https://api.example.com/files/doc/a.txt", ""); w.Code != http.StatusNotFound {
+		t.Errorf("GET private file without signed URL = %d; expected %d", w.Code, http.StatusNotFound)
+	}
+
+	// Download it.
+	getURL, _ := localInstance.GetSignedURL("doc/a.txt", http.MethodGet, time.Minute)
+	if w := send(http.MethodGet, getURL, ""); w.Code != http.StatusOK || w.Body.String() != "content" ||
+		w.Header().Get("Content-Type") != "text/plain" {
+		t.Errorf("GET with signed URL = %d, %q, %q", w.Code, w.Body.String(), w.Header().Get("Content-Type"))
+	}
+	if w := send(http.MethodHead, getURL, ""); w.Code != http.StatusOK {
+		t.Errorf("HEAD with signed URL = %d; expected %d", w.Code, http.StatusOK)
+	}
+
+	// The signature covers the path and expiry.
+	if w := send(http.MethodGet, strings.Replace(getURL, "/doc/a.txt", "/doc/b.txt", 1), ""); w.Code != http.StatusForbidden {
+		t.Errorf("GET with signed URL of another file = %d; expected %d", w.Code, http.StatusForbidden)
+	}
+	u, _ := url.Parse(getURL)
+	query := u.Query()
+	query.Set("expires", strconv.FormatInt(time.Now().Add(-time.Second).Unix(), 10))
+	query.Set("signature", localInstance.signature(http.MethodGet, "/doc/a.txt", time.Now().Add(-time.Second).Unix()))
+	u.RawQuery = query.Encode()
+	if w := send(http.MethodGet, u.String(), ""); w.Code != http.StatusForbidden {
+		t.Errorf("GET with expired signed URL = %d; expected %d", w.Code, http.StatusForbidden)
+	}
+
+	for _, test := range []struct {
+		method  string
+		expires time.Duration
+	}{
+		{http.MethodDelete, time.Minute},
+		{http.MethodGet, 0},
+		{http.MethodGet, MaxSignedURLExpiry + time.Second},
+	} {
+		if _, err = localInstance.GetSignedURL("doc/a.txt", test.method, test.expires); err == nil {
+			t.Errorf("GetSignedURL(%s, %v) = nil error; expected an error", test.method, test.expires)
+		}
+	}
+}
+
+func TestLocalObjectStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "basego-storage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	localConfig = LocalStorageConfig{BaseDirPath: dir}
+	errLocal = nil
+	instance, _ := newLocalStorage()
+	ctx := context.Background()
+
+	opts := StoreOptions{ContentType: "image/jpeg", CacheControl: "max-age=60", Metadata: map[string]string{"checksum": "abc"}}
+	for _, p := range []string{"photo/a.jpg", "photo/b/c.jpg", "photo-d.jpg", "photos/e.jpg", "other/f.jpg"} {
+		if err = instance.StoreObjectContext(ctx, p, strings.NewReader("content"), opts); err != nil {
+			t.Fatalf("StoreObjectContext(%q) error: %v", p, err)
+		}
+	}
+
+	info, code, err := instance.StatObject(ctx, "/photo/a.jpg")
+	if err != nil || info.Filepath != "photo/a.jpg" || info.Size != 7 || info.ETag == "" || info.ModifiedTime.IsZero() ||
+		info.ContentType != "image/jpeg" || info.CacheControl != "max-age=60" || !reflect.DeepEqual(info.Metadata, opts.Metadata) {
+		t.Errorf("StatObject() = %+v, %d, %v", info, code, err)
+	}
+	for _, p := range []string{"photo/none.jpg", "photo/b"} {
+		if _, code, err = instance.StatObject(ctx, p); code != ErrNotFound || err == nil {
+			t.Errorf("StatObject(%q) = %d, %v; expected ErrNotFound", p, code, err)
+		}
+	}
+
+	// The listing is sorted by the filepaths, and skips the metadata directory.
+	var tests = []struct {
+		prefix   string
+		expected []string
+	}{
+		{"photo", []string{"photo-d.jpg", "photo/a.jpg", "photo/b/c.jpg", "photos/e.jpg"}},
+		{"photo/", []string{"photo/a.jpg", "photo/b/c.jpg"}},
+		{"photo/b/c", []string{"photo/b/c.jpg"}},
+		{"none/", []string{}},
+		{"", []string{"other/f.jpg", "photo-d.jpg", "photo/a.jpg", "photo/b/c.jpg", "photos/e.jpg"}},
+	}
+	for _, test := range tests {
+		listed := make([]string, 0)
+		err = ListAllObjects(ctx, instance, test.prefix, func(item ObjectInfo) error {
+			listed = append(listed, item.Filepath)
+			return nil
+		})
+		if err != nil || !reflect.DeepEqual(listed, test.expected) {
+			t.Errorf("ListObjects(%q) listed %v, %v; expected %v", test.prefix, listed, err, test.expected)
+		}
+	}
+	page, err := instance.ListObjects(ctx, "", "", 2)
+	if err != nil || len(page.Objects) != 2 || page.NextPageToken != "photo-d.jpg" {
+		t.Errorf("ListObjects() first page = %+v, %v", page, err)
+	}
+	for _, prefix := range []string{"../", "photo/../../", ".meta/"} {
+		if _, err = instance.ListObjects(ctx, prefix, "", 0); err == nil {
+			t.Errorf("ListObjects(%q) = nil error; expected an error", prefix)
+		}
+	}
+
+	// The copy keeps the metadata, and it's public if the storage should make public.
+	instance.ShouldMakePublic(true)
+	if code, err = instance.CopyObject(ctx, "photo/a.jpg", "copy/a.jpg"); err != nil {
+		t.Fatalf("CopyObject() = %d, %v", code, err)
+	}
+	copied, _, err := instance.StatObject(ctx, "copy/a.jpg")
+	meta, _ := readLocalObjectMeta(dir, filepath.Join(dir, "copy", "a.jpg"))
+	if err != nil || copied.Size != 7 || !reflect.DeepEqual(copied.Metadata, opts.Metadata) || !meta.Public {
+		t.Errorf("StatObject() of the copy = %+v, %v, public %v", copied, err, meta.Public)
+	}
+	if code, err = instance.CopyObject(ctx, "photo/a.jpg", "photo/a.jpg"); err != nil {
+		t.Errorf("CopyObject() to itself = %d, %v", code, err)
+	} else if data, _ := ioutil.ReadFile(filepath.Join(dir, "photo", "a.jpg")); string(data) != "content" {
+		t.Errorf("CopyObject() to itself changed the contents to %q", data)
+	}
+	if code, err = instance.CopyObject(ctx, "photo/none.jpg", "copy/none.jpg"); code != ErrNotFound || err == nil {
+		t.Errorf("CopyObject() of a missing object = %d, %v; expected ErrNotFound", code, err)
+	}
+
+	// A canceled context stops the operations.
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err = instance.StoreObjectContext(canceled, "photo/g.jpg", strings.NewReader("content"), opts); err != context.Canceled {
+		t.Errorf("StoreObjectContext() with a canceled context error = %v; expected %v", err, context.Canceled)
+	}
+}
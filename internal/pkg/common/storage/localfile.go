@@ -0,0 +1,222 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+)
+
+// localMetaDirName is the directory in the local storage's directory keeping the files' metadata.
+// The stored files' paths can't start with a dot, so it can't be overwritten or served.
+const localMetaDirName = ".meta"
+
+// localUploadMaxSize is the largest file which can be uploaded with a signed URL.
+const localUploadMaxSize = 32 << 20
+
+// localFileCacheMaxAge is how long the clients may cache the public files before revalidating them.
+const localFileCacheMaxAge = time.Hour
+
+// localObjectMeta is the metadata of a file in the local storage, which the file system doesn't keep.
+type localObjectMeta struct {
+	ContentType  string            `json:"contentType"`
+	CacheControl string            `json:"cacheControl,omitempty"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+	Public       bool              `json:"public"`
+}
+
+// localObjectMetaFilepath returns the path of the metadata of a file, given the file's complete path.
+func localObjectMetaFilepath(dirPath, fullpath string) string {
+	rel, err := filepath.Rel(dirPath, fullpath)
+	if err != nil {
+		rel = filepath.Base(fullpath)
+	}
+	return filepath.Join(dirPath, localMetaDirName, rel+".json")
+}
+
+func readLocalObjectMeta(dirPath, fullpath string) (meta localObjectMeta, err error) {
+	data, err := ioutil.ReadFile(localObjectMetaFilepath(dirPath, fullpath))
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(data, &meta)
+	return
+}
+
+func writeLocalObjectMeta(dirPath, fullpath string, meta localObjectMeta) error {
+	metaFilepath := localObjectMetaFilepath(dirPath, fullpath)
+	if err := os.MkdirAll(filepath.Dir(metaFilepath), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(metaFilepath, data, 0666)
+}
+
+// ServeLocalFile serves a public file of the local storage, supporting range and conditional requests.
+// A private file is served to a GET request, and stored from a PUT request, with a URL from GetSignedURL.
+// The private files, directories and missing files are all not found without a signed URL.
+// The files without metadata were stored before it was kept, when all files were public, so they're public.
+func ServeLocalFile(w http.ResponseWriter, r *http.Request, objFilepath string) {
+	instance, err := getLocalStorageInstance()
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	p, _, err := instance.completeFilepath("/", objFilepath)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	p = filepath.ToSlash(p)
+	signed, valid := instance.verifySignedRequest(r, p)
+	if signed && !valid {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	} else if r.Method == http.MethodPut {
+		if !signed {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+		receiveLocalFile(w, r, p)
+		return
+	}
+
+	fullpath := filepath.Join(instance.dirPath, filepath.FromSlash(p))
+	meta, err := readLocalObjectMeta(instance.dirPath, fullpath)
+	if os.IsNotExist(err) {
+		// The files stored before the metadata was kept have none, and they're all public.
+		meta.Public = true
+	} else if err != nil {
+		logger.Error("storage", logger.FromError(err))
+	}
+	if !signed && !meta.Public {
+		http.NotFound(w, r)
+		return
+	}
+
+	// Open the file.
+	reader, code, err := instance.FetchObject(p)
+	if err != nil {
+		if code != ErrNotFound {
+			logger.Error("storage", logger.FromError(err))
+		}
+		http.NotFound(w, r)
+		return
+	}
+	defer reader.Close()
+	f, ok := reader.(*os.File)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+
+	// Set the headers, then let ServeContent handle the ranges and the conditions.
+	contentType := meta.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(fullpath))
+	}
+	if contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	cacheControl := "public"
+	if signed {
+		cacheControl = "private"
+	}
+	w.Header().Set("ETag", localFileETag(info))
+	w.Header().Set("Cache-Control", fmt.Sprintf("%s, max-age=%d", cacheControl, int64(localFileCacheMaxAge/time.Second)))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, r, info.Name(), info.ModTime(), f)
+}
+
+// verifySignedRequest checks the signature of a request to a file, if the request is signed.
+// HEAD requests are allowed by the signatures for GET.
+func (instance *tLocalStorage) verifySignedRequest(r *http.Request, p string) (signed, valid bool) {
+	query := r.URL.Query()
+	signature := query.Get("signature")
+	if signature == "" {
+		return false, false
+	} else if len(instance.signingKey) == 0 {
+		return true, false
+	}
+	expiresAt, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return true, false
+	}
+	method := r.Method
+	if method == http.MethodHead {
+		method = http.MethodGet
+	}
+	expected := instance.signature(method, p, expiresAt)
+	return true, hmac.Equal([]byte(signature), []byte(expected))
+}
+
+// receiveLocalFile stores a file uploaded with a signed URL. The file is private.
+func receiveLocalFile(w http.ResponseWriter, r *http.Request, p string) {
+	if r.ContentLength > localUploadMaxSize {
+		http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return
+	}
+	instance, err := newLocalStorage()
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	body := &limitedReader{r: r.Body, n: localUploadMaxSize}
+	if err = instance.StoreObject(p, body, r.Header.Get("Content-Type")); err != nil {
+		instance.DeleteObject(p)
+		if err == errLocalUploadTooLarge {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		} else {
+			logger.Error("storage", logger.FromError(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+var errLocalUploadTooLarge = errors.New("Uploaded file is too large")
+
+// limitedReader reads up to n bytes, then fails if there's more to read.
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitedReader) Read(b []byte) (int, error) {
+	if l.n < 0 {
+		return 0, errLocalUploadTooLarge
+	}
+	if int64(len(b)) > l.n+1 {
+		b = b[:l.n+1]
+	}
+	n, err := l.r.Read(b)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return n, errLocalUploadTooLarge
+	}
+	return n, err
+}
+
+// localFileETag returns the ETag of a file, which changes whenever the file is rewritten.
+func localFileETag(info os.FileInfo) string {
+	return fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size())
+}
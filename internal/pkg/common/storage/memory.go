@@ -0,0 +1,408 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+)
+
+// memoryPublicURL is the base URL of the memory storage's files, which can't be downloaded.
+const memoryPublicURL = "memory://"
+
+// ErrMemoryFault is the default error of the memory storage's injected faults.
+var ErrMemoryFault = errors.New("Injected storage fault")
+
+// Memory is a storage keeping the files in memory, for tests and ephemeral environments.
+// Its faults can be injected to test the failures of the storage.
+// The instances of GetStorageInstance share the files of the memory storage.
+type Memory struct {
+	data       *memoryData
+	makePublic bool
+}
+
+// MemoryObject is a file in the memory storage.
+type MemoryObject struct {
+	Data         []byte
+	ContentType  string
+	CacheControl string
+	Metadata     map[string]string
+	Public       bool
+	ModifiedTime time.Time
+}
+
+// MemoryFault is a fault injected to the memory storage's operations.
+type MemoryFault struct {
+	// Op is the name of the failing method, e.g. "StoreObject", or empty for all. The methods with and
+	// without a context have the same name, e.g. StoreObjectContext is "StoreObject".
+	Op string
+
+	// Path is the pattern of the failing filepaths as in path.Match, or empty for all.
+	Path string
+
+	// Latency delays the operations before they fail or succeed.
+	Latency time.Duration
+
+	// Err is the error of the operations, nil to only delay them. Code is the error code, ErrOther if 0.
+	Err  error
+	Code int
+}
+
+type memoryData struct {
+	sync.RWMutex
+	objects map[string]MemoryObject
+	faults  []MemoryFault
+}
+
+var memoryInstance = NewMemory()
+
+// NewMemory returns a new empty memory storage.
+func NewMemory() *Memory {
+	return &Memory{data: &memoryData{objects: make(map[string]MemoryObject)}}
+}
+
+// UseMemory makes a memory storage the default storage without Init, so the tests can assert on its files.
+func UseMemory(m *Memory) {
+	memoryInstance = m
+	defaultStorage = MemoryStorage
+	isInitialized = true
+}
+
+func initMemoryStorageConfig() {
+	memoryInstance = NewMemory()
+	if s := os.Getenv(envvar.Storage.MemoryLatency); s != "" {
+		if d, err := time.ParseDuration(s); err != nil || d < 0 {
+			logger.Println("storage", fmt.Sprintf("WARN: Memory storage latency '%s' is invalid, it's ignored", s))
+		} else {
+			memoryInstance.InjectFault(MemoryFault{Latency: d})
+			logger.Println("storage", fmt.Sprintf("Memory storage latency set to '%v'", d))
+		}
+	}
+	if s := os.Getenv(envvar.Storage.MemoryFailPaths); s != "" {
+		for _, pattern := range strings.Split(s, ",") {
+			if pattern = strings.TrimSpace(pattern); pattern == "" {
+				continue
+			} else if _, err := path.Match(pattern, ""); err != nil {
+				logger.Println("storage", fmt.Sprintf("WARN: Memory storage fail path '%s' is invalid, it's ignored", pattern))
+				continue
+			}
+			memoryInstance.InjectFault(MemoryFault{Path: pattern, Err: ErrMemoryFault})
+			logger.Println("storage", fmt.Sprintf("Memory storage fails on path '%s'", pattern))
+		}
+	}
+}
+
+func newMemoryStorage() (*Memory, error) {
+	return &Memory{data: memoryInstance.data}, nil
+}
+
+func getMemoryStorageInstance() (*Memory, error) {
+	return memoryInstance, nil
+}
+
+// InjectFault adds a fault to the storage's operations. The faults of an operation delay it by all of their
+// latencies, then it fails with the error of the first failing one.
+func (m *Memory) InjectFault(f MemoryFault) {
+	m.data.Lock()
+	defer m.data.Unlock()
+	m.data.faults = append(m.data.faults, f)
+}
+
+// ClearFaults removes the injected faults.
+func (m *Memory) ClearFaults() {
+	m.data.Lock()
+	defer m.data.Unlock()
+	m.data.faults = nil
+}
+
+// Object returns a copy of the file at the filepath, if it exists.
+func (m *Memory) Object(objFilepath string) (obj MemoryObject, ok bool) {
+	key, err := objectKey(objFilepath)
+	if err != nil {
+		return
+	}
+	m.data.RLock()
+	defer m.data.RUnlock()
+	obj, ok = m.data.objects[key]
+	return copyMemoryObject(obj), ok
+}
+
+// Filepaths returns the sorted filepaths of the files.
+func (m *Memory) Filepaths() []string {
+	m.data.RLock()
+	defer m.data.RUnlock()
+	res := make([]string, 0, len(m.data.objects))
+	for key := range m.data.objects {
+		res = append(res, key)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Reset removes the files and the injected faults.
+func (m *Memory) Reset() {
+	m.data.Lock()
+	defer m.data.Unlock()
+	m.data.objects = make(map[string]MemoryObject)
+	m.data.faults = nil
+}
+
+// GetPublicFileURL creates public file URL of the specified filepath.
+func (m *Memory) GetPublicFileURL(objFilepath string) string {
+	key, err := objectKey(objFilepath)
+	if err != nil {
+		return ""
+	}
+	return memoryPublicURL + (&url.URL{Path: "/" + key}).EscapedPath()
+}
+
+// FetchObject creates a new reader to read the contents of the object specified by the filepath.
+// The caller must call Close on the returned reader when done reading.
+func (m *Memory) FetchObject(srcFilepath string) (reader io.ReadCloser, code int, err error) {
+	return m.FetchObjectContext(context.Background(), srcFilepath)
+}
+
+// DeleteObject deletes the object specified by the filepath.
+func (m *Memory) DeleteObject(srcFilepath string) (code int, err error) {
+	return m.DeleteObjectContext(context.Background(), srcFilepath)
+}
+
+// StoreObject saves a file to the destination filepath in memory.
+// The `path` should not starts with file separators or dots.
+func (m *Memory) StoreObject(dstFilepath string, src io.Reader, contentType string) error {
+	return m.StoreObjectContext(context.Background(), dstFilepath, src, StoreOptions{ContentType: contentType})
+}
+
+// ShouldMakePublic makes the next files stored to the storage either public or not. Only supported for certain storages.
+func (m *Memory) ShouldMakePublic(makePublic bool) {
+	m.makePublic = makePublic
+}
+
+// GetSignedURL creates a URL granting access to the object specified by the filepath until it expires.
+// The URL can't be downloaded, it's only for asserting in the tests.
+func (m *Memory) GetSignedURL(objFilepath, method string, expires time.Duration) (string, error) {
+	key, _, err := m.begin(context.Background(), "GetSignedURL", objFilepath)
+	if err != nil {
+		return "", err
+	} else if err = validateSignedURLRequest(method, expires); err != nil {
+		return "", err
+	}
+	query := url.Values{}
+	query.Set("method", method)
+	query.Set("expires", strconv.FormatInt(time.Now().Add(expires).Unix(), 10))
+	return memoryPublicURL + (&url.URL{Path: "/" + key}).EscapedPath() + "?" + query.Encode(), nil
+}
+
+// FetchObjectContext creates a new reader to read the contents of the object specified by the filepath.
+// The caller must call Close on the returned reader when done reading.
+func (m *Memory) FetchObjectContext(ctx context.Context, srcFilepath string) (reader io.ReadCloser, code int, err error) {
+	key, code, err := m.begin(ctx, "FetchObject", srcFilepath)
+	if err != nil {
+		return
+	}
+	m.data.RLock()
+	defer m.data.RUnlock()
+	obj, ok := m.data.objects[key]
+	if !ok {
+		return nil, ErrNotFound, fmt.Errorf("Object '%s' is not found", key)
+	}
+	return ioutil.NopCloser(bytes.NewReader(obj.Data)), 0, nil
+}
+
+// DeleteObjectContext deletes the object specified by the filepath.
+func (m *Memory) DeleteObjectContext(ctx context.Context, srcFilepath string) (code int, err error) {
+	key, code, err := m.begin(ctx, "DeleteObject", srcFilepath)
+	if err != nil {
+		return
+	}
+	m.data.Lock()
+	defer m.data.Unlock()
+	if _, ok := m.data.objects[key]; !ok {
+		return ErrNotFound, fmt.Errorf("Object '%s' is not found", key)
+	}
+	delete(m.data.objects, key)
+	return 0, nil
+}
+
+// StoreObjectContext saves a file to the destination filepath in memory.
+// The `path` should not starts with file separators or dots.
+func (m *Memory) StoreObjectContext(ctx context.Context, dstFilepath string, src io.Reader, opts StoreOptions) error {
+	key, _, err := m.begin(ctx, "StoreObject", dstFilepath)
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadAll(&contextReader{ctx, src})
+	if err != nil {
+		return err
+	}
+	obj := copyMemoryObject(MemoryObject{
+		Data:         data,
+		ContentType:  opts.ContentType,
+		CacheControl: opts.CacheControl,
+		Metadata:     opts.Metadata,
+		Public:       m.makePublic,
+		ModifiedTime: time.Now(),
+	})
+	m.data.Lock()
+	defer m.data.Unlock()
+	m.data.objects[key] = obj
+	return nil
+}
+
+// StatObject returns the details of the object specified by the filepath without reading its contents.
+func (m *Memory) StatObject(ctx context.Context, objFilepath string) (info ObjectInfo, code int, err error) {
+	key, code, err := m.begin(ctx, "StatObject", objFilepath)
+	if err != nil {
+		return
+	}
+	m.data.RLock()
+	defer m.data.RUnlock()
+	obj, ok := m.data.objects[key]
+	if !ok {
+		return info, ErrNotFound, fmt.Errorf("Object '%s' is not found", key)
+	}
+	info = memoryObjectInfo(key, obj)
+	info.ContentType = obj.ContentType
+	info.CacheControl = obj.CacheControl
+	info.Metadata = copyMemoryObject(obj).Metadata
+	return info, 0, nil
+}
+
+// ListObjects returns a page of the objects whose filepaths start with the prefix, sorted by their filepaths.
+// The page token is the last filepath of the previous page.
+func (m *Memory) ListObjects(ctx context.Context, prefix, pageToken string, pageSize int) (page ObjectPage, err error) {
+	prefix = trimStartingSlashes(filepath.ToSlash(prefix))
+	if _, _, err = m.begin(ctx, "ListObjects", prefix); err != nil {
+		return
+	}
+	if pageSize <= 0 {
+		pageSize = DefaultListPageSize
+	}
+	m.data.RLock()
+	defer m.data.RUnlock()
+	keys := make([]string, 0)
+	for key := range m.data.objects {
+		if strings.HasPrefix(key, prefix) && key > pageToken {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	if len(keys) > pageSize {
+		keys = keys[:pageSize]
+		page.NextPageToken = keys[pageSize-1]
+	}
+	page.Objects = make([]ObjectInfo, 0, len(keys))
+	for _, key := range keys {
+		page.Objects = append(page.Objects, memoryObjectInfo(key, m.data.objects[key]))
+	}
+	return
+}
+
+// CopyObject copies an object with its metadata in memory.
+// The copy is public if the storage should make public.
+func (m *Memory) CopyObject(ctx context.Context, srcFilepath, dstFilepath string) (code int, err error) {
+	srcKey, code, err := m.begin(ctx, "CopyObject", srcFilepath)
+	if err != nil {
+		return
+	}
+	dstKey, code, err := m.begin(ctx, "CopyObject", dstFilepath)
+	if err != nil {
+		return
+	}
+	m.data.Lock()
+	defer m.data.Unlock()
+	obj, ok := m.data.objects[srcKey]
+	if !ok {
+		return ErrNotFound, fmt.Errorf("Object '%s' is not found", srcKey)
+	}
+	obj = copyMemoryObject(obj)
+	obj.Public = m.makePublic
+	obj.ModifiedTime = time.Now()
+	m.data.objects[dstKey] = obj
+	return 0, nil
+}
+
+// begin returns the key of a filepath after applying the faults of the operation on it.
+func (m *Memory) begin(ctx context.Context, op, objFilepath string) (key string, code int, err error) {
+	if op == "ListObjects" {
+		key = objFilepath
+	} else if key, err = objectKey(objFilepath); err != nil {
+		return "", ErrOther, err
+	}
+
+	// Find the faults of the operation.
+	var latency time.Duration
+	var fault *MemoryFault
+	m.data.RLock()
+	for i, f := range m.data.faults {
+		if f.Op != "" && f.Op != op {
+			continue
+		} else if f.Path != "" {
+			if matched, _ := path.Match(f.Path, key); !matched {
+				continue
+			}
+		}
+		latency += f.Latency
+		if fault == nil && f.Err != nil {
+			fault = &m.data.faults[i]
+		}
+	}
+	m.data.RUnlock()
+
+	// Delay the operation, until the context is done.
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+		case <-timer.C:
+		}
+	}
+	if err = ctx.Err(); err != nil {
+		return "", ErrOther, err
+	} else if fault != nil {
+		code = fault.Code
+		if code == 0 {
+			code = ErrOther
+		}
+		return "", code, fault.Err
+	}
+	return key, 0, nil
+}
+
+func memoryObjectInfo(key string, obj MemoryObject) ObjectInfo {
+	return ObjectInfo{
+		Filepath:     key,
+		Size:         int64(len(obj.Data)),
+		ETag:         fmt.Sprintf(`"%x"`, md5.Sum(obj.Data)),
+		ModifiedTime: obj.ModifiedTime,
+	}
+}
+
+// copyMemoryObject returns a copy of an object, so its data and metadata aren't shared.
+func copyMemoryObject(obj MemoryObject) MemoryObject {
+	obj.Data = append([]byte(nil), obj.Data...)
+	if obj.Metadata != nil {
+		metadata := make(map[string]string, len(obj.Metadata))
+		for k, v := range obj.Metadata {
+			metadata[k] = v
+		}
+		obj.Metadata = metadata
+	}
+	return obj
+}
@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
+)
+
+func TestMemory(t *testing.T) {
+	m := NewMemory()
+	ctx := context.Background()
+
+	opts := StoreOptions{ContentType: "image/jpeg", CacheControl: "max-age=60", Metadata: map[string]string{"checksum": "abc"}}
+	if err := m.StoreObjectContext(ctx, "/photo/a.jpg", strings.NewReader("content"), opts); err != nil {
+		t.Fatalf("StoreObjectContext() error: %v", err)
+	}
+	m.ShouldMakePublic(true)
+	m.StoreObject("photo/b.jpg", strings.NewReader("public"), "image/png")
+
+	obj, ok := m.Object("photo/a.jpg")
+	if !ok || string(obj.Data) != "content" || obj.ContentType != "image/jpeg" || obj.Public || obj.Metadata["checksum"] != "abc" {
+		t.Errorf("Object() = %+v, %v", obj, ok)
+	}
+	if obj, _ = m.Object("photo/b.jpg"); !obj.Public {
+		t.Errorf("Object() = %+v; expected public", obj)
+	}
+
+	reader, code, err := m.FetchObject("photo/a.jpg")
+	if err != nil {
+		t.Fatalf("FetchObject() = %d, %v", code, err)
+	}
+	if body, _ := ioutil.ReadAll(reader); string(body) != "content" {
+		t.Errorf("FetchObject() body = %q; expected %q", body, "content")
+	}
+	info, _, err := m.StatObject(ctx, "photo/a.jpg")
+	if err != nil || info.Size != 7 || info.ETag == "" || info.CacheControl != "max-age=60" || !reflect.DeepEqual(info.Metadata, opts.Metadata) {
+		t.Errorf("StatObject() = %+v, %v", info, err)
+	}
+
+	if code, err = m.CopyObject(ctx, "photo/a.jpg", "copy/a.jpg"); err != nil {
+		t.Errorf("CopyObject() = %d, %v", code, err)
+	}
+	page, err := m.ListObjects(ctx, "photo/", "", 1)
+	if err != nil || len(page.Objects) != 1 || page.Objects[0].Filepath != "photo/a.jpg" || page.NextPageToken != "photo/a.jpg" {
+		t.Errorf("ListObjects() = %+v, %v", page, err)
+	}
+	if page, err = m.ListObjects(ctx, "photo/", page.NextPageToken, 1); err != nil || len(page.Objects) != 1 || page.NextPageToken != "" {
+		t.Errorf("ListObjects() second page = %+v, %v", page, err)
+	}
+
+	if code, err = m.DeleteObject("photo/a.jpg"); err != nil {
+		t.Errorf("DeleteObject() = %d, %v", code, err)
+	}
+	if _, code, err = m.FetchObject("photo/a.jpg"); code != ErrNotFound || err == nil {
+		t.Errorf("FetchObject() of a deleted object = %d, %v; expected ErrNotFound", code, err)
+	}
+	if expected := []string{"copy/a.jpg", "photo/b.jpg"}; !reflect.DeepEqual(m.Filepaths(), expected) {
+		t.Errorf("Filepaths() = %v; expected %v", m.Filepaths(), expected)
+	}
+	if err = m.StoreObject("../a.jpg", strings.NewReader(""), ""); err == nil {
+		t.Errorf("StoreObject() outside the storage = nil error; expected an error")
+	}
+}
+
+func TestMemoryFaults(t *testing.T) {
+	m := NewMemory()
+	ctx := context.Background()
+	errUnavailable := errors.New("unavailable")
+	m.InjectFault(MemoryFault{Path: "photo/*-full", Err: errUnavailable})
+	m.InjectFault(MemoryFault{Op: "DeleteObject", Path: "photo/*", Err: errUnavailable, Code: ErrNotFound})
+
+	if err := m.StoreObject("photo/a-full", strings.NewReader("x"), ""); err != errUnavailable {
+		t.Errorf("StoreObject() of a failing path error = %v; expected %v", err, errUnavailable)
+	}
+	if err := m.StoreObject("photo/a-thumb", strings.NewReader("x"), ""); err != nil {
+		t.Errorf("StoreObject() of another path error = %v", err)
+	}
+	if code, err := m.DeleteObject("photo/a-thumb"); code != ErrNotFound || err != errUnavailable {
+		t.Errorf("DeleteObject() of a failing operation = %d, %v; expected ErrNotFound", code, err)
+	}
+
+	// The latency is cut short by the context.
+	m.ClearFaults()
+	m.InjectFault(MemoryFault{Latency: time.Hour})
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, _, err := m.StatObject(timeout, "photo/a-thumb"); err != context.DeadlineExceeded {
+		t.Errorf("StatObject() with latency error = %v; expected %v", err, context.DeadlineExceeded)
+	}
+
+	m.Reset()
+	if err := m.StoreObject("photo/a-full", strings.NewReader("x"), ""); err != nil || len(m.Filepaths()) != 1 {
+		t.Errorf("StoreObject() after Reset() = %v, %v", err, m.Filepaths())
+	}
+}
+
+func TestUseMemory(t *testing.T) {
+	m := NewMemory()
+	UseMemory(m)
+	defer UseMemory(NewMemory())
+
+	// The instances share the memory storage's files, but not whether they make public.
+	st, err := GetStorageInstance(GetDefaultStorage())
+	if err != nil {
+		t.Fatalf("GetStorageInstance() error: %v", err)
+	}
+	st.ShouldMakePublic(true)
+	st.StoreObject("photo/a.jpg", strings.NewReader("content"), "image/jpeg")
+	if obj, ok := m.Object("photo/a.jpg"); !ok || !obj.Public || m.makePublic {
+		t.Errorf("Object() = %+v, %v; expected the public file stored by the instance", obj, ok)
+	}
+	if res := GeneratePublicFileURL("photo/a.jpg", MemoryStorage, true, false); res != "memory:///photo/a.jpg" {
+		t.Errorf("GeneratePublicFileURL() = %q; expected %q", res, "memory:///photo/a.jpg")
+	}
+}
+
+func TestInitMemoryStorageConfig(t *testing.T) {
+	os.Setenv(envvar.Storage.MemoryLatency, "1ms")
+	os.Setenv(envvar.Storage.MemoryFailPaths, "tmp/*, [")
+	defer os.Unsetenv(envvar.Storage.MemoryLatency)
+	defer os.Unsetenv(envvar.Storage.MemoryFailPaths)
+	initMemoryStorageConfig()
+	defer UseMemory(NewMemory())
+
+	m, _ := getMemoryStorageInstance()
+	if len(m.data.faults) != 2 {
+		t.Errorf("faults = %+v; expected the latency and the valid path", m.data.faults)
+	}
+	if err := m.StoreObject("tmp/a", strings.NewReader("x"), ""); err != ErrMemoryFault {
+		t.Errorf("StoreObject() of a failing path error = %v; expected %v", err, ErrMemoryFault)
+	}
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ObjectStorage defines the context-aware interface for object storage operations. It extends Storage,
+// which is kept for compatibility, and every storage returned by GetStorageInstance implements it.
+type ObjectStorage interface {
+	Storage
+
+	// FetchObjectContext creates a new reader to read the contents of the object specified by the filepath.
+	// The caller must call Close on the returned reader when done reading.
+	FetchObjectContext(ctx context.Context, srcFilepath string) (reader io.ReadCloser, code int, err error)
+
+	// DeleteObjectContext deletes the object specified by the filepath.
+	DeleteObjectContext(ctx context.Context, srcFilepath string) (code int, err error)
+
+	// StoreObjectContext saves a file to the destination filepath, public if the storage should make public.
+	// The `path` should not starts with file separators or dots.
+	StoreObjectContext(ctx context.Context, dstFilepath string, src io.Reader, opts StoreOptions) error
+
+	// StatObject returns the details of the object specified by the filepath without reading its contents.
+	StatObject(ctx context.Context, objFilepath string) (info ObjectInfo, code int, err error)
+
+	// ListObjects returns a page of the objects whose filepaths start with the prefix, sorted by their filepaths.
+	// The page starts after the previous page's NextPageToken, or at the first object if the token is empty.
+	// The listed objects have their filepaths, sizes, ETags and modified times, StatObject returns the rest.
+	ListObjects(ctx context.Context, prefix, pageToken string, pageSize int) (page ObjectPage, err error)
+
+	// CopyObject copies an object with its metadata in the storage, without downloading it.
+	// The copy is public if the storage should make public.
+	CopyObject(ctx context.Context, srcFilepath, dstFilepath string) (code int, err error)
+}
+
+// StoreOptions defines the metadata of a stored object.
+type StoreOptions struct {
+	ContentType  string
+	CacheControl string
+
+	// Metadata is the custom metadata. The keys should be lowercase, as S3 lowercases them.
+	Metadata map[string]string
+}
+
+// ObjectInfo contains the details of an object.
+type ObjectInfo struct {
+	Filepath     string
+	Size         int64
+	ContentType  string
+	CacheControl string
+	ETag         string
+	ModifiedTime time.Time
+	Metadata     map[string]string
+}
+
+// ObjectPage is a page of the listed objects. NextPageToken is empty on the last page.
+type ObjectPage struct {
+	Objects       []ObjectInfo
+	NextPageToken string
+}
+
+// DefaultListPageSize is the page size of ListObjects if it's not positive.
+const DefaultListPageSize = 1000
+
+// GetObjectStorageInstance returns the context-aware storage instance of specific type.
+func GetObjectStorageInstance(storage string) (ObjectStorage, error) {
+	st, err := GetStorageInstance(storage)
+	if err != nil {
+		return nil, err
+	}
+	objStorage, ok := st.(ObjectStorage)
+	if !ok {
+		return nil, fmt.Errorf("Storage '%v' doesn't implement ObjectStorage", storage)
+	}
+	return objStorage, nil
+}
+
+// ListAllObjects calls fn with every object whose filepath starts with the prefix, page by page.
+// It stops at the first error, including the one returned by fn.
+func ListAllObjects(ctx context.Context, st ObjectStorage, prefix string, fn func(ObjectInfo) error) error {
+	pageToken := ""
+	for {
+		page, err := st.ListObjects(ctx, prefix, pageToken, DefaultListPageSize)
+		if err != nil {
+			return err
+		}
+		for _, info := range page.Objects {
+			if err = fn(info); err != nil {
+				return err
+			}
+		}
+		if page.NextPageToken == "" {
+			return nil
+		} else if page.NextPageToken == pageToken {
+			return errors.New("Listing objects doesn't advance")
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+var (
+	_ ObjectStorage = (*tLocalStorage)(nil)
+	_ ObjectStorage = (*tGoogleCloudStorage)(nil)
+	_ ObjectStorage = (*tS3Storage)(nil)
+	_ ObjectStorage = (*Memory)(nil)
+)
@@ -0,0 +1,476 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
+	"github.com/jonylim/basego/internal/pkg/common/logger"
+)
+
+// S3StorageConfig defines configurations for using Amazon S3 or an S3-compatible storage, e.g. MinIO.
+type S3StorageConfig struct {
+	// Endpoint is the storage's base URL. Defaults to the AWS S3 endpoint of the region.
+	Endpoint string
+
+	Region, BucketName string
+
+	// PathStyle addresses the buckets as "<endpoint>/<bucket>" instead of "<bucket>.<endpoint host>",
+	// which most S3-compatible storages require.
+	PathStyle bool
+
+	AccessKeyID, SecretAccessKey, SessionToken string
+}
+
+type tS3Storage struct {
+	endpoint   *url.URL
+	region     string
+	bucketName string
+	pathStyle  bool
+	cred       s3Credentials
+	client     *http.Client
+	makePublic bool
+}
+
+// s3MetadataHeaderPrefix is the prefix of the headers of the objects' custom metadata.
+const s3MetadataHeaderPrefix = "X-Amz-Meta-"
+
+// s3ListBucketResult is the response body of ListObjectsV2.
+type s3ListBucketResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		LastModified time.Time `xml:"LastModified"`
+		ETag         string    `xml:"ETag"`
+		Size         int64     `xml:"Size"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// s3Error is the error returned by S3 in the response body.
+type s3Error struct {
+	StatusCode int    `xml:"-"`
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+func (e *s3Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("S3 responded with status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("S3 responded with status %d: %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// isNotFound returns true if the object doesn't exist. HEAD responses have no body, so their 404 has no code.
+func (e *s3Error) isNotFound() bool {
+	return e.StatusCode == http.StatusNotFound && (e.Code == "" || e.Code == "NoSuchKey")
+}
+
+var s3Config S3StorageConfig
+var s3Instance *tS3Storage
+var errS3 = errors.New("Storage is not initialized")
+
+func initS3StorageConfig() {
+	s3Config = S3StorageConfig{
+		Endpoint:        strings.TrimRight(os.Getenv(envvar.S3.Endpoint), "/"),
+		Region:          os.Getenv(envvar.S3.Region),
+		BucketName:      os.Getenv(envvar.S3.Bucket),
+		AccessKeyID:     os.Getenv(envvar.S3.AccessKeyID),
+		SecretAccessKey: os.Getenv(envvar.S3.SecretAccessKey),
+		SessionToken:    os.Getenv(envvar.S3.SessionToken),
+	}
+
+	// Validate the configs.
+	if s3Config.Region == "" {
+		s3Config.Region = "us-east-1"
+		logger.Println("storage", fmt.Sprintf("WARN: S3StorageConfig.Region is empty, set to '%v' as default", s3Config.Region))
+	} else {
+		logger.Println("storage", fmt.Sprintf("S3StorageConfig.Region set to '%s'", s3Config.Region))
+	}
+	if s3Config.Endpoint == "" {
+		s3Config.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", s3Config.Region)
+		logger.Println("storage", fmt.Sprintf("WARN: S3StorageConfig.Endpoint is empty, set to '%v' as default", s3Config.Endpoint))
+	} else {
+		logger.Println("storage", fmt.Sprintf("S3StorageConfig.Endpoint set to '%s'", s3Config.Endpoint))
+	}
+	if s3Config.BucketName == "" {
+		logger.Println("storage", "WARN: S3StorageConfig.BucketName is empty")
+	} else {
+		logger.Println("storage", fmt.Sprintf("S3StorageConfig.BucketName set to '%s'", s3Config.BucketName))
+	}
+	if s := os.Getenv(envvar.S3.PathStyle); s != "" {
+		if v, err := strconv.ParseBool(s); err == nil {
+			s3Config.PathStyle = v
+			logger.Println("storage", fmt.Sprintf("S3StorageConfig.PathStyle set to '%v'", s3Config.PathStyle))
+		} else {
+			logger.Println("storage", fmt.Sprintf("WARN: S3StorageConfig.PathStyle '%s' is invalid, set to '%v' as default", s, s3Config.PathStyle))
+		}
+	}
+	if s3Config.AccessKeyID == "" || s3Config.SecretAccessKey == "" {
+		logger.Println("storage", "WARN: S3StorageConfig.AccessKeyID or S3StorageConfig.SecretAccessKey is empty")
+	}
+
+	// Set default error.
+	if u, err := url.Parse(s3Config.Endpoint); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		errS3 = fmt.Errorf("S3 endpoint '%s' is invalid", s3Config.Endpoint)
+	} else if s3Config.BucketName == "" || s3Config.AccessKeyID == "" || s3Config.SecretAccessKey == "" {
+		errS3 = errors.New("Storage is not configured correctly")
+	} else {
+		errS3 = nil
+		s3Instance, _ = newS3Storage()
+	}
+}
+
+func newS3Storage() (*tS3Storage, error) {
+	if errS3 != nil {
+		return nil, errS3
+	}
+	endpoint, err := url.Parse(s3Config.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &tS3Storage{
+		endpoint:   endpoint,
+		region:     s3Config.Region,
+		bucketName: s3Config.BucketName,
+		pathStyle:  s3Config.PathStyle,
+		cred: s3Credentials{
+			accessKeyID:     s3Config.AccessKeyID,
+			secretAccessKey: s3Config.SecretAccessKey,
+			sessionToken:    s3Config.SessionToken,
+		},
+		client:     http.DefaultClient,
+		makePublic: false,
+	}, nil
+}
+
+func getS3StorageInstance() (*tS3Storage, error) {
+	return s3Instance, errS3
+}
+
+// GetPublicFileURL creates public file URL of the specified filepath.
+func (instance *tS3Storage) GetPublicFileURL(objFilepath string) string {
+	objFilepath = filepath.ToSlash(filepath.Clean(trimStartingSlashes(objFilepath)))
+	if objFilepath == "" {
+		return ""
+	} else if objFilepath[:1] == "." {
+		return ""
+	}
+	return instance.objectURL(objFilepath).String()
+}
+
+// FetchObject creates a new reader to read the contents of the object specified by the filepath.
+// The caller must call Close on the returned reader when done reading.
+func (instance *tS3Storage) FetchObject(srcFilepath string) (reader io.ReadCloser, code int, err error) {
+	return instance.FetchObjectContext(context.Background(), srcFilepath)
+}
+
+// DeleteObject deletes the object specified by the filepath.
+func (instance *tS3Storage) DeleteObject(srcFilepath string) (code int, err error) {
+	return instance.DeleteObjectContext(context.Background(), srcFilepath)
+}
+
+// StoreObject saves a file to the destination filepath in an S3 bucket.
+// The `path` should not starts with file separators or dots.
+func (instance *tS3Storage) StoreObject(dstFilepath string, src io.Reader, contentType string) error {
+	return instance.StoreObjectContext(context.Background(), dstFilepath, src, StoreOptions{ContentType: contentType})
+}
+
+// FetchObjectContext creates a new reader to read the contents of the object specified by the filepath.
+// The caller must call Close on the returned reader when done reading.
+func (instance *tS3Storage) FetchObjectContext(ctx context.Context, srcFilepath string) (reader io.ReadCloser, code int, err error) {
+	key, err := objectKey(srcFilepath)
+	if err != nil {
+		return nil, ErrOther, err
+	}
+	resp, err := instance.do(ctx, http.MethodGet, instance.objectURL(key), nil, nil)
+	if err != nil {
+		return nil, s3ErrorCode(err), err
+	}
+	return resp.Body, 0, nil
+}
+
+// DeleteObjectContext deletes the object specified by the filepath.
+func (instance *tS3Storage) DeleteObjectContext(ctx context.Context, srcFilepath string) (code int, err error) {
+	key, err := objectKey(srcFilepath)
+	if err != nil {
+		return ErrOther, err
+	}
+
+	// S3 deletes a missing object successfully, so check if it exists first.
+	objURL := instance.objectURL(key)
+	resp, err := instance.do(ctx, http.MethodHead, objURL, nil, nil)
+	if err != nil {
+		return s3ErrorCode(err), err
+	}
+	resp.Body.Close()
+
+	// Delete the object.
+	resp, err = instance.do(ctx, http.MethodDelete, objURL, nil, nil)
+	if err != nil {
+		return s3ErrorCode(err), err
+	}
+	resp.Body.Close()
+	return 0, nil
+}
+
+// StoreObjectContext saves a file to the destination filepath in an S3 bucket.
+// The `path` should not starts with file separators or dots.
+func (instance *tS3Storage) StoreObjectContext(ctx context.Context, dstFilepath string, src io.Reader, opts StoreOptions) error {
+	key, err := objectKey(dstFilepath)
+	if err != nil {
+		return err
+	}
+
+	// Read the file, as the payload is signed by its hash.
+	body, err := ioutil.ReadAll(src)
+	if err != nil {
+		return err
+	}
+	header := http.Header{}
+	if opts.ContentType != "" {
+		header.Set("Content-Type", opts.ContentType)
+	}
+	if opts.CacheControl != "" {
+		header.Set("Cache-Control", opts.CacheControl)
+	}
+	for k, v := range opts.Metadata {
+		header.Set(s3MetadataHeaderPrefix+k, v)
+	}
+	if instance.makePublic {
+		header.Set("X-Amz-Acl", "public-read")
+	}
+
+	// Put the object, creating the bucket if it doesn't exist.
+	resp, err := instance.do(ctx, http.MethodPut, instance.objectURL(key), header, body)
+	if e, ok := err.(*s3Error); ok && e.Code == "NoSuchBucket" {
+		if err = instance.createBucket(ctx); err != nil {
+			return err
+		}
+		resp, err = instance.do(ctx, http.MethodPut, instance.objectURL(key), header, body)
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// StatObject returns the details of the object specified by the filepath without reading its contents.
+func (instance *tS3Storage) StatObject(ctx context.Context, objFilepath string) (info ObjectInfo, code int, err error) {
+	key, err := objectKey(objFilepath)
+	if err != nil {
+		return info, ErrOther, err
+	}
+	resp, err := instance.do(ctx, http.MethodHead, instance.objectURL(key), nil, nil)
+	if err != nil {
+		return info, s3ErrorCode(err), err
+	}
+	resp.Body.Close()
+
+	info = ObjectInfo{
+		Filepath:     key,
+		Size:         resp.ContentLength,
+		ContentType:  resp.Header.Get("Content-Type"),
+		CacheControl: resp.Header.Get("Cache-Control"),
+		ETag:         resp.Header.Get("ETag"),
+	}
+	info.ModifiedTime, _ = http.ParseTime(resp.Header.Get("Last-Modified"))
+	for name, values := range resp.Header {
+		if strings.HasPrefix(name, s3MetadataHeaderPrefix) && len(values) > 0 {
+			if info.Metadata == nil {
+				info.Metadata = make(map[string]string)
+			}
+			info.Metadata[strings.ToLower(strings.TrimPrefix(name, s3MetadataHeaderPrefix))] = values[0]
+		}
+	}
+	return info, 0, nil
+}
+
+// ListObjects returns a page of the objects whose filepaths start with the prefix, sorted by their filepaths.
+// The page token is S3's continuation token.
+func (instance *tS3Storage) ListObjects(ctx context.Context, prefix, pageToken string, pageSize int) (page ObjectPage, err error) {
+	if pageSize <= 0 {
+		pageSize = DefaultListPageSize
+	}
+	query := url.Values{}
+	query.Set("list-type", "2")
+	query.Set("prefix", trimStartingSlashes(filepath.ToSlash(prefix)))
+	query.Set("max-keys", strconv.Itoa(pageSize))
+	if pageToken != "" {
+		query.Set("continuation-token", pageToken)
+	}
+	u := instance.objectURL("")
+	u.RawQuery = s3EncodeQuery(query)
+	resp, err := instance.do(ctx, http.MethodGet, u, nil, nil)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	var result s3ListBucketResult
+	if err = xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return
+	}
+	page.Objects = make([]ObjectInfo, 0, len(result.Contents))
+	for _, item := range result.Contents {
+		page.Objects = append(page.Objects, ObjectInfo{
+			Filepath:     item.Key,
+			Size:         item.Size,
+			ETag:         item.ETag,
+			ModifiedTime: item.LastModified,
+		})
+	}
+	if result.IsTruncated {
+		page.NextPageToken = result.NextContinuationToken
+	}
+	return
+}
+
+// CopyObject copies an object with its metadata in the bucket, copied by S3.
+// The copy is public if the storage should make public.
+func (instance *tS3Storage) CopyObject(ctx context.Context, srcFilepath, dstFilepath string) (code int, err error) {
+	srcKey, err := objectKey(srcFilepath)
+	if err != nil {
+		return ErrOther, err
+	}
+	dstKey, err := objectKey(dstFilepath)
+	if err != nil {
+		return ErrOther, err
+	}
+	header := http.Header{}
+	header.Set("X-Amz-Copy-Source", "/"+s3Escape(instance.bucketName, true)+"/"+s3Escape(srcKey, false))
+	if srcKey == dstKey {
+		// S3 doesn't copy an object to itself without changing its metadata, so replace it with the same.
+		info, code, err := instance.StatObject(ctx, srcKey)
+		if err != nil {
+			return code, err
+		}
+		header.Set("X-Amz-Metadata-Directive", "REPLACE")
+		header.Set("Content-Type", info.ContentType)
+		if info.CacheControl != "" {
+			header.Set("Cache-Control", info.CacheControl)
+		}
+		for k, v := range info.Metadata {
+			header.Set(s3MetadataHeaderPrefix+k, v)
+		}
+	}
+	if instance.makePublic {
+		header.Set("X-Amz-Acl", "public-read")
+	}
+
+	// S3 may respond with an error in the body of a successful response.
+	resp, err := instance.do(ctx, http.MethodPut, instance.objectURL(dstKey), header, nil)
+	if err != nil {
+		if e, ok := err.(*s3Error); ok && e.Code == "NoSuchKey" {
+			return ErrNotFound, err
+		}
+		return ErrOther, err
+	}
+	defer resp.Body.Close()
+	e := &s3Error{StatusCode: resp.StatusCode}
+	if b, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 64*1024)); bytes.Contains(b, []byte("<Error>")) {
+		xml.Unmarshal(b, e)
+		return ErrOther, e
+	}
+	return 0, nil
+}
+
+// ShouldMakePublic makes the next files stored to the storage either public or not. Only supported for certain storages.
+func (instance *tS3Storage) ShouldMakePublic(makePublic bool) {
+	instance.makePublic = makePublic
+}
+
+// GetSignedURL creates a URL granting access to the object specified by the filepath until it expires.
+func (instance *tS3Storage) GetSignedURL(objFilepath, method string, expires time.Duration) (string, error) {
+	key, err := objectKey(objFilepath)
+	if err != nil {
+		return "", err
+	} else if err = validateSignedURLRequest(method, expires); err != nil {
+		return "", err
+	}
+	return presignS3URL(method, instance.objectURL(key), instance.cred, instance.region, time.Now(), expires), nil
+}
+
+// createBucket creates the bucket in the storage's region.
+func (instance *tS3Storage) createBucket(ctx context.Context) error {
+	var body []byte
+	if instance.region != "us-east-1" {
+		body = []byte(fmt.Sprintf(`<CreateBucketConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">`+
+			`<LocationConstraint>%s</LocationConstraint></CreateBucketConfiguration>`, instance.region))
+	}
+	resp, err := instance.do(ctx, http.MethodPut, instance.objectURL(""), nil, body)
+	if e, ok := err.(*s3Error); ok && e.Code == "BucketAlreadyOwnedByYou" {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// objectURL returns the URL of an object, or of the bucket if the key is empty.
+func (instance *tS3Storage) objectURL(key string) *url.URL {
+	u := *instance.endpoint
+	basePath := strings.TrimRight(u.Path, "/")
+	if instance.pathStyle {
+		basePath += "/" + instance.bucketName
+	} else {
+		u.Host = instance.bucketName + "." + u.Host
+	}
+	u.Path = basePath + "/" + key
+	u.RawPath = s3Escape(basePath, false) + "/" + s3Escape(key, false)
+	u.RawQuery = ""
+	return &u
+}
+
+// do sends a signed request to S3. If S3 responds with an error status, the error is an *s3Error.
+func (instance *tS3Storage) do(ctx context.Context, method string, u *url.URL, header http.Header, body []byte) (*http.Response, error) {
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	payloadHash := s3EmptyPayloadHash
+	if len(body) > 0 {
+		payloadHash = s3SHA256Hex(body)
+	}
+	signS3Request(req, payloadHash, instance.cred, instance.region, time.Now())
+
+	resp, err := instance.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		e := &s3Error{StatusCode: resp.StatusCode}
+		if b, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 64*1024)); len(b) > 0 {
+			xml.Unmarshal(b, e)
+		}
+		return nil, e
+	}
+	return resp, nil
+}
+
+// s3ErrorCode returns the storage error code of an error returned by S3.
+func s3ErrorCode(err error) int {
+	if e, ok := err.(*s3Error); ok && e.isNotFound() {
+		return ErrNotFound
+	}
+	return ErrOther
+}
@@ -0,0 +1,173 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	s3SigningAlgorithm = "AWS4-HMAC-SHA256"
+	s3TimeFormat       = "20060102T150405Z"
+	s3DateFormat       = "20060102"
+	s3Service          = "s3"
+)
+
+// s3EmptyPayloadHash is the SHA-256 of an empty payload, for the requests without a body.
+const s3EmptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// s3UnsignedPayload is the payload hash of the presigned URLs, whose payloads are unknown when signing.
+const s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+
+// s3Credentials are the credentials signing the requests to S3.
+type s3Credentials struct {
+	accessKeyID, secretAccessKey, sessionToken string
+}
+
+// signS3Request signs a request with AWS Signature Version 4, signing the host and all the request's headers.
+// The headers added after signing, e.g. by the HTTP client, aren't signed and don't invalidate the signature.
+func signS3Request(req *http.Request, payloadHash string, cred s3Credentials, region string, now time.Time) {
+	now = now.UTC()
+	req.Header.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if cred.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", cred.sessionToken)
+	}
+
+	headers, signedHeaders := s3CanonicalHeaders(req)
+	signature := s3Signature(req.Method, req.URL, headers, signedHeaders, payloadHash, cred, region, now)
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3SigningAlgorithm, cred.accessKeyID, s3Scope(region, now), signedHeaders, signature))
+}
+
+// presignS3URL returns a URL granting the method on an object until it expires, signed in its query.
+// Only the host is signed, so the clients can send any headers, e.g. the content type of an upload.
+func presignS3URL(method string, objURL *url.URL, cred s3Credentials, region string, now time.Time, expires time.Duration) string {
+	now = now.UTC()
+	u := *objURL
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", s3SigningAlgorithm)
+	query.Set("X-Amz-Credential", cred.accessKeyID+"/"+s3Scope(region, now))
+	query.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	query.Set("X-Amz-Expires", strconv.FormatInt(int64(expires/time.Second), 10))
+	query.Set("X-Amz-SignedHeaders", "host")
+	if cred.sessionToken != "" {
+		query.Set("X-Amz-Security-Token", cred.sessionToken)
+	}
+	u.RawQuery = s3EncodeQuery(query)
+
+	signature := s3Signature(method, &u, "host:"+u.Host+"\n", "host", s3UnsignedPayload, cred, region, now)
+	u.RawQuery += "&X-Amz-Signature=" + signature
+	return u.String()
+}
+
+// s3Signature returns the signature of a request's canonical form.
+func s3Signature(method string, u *url.URL, headers, signedHeaders, payloadHash string, cred s3Credentials, region string, now time.Time) string {
+	canonicalRequest := strings.Join([]string{
+		method,
+		s3CanonicalURI(u),
+		s3EncodeQuery(u.Query()),
+		headers,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	stringToSign := strings.Join([]string{
+		s3SigningAlgorithm,
+		now.Format(s3TimeFormat),
+		s3Scope(region, now),
+		s3SHA256Hex([]byte(canonicalRequest)),
+	}, "\n")
+	return hex.EncodeToString(s3HMAC(s3SigningKey(cred.secretAccessKey, now, region), stringToSign))
+}
+
+func s3Scope(region string, now time.Time) string {
+	return strings.Join([]string{now.Format(s3DateFormat), region, s3Service, "aws4_request"}, "/")
+}
+
+// s3CanonicalHeaders returns the canonical headers, each ending with a newline, and the signed headers' names.
+func s3CanonicalHeaders(req *http.Request) (canonical, signed string) {
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	values := map[string]string{"host": strings.TrimSpace(host)}
+	for name, vals := range req.Header {
+		trimmed := make([]string, len(vals))
+		for i, v := range vals {
+			trimmed[i] = strings.Join(strings.Fields(v), " ")
+		}
+		values[strings.ToLower(name)] = strings.Join(trimmed, ",")
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name + ":" + values[name] + "\n")
+	}
+	return b.String(), strings.Join(names, ";")
+}
+
+// s3CanonicalURI returns the URL's escaped path. S3 signs the path as sent, without escaping it twice.
+func s3CanonicalURI(u *url.URL) string {
+	if p := u.EscapedPath(); p != "" {
+		return p
+	}
+	return "/"
+}
+
+// s3EncodeQuery returns the query escaped as S3 expects, sorted by name and value.
+func s3EncodeQuery(query url.Values) string {
+	params := make([]string, 0, len(query))
+	for name, vals := range query {
+		for _, v := range vals {
+			params = append(params, s3Escape(name, true)+"="+s3Escape(v, true))
+		}
+	}
+	sort.Strings(params)
+	return strings.Join(params, "&")
+}
+
+// s3Escape escapes a string as S3 expects: all bytes except the unreserved characters,
+// and "/" too unless it's a key's separator.
+func s3Escape(s string, escapeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || (c == '/' && !escapeSlash) {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func s3SigningKey(secretAccessKey string, now time.Time, region string) []byte {
+	key := s3HMAC([]byte("AWS4"+secretAccessKey), now.Format(s3DateFormat))
+	key = s3HMAC(key, region)
+	key = s3HMAC(key, s3Service)
+	return s3HMAC(key, "aws4_request")
+}
+
+func s3HMAC(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func s3SHA256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
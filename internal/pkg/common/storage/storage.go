@@ -4,7 +4,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/jonylim/basego/internal/pkg/common/constant/envvar"
 	"github.com/jonylim/basego/internal/pkg/common/logger"
@@ -28,12 +32,18 @@ type Storage interface {
 
 	// ShouldMakePublic makes the next files stored to the storage either public or not. Only supported for certain storages.
 	ShouldMakePublic(makePublic bool)
+
+	// GetSignedURL creates a URL granting access to the object specified by the filepath until it expires.
+	// The method is either GET to download or PUT to upload the object.
+	GetSignedURL(objFilepath, method string, expires time.Duration) (string, error)
 }
 
 // Constants for available storage types.
 const (
 	LocalStorage       = "local"
 	GoogleCloudStorage = "gcs"
+	S3Storage          = "s3"
+	MemoryStorage      = "memory"
 )
 
 // Constants for error code.
@@ -42,7 +52,15 @@ const (
 	ErrOther    = 9
 )
 
+// MaxSignedURLExpiry is the longest time a signed URL can be valid, as limited by GCS and S3.
+const MaxSignedURLExpiry = 7 * 24 * time.Hour
+
+// cstAccountFileDownloadPath is the path of the account API downloading the account's files.
+const cstAccountFileDownloadPath = "/v1/account/files/"
+
 var defaultStorage string
+var signedURLExpiry = 24 * time.Hour
+var backendURL string
 var isInitialized = false
 
 // Init initializes storage configurations.
@@ -51,8 +69,12 @@ func Init() {
 	SetDefaultStorage(os.Getenv(envvar.Storage.DefaultStorage))
 
 	// Initializes storage configurations.
+	backendURL = strings.TrimRight(os.Getenv(envvar.BackendURL), "/")
+	initSignedURLExpiry()
 	initLocalStorageConfig()
 	initGoogleCloudStorageConfig()
+	initS3StorageConfig()
+	initMemoryStorageConfig()
 
 	// Mark as initialized.
 	isInitialized = true
@@ -63,7 +85,7 @@ func SetDefaultStorage(storage string) {
 	if storage == "" {
 		logger.Println("storage", "ERROR: Default storage is undefined")
 		os.Exit(1)
-	} else if storage != LocalStorage && storage != GoogleCloudStorage {
+	} else if storage != LocalStorage && storage != GoogleCloudStorage && storage != S3Storage && storage != MemoryStorage {
 		logger.Println("storage", fmt.Sprintf("ERROR: Default storage '%v' is invalid", storage))
 		os.Exit(1)
 	} else {
@@ -72,6 +94,29 @@ func SetDefaultStorage(storage string) {
 	defaultStorage = storage
 }
 
+// initSignedURLExpiry sets how long the generated URLs of the private files are valid.
+func initSignedURLExpiry() {
+	s := os.Getenv(envvar.Storage.SignedURLExpiry)
+	if s == "" {
+		logger.Println("storage", fmt.Sprintf("WARN: Signed URL expiry is empty, set to '%v' as default", signedURLExpiry))
+	} else if d, err := time.ParseDuration(s); err != nil || d <= 0 || d > MaxSignedURLExpiry {
+		logger.Println("storage", fmt.Sprintf("WARN: Signed URL expiry '%s' is invalid, set to '%v' as default", s, signedURLExpiry))
+	} else {
+		signedURLExpiry = d
+		logger.Println("storage", fmt.Sprintf("Signed URL expiry set to '%v'", signedURLExpiry))
+	}
+}
+
+// validateSignedURLRequest checks the method and expiry of a signed URL.
+func validateSignedURLRequest(method string, expires time.Duration) error {
+	if method != http.MethodGet && method != http.MethodPut {
+		return fmt.Errorf("Signed URL method '%s' is invalid", method)
+	} else if expires <= 0 || expires > MaxSignedURLExpiry {
+		return fmt.Errorf("Signed URL expiry '%v' is invalid", expires)
+	}
+	return nil
+}
+
 // GetDefaultStorage returns default storage type.
 func GetDefaultStorage() string {
 	return defaultStorage
@@ -85,8 +130,12 @@ func GetStorageInstance(storage string) (Storage, error) {
 	switch storage {
 	case GoogleCloudStorage:
 		return newGoogleCloudStorage()
+	case S3Storage:
+		return newS3Storage()
 	case LocalStorage:
 		return newLocalStorage()
+	case MemoryStorage:
+		return newMemoryStorage()
 	default:
 		return nil, fmt.Errorf("Storage '%v' is invalid", storage)
 	}
@@ -114,18 +163,31 @@ func getPrivateInstance(storage string) (Storage, error) {
 	switch storage {
 	case GoogleCloudStorage:
 		return getGoogleCloudStorageInstance()
+	case S3Storage:
+		return getS3StorageInstance()
 	case LocalStorage:
 		return getLocalStorageInstance()
+	case MemoryStorage:
+		return getMemoryStorageInstance()
 	default:
 		return nil, fmt.Errorf("Storage '%v' is invalid", storage)
 	}
 }
 
+// objectKey returns the cleaned filepath of an object, which can't be empty or start with a dot.
+func objectKey(p string) (string, error) {
+	p = filepath.ToSlash(filepath.Clean(trimStartingSlashes(p)))
+	if p == "" {
+		return "", errors.New("Path can't be empty")
+	} else if p[:1] == "." {
+		return "", errors.New("Path can't start with dot")
+	}
+	return p, nil
+}
+
 func trimStartingSlashes(s string) string {
-	if s != "" {
-		for s[0] == '/' || s[0] == '\\' {
-			s = s[1:]
-		}
+	for s != "" && (s[0] == '/' || s[0] == '\\') {
+		s = s[1:]
 	}
 	return s
 }
@@ -1,35 +1,49 @@
 package storage
 
 import (
+	"crypto/hmac"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/jonylim/basego/internal/pkg/basego-api/v1/model"
+	"github.com/jonylim/basego/internal/pkg/common/constant"
+	"github.com/jonylim/basego/internal/pkg/common/crypto/secrethash"
 	"github.com/jonylim/basego/internal/pkg/common/logger"
 )
 
-// GeneratePublicFileURL returns public URL for a file.
-func GeneratePublicFileURL(filepath, storage string, isEncrypted bool) (url string) {
+// cstAccountFileDownloadPurpose is the purpose of the signatures of the account's file download URLs.
+const cstAccountFileDownloadPurpose = "cstAccountFileDownload"
+
+// GeneratePublicFileURL returns public URL for a file. The URL of a private file is signed, and expires.
+func GeneratePublicFileURL(filepath, storage string, isPublic, isEncrypted bool) (url string) {
 	if filepath != "" {
 		if isEncrypted {
-			// TODO: Not supported yet.
-			logger.Warn("storage", "Public file URL for encrypted file is not supported yet")
+			logger.Warn("storage", "Encrypted files have no public URLs, they're downloaded by the owners' APIs")
 		} else {
 			instance, err := getPrivateInstance(storage)
 			if err != nil {
 				logger.Error("storage", logger.FromError(err))
 				return
 			}
-			url = instance.GetPublicFileURL(filepath)
+			url = getFileURL(instance, filepath, isPublic)
 		}
 	}
 	return
 }
 
 // GenerateCstAccountPhotoURL returns image URL for a customer account's photo.
-func GenerateCstAccountPhotoURL(filename, storage string, isEncrypted bool) model.ImageURL {
+// An encrypted photo's URLs are signed URLs of the account API downloading the account's files, decrypted.
+func GenerateCstAccountPhotoURL(accountID int64, filename, storage string, isPublic, isEncrypted bool) model.ImageURL {
 	if filename != "" {
 		fullFilepath, thumbFilepath := GetCstAccountPhotoFilepath(filename)
 		if isEncrypted {
-			// TODO: Not supported yet.
-			logger.Warn("storage", "Public file URL for encrypted file is not supported yet")
+			return model.ImageURL{
+				Fullsize:  CstAccountFileDownloadURL(accountID, constant.FileCategoryPhoto, PhotoVariantFull),
+				Thumbnail: CstAccountFileDownloadURL(accountID, constant.FileCategoryPhoto, PhotoVariantThumbnail),
+			}
 		} else {
 			instance, err := getPrivateInstance(storage)
 			if err != nil {
@@ -37,10 +51,64 @@ func GenerateCstAccountPhotoURL(filename, storage string, isEncrypted bool) mode
 				return model.ImageURL{}
 			}
 			return model.ImageURL{
-				Fullsize:  instance.GetPublicFileURL(fullFilepath),
-				Thumbnail: instance.GetPublicFileURL(thumbFilepath),
+				Fullsize:  getFileURL(instance, fullFilepath, isPublic),
+				Thumbnail: getFileURL(instance, thumbFilepath, isPublic),
 			}
 		}
 	}
 	return model.ImageURL{}
 }
+
+// CstAccountFileDownloadURL returns the signed URL of the account API downloading a variant of an account's file.
+// Until the URL expires, the file is downloaded without the API's request headers, e.g. by an <img> element.
+func CstAccountFileDownloadURL(accountID int64, category, variant string) string {
+	expiresAt := time.Now().Add(signedURLExpiry).Unix()
+	query := url.Values{}
+	query.Set("account", strconv.FormatInt(accountID, 10))
+	query.Set("expires", strconv.FormatInt(expiresAt, 10))
+	query.Set("signature", cstAccountFileDownloadSignature(accountID, category, variant, expiresAt))
+	return backendURL + cstAccountFileDownloadPath + category + "/" + variant + "?" + query.Encode()
+}
+
+// IsSignedCstAccountFileDownload checks if a request to the account API downloading a file has a signed URL.
+func IsSignedCstAccountFileDownload(query url.Values) bool {
+	return query.Get("signature") != ""
+}
+
+// VerifyCstAccountFileDownloadURL checks the query of a signed URL from CstAccountFileDownloadURL, downloading the
+// file variant at the time, and returns the account ID. The boolean is false if it's invalid or expired.
+func VerifyCstAccountFileDownloadURL(category, variant string, query url.Values, now time.Time) (int64, bool) {
+	accountID, err := strconv.ParseInt(query.Get("account"), 10, 64)
+	if err != nil || accountID <= 0 {
+		return 0, false
+	}
+	expiresAt, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil || now.Unix() > expiresAt {
+		return 0, false
+	}
+	expected := cstAccountFileDownloadSignature(accountID, category, variant, expiresAt)
+	if !hmac.Equal([]byte(query.Get("signature")), []byte(expected)) {
+		return 0, false
+	}
+	return accountID, true
+}
+
+// cstAccountFileDownloadSignature returns the signature of a URL downloading an account's file variant until the
+// expiry in Unix seconds, keyed by the secrets' hash key shared by the instances.
+func cstAccountFileDownloadSignature(accountID int64, category, variant string, expiresAt int64) string {
+	s := strconv.FormatInt(accountID, 10) + "\n" + category + "\n" + variant + "\n" + strconv.FormatInt(expiresAt, 10)
+	return strings.TrimPrefix(secrethash.Sum(cstAccountFileDownloadPurpose, s), secrethash.Prefix)
+}
+
+// getFileURL returns the public URL of a public file, or a signed URL of a private file.
+func getFileURL(instance Storage, filepath string, isPublic bool) string {
+	if isPublic {
+		return instance.GetPublicFileURL(filepath)
+	}
+	url, err := instance.GetSignedURL(filepath, http.MethodGet, signedURLExpiry)
+	if err != nil {
+		logger.Error("storage", logger.FromError(err))
+		return ""
+	}
+	return url
+}
@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonylim/basego/internal/pkg/common/constant"
+	"github.com/jonylim/basego/internal/pkg/common/crypto/secrethash"
+)
+
+func TestCstAccountFileDownloadURL(t *testing.T) {
+	secrethash.SetKey(bytes.Repeat([]byte{1}, secrethash.MinKeySize))
+	defer secrethash.SetKey(nil)
+
+	photoURL := GenerateCstAccountPhotoURL(42, "a", "", false, true)
+	u, err := url.Parse(photoURL.Thumbnail)
+	if err != nil || !strings.HasSuffix(u.Path, "/v1/account/files/photo/thumb") || !IsSignedCstAccountFileDownload(u.Query()) {
+		t.Fatalf("GenerateCstAccountPhotoURL() of an encrypted photo = %+v; expected signed download URLs", photoURL)
+	}
+	now := time.Now()
+	if accountID, ok := VerifyCstAccountFileDownloadURL(constant.FileCategoryPhoto, PhotoVariantThumbnail, u.Query(), now); !ok || accountID != 42 {
+		t.Errorf("VerifyCstAccountFileDownloadURL() = %d, %v; expected 42, true", accountID, ok)
+	}
+
+	// The URL is only valid for its account and variant until it expires.
+	if _, ok := VerifyCstAccountFileDownloadURL(constant.FileCategoryPhoto, PhotoVariantFull, u.Query(), now); ok {
+		t.Errorf("VerifyCstAccountFileDownloadURL() of another variant = true; expected false")
+	}
+	if _, ok := VerifyCstAccountFileDownloadURL(constant.FileCategoryPhoto, PhotoVariantThumbnail, u.Query(), now.Add(signedURLExpiry+time.Minute)); ok {
+		t.Errorf("VerifyCstAccountFileDownloadURL() after the expiry = true; expected false")
+	}
+	query := u.Query()
+	query.Set("account", "43")
+	if _, ok := VerifyCstAccountFileDownloadURL(constant.FileCategoryPhoto, PhotoVariantThumbnail, query, now); ok {
+		t.Errorf("VerifyCstAccountFileDownloadURL() of another account = true; expected false")
+	}
+	if IsSignedCstAccountFileDownload(url.Values{}) {
+		t.Errorf("IsSignedCstAccountFileDownload() without a signature = true; expected false")
+	}
+}
@@ -0,0 +1,22 @@
+-- Password policy: password age & password history.
+
+ALTER TABLE tb_m_cst_account
+    ADD COLUMN password_changed_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE tb_m_cst_account
+    SET password_changed_at = COALESCE(updated_at, created_at);
+
+ALTER TABLE tb_m_cst_account
+    ALTER COLUMN password_changed_at SET DEFAULT CURRENT_TIMESTAMP,
+    ALTER COLUMN password_changed_at SET NOT NULL;
+
+CREATE TABLE tb_t_cst_account_password_history (
+    id            BIGSERIAL PRIMARY KEY,
+    account_id    BIGINT NOT NULL REFERENCES tb_m_cst_account (id),
+    password      VARCHAR(128) NOT NULL,
+    password_salt VARCHAR(32) NOT NULL,
+    created_at    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX ix_cst_account_password_history_account_id
+    ON tb_t_cst_account_password_history (account_id, id DESC);
@@ -0,0 +1,11 @@
+-- Customer account preferences: locale, time zone & notification settings.
+
+CREATE TABLE tb_m_cst_account_preference (
+    id         BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL REFERENCES tb_m_cst_account (id),
+    key        VARCHAR(64) NOT NULL,
+    value      VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_cst_account_preference_account_id_key UNIQUE (account_id, key)
+);
@@ -0,0 +1,25 @@
+-- Hashed secrets: OTP codes & session tokens are saved as keyed hashes, see package secrethash.
+--
+-- Run with the same key as the app, so the hashes of the existing rows match:
+--   psql -v secret_hash_key="$BASEGO_SECRET_HASH_KEY" -f 003_hashed_secrets.sql
+-- Rows not migrated yet are still compared as plaintext by the app, so this can run after deploying.
+-- Delete the cached rows in Redis afterwards, i.e. the keys "cstAccOTP:*" & "cstAccSessToken:*".
+
+CREATE EXTENSION IF NOT EXISTS pgcrypto;
+
+ALTER TABLE tb_t_cst_account_otp
+    ALTER COLUMN code TYPE VARCHAR(128);
+
+UPDATE tb_t_cst_account_otp
+SET code = 'h1:' || encode(hmac(convert_to('otp:' || code, 'UTF8'), decode(:'secret_hash_key', 'base64'), 'sha256'), 'hex')
+WHERE code NOT LIKE 'h1:%';
+
+UPDATE tb_t_cst_account_session_token
+SET access_token = 'h1:' || encode(hmac(convert_to('accessToken:' || access_token, 'UTF8'), decode(:'secret_hash_key', 'base64'), 'sha256'), 'hex')
+WHERE access_token IS NOT NULL
+    AND access_token NOT LIKE 'h1:%';
+
+UPDATE tb_t_cst_account_session_token
+SET refresh_token = 'h1:' || encode(hmac(convert_to('refreshToken:' || refresh_token, 'UTF8'), decode(:'secret_hash_key', 'base64'), 'sha256'), 'hex')
+WHERE refresh_token IS NOT NULL
+    AND refresh_token NOT LIKE 'h1:%';
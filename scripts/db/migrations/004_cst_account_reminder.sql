@@ -0,0 +1,15 @@
+-- Lifecycle reminders: the reminder emails sent to each customer account, e.g. to verify the email address.
+
+CREATE TABLE tb_t_cst_account_reminder (
+    id         BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL REFERENCES tb_m_cst_account (id),
+    kind       VARCHAR(32) NOT NULL,
+    step       INT NOT NULL,
+    otp_id     BIGINT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_cst_account_reminder_account_id_kind_step UNIQUE (account_id, kind, step)
+);
+
+CREATE INDEX ix_cst_account_unverified_created_at
+    ON tb_m_cst_account (created_at)
+    WHERE is_email_verified = FALSE AND deleted_at IS NULL;
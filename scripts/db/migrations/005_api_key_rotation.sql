@@ -0,0 +1,10 @@
+-- API key rotation: the replaced secret keeps working until its expiry, so the clients can switch over.
+
+ALTER TABLE tb_x_api_key
+    ADD COLUMN previous_api_key_secret VARCHAR(128),
+    ADD COLUMN previous_secret_expiry_time TIMESTAMP WITH TIME ZONE;
+
+-- The generated key IDs and secrets are 32 and 64 hex characters.
+ALTER TABLE tb_x_api_key
+    ALTER COLUMN api_key_id TYPE VARCHAR(64),
+    ALTER COLUMN api_key_secret TYPE VARCHAR(128);
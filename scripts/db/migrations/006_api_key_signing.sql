@@ -0,0 +1,25 @@
+-- API key secrets are saved as keyed hashes, see package secrethash, and the keys can require signed requests.
+-- The signing key of a secret is the hex of its SHA-256, which the clients derive from the secret too.
+--
+-- Run with the same key as the app, so the hashes of the existing secrets match:
+--   psql -v secret_hash_key="$BASEGO_SECRET_HASH_KEY" -f 006_api_key_signing.sql
+-- Secrets not migrated yet are still compared as plaintext by the app, so this can run after deploying.
+-- Delete the cached API keys in Redis afterwards, i.e. the keys "apiKey:*".
+
+CREATE EXTENSION IF NOT EXISTS pgcrypto;
+
+ALTER TABLE tb_x_api_key
+    ADD COLUMN require_signature BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN signing_key VARCHAR(128),
+    ADD COLUMN previous_signing_key VARCHAR(128);
+
+UPDATE tb_x_api_key
+SET signing_key = encode(digest(convert_to(api_key_secret, 'UTF8'), 'sha256'), 'hex'),
+    api_key_secret = 'h1:' || encode(hmac(convert_to('apiKey:' || api_key_secret, 'UTF8'), decode(:'secret_hash_key', 'base64'), 'sha256'), 'hex')
+WHERE api_key_secret NOT LIKE 'h1:%';
+
+UPDATE tb_x_api_key
+SET previous_signing_key = encode(digest(convert_to(previous_api_key_secret, 'UTF8'), 'sha256'), 'hex'),
+    previous_api_key_secret = 'h1:' || encode(hmac(convert_to('apiKey:' || previous_api_key_secret, 'UTF8'), decode(:'secret_hash_key', 'base64'), 'sha256'), 'hex')
+WHERE previous_api_key_secret IS NOT NULL
+    AND previous_api_key_secret NOT LIKE 'h1:%';
@@ -0,0 +1,5 @@
+-- API key scopes: the endpoints a key may call, as patterns separated by commas, e.g. "client/server_time, auth/*".
+-- An empty list allows every endpoint, so the existing keys are unchanged.
+
+ALTER TABLE tb_x_api_key
+    ADD COLUMN scopes VARCHAR(1024) NOT NULL DEFAULT '';
@@ -0,0 +1,19 @@
+-- API key usage: the daily and monthly quotas of each key (0 is unlimited), and the hourly rollup of the requests
+-- made with each key by endpoint and status class, flushed from the counters in Redis.
+
+ALTER TABLE tb_x_api_key
+    ADD COLUMN daily_quota   BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN monthly_quota BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE tb_x_api_key_usage (
+    id            BIGSERIAL PRIMARY KEY,
+    domain        VARCHAR(32) NOT NULL,
+    api_key_id    VARCHAR(64) NOT NULL,
+    period_start  TIMESTAMP WITH TIME ZONE NOT NULL,
+    endpoint      VARCHAR(128) NOT NULL,
+    status_class  VARCHAR(3) NOT NULL,
+    request_count BIGINT NOT NULL DEFAULT 0,
+    created_at    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_x_api_key_usage_key_period_endpoint_status UNIQUE (domain, api_key_id, period_start, endpoint, status_class)
+);
@@ -0,0 +1,5 @@
+-- File visibility: the private files are only accessible by signed URLs, which expire.
+-- The existing files stay public, as the storages made them public.
+
+ALTER TABLE tb_m_file
+    ADD COLUMN is_public BOOLEAN NOT NULL DEFAULT TRUE;
@@ -0,0 +1,6 @@
+-- File variants: the images' variants (e.g. thumb, medium, full) and their blurhash placeholders.
+-- The existing files have no variants, their objects are the fullsize and thumbnail images.
+
+ALTER TABLE tb_m_file
+    ADD COLUMN variants JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN blurhash VARCHAR(64) NOT NULL DEFAULT '';